@@ -0,0 +1,221 @@
+// es-reindex rebuilds Elasticsearch indexes from MongoDB without downtime.
+//
+// Usage:
+//
+//	go run cmd/es-reindex/main.go -config="seed" [-keep=2] [businesses users tags]
+//	go run cmd/es-reindex/main.go -config="seed" rollback [businesses users tags]
+//
+// A new versioned index is built from MongoDB with bulk requests. Once the
+// document count matches, the alias is atomically moved to the new index.
+// The newest -keep indexes are kept so the alias can be rolled back, the
+// older ones are deleted.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/es"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/olivere/elastic/v7"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var keep = flag.Int(
+	"keep",
+	2,
+	"number of indexes kept per alias, including the new one",
+)
+
+func main() {
+	global.Init()
+	if *keep < 1 {
+		log.Fatalf("-keep must be at least 1, got %v", *keep)
+	}
+
+	args := flag.Args()
+	rollbackMode := len(args) > 0 && args[0] == "rollback"
+	if rollbackMode {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		args = es.Indexes()
+	}
+
+	for _, alias := range args {
+		if !isIndex(alias) {
+			log.Fatalf("unknown index %q", alias)
+		}
+		if rollbackMode {
+			rollback(alias)
+		} else {
+			reindex(alias)
+		}
+	}
+}
+
+func isIndex(alias string) bool {
+	for _, index := range es.Indexes() {
+		if index == alias {
+			return true
+		}
+	}
+	return false
+}
+
+func reindex(alias string) {
+	log.Printf("start reindexing %v\n", alias)
+	startTime := time.Now()
+
+	index, err := es.CreateIndex(alias)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("created %v\n", index)
+
+	// Don't incluse deleted item.
+	filter := bson.M{
+		"deletedAt": bson.M{"$exists": false},
+	}
+	counter := bulkIndex(alias, index, filter)
+
+	_, err = es.Client().Refresh(index).Do(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	count, err := es.Client().Count(index).Do(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if count != int64(counter) {
+		log.Printf("count mismatch: mongo %v, %v %v\n", counter, index, count)
+		if err := es.DeleteIndex(index); err != nil {
+			log.Println(err)
+		}
+		log.Fatalf("%v was deleted, alias %v is unchanged", index, alias)
+	}
+
+	if err := es.SwapAlias(alias, index); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("alias %v -> %v\n", alias, index)
+
+	// Documents changed while the new index was being built were written
+	// to the old index. Apply them again now that the alias has moved.
+	changed := bson.M{
+		"$or": []bson.M{
+			{"createdAt": bson.M{"$gte": startTime}},
+			{"updatedAt": bson.M{"$gte": startTime}},
+		},
+	}
+	caughtUp := bulkIndex(alias, alias, changed)
+
+	deleted, err := es.PruneVersions(alias, *keep)
+	for _, index := range deleted {
+		log.Printf("deleted %v\n", index)
+	}
+	if err != nil {
+		log.Println(err)
+	}
+
+	log.Printf("count %v\n", counter)
+	log.Printf("catch up %v\n", caughtUp)
+	log.Printf("took  %v\n\n", time.Now().Sub(startTime))
+}
+
+// bulkIndex copies the matching documents of the collection into the
+// index with bulk requests. Soft-deleted documents are removed.
+func bulkIndex(collection, index string, filter bson.M) int {
+	ctx := context.Background()
+	bulkSize := global.Config().ES.BulkSize
+
+	total, err := mongo.DB().Collection(collection).CountDocuments(ctx, filter)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cur, err := mongo.DB().Collection(collection).Find(ctx, filter)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cur.Close(ctx)
+
+	counter := 0
+	bulk := es.Client().Bulk().Index(index)
+	flush := func() {
+		if bulk.NumberOfActions() == 0 {
+			return
+		}
+		res, err := bulk.Do(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, failed := range res.Failed() {
+			// Deleting a document the index never had is fine.
+			if failed.Status == http.StatusNotFound {
+				continue
+			}
+			log.Fatalf("bulk request failed for %v: %+v", failed.Id, failed.Error)
+		}
+		log.Printf("%v: %v/%v\n", index, counter, total)
+	}
+
+	for cur.Next(ctx) {
+		id, record, err := es.NewRecord(collection, cur.Current)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if _, deleted := cur.Current.Lookup("deletedAt").TimeOK(); deleted {
+			bulk.Add(elastic.NewBulkDeleteRequest().Id(id))
+		} else {
+			bulk.Add(elastic.NewBulkIndexRequest().Id(id).Doc(record))
+			counter++
+		}
+		if bulk.NumberOfActions() >= bulkSize {
+			flush()
+		}
+	}
+	if err := cur.Err(); err != nil {
+		log.Fatal(err)
+	}
+	flush()
+
+	return counter
+}
+
+// rollback points the alias back to the version created before the
+// current one.
+func rollback(alias string) {
+	log.Printf("start rolling back %v\n", alias)
+
+	current, err := es.IndicesByAlias(alias)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(current) != 1 {
+		log.Fatalf("alias %v points to %v, expected one index", alias, current)
+	}
+	versions, err := es.Versions(alias)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	previous := ""
+	for _, version := range versions {
+		if version >= current[0] {
+			break
+		}
+		previous = version
+	}
+	if previous == "" {
+		log.Fatalf("no index older than %v to roll back to", current[0])
+	}
+
+	if err := es.SwapAlias(alias, previous); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("alias %v -> %v\n\n", alias, previous)
+}
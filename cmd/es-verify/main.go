@@ -0,0 +1,41 @@
+// es-verify compares MongoDB with Elasticsearch and reports the drift.
+//
+// Usage:
+//
+//	go run cmd/es-verify/main.go -config="seed" [repair]
+//
+// With "repair", drifted and missing records are re-indexed from MongoDB
+// and records without a MongoDB document are deleted.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/service/esverify"
+)
+
+func main() {
+	global.Init()
+	repair := len(flag.Args()) > 0 && flag.Arg(0) == "repair"
+
+	log.Println("start verifying")
+	startTime := time.Now()
+
+	drifts, err := esverify.Verify(context.Background(), repair)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, d := range drifts {
+		log.Println(d)
+	}
+
+	log.Printf("count %v\n", len(drifts))
+	if repair {
+		log.Printf("repaired %v\n", len(drifts))
+	}
+	log.Printf("took  %v\n\n", time.Now().Sub(startTime))
+}
@@ -1,19 +1,31 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 
 	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/http"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/es"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/pg"
+	"github.com/ic3network/mccs-alpha/internal/app/service/accountcheck"
 	"github.com/ic3network/mccs-alpha/internal/app/service/balancecheck"
 	"github.com/ic3network/mccs-alpha/internal/app/service/dailyemail"
+	"github.com/ic3network/mccs-alpha/internal/app/service/esindexer"
+	"github.com/ic3network/mccs-alpha/internal/app/service/esverify"
+	"github.com/ic3network/mccs-alpha/internal/app/service/logretention"
+	"github.com/ic3network/mccs-alpha/internal/app/service/scheduler"
 	"github.com/ic3network/mccs-alpha/internal/migration"
 	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 	"github.com/ic3network/mccs-alpha/internal/pkg/version"
-	"github.com/robfig/cron"
-	"github.com/spf13/viper"
+	"go.uber.org/zap"
 )
 
 func init() {
@@ -35,29 +47,160 @@ func main() {
 		return
 	}
 
-	go ServeBackGround()
+	// The command line tools share the config without these keys.
+	if err := global.Config().ValidateServer(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	shutdownTracing, err := tracing.Init()
+	if err != nil {
+		l.Logger.Fatal("tracing.Init failed", zap.Error(err))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ServeBackGround(ctx)
+	indexerDone := make(chan struct{})
+	go func() {
+		esindexer.Start(ctx)
+		close(indexerDone)
+	}()
 	go RunMigration()
 
-	http.AppServer.Run(viper.GetString("port"))
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- http.AppServer.Run(strconv.Itoa(global.Config().Port))
+	}()
+
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			l.Logger.Fatal("ListenAndServe failed", zap.Error(err))
+		}
+	case <-ctx.Done():
+	}
+	// A second signal kills the process without waiting.
+	stop()
+	shutdown(indexerDone, shutdownTracing)
+}
+
+// shutdown drains the HTTP connections, waits for the background jobs and
+// closes the stores, all within "shutdown_timeout". The spans are flushed
+// last.
+func shutdown(
+	indexerDone <-chan struct{},
+	shutdownTracing func(context.Context) error,
+) {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		global.Config().ShutdownTimeout,
+	)
+	defer cancel()
+
+	l.Logger.Info("shutting down")
+
+	err := http.AppServer.Shutdown(ctx)
+	if err != nil {
+		l.Logger.Error("shutting down the http server failed", zap.Error(err))
+	}
+
+	err = scheduler.Stop(ctx)
+	if err != nil {
+		l.Logger.Error("waiting for the background jobs failed", zap.Error(err))
+	}
+	select {
+	case <-indexerDone:
+	case <-ctx.Done():
+		l.Logger.Error("waiting for the es indexer failed", zap.Error(ctx.Err()))
+	}
+
+	err = mongo.Close(ctx)
+	if err != nil {
+		l.Logger.Error("closing mongo failed", zap.Error(err))
+	}
+	err = pg.Close()
+	if err != nil {
+		l.Logger.Error("closing postgres failed", zap.Error(err))
+	}
+	es.Close()
+
+	err = shutdownTracing(ctx)
+	if err != nil {
+		l.Logger.Error("flushing the spans failed", zap.Error(err))
+	}
+
+	l.Logger.Info("shut down")
 }
 
-// ServeBackGround performs the background activities.
-func ServeBackGround() {
-	c := cron.New()
-	viper.SetDefault("daily_email_schedule", "0 0 7 * * *")
-	c.AddFunc(viper.GetString("daily_email_schedule"), func() {
-		l.Logger.Info("[ServeBackGround] Running daily email schedule. \n")
-		dailyemail.Run()
+// ServeBackGround registers and schedules the background jobs. The context
+// is done once the shutdown starts.
+func ServeBackGround(ctx context.Context) {
+	schedules := jobSchedules(global.Config())
+	scheduler.Register(&scheduler.Job{
+		Name:        "dailyemail",
+		Description: "Emails the matching offers and wants to the users.",
+		Schedule:    schedules["dailyemail"],
+		Run:         dailyemail.Run,
+	})
+	scheduler.Register(&scheduler.Job{
+		Name:        "balancecheck",
+		Description: "Checks that the postings of the last 5 hours sum to zero.",
+		Schedule:    schedules["balancecheck"],
+		Run:         balancecheck.Run,
 	})
-	viper.SetDefault("balance_check_schedule", "0 0 * * * *")
-	c.AddFunc(viper.GetString("balance_check_schedule"), func() {
-		l.Logger.Info("[ServeBackGround] Running balance check schedule. \n")
-		balancecheck.Run()
+	scheduler.Register(&scheduler.Job{
+		Name:        "accountcheck",
+		Description: "Flags the dormant and at-risk accounts.",
+		Schedule:    schedules["accountcheck"],
+		Run:         accountcheck.Run,
 	})
-	c.Start()
+	scheduler.Register(&scheduler.Job{
+		Name:        "accountchecksummary",
+		Description: "Emails the open account flags to the admins.",
+		Schedule:    schedules["accountchecksummary"],
+		Run:         accountcheck.SendSummary,
+	})
+	scheduler.Register(&scheduler.Job{
+		Name:        "logretention",
+		Description: "Archives and deletes the old user actions.",
+		Schedule:    schedules["logretention"],
+		Run:         logretention.Run,
+	})
+	// The consistency check is optional, it only runs on its own when it is
+	// scheduled.
+	if !global.Config().IsMemorySearch() {
+		scheduler.Register(&scheduler.Job{
+			Name:        "esverify",
+			Description: "Compares MongoDB with Elasticsearch.",
+			Schedule:    schedules["esverify"],
+			Run:         esverify.Run,
+		})
+	}
+	scheduler.Start(ctx)
+
+	global.OnConfigReload(func(c *global.Configuration) {
+		scheduler.Reschedule(jobSchedules(c))
+	})
+}
+
+// jobSchedules returns the schedules of the jobs by name.
+func jobSchedules(c *global.Configuration) map[string]string {
+	return map[string]string{
+		"dailyemail":          c.DailyEmailSchedule,
+		"balancecheck":        c.BalanceCheckSchedule,
+		"accountcheck":        c.AccountCheckSchedule,
+		"accountchecksummary": c.AccountCheckSummarySchedule,
+		"logretention":        c.UserActionRetentionSchedule,
+		"esverify":            c.ESVerifySchedule,
+	}
 }
 
 func RunMigration() {
 	// Runs at 2019-08-20
 	migration.SetUserActionCategory()
+	migration.CreateUserActionIndexes()
+	migration.CreateJobRunIndexes()
+	migration.CreateOutboxIndexes()
 }
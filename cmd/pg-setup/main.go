@@ -40,7 +40,7 @@ func setUpAccount() {
 			log.Fatal(err)
 		}
 		// Create account from business.
-		err = service.Account.Create(b.ID.Hex())
+		err = service.Account.Create(ctx, b.ID.Hex())
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -0,0 +1,71 @@
+// seed-generate creates a synthetic network for load tests and demos.
+//
+// Usage:
+//
+//	go run cmd/seed-generate/main.go -config="seed" -businesses=1000 -seed=1
+//
+// The businesses, their users, tags and admin tags go to MongoDB and
+// Elasticsearch, the accounts and a year of transfers to PostgreSQL. The
+// same seed gives the same network, with the history ending on the day it
+// runs. The users log in with the password "password". A run which failed
+// can be started again on the same day with the same options, it updates or
+// skips what was saved.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/seed"
+)
+
+var (
+	businesses = flag.Int("businesses", 100, "number of businesses")
+	transfers  = flag.Int(
+		"transfers",
+		0,
+		"number of transfers tried, default is 20 per business",
+	)
+	seedValue = flag.Int64("seed", 1, "seed of the random data")
+	days      = flag.Int("days", 365, "days of transfer history")
+	maxNegBal = flag.Float64(
+		"max-neg-bal",
+		500,
+		"max negative balance of the trading members",
+	)
+	maxPosBal = flag.Float64(
+		"max-pos-bal",
+		2000,
+		"max positive balance of the trading members",
+	)
+)
+
+func main() {
+	global.Init()
+
+	if *businesses <= 0 || *days <= 0 {
+		log.Fatal("businesses and days should be positive")
+	}
+	// The accounts start at zero, nothing could be sent without credit.
+	if *maxNegBal <= 0 || *maxPosBal <= 0 {
+		log.Fatal("max-neg-bal and max-pos-bal should be positive")
+	}
+	o := seed.Options{
+		Businesses: *businesses,
+		Transfers:  *transfers,
+		Seed:       *seedValue,
+		Days:       *days,
+		End:        time.Now().UTC().Truncate(24 * time.Hour),
+		MaxNegBal:  *maxNegBal,
+		MaxPosBal:  *maxPosBal,
+	}
+	if o.Transfers <= 0 {
+		o.Transfers = 20 * o.Businesses
+	}
+
+	d := seed.Generate(o)
+	seed.Save(context.Background(), d, o)
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+
 	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/seed"
 )
@@ -8,5 +10,5 @@ import (
 func main() {
 	global.Init()
 	seed.LoadData()
-	seed.Run()
+	seed.Run(context.Background())
 }
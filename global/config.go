@@ -0,0 +1,224 @@
+package global
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Configuration is the typed configuration, read from the config file and
+// the environment variables. See configs/seed.yaml for the keys.
+type Configuration struct {
+	Env  string `mapstructure:"env"`
+	URL  string `mapstructure:"url"`
+	Port int    `mapstructure:"port"`
+
+	// Seconds before a password reset link expires.
+	ResetPasswordTimeout int `mapstructure:"reset_password_timeout"`
+	LoginAttemptsLimit   int `mapstructure:"login_attempts_limit"`
+	// Seconds an account is locked after too many login attempts.
+	LoginAttemptsTimeout int `mapstructure:"login_attempts_timeout"`
+
+	PageSize              int `mapstructure:"page_size"`
+	TagsLimit             int `mapstructure:"tags_limit"`
+	TagSuggestionSize     int `mapstructure:"tag_suggestion_size"`
+	SimilarBusinessesSize int `mapstructure:"similar_businesses_size"`
+	FacetSize             int `mapstructure:"facet_size"`
+	AnalyticsTopTraders   int `mapstructure:"analytics_top_traders"`
+	ApplicationSLADays    int `mapstructure:"application_sla_days"`
+
+	EmailFrom                  string `mapstructure:"email_from"`
+	ReceiveTradeContactEmails  bool   `mapstructure:"receive_trade_contact_emails"`
+	ReceiveSignupNotifications bool   `mapstructure:"receive_signup_notifications"`
+
+	DailyEmailSchedule          string `mapstructure:"daily_email_schedule"`
+	BalanceCheckSchedule        string `mapstructure:"balance_check_schedule"`
+	AccountCheckSchedule        string `mapstructure:"account_check_schedule"`
+	AccountCheckSummarySchedule string `mapstructure:"account_check_summary_schedule"`
+	UserActionRetentionSchedule string `mapstructure:"user_action_retention_schedule"`
+	ESVerifySchedule            string `mapstructure:"es_verify_schedule"`
+	ESVerifyRepair              bool   `mapstructure:"es_verify_repair"`
+
+	AccountCheck struct {
+		DormantDays    int     `mapstructure:"dormant_days"`
+		LimitThreshold float64 `mapstructure:"limit_threshold"`
+		TrendWeeks     int     `mapstructure:"trend_weeks"`
+		SnoozeDays     int     `mapstructure:"snooze_days"`
+	} `mapstructure:"account_check"`
+	UserActionRetention struct {
+		Days       int    `mapstructure:"days"`
+		ArchiveTo  string `mapstructure:"archive_to"`
+		ArchiveDir string `mapstructure:"archive_dir"`
+	} `mapstructure:"user_action_retention"`
+
+	ConcurrencyNum    int           `mapstructure:"concurrency_num"`
+	ESIndexerInterval time.Duration `mapstructure:"es_indexer_interval"`
+	JobLockTTL        time.Duration `mapstructure:"job_lock_ttl"`
+	ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout"`
+	ReadinessTimeout  time.Duration `mapstructure:"readiness_timeout"`
+
+	Metrics struct {
+		Token string `mapstructure:"token"`
+	} `mapstructure:"metrics"`
+	Tracing struct {
+		Exporter    string  `mapstructure:"exporter"`
+		SampleRatio float64 `mapstructure:"sample_ratio"`
+		OTLP        struct {
+			Endpoint string `mapstructure:"endpoint"`
+			Insecure bool   `mapstructure:"insecure"`
+		} `mapstructure:"otlp"`
+	} `mapstructure:"tracing"`
+
+	// The balance limits of the new accounts.
+	Transaction struct {
+		MaxNegBal float64 `mapstructure:"maxNegBal"`
+		MaxPosBal float64 `mapstructure:"maxPosBal"`
+	} `mapstructure:"transaction"`
+
+	Psql struct {
+		Host     string `mapstructure:"host"`
+		Port     int    `mapstructure:"port"`
+		User     string `mapstructure:"user"`
+		Password string `mapstructure:"password"`
+		DB       string `mapstructure:"db"`
+	} `mapstructure:"psql"`
+	Mongo struct {
+		URL      string `mapstructure:"url"`
+		Database string `mapstructure:"database"`
+	} `mapstructure:"mongo"`
+	ES struct {
+		URL      string `mapstructure:"url"`
+		BulkSize int    `mapstructure:"bulk_size"`
+	} `mapstructure:"es"`
+	Search struct {
+		Backend string `mapstructure:"backend"`
+	} `mapstructure:"search"`
+
+	JWT struct {
+		PrivateKey string `mapstructure:"private_key"`
+		PublicKey  string `mapstructure:"public_key"`
+	} `mapstructure:"jwt"`
+	Sendgrid struct {
+		Key         string `mapstructure:"key"`
+		SenderEmail string `mapstructure:"sender_email"`
+	} `mapstructure:"sendgrid"`
+	Recaptcha struct {
+		SiteKey   string `mapstructure:"site_key"`
+		SecretKey string `mapstructure:"secret_key"`
+	} `mapstructure:"recaptcha"`
+}
+
+// IsProduction reports whether the app runs in production.
+func (c *Configuration) IsProduction() bool {
+	return c.Env == "production"
+}
+
+// IsTest reports whether the app runs the tests, without the stores.
+func (c *Configuration) IsTest() bool {
+	return c.Env == "test"
+}
+
+// IsMemorySearch reports whether the in-process search backend is used
+// instead of Elasticsearch.
+func (c *Configuration) IsMemorySearch() bool {
+	return c.Search.Backend == "memory"
+}
+
+var config atomic.Pointer[Configuration]
+
+func init() {
+	setDefaults()
+	c := &Configuration{}
+	_ = viper.Unmarshal(c)
+	config.Store(c)
+}
+
+// Config returns the current configuration. The reloadable keys may change
+// between two calls, so read it again instead of keeping it.
+func Config() *Configuration {
+	return config.Load()
+}
+
+// SetConfig replaces the configuration, e.g. to change a key in the tests.
+func SetConfig(c *Configuration) {
+	config.Store(c)
+}
+
+// setDefaults sets the default of every key, so the keys missing from the
+// config file can still be set with the environment variables.
+func setDefaults() {
+	defaults := map[string]interface{}{
+		"env":                               "development",
+		"url":                               "http://localhost:8080",
+		"port":                              8080,
+		"reset_password_timeout":            60,
+		"login_attempts_limit":              3,
+		"login_attempts_timeout":            900,
+		"page_size":                         10,
+		"tags_limit":                        10,
+		"tag_suggestion_size":               10,
+		"similar_businesses_size":           5,
+		"facet_size":                        20,
+		"analytics_top_traders":             10,
+		"application_sla_days":              5,
+		"email_from":                        "MCCS",
+		"receive_trade_contact_emails":      false,
+		"receive_signup_notifications":      false,
+		"daily_email_schedule":              "0 0 7 * * *",
+		"balance_check_schedule":            "0 0 * * * *",
+		"account_check_schedule":            "0 0 2 * * *",
+		"account_check_summary_schedule":    "0 0 8 * * 1",
+		"user_action_retention_schedule":    "0 30 3 * * *",
+		"es_verify_schedule":                "",
+		"es_verify_repair":                  false,
+		"account_check.dormant_days":        90,
+		"account_check.limit_threshold":     0.9,
+		"account_check.trend_weeks":         4,
+		"account_check.snooze_days":         30,
+		"user_action_retention.days":        0,
+		"user_action_retention.archive_to":  "dir",
+		"user_action_retention.archive_dir": "archive/user-actions",
+		"concurrency_num":                   1,
+		"es_indexer_interval":               time.Second,
+		"job_lock_ttl":                      10 * time.Minute,
+		"shutdown_timeout":                  30 * time.Second,
+		"readiness_timeout":                 2 * time.Second,
+		"metrics.token":                     "",
+		"tracing.exporter":                  "",
+		"tracing.sample_ratio":              1.0,
+		"tracing.otlp.endpoint":             "localhost:4318",
+		"tracing.otlp.insecure":             false,
+		"transaction.maxNegBal":             0,
+		"transaction.maxPosBal":             500,
+		"psql.host":                         "localhost",
+		"psql.port":                         5432,
+		"psql.user":                         "postgres",
+		"psql.password":                     "",
+		"psql.db":                           "mccs",
+		"mongo.url":                         "mongodb://localhost:27017/?directConnection=true",
+		"mongo.database":                    "mccs",
+		"es.url":                            "http://localhost:9200",
+		"es.bulk_size":                      500,
+		"search.backend":                    "elasticsearch",
+		"jwt.private_key":                   "",
+		"jwt.public_key":                    "",
+		"sendgrid.key":                      "",
+		"sendgrid.sender_email":             "",
+		"recaptcha.site_key":                "",
+		"recaptcha.secret_key":              "",
+	}
+	for key, value := range defaults {
+		viper.SetDefault(key, value)
+	}
+}
+
+// load reads the configuration from viper.
+func load() (*Configuration, error) {
+	c := &Configuration{}
+	err := viper.Unmarshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,87 @@
+package global
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validConfig(t *testing.T) *Configuration {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	c := *Config()
+	c.Env = "production"
+	c.JWT.PrivateKey = string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}))
+	c.JWT.PublicKey = string(pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: public,
+	}))
+	c.Sendgrid.Key = "key"
+	c.Sendgrid.SenderEmail = "admin@example.com"
+	c.Recaptcha.SiteKey = "site"
+	c.Recaptcha.SecretKey = "secret"
+	return &c
+}
+
+func TestValidate(t *testing.T) {
+	c := validConfig(t)
+	require.NoError(t, c.ValidateServer())
+
+	c.PageSize = 0
+	c.DailyEmailSchedule = "every day"
+	c.JWT.PrivateKey = ""
+	c.Search.Backend = "solr"
+	c.ShutdownTimeout = 0
+
+	err := c.ValidateServer()
+	var configErr *ConfigError
+	require.True(t, errors.As(err, &configErr))
+	assert.Len(t, configErr.Problems, 5)
+	assert.Contains(t, err.Error(), "page_size: must be greater than 0, got 0")
+	assert.Contains(t, err.Error(), `daily_email_schedule: invalid cron spec "every day"`)
+	assert.Contains(t, err.Error(), "jwt.private_key: must be a PEM encoded RSA private key")
+	assert.Contains(t, err.Error(), `search.backend: must be one of ["elasticsearch" "memory"], got "solr"`)
+	assert.Contains(t, err.Error(), "shutdown_timeout: must be a positive duration")
+}
+
+func TestValidateSkipsTheServerKeys(t *testing.T) {
+	c := validConfig(t)
+	c.JWT.PrivateKey = "xxx"
+	c.Sendgrid.Key = ""
+	c.Recaptcha.SecretKey = ""
+
+	require.NoError(t, c.Validate())
+	err := c.ValidateServer()
+	var configErr *ConfigError
+	require.True(t, errors.As(err, &configErr))
+	assert.Len(t, configErr.Problems, 3)
+}
+
+func TestWithReloadable(t *testing.T) {
+	current := validConfig(t)
+	next := *current
+	next.PageSize = 25
+	next.BalanceCheckSchedule = "0 30 * * * *"
+	next.Port = 9090
+	next.Mongo.URL = "mongodb://mongo:27017"
+
+	reloaded := withReloadable(current, &next)
+
+	assert.Equal(t, 25, reloaded.PageSize)
+	assert.Equal(t, "0 30 * * * *", reloaded.BalanceCheckSchedule)
+	assert.Equal(t, current.Port, reloaded.Port, "the port needs a restart")
+	assert.Equal(t, current.Mongo.URL, reloaded.Mongo.URL, "the stores need a restart")
+	assert.Equal(t, 10, current.PageSize, "the current config is not changed")
+}
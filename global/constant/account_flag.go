@@ -0,0 +1,23 @@
+package constant
+
+// AccountFlag reasons are why the account check flagged an account.
+var AccountFlag = struct {
+	Dormant    string
+	NearMaxNeg string
+	NearMaxPos string
+	Declining  string
+}{
+	Dormant:    "dormant",
+	NearMaxNeg: "nearMaxNegBalance",
+	NearMaxPos: "nearMaxPosBalance",
+	Declining:  "declining",
+}
+
+// FlagStatus is the resolution status of a flagged account.
+var FlagStatus = struct {
+	Open     string
+	Resolved string
+}{
+	Open:     "open",
+	Resolved: "resolved",
+}
@@ -0,0 +1,22 @@
+package constant
+
+// AuditTarget is the type of the record an audit event is about.
+var AuditTarget = struct {
+	Business  string
+	User      string
+	Tag       string
+	AdminTag  string
+	Transfer  string
+	Directory string
+	Agreement string
+	Job       string
+}{
+	Business:  "business",
+	User:      "user",
+	Tag:       "tag",
+	AdminTag:  "adminTag",
+	Transfer:  "transfer",
+	Directory: "directory",
+	Agreement: "agreement",
+	Job:       "job",
+}
@@ -0,0 +1,21 @@
+package constant
+
+// JobStatus is the outcome of a background job run.
+var JobStatus = struct {
+	Running   string
+	Succeeded string
+	Failed    string
+}{
+	Running:   "running",
+	Succeeded: "succeeded",
+	Failed:    "failed",
+}
+
+// JobTrigger tells what started a background job run.
+var JobTrigger = struct {
+	Scheduled string
+	Manual    string
+}{
+	Scheduled: "scheduled",
+	Manual:    "manual",
+}
@@ -0,0 +1,16 @@
+package constant
+
+// Outbox actions are the changes the indexer applies to Elasticsearch.
+var Outbox = struct {
+	Sync           string
+	RenameTag      string
+	RenameAdminTag string
+	DeleteTag      string
+	DeleteAdminTag string
+}{
+	Sync:           "sync",
+	RenameTag:      "renameTag",
+	RenameAdminTag: "renameAdminTag",
+	DeleteTag:      "deleteTag",
+	DeleteAdminTag: "deleteAdminTag",
+}
@@ -0,0 +1,29 @@
+package constant
+
+// TradingApplication status of the trading membership applications.
+var TradingApplication = struct {
+	Pending       string
+	InfoRequested string
+	Approved      string
+	Rejected      string
+}{
+	Pending:       "pending",
+	InfoRequested: "infoRequested",
+	Approved:      "approved",
+	Rejected:      "rejected",
+}
+
+// ApplicationEvent is what happened to a trading membership application.
+var ApplicationEvent = struct {
+	Submitted     string
+	Resubmitted   string
+	InfoRequested string
+	Approved      string
+	Rejected      string
+}{
+	Submitted:     "submitted",
+	Resubmitted:   "resubmitted",
+	InfoRequested: "infoRequested",
+	Approved:      "approved",
+	Rejected:      "rejected",
+}
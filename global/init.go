@@ -21,31 +21,74 @@ var (
 		"config file name, default is development",
 	)
 	ShowVersionInfo = flag.Bool("v", false, "show version info or not")
+	checkConfig     = flag.Bool(
+		"check-config",
+		false,
+		"validate the config file and exit",
+	)
 )
 
 func Init() {
 	once.Do(func() {
-		isTest := false
-		for _, arg := range os.Args {
-			if strings.Contains(arg, "test") {
-				isTest = true
-				break
-			}
-		}
+		// The stores are set up in the package inits, before the tests
+		// could change the config name.
+		isTest := runningTests()
 
 		if !isTest && !flag.Parsed() {
 			flag.Parse()
 		}
+		// Tests always run with configs/test.yaml.
+		if isTest {
+			*configName = "test"
+		}
 
 		if err := initConfig(); err != nil {
 			panic(fmt.Errorf("initconfig failed: %s", err))
 		}
+		c, err := load()
+		if err != nil {
+			exit(fmt.Errorf("reading %s failed: %w", viper.ConfigFileUsed(), err))
+		}
+		// The stores connect once the config is read, an invalid config
+		// stops the binaries before that. The keys only the web server
+		// uses are checked by the server, see ValidateServer.
+		if err := c.Validate(); err != nil {
+			exit(err)
+		}
+		if *checkConfig {
+			if err := c.ValidateServer(); err != nil {
+				exit(err)
+			}
+			fmt.Printf("%s is valid\n", viper.ConfigFileUsed())
+			os.Exit(0)
+		}
+		config.Store(c)
 		watchConfig()
 
-		l.Init(viper.GetString("env"))
+		l.Init(c.Env)
 	})
 }
 
+// runningTests reports whether this is a test binary built by "go test".
+// The testing flags are only registered once the package inits are done,
+// so the arguments are checked instead.
+func runningTests() bool {
+	if strings.HasSuffix(os.Args[0], ".test") || strings.HasSuffix(os.Args[0], ".test.exe") {
+		return true
+	}
+	for _, arg := range os.Args[1:] {
+		if strings.HasPrefix(arg, "-test.") {
+			return true
+		}
+	}
+	return false
+}
+
+func exit(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
 func initConfig() error {
 	if err := setConfigNameAndType(); err != nil {
 		return fmt.Errorf("setting config name and type failed: %w", err)
@@ -86,5 +129,6 @@ func watchConfig() {
 	viper.WatchConfig()
 	viper.OnConfigChange(func(e fsnotify.Event) {
 		log.Printf("Config file changed: %s", e.Name)
+		reload()
 	})
 }
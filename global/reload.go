@@ -0,0 +1,78 @@
+package global
+
+import (
+	"sync"
+
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"go.uber.org/zap"
+)
+
+var (
+	reloadMu  sync.Mutex
+	listeners []func(*Configuration)
+)
+
+// OnConfigReload calls f with the new configuration every time the config
+// file changes.
+func OnConfigReload(f func(*Configuration)) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	listeners = append(listeners, f)
+}
+
+// reload applies the reloadable keys of the changed config file. The other
+// keys are read once at startup, a change to them needs a restart.
+func reload() {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	next, err := load()
+	if err == nil {
+		err = next.Validate()
+	}
+	if err != nil {
+		l.Logger.Error("config reload failed, the current config is kept", zap.Error(err))
+		return
+	}
+
+	current := Config()
+	reloaded := withReloadable(current, next)
+	if *reloaded != *next {
+		l.Logger.Warn("config reloaded, the changes to the keys other than the page sizes, limits and schedules need a restart")
+	} else {
+		l.Logger.Info("config reloaded")
+	}
+	config.Store(reloaded)
+	for _, f := range listeners {
+		f(reloaded)
+	}
+}
+
+// withReloadable returns a copy of the current configuration with the keys
+// that are safe to change at runtime taken from next.
+func withReloadable(current *Configuration, next *Configuration) *Configuration {
+	c := *current
+
+	c.PageSize = next.PageSize
+	c.TagsLimit = next.TagsLimit
+	c.TagSuggestionSize = next.TagSuggestionSize
+	c.SimilarBusinessesSize = next.SimilarBusinessesSize
+	c.FacetSize = next.FacetSize
+	c.AnalyticsTopTraders = next.AnalyticsTopTraders
+	c.ApplicationSLADays = next.ApplicationSLADays
+
+	c.LoginAttemptsLimit = next.LoginAttemptsLimit
+	c.LoginAttemptsTimeout = next.LoginAttemptsTimeout
+	c.ResetPasswordTimeout = next.ResetPasswordTimeout
+	c.Transaction = next.Transaction
+	c.AccountCheck = next.AccountCheck
+
+	c.DailyEmailSchedule = next.DailyEmailSchedule
+	c.BalanceCheckSchedule = next.BalanceCheckSchedule
+	c.AccountCheckSchedule = next.AccountCheckSchedule
+	c.AccountCheckSummarySchedule = next.AccountCheckSummarySchedule
+	c.UserActionRetentionSchedule = next.UserActionRetentionSchedule
+	c.ESVerifySchedule = next.ESVerifySchedule
+
+	return &c
+}
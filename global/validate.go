@@ -0,0 +1,183 @@
+package global
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/robfig/cron"
+)
+
+// ConfigError lists the invalid keys of the configuration.
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return "invalid config:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(key string, format string, args ...interface{}) {
+	v.problems = append(v.problems, key+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ConfigError{Problems: v.problems}
+}
+
+func (v *validator) required(key string, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.addf(key, "is required")
+	}
+}
+
+func (v *validator) positive(key string, value int) {
+	if value <= 0 {
+		v.addf(key, "must be greater than 0, got %d", value)
+	}
+}
+
+func (v *validator) duration(key string, value time.Duration) {
+	if value <= 0 {
+		v.addf(key, "must be a positive duration, e.g. 30s, got %s", value)
+	}
+}
+
+func (v *validator) url(key string, value string) {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		v.addf(key, "must be an absolute URL, got %q", value)
+	}
+}
+
+// schedule checks the cron spec, an empty spec only runs the job manually.
+func (v *validator) schedule(key string, spec string) {
+	if spec == "" {
+		return
+	}
+	_, err := cron.Parse(spec)
+	if err != nil {
+		v.addf(key, "invalid cron spec %q: %s", spec, err)
+	}
+}
+
+func (v *validator) oneOf(key string, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.addf(key, "must be one of %q, got %q", allowed, value)
+}
+
+// Validate checks the keys every binary uses and reports all the invalid
+// keys at once.
+func (c *Configuration) Validate() error {
+	v := &validator{}
+	c.validate(v)
+	return v.err()
+}
+
+// ValidateServer also checks the keys only the web server uses, the command
+// line tools run without them.
+func (c *Configuration) ValidateServer() error {
+	v := &validator{}
+	c.validate(v)
+
+	if _, err := jwtlib.ParseRSAPrivateKeyFromPEM([]byte(c.JWT.PrivateKey)); err != nil {
+		v.addf("jwt.private_key", "must be a PEM encoded RSA private key: %s", err)
+	}
+	if _, err := jwtlib.ParseRSAPublicKeyFromPEM([]byte(c.JWT.PublicKey)); err != nil {
+		v.addf("jwt.public_key", "must be a PEM encoded RSA public key: %s", err)
+	}
+	v.required("sendgrid.key", c.Sendgrid.Key)
+	v.required("sendgrid.sender_email", c.Sendgrid.SenderEmail)
+	v.required("recaptcha.site_key", c.Recaptcha.SiteKey)
+	v.required("recaptcha.secret_key", c.Recaptcha.SecretKey)
+	return v.err()
+}
+
+func (c *Configuration) validate(v *validator) {
+	v.required("env", c.Env)
+	v.url("url", c.URL)
+	if c.Port <= 0 || c.Port > 65535 {
+		v.addf("port", "must be between 1 and 65535, got %d", c.Port)
+	}
+
+	v.positive("reset_password_timeout", c.ResetPasswordTimeout)
+	v.positive("login_attempts_limit", c.LoginAttemptsLimit)
+	v.positive("login_attempts_timeout", c.LoginAttemptsTimeout)
+	v.positive("page_size", c.PageSize)
+	v.positive("tags_limit", c.TagsLimit)
+	v.positive("tag_suggestion_size", c.TagSuggestionSize)
+	v.positive("similar_businesses_size", c.SimilarBusinessesSize)
+	v.positive("facet_size", c.FacetSize)
+	v.positive("analytics_top_traders", c.AnalyticsTopTraders)
+	v.positive("application_sla_days", c.ApplicationSLADays)
+	v.required("email_from", c.EmailFrom)
+
+	v.schedule("daily_email_schedule", c.DailyEmailSchedule)
+	v.schedule("balance_check_schedule", c.BalanceCheckSchedule)
+	v.schedule("account_check_schedule", c.AccountCheckSchedule)
+	v.schedule("account_check_summary_schedule", c.AccountCheckSummarySchedule)
+	v.schedule("user_action_retention_schedule", c.UserActionRetentionSchedule)
+	v.schedule("es_verify_schedule", c.ESVerifySchedule)
+
+	v.positive("account_check.dormant_days", c.AccountCheck.DormantDays)
+	if c.AccountCheck.LimitThreshold <= 0 || c.AccountCheck.LimitThreshold > 1 {
+		v.addf("account_check.limit_threshold", "must be between 0 and 1, got %v", c.AccountCheck.LimitThreshold)
+	}
+	v.positive("account_check.trend_weeks", c.AccountCheck.TrendWeeks)
+	if c.AccountCheck.SnoozeDays < 0 {
+		v.addf("account_check.snooze_days", "must not be negative, got %d", c.AccountCheck.SnoozeDays)
+	}
+	if c.UserActionRetention.Days < 0 {
+		v.addf("user_action_retention.days", "must not be negative, got %d", c.UserActionRetention.Days)
+	}
+	v.oneOf("user_action_retention.archive_to", c.UserActionRetention.ArchiveTo, "dir", "gridfs")
+	if c.UserActionRetention.Days > 0 && c.UserActionRetention.ArchiveTo == "dir" {
+		v.required("user_action_retention.archive_dir", c.UserActionRetention.ArchiveDir)
+	}
+
+	v.positive("concurrency_num", c.ConcurrencyNum)
+	v.duration("es_indexer_interval", c.ESIndexerInterval)
+	v.duration("job_lock_ttl", c.JobLockTTL)
+	v.duration("shutdown_timeout", c.ShutdownTimeout)
+	v.duration("readiness_timeout", c.ReadinessTimeout)
+
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, "", "stdout", "otlp")
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.addf("tracing.sample_ratio", "must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+	}
+	if c.Tracing.Exporter == "otlp" {
+		v.required("tracing.otlp.endpoint", c.Tracing.OTLP.Endpoint)
+	}
+
+	if c.Transaction.MaxNegBal < 0 {
+		v.addf("transaction.maxNegBal", "must not be negative, got %v", c.Transaction.MaxNegBal)
+	}
+	if c.Transaction.MaxPosBal < 0 {
+		v.addf("transaction.maxPosBal", "must not be negative, got %v", c.Transaction.MaxPosBal)
+	}
+
+	v.required("psql.host", c.Psql.Host)
+	v.positive("psql.port", c.Psql.Port)
+	v.required("psql.user", c.Psql.User)
+	v.required("psql.db", c.Psql.DB)
+	v.required("mongo.url", c.Mongo.URL)
+	v.required("mongo.database", c.Mongo.Database)
+	v.oneOf("search.backend", c.Search.Backend, "elasticsearch", "memory")
+	if !c.IsMemorySearch() {
+		v.url("es.url", c.ES.URL)
+	}
+	v.positive("es.bulk_size", c.ES.BulkSize)
+}
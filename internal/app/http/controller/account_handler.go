@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"sync"
@@ -86,9 +87,9 @@ type sreachResponse struct {
 func (a *accountHandler) searchAccountPage() func(http.ResponseWriter, *http.Request) {
 	t := template.NewView("admin/accounts")
 	return func(w http.ResponseWriter, r *http.Request) {
-		adminTags, err := service.AdminTag.GetAll()
+		adminTags, err := service.AdminTag.GetAll(r.Context())
 		if err != nil {
-			l.Logger.Error("SearchAccountPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("SearchAccountPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -104,7 +105,7 @@ func (a *accountHandler) searchAccount() func(http.ResponseWriter, *http.Request
 
 		page, err := strconv.Atoi(q.Get("page"))
 		if err != nil {
-			l.Logger.Error("SearchAccount failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("SearchAccount failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -135,9 +136,9 @@ func (a *accountHandler) searchAccount() func(http.ResponseWriter, *http.Request
 			return
 		}
 
-		adminTags, err := service.AdminTag.GetAll()
+		adminTags, err := service.AdminTag.GetAll(r.Context())
 		if err != nil {
-			l.Logger.Error("SearchAccount failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("SearchAccount failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -170,9 +171,9 @@ func (a *accountHandler) searchAccount() func(http.ResponseWriter, *http.Request
 				LocationCountry:  f.LocationCountry,
 				AdminTag:         f.Category,
 			}
-			findResult, err = service.Business.FindBusiness(&c, int64(f.Page))
+			findResult, err = service.Business.FindBusiness(r.Context(), &c, int64(f.Page))
 			if err != nil {
-				l.Logger.Error("SearchAccount failed", zap.Error(err))
+				l.WithContext(r.Context()).Error("SearchAccount failed", zap.Error(err))
 				t.Error(w, r, res, err)
 				return
 			}
@@ -183,15 +184,15 @@ func (a *accountHandler) searchAccount() func(http.ResponseWriter, *http.Request
 		accounts := make([]account, 0)
 		// Find the user and account balance using business id.
 		for _, business := range findResult.Businesses {
-			user, err := service.User.FindByBusinessID(business.ID)
+			user, err := service.User.FindByBusinessID(r.Context(), business.ID)
 			if err != nil {
-				l.Logger.Error("SearchAccount failed", zap.Error(err))
+				l.WithContext(r.Context()).Error("SearchAccount failed", zap.Error(err))
 				t.Error(w, r, res, err)
 				return
 			}
-			acc, err := service.Account.FindByBusinessID(business.ID.Hex())
+			acc, err := service.Account.FindByBusinessID(r.Context(), business.ID.Hex())
 			if err != nil {
-				l.Logger.Error("SearchAccount failed", zap.Error(err))
+				l.WithContext(r.Context()).Error("SearchAccount failed", zap.Error(err))
 				t.Error(w, r, res, err)
 				return
 			}
@@ -213,9 +214,9 @@ func (a *accountHandler) searchAccount() func(http.ResponseWriter, *http.Request
 			LastName: f.LastName,
 			Email:    f.Email,
 		}
-		findUserResult, err := service.User.FindUsers(&u, int64(f.Page))
+		findUserResult, err := service.User.FindUsers(r.Context(), &u, int64(f.Page))
 		if err != nil {
-			l.Logger.Error("SearchAccount failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("SearchAccount failed", zap.Error(err))
 			t.Error(w, r, res, err)
 			return
 		}
@@ -224,15 +225,15 @@ func (a *accountHandler) searchAccount() func(http.ResponseWriter, *http.Request
 
 		// Find the business and account balance.
 		for _, user := range findUserResult.Users {
-			business, err := service.Business.FindByID(user.CompanyID)
+			business, err := service.Business.FindByID(r.Context(), user.CompanyID)
 			if err != nil {
-				l.Logger.Error("SearchAccount failed", zap.Error(err))
+				l.WithContext(r.Context()).Error("SearchAccount failed", zap.Error(err))
 				t.Error(w, r, res, err)
 				return
 			}
-			acc, err := service.Account.FindByBusinessID(business.ID.Hex())
+			acc, err := service.Account.FindByBusinessID(r.Context(), business.ID.Hex())
 			if err != nil {
-				l.Logger.Error("SearchAccount failed", zap.Error(err))
+				l.WithContext(r.Context()).Error("SearchAccount failed", zap.Error(err))
 				t.Error(w, r, res, err)
 				return
 			}
@@ -255,15 +256,15 @@ func (a *accountHandler) accountPage() func(http.ResponseWriter, *http.Request)
 		Business *types.Business
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
-		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		user, err := UserHandler.FindByID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Error("AccountPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("AccountPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
-		business, err := service.Business.FindByID(user.CompanyID)
+		business, err := service.Business.FindByID(r.Context(), user.CompanyID)
 		if err != nil {
-			l.Logger.Error("AccountPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("AccountPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -278,15 +279,15 @@ func (a *accountHandler) updateAccount() func(http.ResponseWriter, *http.Request
 		formData := helper.GetUpdateData(r)
 
 		// Find the user and he's business.
-		user, err := service.User.FindByEmail(formData.User.Email)
+		user, err := service.User.FindByEmail(r.Context(), formData.User.Email)
 		if err != nil {
-			l.Logger.Error("appServer UpdateAccount failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("appServer UpdateAccount failed", zap.Error(err))
 			t.Error(w, r, formData, err)
 			return
 		}
-		oldBusiness, err := service.Business.FindByID(user.CompanyID)
+		oldBusiness, err := service.Business.FindByID(r.Context(), user.CompanyID)
 		if err != nil {
-			l.Logger.Error("appServer UpdateAccount failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("appServer UpdateAccount failed", zap.Error(err))
 			return
 		}
 
@@ -294,11 +295,12 @@ func (a *accountHandler) updateAccount() func(http.ResponseWriter, *http.Request
 		errorMessages := []string{}
 		if formData.CurrentPassword != "" {
 			_, err := service.User.Login(
+				r.Context(),
 				formData.User.Email,
 				formData.CurrentPassword,
 			)
 			if err != nil {
-				l.Logger.Error("appServer UpdateAccount failed", zap.Error(err))
+				l.WithContext(r.Context()).Error("appServer UpdateAccount failed", zap.Error(err))
 				t.Error(w, r, formData, err)
 				return
 			}
@@ -310,7 +312,7 @@ func (a *accountHandler) updateAccount() func(http.ResponseWriter, *http.Request
 			errorMessages = append(errorMessages, data.Validate()...)
 		}
 		if len(errorMessages) > 0 {
-			l.Logger.Info(
+			l.WithContext(r.Context()).Info(
 				"appServer UpdateAccount failed",
 				zap.Strings("input invalid", errorMessages),
 			)
@@ -319,9 +321,9 @@ func (a *accountHandler) updateAccount() func(http.ResponseWriter, *http.Request
 		}
 
 		formData.User.ID = user.ID
-		err = service.User.UpdateUserInfo(formData.User)
+		err = service.User.UpdateUserInfo(r.Context(), formData.User)
 		if err != nil {
-			l.Logger.Error("appServer UpdateAccount failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("appServer UpdateAccount failed", zap.Error(err))
 			t.Error(w, r, formData, err)
 			return
 		}
@@ -340,30 +342,33 @@ func (a *accountHandler) updateAccount() func(http.ResponseWriter, *http.Request
 		formData.Business.WantsRemoved = wantsRemoved
 
 		err = service.Business.UpdateBusiness(
+			r.Context(),
 			user.CompanyID,
 			formData.Business,
 			false,
 		)
 		if err != nil {
-			l.Logger.Error("appServer UpdateAccount failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("appServer UpdateAccount failed", zap.Error(err))
 			t.Error(w, r, formData, err)
 			return
 		}
 
 		if formData.CurrentPassword != "" && formData.ConfirmPassword != "" {
 			err = service.User.ResetPassword(
+				r.Context(),
 				user.Email,
 				formData.ConfirmPassword,
 			)
 			if err != nil {
-				l.Logger.Error("appServer UpdateAccount failed", zap.Error(err))
+				l.WithContext(r.Context()).Error("appServer UpdateAccount failed", zap.Error(err))
 				t.Error(w, r, formData, err)
 				return
 			}
 		}
 
-		go func() {
+		goBackground(r.Context(), func(ctx context.Context) {
 			err := service.UserAction.Log(
+				ctx,
 				log.User.ModifyAccount(
 					user,
 					formData.User,
@@ -372,38 +377,38 @@ func (a *accountHandler) updateAccount() func(http.ResponseWriter, *http.Request
 				),
 			)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"BuildModifyAccountAction failed",
 					zap.Error(err),
 				)
 			}
-		}()
+		})
 
 		// User Update tags logic:
 		// 	1. Update the tags collection only when the business is in accepted status.
-		go func() {
+		goBackground(r.Context(), func(ctx context.Context) {
 			if util.IsAcceptedStatus(oldBusiness.Status) {
-				err := TagHandler.SaveOfferTags(formData.Business.OffersAdded)
+				err := TagHandler.SaveOfferTags(ctx, formData.Business.OffersAdded)
 				if err != nil {
-					l.Logger.Error("saveOfferTags failed", zap.Error(err))
+					l.WithContext(ctx).Error("saveOfferTags failed", zap.Error(err))
 				}
-				err = TagHandler.SaveWantTags(formData.Business.WantsAdded)
+				err = TagHandler.SaveWantTags(ctx, formData.Business.WantsAdded)
 				if err != nil {
-					l.Logger.Error("saveWantTags failed", zap.Error(err))
+					l.WithContext(ctx).Error("saveWantTags failed", zap.Error(err))
 				}
 			}
-		}()
+		})
 
 		t.Success(w, r, formData, "Your account has been updated!")
 	}
 }
 
-func (a *accountHandler) FindByUserID(uID string) (*types.Account, error) {
-	business, err := BusinessHandler.FindByUserID(uID)
+func (a *accountHandler) FindByUserID(ctx context.Context, uID string) (*types.Account, error) {
+	business, err := BusinessHandler.FindByUserID(ctx, uID)
 	if err != nil {
 		return nil, e.Wrap(err, "controller.Business.FindByUserID failed")
 	}
-	account, err := service.Account.FindByBusinessID(business.ID.Hex())
+	account, err := service.Account.FindByBusinessID(ctx, business.ID.Hex())
 	if err != nil {
 		return nil, e.Wrap(err, "controller.Business.FindByUserID failed")
 	}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/service"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/ic3network/mccs-alpha/internal/pkg/template"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type adminAccountFlagHandler struct {
+	once *sync.Once
+}
+
+var AdminAccountFlagHandler = newAdminAccountFlagHandler()
+
+func newAdminAccountFlagHandler() *adminAccountFlagHandler {
+	return &adminAccountFlagHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (h *adminAccountFlagHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	h.once.Do(func() {
+		adminPrivate.Path("/flagged-accounts").
+			HandlerFunc(h.flaggedAccountsPage()).
+			Methods("GET")
+		adminPrivate.Path("/flagged-accounts/{id}/notes").
+			HandlerFunc(h.addNote()).
+			Methods("POST")
+		adminPrivate.Path("/flagged-accounts/{id}/resolve").
+			HandlerFunc(h.resolve()).
+			Methods("POST")
+	})
+}
+
+type flaggedAccount struct {
+	*types.AccountFlag
+	BusinessName string
+}
+
+func (h *adminAccountFlagHandler) flaggedAccountsPage() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("admin/flagged-accounts")
+	type formData struct {
+		Status string
+		Page   int
+	}
+	type response struct {
+		FormData        formData
+		AccountFlags    []*flaggedAccount
+		NumberOfResults int
+		TotalPages      int
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		page, err := strconv.Atoi(q.Get("page"))
+		if err != nil || page < 1 {
+			page = 1
+		}
+		status := q.Get("status")
+		if q["status"] == nil {
+			status = constant.FlagStatus.Open
+		}
+		f := formData{Status: status, Page: page}
+		res := response{FormData: f}
+
+		result, err := service.AccountFlag.Find(r.Context(), status, int64(page))
+		if err != nil {
+			l.WithContext(r.Context()).Error("FlaggedAccountsPage failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		res.NumberOfResults = result.NumberOfResults
+		res.TotalPages = result.TotalPages
+
+		ids := make([]string, 0, len(result.AccountFlags))
+		for _, flag := range result.AccountFlags {
+			ids = append(ids, flag.BusinessID.Hex())
+		}
+		businesses, err := service.Business.FindByIDs(r.Context(), ids)
+		if err != nil {
+			l.WithContext(r.Context()).Error("FlaggedAccountsPage failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		names := make(map[string]string, len(businesses))
+		for _, b := range businesses {
+			names[b.ID.Hex()] = b.BusinessName
+		}
+		for _, flag := range result.AccountFlags {
+			res.AccountFlags = append(res.AccountFlags, &flaggedAccount{
+				AccountFlag:  flag,
+				BusinessName: names[flag.BusinessID.Hex()],
+			})
+		}
+
+		t.Render(w, r, res, nil)
+	}
+}
+
+// redirectToFlaggedAccounts goes back to the list the admin came from.
+func redirectToFlaggedAccounts(w http.ResponseWriter, r *http.Request) {
+	referer := r.Header.Get("Referer")
+	if strings.Contains(referer, "/admin/flagged-accounts") {
+		http.Redirect(w, r, referer, http.StatusFound)
+		return
+	}
+	http.Redirect(w, r, "/admin/flagged-accounts", http.StatusFound)
+}
+
+func (h *adminAccountFlagHandler) addNote() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+		if err != nil {
+			http.Redirect(w, r, "/admin/flagged-accounts", http.StatusFound)
+			return
+		}
+		note := strings.TrimSpace(r.FormValue("note"))
+		if note == "" {
+			redirectToFlaggedAccounts(w, r)
+			return
+		}
+
+		adminID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
+		adminUser, err := service.AdminUser.FindByID(r.Context(), adminID)
+		if err != nil {
+			l.WithContext(r.Context()).Error("AddAccountFlagNote failed", zap.Error(err))
+			http.Redirect(w, r, "/admin/flagged-accounts", http.StatusFound)
+			return
+		}
+		err = service.AccountFlag.AddNote(r.Context(), id, adminUser.Email, note)
+		if err != nil {
+			l.WithContext(r.Context()).Error("AddAccountFlagNote failed", zap.Error(err))
+		}
+		redirectToFlaggedAccounts(w, r)
+	}
+}
+
+func (h *adminAccountFlagHandler) resolve() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+		if err != nil {
+			http.Redirect(w, r, "/admin/flagged-accounts", http.StatusFound)
+			return
+		}
+		err = service.AccountFlag.Resolve(r.Context(), id)
+		if err != nil {
+			l.WithContext(r.Context()).Error("ResolveAccountFlag failed", zap.Error(err))
+		}
+		redirectToFlaggedAccounts(w, r)
+	}
+}
@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/internal/app/service"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/flash"
+	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/ic3network/mccs-alpha/internal/pkg/log"
+	"github.com/ic3network/mccs-alpha/internal/pkg/template"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type adminAgreementHandler struct {
+	once *sync.Once
+}
+
+var AdminAgreementHandler = newAdminAgreementHandler()
+
+func newAdminAgreementHandler() *adminAgreementHandler {
+	return &adminAgreementHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (h *adminAgreementHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	h.once.Do(func() {
+		adminPrivate.Path("/agreements").
+			HandlerFunc(h.agreementsPage()).
+			Methods("GET")
+		adminPrivate.Path("/agreements").
+			HandlerFunc(h.publish()).
+			Methods("POST")
+		adminPrivate.Path("/agreements/{version:[0-9]+}").
+			HandlerFunc(h.agreementPage()).
+			Methods("GET")
+	})
+}
+
+type agreementVersion struct {
+	*types.Agreement
+	Acceptances int
+}
+
+type agreementsPageData struct {
+	Text               string
+	RequiredForTrading bool
+	Agreements         []*agreementVersion
+}
+
+func (h *adminAgreementHandler) loadAgreements(ctx context.Context, res *agreementsPageData) error {
+	agreements, err := service.Agreement.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	counts, err := service.Agreement.AcceptanceCounts(ctx)
+	if err != nil {
+		return err
+	}
+	for _, a := range agreements {
+		res.Agreements = append(res.Agreements, &agreementVersion{
+			Agreement:   a,
+			Acceptances: counts[a.Version],
+		})
+	}
+	if res.Text == "" && len(agreements) > 0 {
+		res.Text = agreements[0].Text
+	}
+	return nil
+}
+
+func (h *adminAgreementHandler) agreementsPage() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("admin/agreements")
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := &agreementsPageData{}
+		err := h.loadAgreements(r.Context(), res)
+		if err != nil {
+			l.WithContext(r.Context()).Error("AgreementsPage failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		t.Render(w, r, res, nil)
+	}
+}
+
+func (h *adminAgreementHandler) publish() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("admin/agreements")
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := &agreementsPageData{
+			Text:               strings.TrimSpace(r.FormValue("text")),
+			RequiredForTrading: r.FormValue("required_for_trading") == "on",
+		}
+		if res.Text == "" {
+			err := h.loadAgreements(r.Context(), res)
+			if err != nil {
+				l.WithContext(r.Context()).Error("PublishAgreement failed", zap.Error(err))
+			}
+			t.Render(w, r, res, []string{"Please enter the text of the agreement."})
+			return
+		}
+
+		adminID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
+		adminUser, err := service.AdminUser.FindByID(r.Context(), adminID)
+		if err != nil {
+			l.WithContext(r.Context()).Error("PublishAgreement failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		agreement, err := service.Agreement.Publish(
+			r.Context(),
+			res.Text,
+			adminUser.Email,
+			res.RequiredForTrading,
+		)
+		if err != nil {
+			l.WithContext(r.Context()).Error("PublishAgreement failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		auditReq := helper.AuditRequest(r)
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := service.Audit.Record(
+				ctx,
+				log.Audit.PublishAgreement(adminUser, auditReq, agreement),
+			)
+			if err != nil {
+				l.WithContext(ctx).Error("log.Audit.PublishAgreement failed", zap.Error(err))
+			}
+			err = service.UserAction.Log(ctx, log.Admin.PublishAgreement(adminUser, agreement))
+			if err != nil {
+				l.WithContext(ctx).Error("log.Admin.PublishAgreement failed", zap.Error(err))
+			}
+		})
+
+		flash.Success(w, "Version "+strconv.Itoa(agreement.Version)+" of the Membership Agreement has been published.")
+		http.Redirect(w, r, "/admin/agreements", http.StatusFound)
+	}
+}
+
+type agreementAcceptance struct {
+	*types.AgreementAcceptance
+	BusinessName string
+	Email        string
+}
+
+func (h *adminAgreementHandler) agreementPage() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("admin/agreement")
+	type response struct {
+		Agreement       *types.Agreement
+		Acceptances     []*agreementAcceptance
+		Page            int
+		NumberOfResults int
+		TotalPages      int
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		version, _ := strconv.Atoi(mux.Vars(r)["version"])
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil || page < 1 {
+			page = 1
+		}
+		res := response{Page: page}
+
+		res.Agreement, err = service.Agreement.FindByVersion(r.Context(), version)
+		if err != nil {
+			http.Redirect(w, r, "/admin/agreements", http.StatusFound)
+			return
+		}
+		result, err := service.Agreement.FindAcceptances(r.Context(), version, int64(page))
+		if err != nil {
+			l.WithContext(r.Context()).Error("AgreementPage failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		res.NumberOfResults = result.NumberOfResults
+		res.TotalPages = result.TotalPages
+
+		businessIDs := make([]string, 0, len(result.AgreementAcceptances))
+		userIDs := make([]string, 0, len(result.AgreementAcceptances))
+		for _, a := range result.AgreementAcceptances {
+			businessIDs = append(businessIDs, a.BusinessID.Hex())
+			userIDs = append(userIDs, a.UserID.Hex())
+		}
+		businesses, err := service.Business.FindByIDs(r.Context(), businessIDs)
+		if err != nil {
+			l.WithContext(r.Context()).Error("AgreementPage failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		users, err := service.User.FindByIDs(r.Context(), userIDs)
+		if err != nil {
+			l.WithContext(r.Context()).Error("AgreementPage failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		names := make(map[string]string, len(businesses))
+		for _, b := range businesses {
+			names[b.ID.Hex()] = b.BusinessName
+		}
+		emails := make(map[string]string, len(users))
+		for _, u := range users {
+			emails[u.ID.Hex()] = u.Email
+		}
+		for _, a := range result.AgreementAcceptances {
+			res.Acceptances = append(res.Acceptances, &agreementAcceptance{
+				AgreementAcceptance: a,
+				BusinessName:        names[a.BusinessID.Hex()],
+				Email:               emails[a.UserID.Hex()],
+			})
+		}
+
+		t.Render(w, r, res, nil)
+	}
+}
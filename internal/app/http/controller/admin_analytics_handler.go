@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/internal/app/service"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/jsonerror"
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/ic3network/mccs-alpha/internal/pkg/template"
+	"github.com/ic3network/mccs-alpha/internal/pkg/util"
+	"go.uber.org/zap"
+)
+
+type adminAnalyticsHandler struct {
+	once *sync.Once
+}
+
+var AdminAnalyticsHandler = newAdminAnalyticsHandler()
+
+func newAdminAnalyticsHandler() *adminAnalyticsHandler {
+	return &adminAnalyticsHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (h *adminAnalyticsHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	h.once.Do(func() {
+		adminPrivate.Path("/analytics").
+			HandlerFunc(h.analyticsPage()).
+			Methods("GET")
+		adminPrivate.Path("/analytics/export").
+			HandlerFunc(h.exportAnalytics()).
+			Methods("GET")
+		adminPrivate.Path("/api/analytics").
+			HandlerFunc(h.getAnalytics()).
+			Methods("GET")
+	})
+}
+
+type analyticsFormData struct {
+	Interval string
+	DateFrom string
+	DateTo   string
+}
+
+// analyticsCriteria defaults to the weekly analytics of the last 90 days.
+func analyticsCriteria(r *http.Request) (analyticsFormData, *types.AnalyticsCriteria) {
+	q := r.URL.Query()
+	f := analyticsFormData{
+		Interval: q.Get("interval"),
+		DateFrom: q.Get("date-from"),
+		DateTo:   q.Get("date-to"),
+	}
+	if f.Interval == "" {
+		f.Interval = "week"
+	}
+	c := &types.AnalyticsCriteria{
+		Interval: f.Interval,
+		DateFrom: util.ParseTime(f.DateFrom),
+		DateTo:   util.ParseTime(f.DateTo),
+	}
+	if c.DateTo.IsZero() {
+		c.DateTo = time.Now().UTC()
+		f.DateTo = c.DateTo.Format("2006-01-02")
+	} else {
+		// Include the whole last day.
+		c.DateTo = c.DateTo.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	if c.DateFrom.IsZero() {
+		c.DateFrom = c.DateTo.AddDate(0, 0, -90).Truncate(24 * time.Hour)
+		f.DateFrom = c.DateFrom.Format("2006-01-02")
+	}
+	return f, c
+}
+
+func (h *adminAnalyticsHandler) analyticsPage() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("admin/analytics")
+	type response struct {
+		FormData  analyticsFormData
+		Analytics *types.TradeAnalytics
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, c := analyticsCriteria(r)
+		res := response{FormData: f}
+
+		analytics, err := service.Analytics.Trade(r.Context(), c)
+		if err != nil {
+			l.WithContext(r.Context()).Error("AnalyticsPage failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		res.Analytics = analytics
+
+		t.Render(w, r, res, nil)
+	}
+}
+
+func (h *adminAnalyticsHandler) getAnalytics() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, c := analyticsCriteria(r)
+
+		analytics, err := service.Analytics.Trade(r.Context(), c)
+		if err != nil {
+			l.WithContext(r.Context()).Error("GetAnalytics failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
+			return
+		}
+
+		js, err := json.Marshal(analytics)
+		if err != nil {
+			l.WithContext(r.Context()).Error("GetAnalytics failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(js)
+	}
+}
+
+// exportAnalytics downloads the trades and new members of each period.
+func (h *adminAnalyticsHandler) exportAnalytics() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, c := analyticsCriteria(r)
+
+		analytics, err := service.Analytics.Trade(r.Context(), c)
+		if err != nil {
+			l.WithContext(r.Context()).Error("ExportAnalytics failed", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("Something went wrong. Please try again later."))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set(
+			"Content-Disposition",
+			"attachment; filename=analytics-"+f.Interval+"-"+f.DateFrom+"-"+f.DateTo+".csv",
+		)
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"period", "trade_count", "trade_volume", "new_members"})
+		for _, p := range analytics.Periods {
+			writer.Write([]string{
+				p.Period,
+				strconv.Itoa(p.TradeCount),
+				strconv.FormatFloat(p.TradeVolume, 'f', 2, 64),
+				strconv.Itoa(p.NewMembers),
+			})
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			l.WithContext(r.Context()).Error("ExportAnalytics failed", zap.Error(err))
+		}
+	}
+}
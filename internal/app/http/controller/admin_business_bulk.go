@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/service"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/jsonerror"
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/ic3network/mccs-alpha/internal/pkg/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// maxBulkBusinesses is the most businesses a bulk action can change.
+const maxBulkBusinesses = 200
+
+var bulkStatuses = map[string]bool{
+	constant.Business.Pending:  true,
+	constant.Business.Accepted: true,
+	constant.Business.Rejected: true,
+	constant.Trading.Pending:   true,
+	constant.Trading.Accepted:  true,
+	constant.Trading.Rejected:  true,
+}
+
+// bulkUpdateBusinesses applies the action to each selected business. The
+// businesses are updated one by one, the result of each is returned.
+func (a *adminBusinessHandler) bulkUpdateBusinesses() func(http.ResponseWriter, *http.Request) {
+	type response struct {
+		Updated int                       `json:"updated"`
+		Failed  int                       `json:"failed"`
+		Results []*types.BulkActionResult `json:"results"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.BulkAction
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			l.WithContext(r.Context()).Error("BulkUpdateBusinesses failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusBadRequest, jsonerror.New(e.InvalidInput, e.Msg[e.InvalidInput]))
+			return
+		}
+		if len(req.BusinessIDs) == 0 {
+			jsonerror.Write(w, r, http.StatusBadRequest, jsonerror.New(e.InvalidInput, "Please select at least one business."))
+			return
+		}
+		if len(req.BusinessIDs) > maxBulkBusinesses {
+			jsonerror.Write(w, r, http.StatusBadRequest, jsonerror.New(e.InvalidInput, "Please select at most 200 businesses."))
+			return
+		}
+		if req.Status != "" && !bulkStatuses[req.Status] {
+			jsonerror.Write(w, r, http.StatusBadRequest, jsonerror.New(e.InvalidInput, "Please select a valid status."))
+			return
+		}
+		if req.Status == "" && len(req.AddAdminTags) == 0 &&
+			len(req.RemoveAdminTags) == 0 && req.MaxPosBal == nil &&
+			req.MaxNegBal == nil {
+			jsonerror.Write(w, r, http.StatusBadRequest, jsonerror.New(e.InvalidInput, "Please choose a change to apply."))
+			return
+		}
+
+		adminID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
+		adminUser, err := service.AdminUser.FindByID(r.Context(), adminID)
+		if err != nil {
+			l.WithContext(r.Context()).Error("BulkUpdateBusinesses failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
+			return
+		}
+
+		// All the changes of the action share the correlation ID.
+		auditReq := helper.AuditRequest(r)
+		res := response{Results: make([]*types.BulkActionResult, 0, len(req.BusinessIDs))}
+		for _, id := range req.BusinessIDs {
+			result := &types.BulkActionResult{BusinessID: id}
+			res.Results = append(res.Results, result)
+
+			bID, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				result.Error = "Invalid business id."
+				res.Failed++
+				continue
+			}
+			change, err := service.Business.ApplyBulkAction(r.Context(), bID, &req)
+			if err != nil {
+				l.WithContext(r.Context()).Info("BulkUpdateBusinesses failed", zap.String("businessID", id), zap.Error(err))
+				result.Error = err.Error()
+				res.Failed++
+				continue
+			}
+			result.BusinessName = change.OldBusiness.BusinessName
+			res.Updated++
+
+			goBackground(r.Context(), func(ctx context.Context) {
+				a.afterBulkUpdate(ctx, adminUser, auditReq, bID, change)
+			})
+		}
+
+		js, err := json.Marshal(res)
+		if err != nil {
+			l.WithContext(r.Context()).Error("BulkUpdateBusinesses failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(js)
+	}
+}
+
+// afterBulkUpdate logs the change and emails the user about a new status
+// or new balance limits.
+func (a *adminBusinessHandler) afterBulkUpdate(
+	ctx context.Context,
+	adminUser *types.AdminUser,
+	auditReq *types.AuditRequest,
+	bID primitive.ObjectID,
+	change *types.BusinessChange,
+) {
+	err := service.Audit.Record(
+		ctx,
+		log.Audit.ModifyBusiness(
+			adminUser,
+			auditReq,
+			change.OldBusiness,
+			change.NewBusiness,
+			change.OldBalance,
+			change.NewBalance,
+		),
+	)
+	if err != nil {
+		l.WithContext(ctx).Error("log.Audit.ModifyBusiness failed", zap.Error(err))
+	}
+
+	user, err := service.User.FindByBusinessID(ctx, bID)
+	if err != nil {
+		l.WithContext(ctx).Error("log.Admin.ModifyBusiness failed", zap.Error(err))
+		return
+	}
+	err = service.UserAction.Log(
+		ctx,
+		log.Admin.ModifyBusiness(
+			adminUser,
+			user,
+			change.OldBusiness,
+			change.NewBusiness,
+			change.OldBalance,
+			change.NewBalance,
+		),
+	)
+	if err != nil {
+		l.WithContext(ctx).Error("log.Admin.ModifyBusiness failed", zap.Error(err))
+	}
+
+	if change.NewBusiness.Status != change.OldBusiness.Status {
+		err := email.BusinessStatus.Changed(
+			user,
+			change.NewBusiness.BusinessName,
+			change.NewBusiness.Status,
+		)
+		if err != nil {
+			l.WithContext(ctx).Error("email.BusinessStatus.Changed failed", zap.Error(err))
+		}
+	}
+	if change.NewBalance.MaxPosBal != change.OldBalance.MaxPosBal ||
+		change.NewBalance.MaxNegBal != change.OldBalance.MaxNegBal {
+		err := email.BusinessStatus.BalanceLimitChanged(
+			user,
+			change.NewBusiness.BusinessName,
+			change.NewBalance,
+		)
+		if err != nil {
+			l.WithContext(ctx).Error("email.BusinessStatus.BalanceLimitChanged failed", zap.Error(err))
+		}
+	}
+}
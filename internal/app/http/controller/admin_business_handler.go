@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"github.com/ic3network/mccs-alpha/global/constant"
 	"github.com/ic3network/mccs-alpha/internal/app/service"
 	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/jsonerror"
 	"github.com/ic3network/mccs-alpha/internal/pkg/l"
 	"github.com/ic3network/mccs-alpha/internal/pkg/log"
 	"github.com/ic3network/mccs-alpha/internal/pkg/template"
@@ -49,6 +51,9 @@ func (a *adminBusinessHandler) RegisterRoutes(
 			HandlerFunc(a.updateBusiness()).
 			Methods("POST")
 
+		adminPrivate.Path("/api/businesses/bulk").
+			HandlerFunc(a.bulkUpdateBusinesses()).
+			Methods("POST")
 		adminPrivate.Path("/api/businesses/{id}").
 			HandlerFunc(a.deleteBusiness()).
 			Methods("DELETE")
@@ -65,15 +70,15 @@ func (a *adminBusinessHandler) adminBusinessPage() func(http.ResponseWriter, *ht
 		vars := mux.Vars(r)
 		id := vars["id"]
 
-		business, err := BusinessHandler.FindByID(id)
+		business, err := BusinessHandler.FindByID(r.Context(), id)
 		if err != nil {
-			l.Logger.Error("AdminBusinessPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("AdminBusinessPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
-		balance, err := service.BalanceLimit.FindByBusinessID(id)
+		balance, err := service.BalanceLimit.FindByBusinessID(r.Context(), id)
 		if err != nil {
-			l.Logger.Error("AdminBusinessPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("AdminBusinessPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -94,7 +99,7 @@ func (a *adminBusinessHandler) updateBusiness() func(http.ResponseWriter, *http.
 		id := vars["id"]
 		bID, err := primitive.ObjectIDFromHex(id)
 		if err != nil {
-			l.Logger.Error("UpdateBusiness failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("UpdateBusiness failed", zap.Error(err))
 			t.Error(w, r, d, err)
 			return
 		}
@@ -123,9 +128,9 @@ func (a *adminBusinessHandler) updateBusiness() func(http.ResponseWriter, *http.
 		}
 
 		// Check if the current balance has exceeded the input balances.
-		account, err := service.Account.FindByBusinessID(bID.Hex())
+		account, err := service.Account.FindByBusinessID(r.Context(), bID.Hex())
 		if err != nil {
-			l.Logger.Error("UpdateBusiness failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("UpdateBusiness failed", zap.Error(err))
 			t.Error(w, r, d, err)
 			return
 		}
@@ -148,15 +153,15 @@ func (a *adminBusinessHandler) updateBusiness() func(http.ResponseWriter, *http.
 			)
 		}
 		if len(errorMessages) > 0 {
-			l.Logger.Error("UpdateBusiness failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("UpdateBusiness failed", zap.Error(err))
 			t.Render(w, r, d, errorMessages)
 			return
 		}
 
 		// Update Business
-		oldBusiness, err := service.Business.FindByID(bID)
+		oldBusiness, err := service.Business.FindByID(r.Context(), bID)
 		if err != nil {
-			l.Logger.Error("UpdateBusiness failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("UpdateBusiness failed", zap.Error(err))
 			t.Error(w, r, d, err)
 			return
 		}
@@ -172,50 +177,72 @@ func (a *adminBusinessHandler) updateBusiness() func(http.ResponseWriter, *http.
 		)
 		d.Business.WantsAdded = wantsAdded
 		d.Business.WantsRemoved = wantsRemoved
-		err = service.Business.UpdateBusiness(bID, d.Business, true)
+		err = service.Business.UpdateBusiness(r.Context(), bID, d.Business, true)
 		if err != nil {
-			l.Logger.Error("UpdateBusiness failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("UpdateBusiness failed", zap.Error(err))
 			t.Error(w, r, d, err)
 			return
 		}
 
 		// Update BalanceLimit
-		oldBalance, err := service.BalanceLimit.FindByAccountID(account.ID)
+		oldBalance, err := service.BalanceLimit.FindByAccountID(r.Context(), account.ID)
 		if err != nil {
-			l.Logger.Error("UpdateBusiness failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("UpdateBusiness failed", zap.Error(err))
 			t.Error(w, r, d, err)
 			return
 		}
 		err = service.BalanceLimit.Update(
+			r.Context(),
 			account.ID,
 			d.Balance.MaxPosBal,
 			d.Balance.MaxNegBal,
 		)
 		if err != nil {
-			l.Logger.Error("UpdateBusiness failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("UpdateBusiness failed", zap.Error(err))
 			t.Error(w, r, d, err)
 			return
 		}
 
 		// Update the admin tags collection.
-		go func() {
-			err := AdminTagHandler.SaveAdminTags(d.Business.AdminTags)
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := AdminTagHandler.SaveAdminTags(ctx, d.Business.AdminTags)
 			if err != nil {
-				l.Logger.Error("saveAdminTags failed", zap.Error(err))
+				l.WithContext(ctx).Error("saveAdminTags failed", zap.Error(err))
 			}
-		}()
-		go func() {
+		})
+		auditReq := helper.AuditRequest(r)
+		goBackground(r.Context(), func(ctx context.Context) {
 			objID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
-			adminUser, err := service.AdminUser.FindByID(objID)
-			user, err := service.User.FindByBusinessID(bID)
+			adminUser, err := service.AdminUser.FindByID(ctx, objID)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error("log.Audit.ModifyBusiness failed", zap.Error(err))
+				return
+			}
+			err = service.Audit.Record(
+				ctx,
+				log.Audit.ModifyBusiness(
+					adminUser,
+					auditReq,
+					oldBusiness,
+					d.Business,
+					oldBalance,
+					d.Balance,
+				),
+			)
+			if err != nil {
+				l.WithContext(ctx).Error("log.Audit.ModifyBusiness failed", zap.Error(err))
+			}
+
+			user, err := service.User.FindByBusinessID(ctx, bID)
+			if err != nil {
+				l.WithContext(ctx).Error(
 					"log.Admin.ModifyBusiness failed",
 					zap.Error(err),
 				)
 				return
 			}
 			err = service.UserAction.Log(
+				ctx,
 				log.Admin.ModifyBusiness(
 					adminUser,
 					user,
@@ -226,64 +253,67 @@ func (a *adminBusinessHandler) updateBusiness() func(http.ResponseWriter, *http.
 				),
 			)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"log.Admin.ModifyBusiness failed",
 					zap.Error(err),
 				)
 			}
-		}()
+		})
 
 		// Admin Update tags logic:
 		// 	1. When a business' status is changed from pending/rejected to accepted.
 		// 	   - update all tags.
 		// 	2. When the business is in accepted status.
 		//	   - only update added tags.
-		go func() {
+		goBackground(r.Context(), func(ctx context.Context) {
 			if !util.IsAcceptedStatus(oldBusiness.Status) &&
 				util.IsAcceptedStatus(d.Business.Status) {
 				err := service.Business.UpdateAllTagsCreatedAt(
+					ctx,
 					oldBusiness.ID,
 					time.Now(),
 				)
 				if err != nil {
-					l.Logger.Error(
+					l.WithContext(ctx).Error(
 						"UpdateAllTagsCreatedAt failed",
 						zap.Error(err),
 					)
 				}
 				err = TagHandler.SaveOfferTags(
+					ctx,
 					helper.GetTagNames(d.Business.Offers),
 				)
 				if err != nil {
-					l.Logger.Error("saveOfferTags failed", zap.Error(err))
+					l.WithContext(ctx).Error("saveOfferTags failed", zap.Error(err))
 				}
 				err = TagHandler.SaveWantTags(
+					ctx,
 					helper.GetTagNames(d.Business.Wants),
 				)
 				if err != nil {
-					l.Logger.Error("saveWantTags failed", zap.Error(err))
+					l.WithContext(ctx).Error("saveWantTags failed", zap.Error(err))
 				}
 			}
 			if util.IsAcceptedStatus(oldBusiness.Status) &&
 				util.IsAcceptedStatus(d.Business.Status) {
-				err := TagHandler.SaveOfferTags(d.Business.OffersAdded)
+				err := TagHandler.SaveOfferTags(ctx, d.Business.OffersAdded)
 				if err != nil {
-					l.Logger.Error("saveOfferTags failed", zap.Error(err))
+					l.WithContext(ctx).Error("saveOfferTags failed", zap.Error(err))
 				}
-				err = TagHandler.SaveWantTags(d.Business.WantsAdded)
+				err = TagHandler.SaveWantTags(ctx, d.Business.WantsAdded)
 				if err != nil {
-					l.Logger.Error("saveWantTags failed", zap.Error(err))
+					l.WithContext(ctx).Error("saveWantTags failed", zap.Error(err))
 				}
 			}
-		}()
-		go func() {
+		})
+		goBackground(r.Context(), func(ctx context.Context) {
 			// Set timestamp when first trading status applied.
 			if oldBusiness.MemberStartedAt.IsZero() &&
 				(oldBusiness.Status == constant.Business.Accepted) &&
 				(d.Business.Status == constant.Trading.Accepted) {
-				service.Business.SetMemberStartedAt(bID)
+				service.Business.SetMemberStartedAt(ctx, bID)
 			}
-		}()
+		})
 
 		t.Success(w, r, d, "The business has been updated!")
 	}
@@ -295,32 +325,70 @@ func (a *adminBusinessHandler) deleteBusiness() func(http.ResponseWriter, *http.
 		id := vars["id"]
 		bsID, err := primitive.ObjectIDFromHex(id)
 		if err != nil {
-			l.Logger.Error("DeleteBusiness failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("DeleteBusiness failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		err = service.Business.DeleteByID(bsID)
+		business, err := service.Business.FindByID(r.Context(), bsID)
 		if err != nil {
-			l.Logger.Error("DeleteBusiness failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("DeleteBusiness failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		user, err := service.User.FindByBusinessID(bsID)
+		err = service.Business.DeleteByID(r.Context(), bsID)
 		if err != nil {
-			l.Logger.Error("DeleteBusiness failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("DeleteBusiness failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		err = service.User.DeleteByID(user.ID)
+		user, err := service.User.FindByBusinessID(r.Context(), bsID)
 		if err != nil {
-			l.Logger.Error("DeleteBusiness failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("DeleteBusiness failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
+		err = service.User.DeleteByID(r.Context(), user.ID)
+		if err != nil {
+			l.WithContext(r.Context()).Error("DeleteBusiness failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
+			return
+		}
+
+		auditReq := helper.AuditRequest(r)
+		goBackground(r.Context(), func(ctx context.Context) {
+			objID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
+			adminUser, err := service.AdminUser.FindByID(ctx, objID)
+			if err != nil {
+				l.WithContext(ctx).Error("log.Admin.DeleteBusiness failed", zap.Error(err))
+				return
+			}
+			err = service.Audit.Record(
+				ctx,
+				log.Audit.DeleteBusiness(adminUser, auditReq, business),
+			)
+			if err != nil {
+				l.WithContext(ctx).Error("log.Audit.DeleteBusiness failed", zap.Error(err))
+			}
+			err = service.Audit.Record(
+				ctx,
+				log.Audit.DeleteUser(adminUser, auditReq, user),
+			)
+			if err != nil {
+				l.WithContext(ctx).Error("log.Audit.DeleteUser failed", zap.Error(err))
+			}
+			err = service.UserAction.Log(
+				ctx,
+				log.Admin.DeleteBusiness(adminUser, business, user),
+			)
+			if err != nil {
+				l.WithContext(ctx).Error("log.Admin.DeleteBusiness failed", zap.Error(err))
+			}
+		})
+
 		w.WriteHeader(http.StatusOK)
 	}
 }
@@ -0,0 +1,259 @@
+package controller
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/internal/app/service"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/jsonerror"
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/ic3network/mccs-alpha/internal/pkg/log"
+	"github.com/ic3network/mccs-alpha/internal/pkg/template"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type adminDirectoryHandler struct {
+	once *sync.Once
+}
+
+var AdminDirectoryHandler = newAdminDirectoryHandler()
+
+func newAdminDirectoryHandler() *adminDirectoryHandler {
+	return &adminDirectoryHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (h *adminDirectoryHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	h.once.Do(func() {
+		adminPrivate.Path("/directory").
+			HandlerFunc(h.directoryPage()).
+			Methods("GET")
+		adminPrivate.Path("/directory/import").
+			HandlerFunc(h.importPage()).
+			Methods("POST")
+		adminPrivate.Path("/directory/export").
+			HandlerFunc(h.export()).
+			Methods("GET")
+		adminPrivate.Path("/api/directory/import").
+			HandlerFunc(h.importDirectory()).
+			Methods("POST")
+	})
+}
+
+// maxImportSize is the largest file the import accepts.
+const maxImportSize = 32 << 20
+
+type importFormData struct {
+	DryRun           bool
+	SendWelcomeEmail bool
+}
+
+type directoryPageData struct {
+	FormData importFormData
+	Columns  []string
+	Report   *types.ImportReport
+}
+
+func (h *adminDirectoryHandler) directoryPage() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("admin/directory")
+	return func(w http.ResponseWriter, r *http.Request) {
+		t.Render(w, r, directoryPageData{
+			FormData: importFormData{DryRun: true},
+			Columns:  helper.DirectoryColumns,
+		}, nil)
+	}
+}
+
+// parseImport reads the uploaded file, the format is taken from the file
+// extension.
+func parseImport(r *http.Request) (string, []*types.DirectoryRow, *types.ImportOptions, error) {
+	err := r.ParseMultipartForm(maxImportSize)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return "", nil, nil, err
+	}
+	defer file.Close()
+
+	opts := &types.ImportOptions{
+		DryRun:           r.FormValue("dry_run") == "true",
+		SendWelcomeEmail: r.FormValue("send_welcome_email") == "true",
+	}
+	rows, err := parseImportFile(header, file)
+	if err != nil {
+		return header.Filename, nil, nil, err
+	}
+	return header.Filename, rows, opts, nil
+}
+
+func parseImportFile(
+	header *multipart.FileHeader,
+	file multipart.File,
+) ([]*types.DirectoryRow, error) {
+	if strings.ToLower(filepath.Ext(header.Filename)) == ".json" {
+		return helper.ParseDirectoryJSON(file)
+	}
+	return helper.ParseDirectoryCSV(file)
+}
+
+// afterImport sends the welcome emails and logs the import.
+func (h *adminDirectoryHandler) afterImport(
+	r *http.Request,
+	fileName string,
+	opts *types.ImportOptions,
+	report *types.ImportReport,
+) {
+	if report.DryRun {
+		return
+	}
+	if opts.SendWelcomeEmail {
+		goBackground(r.Context(), func(ctx context.Context) {
+			for _, row := range report.Rows {
+				if row.User == nil {
+					continue
+				}
+				err := email.SendWelcomeEmail(row.BusinessName, row.User)
+				if err != nil {
+					l.WithContext(ctx).Error("email.SendWelcomeEmail failed", zap.Error(err))
+				}
+			}
+		})
+	}
+	auditReq := helper.AuditRequest(r)
+	goBackground(r.Context(), func(ctx context.Context) {
+		objID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
+		adminUser, err := service.AdminUser.FindByID(ctx, objID)
+		if err != nil {
+			l.WithContext(ctx).Error("log.Admin.ImportDirectory failed", zap.Error(err))
+			return
+		}
+		err = service.Audit.Record(
+			ctx,
+			log.Audit.ImportDirectory(adminUser, auditReq, fileName, report),
+		)
+		if err != nil {
+			l.WithContext(ctx).Error("log.Audit.ImportDirectory failed", zap.Error(err))
+		}
+		err = service.UserAction.Log(
+			ctx,
+			log.Admin.ImportDirectory(adminUser, fileName, report),
+		)
+		if err != nil {
+			l.WithContext(ctx).Error("log.Admin.ImportDirectory failed", zap.Error(err))
+		}
+	})
+}
+
+func (h *adminDirectoryHandler) importPage() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("admin/directory")
+	return func(w http.ResponseWriter, r *http.Request) {
+		fileName, rows, opts, err := parseImport(r)
+		if err != nil {
+			l.WithContext(r.Context()).Info("ImportDirectory failed", zap.Error(err))
+			t.Render(
+				w,
+				r,
+				directoryPageData{Columns: helper.DirectoryColumns},
+				[]string{"The file could not be read: " + err.Error()},
+			)
+			return
+		}
+		res := directoryPageData{
+			FormData: importFormData{
+				DryRun:           opts.DryRun,
+				SendWelcomeEmail: opts.SendWelcomeEmail,
+			},
+			Columns: helper.DirectoryColumns,
+		}
+
+		res.Report = service.Directory.Import(r.Context(), rows, opts)
+		h.afterImport(r, fileName, opts, res.Report)
+
+		t.Render(w, r, res, nil)
+	}
+}
+
+func (h *adminDirectoryHandler) importDirectory() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fileName, rows, opts, err := parseImport(r)
+		if err != nil {
+			l.WithContext(r.Context()).Info("ImportDirectory failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusBadRequest, jsonerror.New(e.InvalidInput, "The file could not be read: "+err.Error()))
+			return
+		}
+
+		report := service.Directory.Import(r.Context(), rows, opts)
+		h.afterImport(r, fileName, opts, report)
+
+		js, err := json.Marshal(report)
+		if err != nil {
+			l.WithContext(r.Context()).Error("ImportDirectory failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(js)
+	}
+}
+
+// export downloads the whole directory as CSV or JSON, in the format the
+// import reads.
+func (h *adminDirectoryHandler) export() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rows, err := service.Directory.Export(r.Context())
+		if err != nil {
+			l.WithContext(r.Context()).Error("ExportDirectory failed", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("Something went wrong. Please try again later."))
+			return
+		}
+
+		fileName := "directory-" + time.Now().Format("2006-01-02")
+		if r.URL.Query().Get("format") == "json" {
+			js, err := json.Marshal(rows)
+			if err != nil {
+				l.WithContext(r.Context()).Error("ExportDirectory failed", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte("Something went wrong. Please try again later."))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Disposition", "attachment; filename="+fileName+".json")
+			w.Write(js)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename="+fileName+".csv")
+		writer := csv.NewWriter(w)
+		writer.Write(helper.DirectoryColumns)
+		for _, row := range rows {
+			writer.Write(helper.DirectoryCSVRecord(row))
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			l.WithContext(r.Context()).Error("ExportDirectory failed", zap.Error(err))
+		}
+	}
+}
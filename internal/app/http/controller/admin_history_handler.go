@@ -61,7 +61,7 @@ func (h *adminHistoryHandler) historyPage() func(http.ResponseWriter, *http.Requ
 
 		page, err := strconv.Atoi(q.Get("page"))
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"controller.AdminHistory.HistoryPage failed",
 				zap.Error(err),
 			)
@@ -74,9 +74,9 @@ func (h *adminHistoryHandler) historyPage() func(http.ResponseWriter, *http.Requ
 			DateTo:   q.Get("date-to"),
 			Page:     page,
 		}
-		user, err := UserHandler.FindByBusinessID(bID)
+		user, err := UserHandler.FindByBusinessID(r.Context(), bID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"controller.AdminHistory.HistoryPage failed",
 				zap.Error(err),
 			)
@@ -86,9 +86,9 @@ func (h *adminHistoryHandler) historyPage() func(http.ResponseWriter, *http.Requ
 		res := response{FormData: f, BusinessID: bID, Email: user.Email}
 
 		// Get the account balance.
-		account, err := service.Account.FindByBusinessID(bID)
+		account, err := service.Account.FindByBusinessID(r.Context(), bID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"controller.AdminHistory.HistoryPage failed",
 				zap.Error(err),
 			)
@@ -99,13 +99,14 @@ func (h *adminHistoryHandler) historyPage() func(http.ResponseWriter, *http.Requ
 
 		// Get the recent transactions.
 		transactions, totalPages, err := service.Transaction.FindInRange(
+			r.Context(),
 			account.ID,
 			util.ParseTime(f.DateFrom),
 			util.ParseTime(f.DateTo),
 			page,
 		)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"controller.AdminHistory.HistoryPage failed",
 				zap.Error(err),
 			)
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/internal/app/service"
+	"github.com/ic3network/mccs-alpha/internal/app/service/scheduler"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/flash"
+	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/ic3network/mccs-alpha/internal/pkg/log"
+	"github.com/ic3network/mccs-alpha/internal/pkg/template"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type adminJobHandler struct {
+	once *sync.Once
+}
+
+var AdminJobHandler = newAdminJobHandler()
+
+func newAdminJobHandler() *adminJobHandler {
+	return &adminJobHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (h *adminJobHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	h.once.Do(func() {
+		adminPrivate.Path("/jobs").HandlerFunc(h.jobsPage()).Methods("GET")
+		adminPrivate.Path("/jobs/{name}").HandlerFunc(h.jobPage()).Methods("GET")
+		adminPrivate.Path("/jobs/{name}/run").HandlerFunc(h.trigger()).Methods("POST")
+	})
+}
+
+type jobStatus struct {
+	Name        string
+	Description string
+	Schedule    string
+	Latest      *types.JobRun
+}
+
+type jobsPageData struct {
+	Jobs []*jobStatus
+}
+
+func (h *adminJobHandler) loadJobs(ctx context.Context) (*jobsPageData, error) {
+	latest, err := service.JobRun.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := &jobsPageData{}
+	for _, job := range scheduler.Jobs() {
+		res.Jobs = append(res.Jobs, &jobStatus{
+			Name:        job.Name,
+			Description: job.Description,
+			Schedule:    job.Schedule,
+			Latest:      latest[job.Name],
+		})
+	}
+	return res, nil
+}
+
+func (h *adminJobHandler) jobsPage() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("admin/jobs")
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := h.loadJobs(r.Context())
+		if err != nil {
+			l.WithContext(r.Context()).Error("JobsPage failed", zap.Error(err))
+			t.Error(w, r, &jobsPageData{}, err)
+			return
+		}
+		t.Render(w, r, res, nil)
+	}
+}
+
+func (h *adminJobHandler) jobPage() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("admin/job")
+	type response struct {
+		Job             *scheduler.Job
+		JobRuns         []*types.JobRun
+		Page            int
+		NumberOfResults int
+		TotalPages      int
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, err := scheduler.Find(mux.Vars(r)["name"])
+		if err != nil {
+			http.Redirect(w, r, "/admin/jobs", http.StatusFound)
+			return
+		}
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil || page < 1 {
+			page = 1
+		}
+		res := response{Job: job, Page: page}
+
+		result, err := service.JobRun.FindByJob(r.Context(), job.Name, int64(page))
+		if err != nil {
+			l.WithContext(r.Context()).Error("JobPage failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		res.JobRuns = result.JobRuns
+		res.NumberOfResults = result.NumberOfResults
+		res.TotalPages = result.TotalPages
+
+		t.Render(w, r, res, nil)
+	}
+}
+
+func (h *adminJobHandler) trigger() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("admin/jobs")
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["name"]
+
+		adminID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
+		adminUser, err := service.AdminUser.FindByID(r.Context(), adminID)
+		if err != nil {
+			l.WithContext(r.Context()).Error("TriggerJob failed", zap.Error(err))
+			t.Error(w, r, &jobsPageData{}, err)
+			return
+		}
+
+		err = scheduler.Trigger(name, adminUser.Email)
+		if err != nil {
+			l.WithContext(r.Context()).Info("TriggerJob failed", zap.Error(err))
+			res, lerr := h.loadJobs(r.Context())
+			if lerr != nil {
+				l.WithContext(r.Context()).Error("TriggerJob failed", zap.Error(lerr))
+				res = &jobsPageData{}
+			}
+			t.Error(w, r, res, err)
+			return
+		}
+		auditReq := helper.AuditRequest(r)
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := service.Audit.Record(ctx, log.Audit.TriggerJob(adminUser, auditReq, name))
+			if err != nil {
+				l.WithContext(ctx).Error("log.Audit.TriggerJob failed", zap.Error(err))
+			}
+			err = service.UserAction.Log(ctx, log.Admin.TriggerJob(adminUser, name))
+			if err != nil {
+				l.WithContext(ctx).Error("log.Admin.TriggerJob failed", zap.Error(err))
+			}
+		})
+
+		flash.Success(w, "The "+name+" job has been started.")
+		http.Redirect(w, r, "/admin/jobs", http.StatusFound)
+	}
+}
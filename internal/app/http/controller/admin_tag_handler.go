@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -10,7 +11,9 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/ic3network/mccs-alpha/internal/app/service"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
 	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/jsonerror"
 	"github.com/ic3network/mccs-alpha/internal/pkg/l"
 	"github.com/ic3network/mccs-alpha/internal/pkg/log"
 	"github.com/ic3network/mccs-alpha/internal/pkg/template"
@@ -59,9 +62,9 @@ func (a *adminTagHandler) RegisterRoutes(
 	})
 }
 
-func (a *adminTagHandler) SaveAdminTags(adminTags []string) error {
+func (a *adminTagHandler) SaveAdminTags(ctx context.Context, adminTags []string) error {
 	for _, adminTag := range adminTags {
-		err := service.AdminTag.Create(adminTag)
+		err := service.AdminTag.Create(ctx, adminTag)
 		if err != nil {
 			return err
 		}
@@ -86,55 +89,71 @@ func (a *adminTagHandler) createAdminTag() func(http.ResponseWriter, *http.Reque
 		decoder := json.NewDecoder(r.Body)
 		err := decoder.Decode(&req)
 		if err != nil {
-			l.Logger.Error("CreateAdminTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			l.WithContext(r.Context()).Error("CreateAdminTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
 		if req.Name == "" {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Please enter the admin tag name"))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.New(e.InvalidInput, "Please enter the admin tag name."))
 			return
 		}
 		req.Name = helper.FormatAdminTag(req.Name)
 
-		_, err = service.AdminTag.FindByName(req.Name)
+		_, err = service.AdminTag.FindByName(r.Context(), req.Name)
 		if err == nil {
-			l.Logger.Info("Admin tag already exists!")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Admin tag already exists!"))
+			l.WithContext(r.Context()).Info("Admin tag already exists!")
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.New(e.TagExisted, "Admin tag already exists."))
 			return
 		}
 
-		err = service.AdminTag.Create(req.Name)
+		err = service.AdminTag.Create(r.Context(), req.Name)
 		if err != nil {
-			l.Logger.Error("CreateAdminTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			l.WithContext(r.Context()).Error("CreateAdminTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		go func() {
+		auditReq := helper.AuditRequest(r)
+		goBackground(r.Context(), func(ctx context.Context) {
 			objID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
-			adminUser, err := service.AdminUser.FindByID(objID)
+			adminUser, err := service.AdminUser.FindByID(ctx, objID)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"log.Admin.CreateAdminTag failed",
 					zap.Error(err),
 				)
 				return
 			}
+			adminTag, err := service.AdminTag.FindByName(ctx, req.Name)
+			if err != nil {
+				l.WithContext(ctx).Error(
+					"log.Audit.CreateAdminTag failed",
+					zap.Error(err),
+				)
+			} else {
+				err = service.Audit.Record(
+					ctx,
+					log.Audit.CreateAdminTag(adminUser, auditReq, adminTag),
+				)
+				if err != nil {
+					l.WithContext(ctx).Error(
+						"log.Audit.CreateAdminTag failed",
+						zap.Error(err),
+					)
+				}
+			}
 			err = service.UserAction.Log(
+				ctx,
 				log.Admin.CreateAdminTag(adminUser, req.Name),
 			)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"log.Admin.CreateAdminTag failed",
 					zap.Error(err),
 				)
 			}
-		}()
+		})
 
 		w.WriteHeader(http.StatusCreated)
 	}
@@ -153,7 +172,7 @@ func (a *adminTagHandler) searchAdminTags() func(http.ResponseWriter, *http.Requ
 	return func(w http.ResponseWriter, r *http.Request) {
 		page, err := strconv.Atoi(r.URL.Query().Get("page"))
 		if err != nil {
-			l.Logger.Error("SearchAdminTags failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("SearchAdminTags failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -169,9 +188,9 @@ func (a *adminTagHandler) searchAdminTags() func(http.ResponseWriter, *http.Requ
 			return
 		}
 
-		findResult, err := service.AdminTag.FindTags(f.Name, int64(f.Page))
+		findResult, err := service.AdminTag.FindTags(r.Context(), f.Name, int64(f.Page))
 		if err != nil {
-			l.Logger.Error("SearchAdminTags failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("SearchAdminTags failed", zap.Error(err))
 			t.Error(w, r, res, err)
 			return
 		}
@@ -192,83 +211,95 @@ func (a *adminTagHandler) renameAdminTag() func(http.ResponseWriter, *http.Reque
 		decoder := json.NewDecoder(r.Body)
 		err := decoder.Decode(&req)
 		if err != nil {
-			l.Logger.Error("RenameAdminTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			l.WithContext(r.Context()).Error("RenameAdminTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
 		if req.Name == "" {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Please enter the tag name"))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.New(e.InvalidInput, "Please enter the tag name."))
 			return
 		}
 		req.Name = helper.FormatAdminTag(req.Name)
 
-		_, err = service.AdminTag.FindByName(req.Name)
+		_, err = service.AdminTag.FindByName(r.Context(), req.Name)
 		if err == nil {
-			l.Logger.Info("RenameAdminTag failed: Admin tag already exists")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Admin tag already exists!"))
+			l.WithContext(r.Context()).Info("RenameAdminTag failed: Admin tag already exists")
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.New(e.TagExisted, "Admin tag already exists."))
 			return
 		}
 
 		adminTagID, err := primitive.ObjectIDFromHex(req.ID)
 		if err != nil {
-			l.Logger.Error("RenameAdminTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			l.WithContext(r.Context()).Error("RenameAdminTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		adminTag, err := service.AdminTag.FindByID(adminTagID)
+		adminTag, err := service.AdminTag.FindByID(r.Context(), adminTagID)
 		if err != nil {
-			l.Logger.Error("RenameAdminTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("AdminTag not found."))
+			l.WithContext(r.Context()).Error("RenameAdminTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.New(e.TagNotFound, "Admin tag not found."))
 			return
 		}
 		oldName := adminTag.Name
 
-		go func() {
-			err := service.Business.RenameAdminTag(oldName, req.Name)
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := service.Business.RenameAdminTag(ctx, oldName, req.Name)
 			if err != nil {
-				l.Logger.Error("RenameAdminTag failed", zap.Error(err))
+				l.WithContext(ctx).Error("RenameAdminTag failed", zap.Error(err))
 			}
-		}()
+		})
 
 		adminTag = &types.AdminTag{
 			ID:   adminTagID,
 			Name: req.Name,
 		}
-		err = service.AdminTag.Update(adminTag)
+		err = service.AdminTag.Update(r.Context(), adminTag)
 		if err != nil {
-			l.Logger.Error("RenameAdminTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			l.WithContext(r.Context()).Error("RenameAdminTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		go func() {
+		auditReq := helper.AuditRequest(r)
+		goBackground(r.Context(), func(ctx context.Context) {
 			objID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
-			adminUser, err := service.AdminUser.FindByID(objID)
+			adminUser, err := service.AdminUser.FindByID(ctx, objID)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"log.Admin.ModifyAdminTag failed",
 					zap.Error(err),
 				)
 				return
 			}
+			err = service.Audit.Record(
+				ctx,
+				log.Audit.ModifyAdminTag(
+					adminUser,
+					auditReq,
+					adminTagID,
+					oldName,
+					req.Name,
+				),
+			)
+			if err != nil {
+				l.WithContext(ctx).Error(
+					"log.Audit.ModifyAdminTag failed",
+					zap.Error(err),
+				)
+			}
 			err = service.UserAction.Log(
+				ctx,
 				log.Admin.ModifyAdminTag(adminUser, oldName, req.Name),
 			)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"log.Admin.ModifyAdminTag failed",
 					zap.Error(err),
 				)
 			}
-		}()
+		})
 
 		w.WriteHeader(http.StatusCreated)
 	}
@@ -280,51 +311,63 @@ func (a *adminTagHandler) deleteAdminTag() func(http.ResponseWriter, *http.Reque
 		id := vars["id"]
 		adminTagID, err := primitive.ObjectIDFromHex(id)
 		if err != nil {
-			l.Logger.Error("DeleteAdminTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("DeleteAdminTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		adminTag, err := service.AdminTag.FindByID(adminTagID)
+		adminTag, err := service.AdminTag.FindByID(r.Context(), adminTagID)
 		if err != nil {
-			l.Logger.Error("DeleteAdminTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("DeleteAdminTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		err = service.AdminTag.DeleteByID(adminTagID)
+		err = service.AdminTag.DeleteByID(r.Context(), adminTagID)
 		if err != nil {
-			l.Logger.Error("DeleteAdminTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("DeleteAdminTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		go func() {
-			err := service.Business.DeleteAdminTags(adminTag.Name)
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := service.Business.DeleteAdminTags(ctx, adminTag.Name)
 			if err != nil {
-				l.Logger.Error("DeleteAdminTags failed", zap.Error(err))
+				l.WithContext(ctx).Error("DeleteAdminTags failed", zap.Error(err))
 			}
-		}()
-		go func() {
+		})
+		auditReq := helper.AuditRequest(r)
+		goBackground(r.Context(), func(ctx context.Context) {
 			objID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
-			adminUser, err := service.AdminUser.FindByID(objID)
+			adminUser, err := service.AdminUser.FindByID(ctx, objID)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"log.Admin.DeleteAdminTag failed",
 					zap.Error(err),
 				)
 				return
 			}
+			err = service.Audit.Record(
+				ctx,
+				log.Audit.DeleteAdminTag(adminUser, auditReq, adminTag),
+			)
+			if err != nil {
+				l.WithContext(ctx).Error(
+					"log.Audit.DeleteAdminTag failed",
+					zap.Error(err),
+				)
+			}
 			err = service.UserAction.Log(
+				ctx,
 				log.Admin.DeleteAdminTag(adminUser, adminTag.Name),
 			)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"log.Admin.DeleteAdminTag failed",
 					zap.Error(err),
 				)
 			}
-		}()
+		})
 
 		w.WriteHeader(http.StatusOK)
 	}
@@ -335,23 +378,23 @@ func (a *adminTagHandler) list() func(http.ResponseWriter, *http.Request) {
 		vars := mux.Vars(r)
 		prefix := vars["prefix"]
 
-		tags, err := service.AdminTag.TagStartWith(prefix)
+		tags, err := service.AdminTag.TagStartWith(r.Context(), prefix)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"controller.AdminTagHandler.List failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
 		js, err := json.Marshal(tags)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"controller.AdminTagHandler.List failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
@@ -0,0 +1,322 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/service"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/ic3network/mccs-alpha/internal/pkg/log"
+	"github.com/ic3network/mccs-alpha/internal/pkg/template"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type adminTradingApplicationHandler struct {
+	once *sync.Once
+}
+
+var AdminTradingApplicationHandler = newAdminTradingApplicationHandler()
+
+func newAdminTradingApplicationHandler() *adminTradingApplicationHandler {
+	return &adminTradingApplicationHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (h *adminTradingApplicationHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	h.once.Do(func() {
+		adminPrivate.Path("/applications").
+			HandlerFunc(h.applicationsPage()).
+			Methods("GET")
+		adminPrivate.Path("/applications/{id}").
+			HandlerFunc(h.applicationPage()).
+			Methods("GET")
+		adminPrivate.Path("/applications/{id}/notes").
+			HandlerFunc(h.addNote()).
+			Methods("POST")
+		adminPrivate.Path("/applications/{id}/decision").
+			HandlerFunc(h.decide()).
+			Methods("POST")
+	})
+}
+
+type queuedApplication struct {
+	*types.TradingApplication
+	BusinessName string
+	Applicant    string
+	AgeDays      int
+	Overdue      bool
+}
+
+// applicationSLADays is the number of days an application should wait
+// for a decision at most.
+func applicationSLADays() int {
+	return global.Config().ApplicationSLADays
+}
+
+// applicationAge returns the number of days the application has waited
+// and whether it waited longer than the review target.
+func applicationAge(a *types.TradingApplication, now time.Time) (int, bool) {
+	days := int(now.Sub(a.SubmittedAt).Hours() / 24)
+	open := a.Status == constant.TradingApplication.Pending
+	return days, open && days >= applicationSLADays()
+}
+
+func (h *adminTradingApplicationHandler) applicationsPage() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("admin/applications")
+	type formData struct {
+		Status string
+		Page   int
+	}
+	type response struct {
+		FormData            formData
+		TradingApplications []*queuedApplication
+		NumberOfResults     int
+		TotalPages          int
+		SLADays             int
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		page, err := strconv.Atoi(q.Get("page"))
+		if err != nil || page < 1 {
+			page = 1
+		}
+		f := formData{Status: q.Get("status"), Page: page}
+		res := response{FormData: f, SLADays: applicationSLADays()}
+
+		result, err := service.TradingApplication.Find(r.Context(), f.Status, int64(page))
+		if err != nil {
+			l.WithContext(r.Context()).Error("ApplicationsPage failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		res.NumberOfResults = result.NumberOfResults
+		res.TotalPages = result.TotalPages
+
+		ids := make([]string, 0, len(result.TradingApplications))
+		for _, a := range result.TradingApplications {
+			ids = append(ids, a.BusinessID.Hex())
+		}
+		businesses, err := service.Business.FindByIDs(r.Context(), ids)
+		if err != nil {
+			l.WithContext(r.Context()).Error("ApplicationsPage failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		names := make(map[string]string, len(businesses))
+		for _, b := range businesses {
+			names[b.ID.Hex()] = b.BusinessName
+		}
+		now := time.Now()
+		for _, a := range result.TradingApplications {
+			days, overdue := applicationAge(a, now)
+			res.TradingApplications = append(res.TradingApplications, &queuedApplication{
+				TradingApplication: a,
+				BusinessName:       names[a.BusinessID.Hex()],
+				Applicant:          a.Data.FirstName + " " + a.Data.LastName,
+				AgeDays:            days,
+				Overdue:            overdue,
+			})
+		}
+
+		t.Render(w, r, res, nil)
+	}
+}
+
+type applicationPageData struct {
+	Application *types.TradingApplication
+	Business    *types.Business
+	User        *types.User
+	History     []*types.TradingApplicationEvent
+	AgeDays     int
+	Overdue     bool
+	Open        bool
+}
+
+func (h *adminTradingApplicationHandler) loadApplication(ctx context.Context, id primitive.ObjectID) (*applicationPageData, error) {
+	a, err := service.TradingApplication.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	business, err := service.Business.FindByID(ctx, a.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := service.User.FindByID(ctx, a.UserID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := service.TradingApplication.History(ctx, a.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	days, overdue := applicationAge(a, time.Now())
+	return &applicationPageData{
+		Application: a,
+		Business:    business,
+		User:        user,
+		History:     history,
+		AgeDays:     days,
+		Overdue:     overdue,
+		Open: a.Status == constant.TradingApplication.Pending ||
+			a.Status == constant.TradingApplication.InfoRequested,
+	}, nil
+}
+
+func (h *adminTradingApplicationHandler) applicationPage() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("admin/application")
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+		if err != nil {
+			http.Redirect(w, r, "/admin/applications", http.StatusFound)
+			return
+		}
+		res, err := h.loadApplication(r.Context(), id)
+		if err != nil {
+			l.WithContext(r.Context()).Error("ApplicationPage failed", zap.Error(err))
+			http.Redirect(w, r, "/admin/applications", http.StatusFound)
+			return
+		}
+		t.Render(w, r, res, nil)
+	}
+}
+
+func (h *adminTradingApplicationHandler) addNote() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+		if err != nil {
+			http.Redirect(w, r, "/admin/applications", http.StatusFound)
+			return
+		}
+		note := strings.TrimSpace(r.FormValue("note"))
+		if note != "" {
+			adminID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
+			adminUser, err := service.AdminUser.FindByID(r.Context(), adminID)
+			if err == nil {
+				err = service.TradingApplication.AddNote(r.Context(), id, adminUser.Email, note)
+			}
+			if err != nil {
+				l.WithContext(r.Context()).Error("AddApplicationNote failed", zap.Error(err))
+			}
+		}
+		http.Redirect(w, r, "/admin/applications/"+id.Hex(), http.StatusFound)
+	}
+}
+
+func (h *adminTradingApplicationHandler) decide() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("admin/application")
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+		if err != nil {
+			http.Redirect(w, r, "/admin/applications", http.StatusFound)
+			return
+		}
+		res, err := h.loadApplication(r.Context(), id)
+		if err != nil {
+			l.WithContext(r.Context()).Error("DecideApplication failed", zap.Error(err))
+			http.Redirect(w, r, "/admin/applications", http.StatusFound)
+			return
+		}
+
+		action := r.FormValue("action")
+		message := strings.TrimSpace(r.FormValue("message"))
+		if action == constant.ApplicationEvent.InfoRequested && message == "" {
+			t.Error(w, r, res, e.New(e.ApplicationMessageRequired, "message is empty"))
+			return
+		}
+
+		adminID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
+		adminUser, err := service.AdminUser.FindByID(r.Context(), adminID)
+		if err != nil {
+			l.WithContext(r.Context()).Error("DecideApplication failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		_, change, err := service.TradingApplication.Decide(r.Context(), id, action, adminUser.Email, message)
+		if err != nil {
+			l.WithContext(r.Context()).Error("DecideApplication failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+
+		auditReq := helper.AuditRequest(r)
+		goBackground(r.Context(), func(ctx context.Context) {
+			h.afterDecision(
+				ctx,
+				adminUser,
+				auditReq,
+				res.User,
+				res.Business.BusinessName,
+				action,
+				message,
+				change,
+			)
+		})
+
+		http.Redirect(w, r, "/admin/applications/"+id.Hex(), http.StatusFound)
+	}
+}
+
+// afterDecision logs the change of the business and emails the applicant.
+func (h *adminTradingApplicationHandler) afterDecision(
+	ctx context.Context,
+	adminUser *types.AdminUser,
+	auditReq *types.AuditRequest,
+	user *types.User,
+	businessName string,
+	action string,
+	message string,
+	change *types.BusinessChange,
+) {
+	if change != nil {
+		err := service.Audit.Record(
+			ctx,
+			log.Audit.ModifyBusiness(
+				adminUser,
+				auditReq,
+				change.OldBusiness,
+				change.NewBusiness,
+				change.OldBalance,
+				change.NewBalance,
+			),
+		)
+		if err != nil {
+			l.WithContext(ctx).Error("log.Audit.ModifyBusiness failed", zap.Error(err))
+		}
+		err = service.UserAction.Log(
+			ctx,
+			log.Admin.ModifyBusiness(
+				adminUser,
+				user,
+				change.OldBusiness,
+				change.NewBusiness,
+				change.OldBalance,
+				change.NewBalance,
+			),
+		)
+		if err != nil {
+			l.WithContext(ctx).Error("log.Admin.ModifyBusiness failed", zap.Error(err))
+		}
+	}
+	err := email.TradingApplication.Decided(user, businessName, action, message)
+	if err != nil {
+		l.WithContext(ctx).Error("email.TradingApplication.Decided failed", zap.Error(err))
+	}
+}
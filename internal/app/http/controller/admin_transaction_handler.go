@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -12,6 +13,8 @@ import (
 	"github.com/ic3network/mccs-alpha/internal/app/service"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/flash"
+	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/jsonerror"
 	"github.com/ic3network/mccs-alpha/internal/pkg/l"
 	"github.com/ic3network/mccs-alpha/internal/pkg/log"
 	"github.com/ic3network/mccs-alpha/internal/pkg/template"
@@ -125,15 +128,15 @@ func (tr *adminTransactionHandler) transaction() func(http.ResponseWriter, *http
 		}
 		f.Amount = amount
 
-		from, err := BusinessHandler.FindByEmail(f.FromEmail)
+		from, err := BusinessHandler.FindByEmail(r.Context(), f.FromEmail)
 		if err != nil {
-			l.Logger.Info("Transaction failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("Transaction failed", zap.Error(err))
 			t.Error(w, r, res, err)
 			return
 		}
-		to, err := BusinessHandler.FindByEmail(f.ToEmail)
+		to, err := BusinessHandler.FindByEmail(r.Context(), f.ToEmail)
 		if err != nil {
-			l.Logger.Info("Transaction failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("Transaction failed", zap.Error(err))
 			t.Error(w, r, res, err)
 			return
 		}
@@ -165,7 +168,8 @@ func (tr *adminTransactionHandler) transaction() func(http.ResponseWriter, *http
 			return
 		}
 
-		err = service.AdminTransaction.Create(
+		transactionID, err := service.AdminTransaction.Create(
+			r.Context(),
 			from.ID.Hex(),
 			f.FromEmail,
 			from.BusinessName,
@@ -176,19 +180,36 @@ func (tr *adminTransactionHandler) transaction() func(http.ResponseWriter, *http
 			f.Description,
 		)
 		if err != nil {
-			l.Logger.Info("Transaction failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("Transaction failed", zap.Error(err))
 			t.Error(w, r, res, err)
 			return
 		}
 
-		go func() {
+		auditReq := helper.AuditRequest(r)
+		goBackground(r.Context(), func(ctx context.Context) {
 			objID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
-			adminUser, err := service.AdminUser.FindByID(objID)
+			adminUser, err := service.AdminUser.FindByID(ctx, objID)
 			if err != nil {
-				l.Logger.Error("log.Admin.Transaction failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.Admin.Transaction failed", zap.Error(err))
 				return
 			}
+			err = service.Audit.Record(
+				ctx,
+				log.Audit.Transfer(
+					adminUser,
+					auditReq,
+					transactionID,
+					f.FromEmail,
+					f.ToEmail,
+					f.Amount,
+					f.Description,
+				),
+			)
+			if err != nil {
+				l.WithContext(ctx).Error("log.Audit.Transfer failed", zap.Error(err))
+			}
 			err = service.UserAction.Log(
+				ctx,
 				log.Admin.Transfer(
 					adminUser,
 					f.FromEmail,
@@ -198,9 +219,9 @@ func (tr *adminTransactionHandler) transaction() func(http.ResponseWriter, *http
 				),
 			)
 			if err != nil {
-				l.Logger.Error("log.Admin.Transaction failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.Admin.Transaction failed", zap.Error(err))
 			}
-		}()
+		})
 
 		flash.Success(
 			w,
@@ -220,31 +241,31 @@ func (tr *adminTransactionHandler) pendingTransactions() func(http.ResponseWrite
 	return func(w http.ResponseWriter, r *http.Request) {
 		q := r.URL.Query()
 
-		user, err := UserHandler.FindByBusinessID(q.Get("business_id"))
+		user, err := UserHandler.FindByBusinessID(r.Context(), q.Get("business_id"))
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"AdminTransactionHandler.pendingTransactions failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
-		account, err := AccountHandler.FindByUserID(user.ID.Hex())
+		account, err := AccountHandler.FindByUserID(r.Context(), user.ID.Hex())
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"AdminTransactionHandler.pendingTransactions failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
-		transactions, err := service.Transaction.FindPendings(account.ID)
+		transactions, err := service.Transaction.FindPendings(r.Context(), account.ID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"AdminTransactionHandler.pendingTransactions failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		res := response{Transactions: transactions}
@@ -296,44 +317,44 @@ func (tr *adminTransactionHandler) cancelTransaction() func(http.ResponseWriter,
 		decoder := json.NewDecoder(r.Body)
 		err := decoder.Decode(&req)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"AdminTransactionHandler.cancelTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		transaction, err := service.Transaction.Find(req.TransactionID)
+		transaction, err := service.Transaction.Find(r.Context(), req.TransactionID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"AdminTransactionHandler.cancelTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
 		shouldContinue, err := tr.isInitiatedStatus(w, transaction)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"AdminTransactionHandler.cancelTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		if !shouldContinue {
 			return
 		}
 
-		err = service.Transaction.Cancel(req.TransactionID, req.Reason)
+		err = service.Transaction.Cancel(r.Context(), req.TransactionID, req.Reason)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"AdminTransactionHandler.cancelTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		w.WriteHeader(http.StatusOK)
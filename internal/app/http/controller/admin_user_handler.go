@@ -1,10 +1,12 @@
 package controller
 
 import (
+	"context"
 	"net/http"
 	"sync"
 
 	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/service"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/cookie"
@@ -17,7 +19,6 @@ import (
 	"github.com/ic3network/mccs-alpha/internal/pkg/recaptcha"
 	"github.com/ic3network/mccs-alpha/internal/pkg/template"
 	"github.com/ic3network/mccs-alpha/internal/pkg/validator"
-	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 )
@@ -61,13 +62,13 @@ func (a *adminUserHandler) dashboardPage() func(http.ResponseWriter, *http.Reque
 	return func(w http.ResponseWriter, r *http.Request) {
 		objID, err := primitive.ObjectIDFromHex(r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Error("AdminDashboardPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("AdminDashboardPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
-		adminUser, err := service.AdminUser.FindByID(objID)
+		adminUser, err := service.AdminUser.FindByID(r.Context(), objID)
 		if err != nil {
-			l.Logger.Error("AdminDashboardPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("AdminDashboardPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -86,7 +87,7 @@ func (a *adminUserHandler) loginPage() func(http.ResponseWriter, *http.Request)
 		t.Render(
 			w,
 			r,
-			formData{RecaptchaSitekey: viper.GetString("recaptcha.site_key")},
+			formData{RecaptchaSitekey: global.Config().Recaptcha.SiteKey},
 			nil,
 		)
 	}
@@ -105,13 +106,13 @@ func (a *adminUserHandler) loginHandler() func(http.ResponseWriter, *http.Reques
 		f := formData{
 			Email:            r.FormValue("email"),
 			Password:         r.FormValue("password"),
-			RecaptchaSitekey: viper.GetString("recaptcha.site_key"),
+			RecaptchaSitekey: global.Config().Recaptcha.SiteKey,
 		}
 
-		if viper.GetString("env") == "production" {
+		if global.Config().IsProduction() {
 			isValid := recaptcha.Verify(*r)
 			if !isValid {
-				l.Logger.Error(
+				l.WithContext(r.Context()).Error(
 					"AdminLoginHandler failed",
 					zap.Strings("errs", recaptcha.Error()),
 				)
@@ -120,15 +121,15 @@ func (a *adminUserHandler) loginHandler() func(http.ResponseWriter, *http.Reques
 			}
 		}
 
-		user, err := service.AdminUser.Login(f.Email, f.Password)
+		user, err := service.AdminUser.Login(r.Context(), f.Email, f.Password)
 		if err != nil {
-			l.Logger.Info("AdminLoginHandler failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("AdminLoginHandler failed", zap.Error(err))
 			t.Error(w, r, f, err)
-			go func() {
-				user, err := service.AdminUser.FindByEmail(f.Email)
+			goBackground(r.Context(), func(ctx context.Context) {
+				user, err := service.AdminUser.FindByEmail(ctx, f.Email)
 				if err != nil {
 					if !e.IsUserNotFound(err) {
-						l.Logger.Error(
+						l.WithContext(ctx).Error(
 							"BuildLoginFailureAction failed",
 							zap.Error(err),
 						)
@@ -136,35 +137,37 @@ func (a *adminUserHandler) loginHandler() func(http.ResponseWriter, *http.Reques
 					return
 				}
 				err = service.UserAction.Log(
+					ctx,
 					log.Admin.LoginFailure(user, ip.FromRequest(r)),
 				)
 				if err != nil {
-					l.Logger.Error(
+					l.WithContext(ctx).Error(
 						"BuildLoginFailureAction failed",
 						zap.Error(err),
 					)
 				}
-			}()
+			})
 			return
 		}
 
 		token, err := jwt.NewJWTManager().Generate(user.ID.Hex(), true)
 		http.SetCookie(w, cookie.CreateCookie(token))
 
-		go func() {
-			err := service.AdminUser.UpdateLoginInfo(user.ID, ip.FromRequest(r))
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := service.AdminUser.UpdateLoginInfo(ctx, user.ID, ip.FromRequest(r))
 			if err != nil {
-				l.Logger.Error("AdminLoginHandler failed", zap.Error(err))
+				l.WithContext(ctx).Error("AdminLoginHandler failed", zap.Error(err))
 			}
-		}()
-		go func() {
+		})
+		goBackground(r.Context(), func(ctx context.Context) {
 			err := service.UserAction.Log(
+				ctx,
 				log.Admin.LoginSuccess(user, ip.FromRequest(r)),
 			)
 			if err != nil {
-				l.Logger.Error("log.Admin.LoginSuccess failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.Admin.LoginSuccess failed", zap.Error(err))
 			}
-		}()
+		})
 
 		http.Redirect(w, r, "/", http.StatusFound)
 	}
@@ -185,9 +188,9 @@ func (a *adminUserHandler) userPage() func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		userID := vars["id"]
-		user, err := UserHandler.FindByID(userID)
+		user, err := UserHandler.FindByID(r.Context(), userID)
 		if err != nil {
-			l.Logger.Error("UserPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("UserPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -205,7 +208,7 @@ func (a *adminUserHandler) updateUser() func(http.ResponseWriter, *http.Request)
 		id := vars["id"]
 		userID, err := primitive.ObjectIDFromHex(id)
 		if err != nil {
-			l.Logger.Error("UpdateUser failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("UpdateUser failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -217,7 +220,7 @@ func (a *adminUserHandler) updateUser() func(http.ResponseWriter, *http.Request)
 		errorMessages := validator.ValidateUser(updateData.User)
 
 		if (r.FormValue("origin_email") != updateData.User.Email) &&
-			service.User.UserEmailExists(updateData.User.Email) {
+			service.User.UserEmailExists(r.Context(), updateData.User.Email) {
 			errorMessages = append(
 				errorMessages,
 				"Email address is already registered",
@@ -225,21 +228,21 @@ func (a *adminUserHandler) updateUser() func(http.ResponseWriter, *http.Request)
 		}
 
 		if len(errorMessages) > 0 {
-			l.Logger.Error("UpdateUser failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("UpdateUser failed", zap.Error(err))
 			t.Render(w, r, updateData, errorMessages)
 			return
 		}
 
-		oldUser, err := service.User.FindByEmail(r.FormValue("origin_email"))
+		oldUser, err := service.User.FindByEmail(r.Context(), r.FormValue("origin_email"))
 		if err != nil {
-			l.Logger.Error("UpdateUser failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("UpdateUser failed", zap.Error(err))
 			t.Error(w, r, updateData, err)
 			return
 		}
 
-		err = service.User.AdminUpdateUser(updateData.User)
+		err = service.User.AdminUpdateUser(r.Context(), updateData.User)
 		if err != nil {
-			l.Logger.Error("UpdateUser failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("UpdateUser failed", zap.Error(err))
 			t.Error(w, r, updateData, err)
 			return
 		}
@@ -250,7 +253,7 @@ func (a *adminUserHandler) updateUser() func(http.ResponseWriter, *http.Request)
 				updateData.ConfirmPassword,
 			)
 			if len(errorMessages) > 0 {
-				l.Logger.Error(
+				l.WithContext(r.Context()).Error(
 					"UpdateUser failed",
 					zap.Strings("input invalid", errorMessages),
 				)
@@ -258,30 +261,40 @@ func (a *adminUserHandler) updateUser() func(http.ResponseWriter, *http.Request)
 				return
 			}
 			err = service.User.ResetPassword(
+				r.Context(),
 				updateData.User.Email,
 				updateData.ConfirmPassword,
 			)
 			if err != nil {
-				l.Logger.Error("UpdateUser failed", zap.Error(err))
+				l.WithContext(r.Context()).Error("UpdateUser failed", zap.Error(err))
 				t.Error(w, r, updateData, err)
 				return
 			}
 		}
 
-		go func() {
+		auditReq := helper.AuditRequest(r)
+		goBackground(r.Context(), func(ctx context.Context) {
 			objID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
-			adminUser, err := service.AdminUser.FindByID(objID)
+			adminUser, err := service.AdminUser.FindByID(ctx, objID)
 			if err != nil {
-				l.Logger.Error("log.Admin.ModifyUser failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.Admin.ModifyUser failed", zap.Error(err))
 				return
 			}
+			err = service.Audit.Record(
+				ctx,
+				log.Audit.ModifyUser(adminUser, auditReq, oldUser, updateData.User),
+			)
+			if err != nil {
+				l.WithContext(ctx).Error("log.Audit.ModifyUser failed", zap.Error(err))
+			}
 			err = service.UserAction.Log(
+				ctx,
 				log.Admin.ModifyUser(adminUser, oldUser, updateData.User),
 			)
 			if err != nil {
-				l.Logger.Error("log.Admin.ModifyUser failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.Admin.ModifyUser failed", zap.Error(err))
 			}
-		}()
+		})
 
 		t.Success(w, r, updateData, "The user has been updated!")
 	}
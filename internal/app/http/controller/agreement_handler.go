@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/internal/app/service"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/flash"
+	"github.com/ic3network/mccs-alpha/internal/pkg/ip"
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/ic3network/mccs-alpha/internal/pkg/log"
+	"github.com/ic3network/mccs-alpha/internal/pkg/template"
+	"go.uber.org/zap"
+)
+
+type agreementHandler struct {
+	once *sync.Once
+}
+
+var AgreementHandler = newAgreementHandler()
+
+func newAgreementHandler() *agreementHandler {
+	return &agreementHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (a *agreementHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	a.once.Do(func() {
+		private.Path("/membership-agreement").
+			HandlerFunc(a.agreementPage()).
+			Methods("GET")
+		private.Path("/membership-agreement").
+			HandlerFunc(a.accept()).
+			Methods("POST")
+	})
+}
+
+type agreementPageData struct {
+	Agreement *types.Agreement
+	// Pending is true when the user has to accept the agreement.
+	Pending bool
+}
+
+// load returns the latest version of the agreement and whether the user
+// has to accept it.
+func (a *agreementHandler) load(
+	r *http.Request,
+) (*agreementPageData, *types.User, *types.Business, error) {
+	user, err := UserHandler.FindByID(r.Context(), r.Header.Get("userID"))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	business, err := service.Business.FindByID(r.Context(), user.CompanyID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	latest, err := service.Agreement.Latest(r.Context())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	pending, err := service.Agreement.Pending(r.Context(), user, business)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return &agreementPageData{
+		Agreement: latest,
+		Pending:   pending != nil,
+	}, user, business, nil
+}
+
+func (a *agreementHandler) agreementPage() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("membership-agreement")
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, _, _, err := a.load(r)
+		if err != nil {
+			l.WithContext(r.Context()).Error("AgreementPage failed", zap.Error(err))
+			t.Error(w, r, nil, err)
+			return
+		}
+		if res.Agreement == nil {
+			http.Redirect(w, r, "https://opencredit.network/membership-agreement/", http.StatusFound)
+			return
+		}
+		t.Render(w, r, res, nil)
+	}
+}
+
+func (a *agreementHandler) accept() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("membership-agreement")
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, user, business, err := a.load(r)
+		if err != nil {
+			l.WithContext(r.Context()).Error("AcceptAgreement failed", zap.Error(err))
+			t.Error(w, r, nil, err)
+			return
+		}
+		if !res.Pending {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+
+		version, _ := strconv.Atoi(r.FormValue("version"))
+		if version != res.Agreement.Version {
+			t.Render(w, r, res, []string{
+				"The Membership Agreement has been updated. Please read and accept the new version.",
+			})
+			return
+		}
+		if r.FormValue("authorised") != "on" {
+			t.Render(w, r, res, []string{
+				"Please confirm that you have read and agree to the Membership Agreement.",
+			})
+			return
+		}
+
+		addr := ip.FromRequest(r)
+		err = service.Agreement.Accept(r.Context(), user.ID, business.ID, version, addr)
+		if err != nil {
+			l.WithContext(r.Context()).Error("AcceptAgreement failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := service.UserAction.Log(ctx, log.User.AcceptAgreement(user, version, addr))
+			if err != nil {
+				l.WithContext(ctx).Error("log.User.AcceptAgreement failed", zap.Error(err))
+			}
+		})
+
+		flash.Success(w, "Thank you for accepting the Membership Agreement.")
+		http.Redirect(w, r, "/", http.StatusFound)
+	}
+}
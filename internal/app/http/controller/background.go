@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ic3network/mccs-alpha/internal/pkg/util"
+)
+
+// background keeps track of the work the handlers start after answering,
+// e.g. the emails and the user action logs, so the shutdown can wait for it.
+var background sync.WaitGroup
+
+// goBackground runs fn in a new goroutine WaitBackground waits for.
+// fn gets ctx detached from its cancellation, the request is over by then.
+func goBackground(ctx context.Context, fn func(ctx context.Context)) {
+	ctx = util.Detach(ctx)
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		fn(ctx)
+	}()
+}
+
+// WaitBackground waits for the work started by the handlers until the
+// context is done. It is called once the server stopped taking requests.
+func WaitBackground(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
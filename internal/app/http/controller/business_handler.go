@@ -1,8 +1,10 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"sync"
 
 	"strconv"
@@ -13,6 +15,7 @@ import (
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/email"
 	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/jsonerror"
 	"github.com/ic3network/mccs-alpha/internal/pkg/l"
 	"github.com/ic3network/mccs-alpha/internal/pkg/template"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -68,36 +71,36 @@ func (b *businessHandler) RegisterRoutes(
 	})
 }
 
-func (b *businessHandler) FindByID(businessID string) (*types.Business, error) {
+func (b *businessHandler) FindByID(ctx context.Context, businessID string) (*types.Business, error) {
 	objID, err := primitive.ObjectIDFromHex(businessID)
 	if err != nil {
 		return nil, err
 	}
-	business, err := service.Business.FindByID(objID)
+	business, err := service.Business.FindByID(ctx, objID)
 	if err != nil {
 		return nil, err
 	}
 	return business, nil
 }
 
-func (b *businessHandler) FindByEmail(email string) (*types.Business, error) {
-	user, err := service.User.FindByEmail(email)
+func (b *businessHandler) FindByEmail(ctx context.Context, email string) (*types.Business, error) {
+	user, err := service.User.FindByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
-	bs, err := service.Business.FindByID(user.CompanyID)
+	bs, err := service.Business.FindByID(ctx, user.CompanyID)
 	if err != nil {
 		return nil, err
 	}
 	return bs, nil
 }
 
-func (b *businessHandler) FindByUserID(uID string) (*types.Business, error) {
-	user, err := UserHandler.FindByID(uID)
+func (b *businessHandler) FindByUserID(ctx context.Context, uID string) (*types.Business, error) {
+	user, err := UserHandler.FindByID(ctx, uID)
 	if err != nil {
 		return nil, err
 	}
-	bs, err := service.Business.FindByID(user.CompanyID)
+	bs, err := service.Business.FindByID(ctx, user.CompanyID)
 	if err != nil {
 		return nil, err
 	}
@@ -111,6 +114,28 @@ type searchBusinessFormData struct {
 	Category              string
 	ShowUserFavoritesOnly bool
 	Page                  int
+	// Facets are the selected facet values keyed by query parameter.
+	Facets url.Values
+}
+
+// Query parameters of the search facets.
+const (
+	facetAdminTags = "admin_tags"
+	facetCountries = "countries"
+	facetCities    = "cities"
+	facetStatuses  = "statuses"
+)
+
+func selectedFacets(q url.Values) url.Values {
+	facets := url.Values{}
+	for _, key := range []string{facetAdminTags, facetCountries, facetCities, facetStatuses} {
+		for _, value := range q[key] {
+			if value != "" {
+				facets.Add(key, value)
+			}
+		}
+	}
+	return facets
 }
 
 type searchBusinessResponse struct {
@@ -124,16 +149,16 @@ type searchBusinessResponse struct {
 func (b *businessHandler) searchBusinessPage() func(http.ResponseWriter, *http.Request) {
 	t := template.NewView("businesses")
 	return func(w http.ResponseWriter, r *http.Request) {
-		adminTags, err := service.AdminTag.GetAll()
+		adminTags, err := service.AdminTag.GetAll(r.Context())
 		if err != nil {
-			l.Logger.Error("SearchBusinessPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("SearchBusinessPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
 		res := searchBusinessResponse{
 			Categories: helper.GetAdminTagNames(adminTags),
 		}
-		_, err = UserHandler.FindByID(r.Header.Get("userID"))
+		_, err = UserHandler.FindByID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
 			res.IsUserLoggedIn = false
 		} else {
@@ -150,7 +175,7 @@ func (b *businessHandler) searchBusiness() func(http.ResponseWriter, *http.Reque
 
 		page, err := strconv.Atoi(q.Get("page"))
 		if err != nil {
-			l.Logger.Error("SearchBusiness failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("SearchBusiness failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -162,10 +187,11 @@ func (b *businessHandler) searchBusiness() func(http.ResponseWriter, *http.Reque
 			Category:              q.Get("category"),
 			ShowUserFavoritesOnly: q.Get("show-favorites-only") == "true",
 			Page:                  page,
+			Facets:                selectedFacets(q),
 		}
 		res := searchBusinessResponse{FormData: f}
 
-		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		user, err := UserHandler.FindByID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
 			res.IsUserLoggedIn = false
 		} else {
@@ -186,18 +212,22 @@ func (b *businessHandler) searchBusiness() func(http.ResponseWriter, *http.Reque
 			AdminTag:              f.Category,
 			ShowUserFavoritesOnly: f.ShowUserFavoritesOnly,
 			FavoriteBusinesses:    res.FavoriteBusinesses,
+			AdminTags:             f.Facets[facetAdminTags],
+			LocationCountries:     f.Facets[facetCountries],
+			LocationCities:        f.Facets[facetCities],
+			FacetStatuses:         f.Facets[facetStatuses],
 		}
-		findResult, err := service.Business.FindBusiness(&c, int64(f.Page))
+		findResult, err := service.Business.FindBusiness(r.Context(), &c, int64(f.Page))
 		res.Result = findResult
 		if err != nil {
-			l.Logger.Error("SearchBusiness failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("SearchBusiness failed", zap.Error(err))
 			t.Error(w, r, res, err)
 			return
 		}
 
-		adminTags, err := service.AdminTag.GetAll()
+		adminTags, err := service.AdminTag.GetAll(r.Context())
 		if err != nil {
-			l.Logger.Error("SearchBusiness failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("SearchBusiness failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -214,13 +244,18 @@ func (b *businessHandler) businessPage() func(http.ResponseWriter, *http.Request
 		BusinessEmail  string
 		Business       *types.Business
 		User           *types.User
+		// Similar businesses offer what this business offers.
+		Similar []*types.Business
+		// Complementary businesses offer what the user wants and want what
+		// the user offers.
+		Complementary []*types.Business
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		bID := vars["id"]
-		business, err := b.FindByID(bID)
+		business, err := b.FindByID(r.Context(), bID)
 		if err != nil {
-			l.Logger.Error("BusinessPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("BusinessPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -229,15 +264,15 @@ func (b *businessHandler) businessPage() func(http.ResponseWriter, *http.Request
 			Business: business,
 		}
 
-		businessUser, err := UserHandler.FindByBusinessID(bID)
+		businessUser, err := UserHandler.FindByBusinessID(r.Context(), bID)
 		if err != nil {
-			l.Logger.Error("BusinessPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("BusinessPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
 		f.BusinessEmail = businessUser.Email
 
-		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		user, err := UserHandler.FindByID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
 			f.IsUserLoggedIn = false
 		} else {
@@ -245,6 +280,34 @@ func (b *businessHandler) businessPage() func(http.ResponseWriter, *http.Request
 			f.User = user
 		}
 
+		// Recommendations are optional, the page is still shown without them.
+		excludeIDs := []string{bID}
+		var viewerBusiness *types.Business
+		if f.IsUserLoggedIn && !user.CompanyID.IsZero() {
+			excludeIDs = append(excludeIDs, user.CompanyID.Hex())
+			viewerBusiness, err = service.Business.FindByID(r.Context(), user.CompanyID)
+			if err != nil {
+				l.WithContext(r.Context()).Error("BusinessPage failed", zap.Error(err))
+			}
+		}
+		f.Similar, err = service.Business.FindSimilar(r.Context(), &types.SimilarCriteria{
+			Offers:     helper.GetTagNames(business.Offers),
+			ExcludeIDs: excludeIDs,
+		})
+		if err != nil {
+			l.WithContext(r.Context()).Error("BusinessPage failed", zap.Error(err))
+		}
+		if viewerBusiness != nil {
+			f.Complementary, err = service.Business.FindSimilar(r.Context(), &types.SimilarCriteria{
+				Offers:     helper.GetTagNames(viewerBusiness.Wants),
+				Wants:      helper.GetTagNames(viewerBusiness.Offers),
+				ExcludeIDs: excludeIDs,
+			})
+			if err != nil {
+				l.WithContext(r.Context()).Error("BusinessPage failed", zap.Error(err))
+			}
+		}
+
 		t.Render(w, r, f, nil)
 	}
 }
@@ -260,25 +323,22 @@ func (b *businessHandler) contactBusiness() func(http.ResponseWriter, *http.Requ
 		decoder := json.NewDecoder(r.Body)
 		err := decoder.Decode(&req)
 		if err != nil {
-			l.Logger.Error("ContactBusiness failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			l.WithContext(r.Context()).Error("ContactBusiness failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		user, err := UserHandler.FindByID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Error("ContactBusiness failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			l.WithContext(r.Context()).Error("ContactBusiness failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		businessOwner, err := UserHandler.FindByBusinessID(req.BusinessID)
+		businessOwner, err := UserHandler.FindByBusinessID(r.Context(), req.BusinessID)
 		if err != nil {
-			l.Logger.Error("ContactBusiness failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			l.WithContext(r.Context()).Error("ContactBusiness failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
@@ -292,9 +352,8 @@ func (b *businessHandler) contactBusiness() func(http.ResponseWriter, *http.Requ
 			req.Body,
 		)
 		if err != nil {
-			l.Logger.Error("ContactBusiness failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			l.WithContext(r.Context()).Error("ContactBusiness failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
@@ -326,27 +385,27 @@ func (b *businessHandler) businessStatus() func(http.ResponseWriter, *http.Reque
 		if q.Get("business_id") != "" {
 			objID, err := primitive.ObjectIDFromHex(q.Get("business_id"))
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(r.Context()).Error(
 					"BusinessHandler.businessStatus failed",
 					zap.Error(err),
 				)
-				w.WriteHeader(http.StatusInternalServerError)
+				jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 				return
 			}
-			business, err = service.Business.FindByID(objID)
+			business, err = service.Business.FindByID(r.Context(), objID)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(r.Context()).Error(
 					"BusinessHandler.businessStatus failed",
 					zap.Error(err),
 				)
-				w.WriteHeader(http.StatusInternalServerError)
+				jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 				return
 			}
 		} else {
-			business, err = BusinessHandler.FindByUserID(r.Header.Get("userID"))
+			business, err = BusinessHandler.FindByUserID(r.Context(), r.Header.Get("userID"))
 			if err != nil {
-				l.Logger.Error("BusinessHandler.businessStatus failed", zap.Error(err))
-				w.WriteHeader(http.StatusInternalServerError)
+				l.WithContext(r.Context()).Error("BusinessHandler.businessStatus failed", zap.Error(err))
+				jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 				return
 			}
 		}
@@ -354,11 +413,11 @@ func (b *businessHandler) businessStatus() func(http.ResponseWriter, *http.Reque
 		res := &response{Status: business.Status}
 		js, err := json.Marshal(res)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"BusinessHandler.businessStatus failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
@@ -374,34 +433,34 @@ func (b *businessHandler) getBusinessName() func(http.ResponseWriter, *http.Requ
 	return func(w http.ResponseWriter, r *http.Request) {
 		q := r.URL.Query()
 
-		user, err := service.User.FindByEmail(q.Get("email"))
+		user, err := service.User.FindByEmail(r.Context(), q.Get("email"))
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"BusinessHandler.getBusinessName failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		business, err := service.Business.FindByID(user.CompanyID)
+		business, err := service.Business.FindByID(r.Context(), user.CompanyID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"BusinessHandler.getBusinessName failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
 		res := response{Name: business.BusinessName}
 		js, err := json.Marshal(res)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"BusinessHandler.getBusinessName failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
@@ -420,31 +479,31 @@ func (b *businessHandler) tradingMemberStatus() func(http.ResponseWriter, *http.
 
 		objID, err := primitive.ObjectIDFromHex(q.Get("business_id"))
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"BusinessHandler.tradingMemberStatus failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		other, err := service.Business.FindByID(objID)
+		other, err := service.Business.FindByID(r.Context(), objID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"BusinessHandler.tradingMemberStatus failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		self, err := BusinessHandler.FindByUserID(r.Header.Get("userID"))
+		self, err := BusinessHandler.FindByUserID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"BusinessHandler.tradingMemberStatus failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
@@ -457,11 +516,11 @@ func (b *businessHandler) tradingMemberStatus() func(http.ResponseWriter, *http.
 		}
 		js, err := json.Marshal(res)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"BusinessHandler.tradingMemberStatus failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
@@ -45,19 +45,21 @@ func (d *dashBoardHandler) dashboardPage() func(http.ResponseWriter, *http.Reque
 		MatchedOffers map[string][]string
 		MatchedWants  map[string][]string
 		Balance       float64
+		// Agreement is the membership agreement the user has to accept.
+		Agreement *types.Agreement
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
-		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		user, err := UserHandler.FindByID(r.Context(), r.Header.Get("userID"))
 
 		if err != nil {
-			l.Logger.Error("DashboardPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("DashboardPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
 
-		business, err := service.Business.FindByID(user.CompanyID)
+		business, err := service.Business.FindByID(r.Context(), user.CompanyID)
 		if err != nil {
-			l.Logger.Error("DashboardPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("DashboardPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -68,20 +70,22 @@ func (d *dashBoardHandler) dashboardPage() func(http.ResponseWriter, *http.Reque
 		}
 
 		matchedOffers, err := service.Tag.MatchOffers(
+			r.Context(),
 			helper.GetTagNames(business.Offers),
 			lastLoginDate,
 		)
 		if err != nil {
-			l.Logger.Error("DashboardPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("DashboardPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
 		matchedWants, err := service.Tag.MatchWants(
+			r.Context(),
 			helper.GetTagNames(business.Wants),
 			lastLoginDate,
 		)
 		if err != nil {
-			l.Logger.Error("DashboardPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("DashboardPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -94,14 +98,19 @@ func (d *dashBoardHandler) dashboardPage() func(http.ResponseWriter, *http.Reque
 		}
 
 		// Get the account balance.
-		account, err := service.Account.FindByBusinessID(user.CompanyID.Hex())
+		account, err := service.Account.FindByBusinessID(r.Context(), user.CompanyID.Hex())
 		if err != nil {
-			l.Logger.Error("DashboardPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("DashboardPage failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
 		res.Balance = account.Balance
 
+		res.Agreement, err = service.Agreement.Pending(r.Context(), user, business)
+		if err != nil {
+			l.WithContext(r.Context()).Error("DashboardPage failed", zap.Error(err))
+		}
+
 		t.Render(w, r, res, nil)
 	}
 }
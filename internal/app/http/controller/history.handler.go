@@ -45,7 +45,7 @@ func (h *historyHandler) historyPage() func(http.ResponseWriter, *http.Request)
 	t := template.NewView("history")
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Only allow access to History screens for users with trading-accepted status
-		business, _ := BusinessHandler.FindByUserID(r.Header.Get("userID"))
+		business, _ := BusinessHandler.FindByUserID(r.Context(), r.Header.Get("userID"))
 		if business.Status != constant.Trading.Accepted {
 			http.Redirect(w, r, "/", http.StatusFound)
 		}
@@ -71,7 +71,7 @@ func (h *historyHandler) searchHistory() func(http.ResponseWriter, *http.Request
 
 		page, err := strconv.Atoi(q.Get("page"))
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"controller.History.HistoryPage failed",
 				zap.Error(err),
 			)
@@ -86,9 +86,9 @@ func (h *historyHandler) searchHistory() func(http.ResponseWriter, *http.Request
 		}
 		res := response{FormData: f}
 
-		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		user, err := UserHandler.FindByID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"controller.History.HistoryPage failed",
 				zap.Error(err),
 			)
@@ -97,9 +97,9 @@ func (h *historyHandler) searchHistory() func(http.ResponseWriter, *http.Request
 		}
 
 		// Get the account balance.
-		account, err := service.Account.FindByBusinessID(user.CompanyID.Hex())
+		account, err := service.Account.FindByBusinessID(r.Context(), user.CompanyID.Hex())
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"controller.History.HistoryPage failed",
 				zap.Error(err),
 			)
@@ -110,13 +110,14 @@ func (h *historyHandler) searchHistory() func(http.ResponseWriter, *http.Request
 
 		// Get the recent transactions.
 		transactions, totalPages, err := service.Transaction.FindInRange(
+			r.Context(),
 			account.ID,
 			util.ParseTime(f.DateFrom),
 			util.ParseTime(f.DateTo),
 			page,
 		)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"controller.History.HistoryPage failed",
 				zap.Error(err),
 			)
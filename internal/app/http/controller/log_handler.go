@@ -2,8 +2,10 @@ package controller
 
 import (
 	"net/http"
+	"net/url"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/ic3network/mccs-alpha/internal/app/service"
@@ -37,64 +39,91 @@ func (lh *logHandler) RegisterRoutes(
 		adminPrivate.Path("/log/search").
 			HandlerFunc(lh.searchLog()).
 			Methods("GET")
+		adminPrivate.Path("/log/audit").
+			HandlerFunc(lh.auditPage()).
+			Methods("GET")
+		adminPrivate.Path("/log/audit/export").
+			HandlerFunc(lh.exportAudit()).
+			Methods("GET")
 	})
 }
 
+type logFormData struct {
+	Email     string
+	DateFrom  string
+	DateTo    string
+	Category  string
+	Details   string
+	Action    string
+	IPAddress string
+	Page      int
+}
+
+type logPageData struct {
+	FormData    logFormData
+	Actions     []string
+	UserActions []*types.UserAction
+	TotalPages  int
+}
+
 func (lh *logHandler) logPage() func(http.ResponseWriter, *http.Request) {
 	t := template.NewView("/admin/log")
 	return func(w http.ResponseWriter, r *http.Request) {
-		t.Render(w, r, nil, nil)
+		actions, err := service.UserAction.Actions(r.Context())
+		if err != nil {
+			l.WithContext(r.Context()).Error("LogPage failed", zap.Error(err))
+		}
+		t.Render(w, r, logPageData{Actions: actions}, nil)
 	}
 }
 
 func (lh *logHandler) searchLog() func(http.ResponseWriter, *http.Request) {
 	t := template.NewView("/admin/log")
-	type formData struct {
-		Email    string
-		DateFrom string
-		DateTo   string
-		Category string
-		Page     int
-	}
-	type response struct {
-		FormData    formData
-		UserActions []*types.UserAction
-		TotalPages  int
-	}
 	return func(w http.ResponseWriter, r *http.Request) {
 		q := r.URL.Query()
 
 		page, err := strconv.Atoi(q.Get("page"))
 		if err != nil {
-			l.Logger.Error("SearchUserLogs failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("SearchUserLogs failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
 
-		f := formData{
-			Email:    q.Get("email"),
-			Category: q.Get("category"),
-			DateFrom: q.Get("date-from"),
-			DateTo:   q.Get("date-to"),
-			Page:     page,
+		f := logFormData{
+			Email:     q.Get("email"),
+			Category:  q.Get("category"),
+			DateFrom:  q.Get("date-from"),
+			DateTo:    q.Get("date-to"),
+			Details:   q.Get("details"),
+			Action:    q.Get("action"),
+			IPAddress: q.Get("ip"),
+			Page:      page,
+		}
+		res := logPageData{FormData: f}
+		res.Actions, err = service.UserAction.Actions(r.Context())
+		if err != nil {
+			l.WithContext(r.Context()).Error("SearchUserLogs failed", zap.Error(err))
 		}
-		res := response{FormData: f}
 
 		c := types.UserActionSearchCriteria{
-			Email:    f.Email,
-			Category: f.Category,
-			DateFrom: util.ParseTime(f.DateFrom),
-			DateTo:   util.ParseTime(f.DateTo),
+			Email:     f.Email,
+			Category:  f.Category,
+			Details:   f.Details,
+			Action:    f.Action,
+			IPAddress: f.IPAddress,
+			DateFrom:  util.ParseTime(f.DateFrom),
+			DateTo:    util.ParseTime(f.DateTo),
 		}
 
 		userAction, totalPages, err := service.UserAction.Find(
+			r.Context(),
 			&c,
 			int64(f.Page),
 		)
 		res.TotalPages = totalPages
 		res.UserActions = userAction
 		if err != nil {
-			l.Logger.Error("SearchUserLogs failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("SearchUserLogs failed", zap.Error(err))
 			t.Error(w, r, res, err)
 			return
 		}
@@ -102,3 +131,79 @@ func (lh *logHandler) searchLog() func(http.ResponseWriter, *http.Request) {
 		t.Render(w, r, res, nil)
 	}
 }
+
+type auditFormData struct {
+	ActorEmail string
+	TargetType string
+	TargetID   string
+	DateFrom   string
+	DateTo     string
+	Page       int
+}
+
+func (f *auditFormData) criteria() *types.AuditSearchCriteria {
+	return &types.AuditSearchCriteria{
+		ActorEmail: f.ActorEmail,
+		TargetType: f.TargetType,
+		TargetID:   f.TargetID,
+		DateFrom:   util.ParseTime(f.DateFrom),
+		DateTo:     util.ParseTime(f.DateTo),
+	}
+}
+
+func auditForm(r *http.Request) *auditFormData {
+	q := r.URL.Query()
+	page, err := strconv.Atoi(q.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	return &auditFormData{
+		ActorEmail: q.Get("actor"),
+		TargetType: q.Get("target-type"),
+		TargetID:   q.Get("target-id"),
+		DateFrom:   q.Get("date-from"),
+		DateTo:     q.Get("date-to"),
+		Page:       page,
+	}
+}
+
+func (lh *logHandler) auditPage() func(http.ResponseWriter, *http.Request) {
+	t := template.NewView("/admin/audit-log")
+	type response struct {
+		FormData    *auditFormData
+		Query       url.Values
+		AuditEvents []*types.AuditEvent
+		TotalPages  int
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		f := auditForm(r)
+		q := r.URL.Query()
+		q.Del("page")
+		res := response{FormData: f, Query: q}
+
+		events, totalPages, err := service.Audit.Find(r.Context(), f.criteria(), int64(f.Page))
+		if err != nil {
+			l.WithContext(r.Context()).Error("AuditPage failed", zap.Error(err))
+			t.Error(w, r, res, err)
+			return
+		}
+		res.AuditEvents = events
+		res.TotalPages = totalPages
+
+		t.Render(w, r, res, nil)
+	}
+}
+
+func (lh *logHandler) exportAudit() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f := auditForm(r)
+		fileName := "audit-" + time.Now().Format("20060102") + ".jsonl"
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", "attachment; filename="+fileName)
+		err := service.Audit.Export(r.Context(), f.criteria(), w)
+		if err != nil {
+			// The response has already started, the file ends early.
+			l.WithContext(r.Context()).Error("ExportAudit failed", zap.Error(err))
+		}
+	}
+}
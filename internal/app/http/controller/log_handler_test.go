@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditForm(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		form     *auditFormData
+		criteria *types.AuditSearchCriteria
+	}{
+		{
+			"should default to the first page",
+			"",
+			&auditFormData{Page: 1},
+			&types.AuditSearchCriteria{},
+		},
+		{
+			"should ignore an invalid page",
+			"?page=-2",
+			&auditFormData{Page: 1},
+			&types.AuditSearchCriteria{},
+		},
+		{
+			"should parse every filter",
+			"?actor=admin%40ocn.test&target-type=business&target-id=abc" +
+				"&date-from=2020-03-01&date-to=2020-03-31&page=3",
+			&auditFormData{
+				ActorEmail: "admin@ocn.test",
+				TargetType: "business",
+				TargetID:   "abc",
+				DateFrom:   "2020-03-01",
+				DateTo:     "2020-03-31",
+				Page:       3,
+			},
+			&types.AuditSearchCriteria{
+				ActorEmail: "admin@ocn.test",
+				TargetType: "business",
+				TargetID:   "abc",
+				DateFrom:   time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+				DateTo:     time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			"should ignore an invalid date",
+			"?date-from=yesterday",
+			&auditFormData{DateFrom: "yesterday", Page: 1},
+			&types.AuditSearchCriteria{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/admin/audit-log"+tt.query, nil)
+			form := auditForm(r)
+			assert.Equal(t, tt.form, form)
+			assert.Equal(t, tt.criteria, form.criteria())
+		})
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/internal/app/service"
+	"github.com/ic3network/mccs-alpha/internal/pkg/metrics"
 	"github.com/shirou/gopsutil/cpu"
 	"github.com/shirou/gopsutil/disk"
 	"github.com/shirou/gopsutil/load"
 	"github.com/shirou/gopsutil/mem"
+	"github.com/unrolled/render"
 )
 
 type serviceDiscovery struct {
@@ -35,6 +39,12 @@ func (s *serviceDiscovery) RegisterRoutes(
 		public.Path("/disk").HandlerFunc(s.diskCheck).Methods("GET")
 		public.Path("/cpu").HandlerFunc(s.cpuCheck).Methods("GET")
 		public.Path("/ram").HandlerFunc(s.ramCheck).Methods("GET")
+		public.Path("/es-lag").HandlerFunc(s.esLagCheck).Methods("GET")
+		public.Path("/livez").HandlerFunc(s.liveness).Methods("GET")
+		public.Path("/readyz").HandlerFunc(s.readiness).Methods("GET")
+		metrics.RegisterOutstandingCredit(service.Analytics.OutstandingCredit)
+		metrics.RegisterOutbox(service.Outbox.Stats)
+		public.Path("/metrics").Handler(metrics.Handler()).Methods("GET")
 	})
 }
 
@@ -52,6 +62,23 @@ func (s *serviceDiscovery) healthCheck(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("\n" + message))
 }
 
+// Liveness only tells that the process is serving requests, the stores
+// are checked by the readiness probe.
+func (s *serviceDiscovery) liveness(w http.ResponseWriter, r *http.Request) {
+	render.New().JSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Readiness reports the status of every store, the instance is not ready
+// when any of them is unavailable.
+func (s *serviceDiscovery) readiness(w http.ResponseWriter, r *http.Request) {
+	result := service.Health.Readiness(r.Context())
+	status := http.StatusOK
+	if !result.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	render.New().JSON(w, status, result)
+}
+
 // DiskCheck checks the disk usage.
 func (s *serviceDiscovery) diskCheck(w http.ResponseWriter, r *http.Request) {
 	u, _ := disk.Usage("/")
@@ -151,3 +178,36 @@ func (s *serviceDiscovery) ramCheck(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(status)
 	w.Write([]byte("\n" + message))
 }
+
+// ESLagCheck checks how far Elasticsearch is behind MongoDB.
+func (s *serviceDiscovery) esLagCheck(w http.ResponseWriter, r *http.Request) {
+	stats, err := service.Outbox.Stats(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("\nCRITICAL - " + err.Error()))
+		return
+	}
+
+	lag := stats.Lag()
+
+	status := http.StatusOK
+	text := "OK"
+
+	if lag >= 10*time.Minute {
+		status = http.StatusInternalServerError
+		text = "CRITICAL"
+	} else if lag >= time.Minute || stats.DeadLetters != 0 {
+		status = http.StatusTooManyRequests
+		text = "WARNING"
+	}
+
+	message := fmt.Sprintf(
+		"%s - Pending events: %d | Lag: %.0fs | Dead letters: %d",
+		text,
+		stats.Pending,
+		lag.Seconds(),
+		stats.DeadLetters,
+	)
+	w.WriteHeader(status)
+	w.Write([]byte("\n" + message))
+}
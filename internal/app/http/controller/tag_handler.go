@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -10,7 +11,9 @@ import (
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 
 	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
 	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/jsonerror"
 	"github.com/ic3network/mccs-alpha/internal/pkg/l"
 	"github.com/ic3network/mccs-alpha/internal/pkg/log"
 	"github.com/ic3network/mccs-alpha/internal/pkg/template"
@@ -42,6 +45,9 @@ func (h *tagHandler) RegisterRoutes(
 			HandlerFunc(h.searchTags()).
 			Methods("GET")
 
+		public.Path("/api/tags/{tagType:offers|wants}/{prefix}").
+			HandlerFunc(h.suggestTags()).
+			Methods("GET")
 		public.Path("/api/tags/{tagName}").
 			HandlerFunc(h.getTagSuggestions()).
 			Methods("GET")
@@ -57,10 +63,10 @@ func (h *tagHandler) RegisterRoutes(
 	})
 }
 
-func (h *tagHandler) SaveOfferTags(added []string) error {
+func (h *tagHandler) SaveOfferTags(ctx context.Context, added []string) error {
 	for _, tagName := range added {
 		// TODO: UpdateOffers
-		err := service.Tag.UpdateOffer(tagName)
+		err := service.Tag.UpdateOffer(ctx, tagName)
 		if err != nil {
 			return err
 		}
@@ -68,10 +74,10 @@ func (h *tagHandler) SaveOfferTags(added []string) error {
 	return nil
 }
 
-func (h *tagHandler) SaveWantTags(added []string) error {
+func (h *tagHandler) SaveWantTags(ctx context.Context, added []string) error {
 	for _, tagName := range added {
 		// TODO: UpdateWants
-		err := service.Tag.UpdateWant(tagName)
+		err := service.Tag.UpdateWant(ctx, tagName)
 		if err != nil {
 			return err
 		}
@@ -100,10 +106,10 @@ func (h *tagHandler) getTagSuggestions() func(http.ResponseWriter, *http.Request
 		vars := mux.Vars(r)
 		tagName := vars["tagName"]
 
-		findResult, err := service.Tag.FindTags(tagName, int64(1))
+		findResult, err := service.Tag.FindTags(r.Context(), tagName, int64(1))
 		if err != nil {
-			l.Logger.Error("GetTagSuggestions failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("GetTagSuggestions failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
@@ -123,8 +129,56 @@ func (h *tagHandler) getTagSuggestions() func(http.ResponseWriter, *http.Request
 
 		js, err := json.Marshal(res)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(js)
+	}
+}
+
+// suggestTags autocompletes the offers and wants of the business forms.
+// The tags used by the most trading members come first.
+func (h *tagHandler) suggestTags() func(http.ResponseWriter, *http.Request) {
+	type result struct {
+		Name  string `json:"name,omitempty"`
+		Value string `json:"value,omitempty"`
+		Text  string `json:"text,omitempty"`
+		Count int    `json:"count"`
+	}
+	type response struct {
+		Success bool     `json:"success,omitempty"`
+		Results []result `json:"results"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		suggestions, err := service.Tag.Suggest(r.Context(), vars["tagType"], vars["prefix"])
+		if err != nil {
+			l.WithContext(r.Context()).Error("SuggestTags failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
+			return
+		}
+
+		results := make([]result, 0, len(suggestions))
+		for _, s := range suggestions {
+			results = append(results, result{
+				Name:  s.Name + " (" + strconv.Itoa(s.Count) + ")",
+				Value: s.Name,
+				Text:  s.Name,
+				Count: s.Count,
+			})
+		}
+
+		res := response{
+			Success: true,
+			Results: results,
+		}
+
+		js, err := json.Marshal(res)
+		if err != nil {
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
+			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -142,56 +196,65 @@ func (h *tagHandler) createTag() func(http.ResponseWriter, *http.Request) {
 		decoder := json.NewDecoder(r.Body)
 		err := decoder.Decode(&req)
 		if err != nil {
-			l.Logger.Error("CreateTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			l.WithContext(r.Context()).Error("CreateTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
 		if req.Name == "" {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Please enter the tag name"))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.New(e.InvalidInput, "Please enter the tag name."))
 			return
 		}
 
 		tagNames := helper.GetTags(req.Name)
 		if len(tagNames) == 0 {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Please enter a valid tag name"))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.New(e.InvalidInput, "Please enter a valid tag name."))
 			return
 		}
 
 		tagName := tagNames[0].Name
-		_, err = service.Tag.FindByName(tagName)
+		_, err = service.Tag.FindByName(r.Context(), tagName)
 		if err == nil {
-			l.Logger.Info("[CreateTag] failed: Tag already exists")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Tag already exists!"))
+			l.WithContext(r.Context()).Info("[CreateTag] failed: Tag already exists")
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.New(e.TagExisted, e.Msg[e.TagExisted]))
 			return
 		}
 
-		err = service.Tag.Create(tagName)
+		err = service.Tag.Create(r.Context(), tagName)
 		if err != nil {
-			l.Logger.Error("CreateTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			l.WithContext(r.Context()).Error("CreateTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		go func() {
+		auditReq := helper.AuditRequest(r)
+		goBackground(r.Context(), func(ctx context.Context) {
 			objID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
-			adminUser, err := service.AdminUser.FindByID(objID)
+			adminUser, err := service.AdminUser.FindByID(ctx, objID)
 			if err != nil {
-				l.Logger.Error("log.Admin.CreateTag failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.Admin.CreateTag failed", zap.Error(err))
 				return
 			}
+			tag, err := service.Tag.FindByName(ctx, tagName)
+			if err != nil {
+				l.WithContext(ctx).Error("log.Audit.CreateTag failed", zap.Error(err))
+			} else {
+				err = service.Audit.Record(
+					ctx,
+					log.Audit.CreateTag(adminUser, auditReq, tag),
+				)
+				if err != nil {
+					l.WithContext(ctx).Error("log.Audit.CreateTag failed", zap.Error(err))
+				}
+			}
 			err = service.UserAction.Log(
+				ctx,
 				log.Admin.CreateTag(adminUser, tagName),
 			)
 			if err != nil {
-				l.Logger.Error("log.Admin.CreateTag failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.Admin.CreateTag failed", zap.Error(err))
 			}
-		}()
+		})
 
 		w.WriteHeader(http.StatusCreated)
 	}
@@ -210,7 +273,7 @@ func (h *tagHandler) searchTags() func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		page, err := strconv.Atoi(r.URL.Query().Get("page"))
 		if err != nil {
-			l.Logger.Error("SearchTags failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("SearchTags failed", zap.Error(err))
 			t.Error(w, r, nil, err)
 			return
 		}
@@ -226,9 +289,9 @@ func (h *tagHandler) searchTags() func(http.ResponseWriter, *http.Request) {
 			return
 		}
 
-		findResult, err := service.Tag.FindTags(f.Name, int64(f.Page))
+		findResult, err := service.Tag.FindTags(r.Context(), f.Name, int64(f.Page))
 		if err != nil {
-			l.Logger.Error("SearchTags failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("SearchTags failed", zap.Error(err))
 			t.Error(w, r, res, err)
 			return
 		}
@@ -249,76 +312,79 @@ func (h *tagHandler) renameTag() func(http.ResponseWriter, *http.Request) {
 		decoder := json.NewDecoder(r.Body)
 		err := decoder.Decode(&req)
 		if err != nil {
-			l.Logger.Error("RenameTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			l.WithContext(r.Context()).Error("RenameTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
 		if req.Name == "" {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Please enter the tag name"))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.New(e.InvalidInput, "Please enter the tag name."))
 			return
 		}
 
-		_, err = service.Tag.FindByName(req.Name)
+		_, err = service.Tag.FindByName(r.Context(), req.Name)
 		if err == nil {
-			l.Logger.Info("[RenameTag] failed: Tag already exists")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Tag already exists!"))
+			l.WithContext(r.Context()).Info("[RenameTag] failed: Tag already exists")
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.New(e.TagExisted, e.Msg[e.TagExisted]))
 			return
 		}
 
 		tagID, err := primitive.ObjectIDFromHex(req.ID)
 		if err != nil {
-			l.Logger.Error("RenameTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			l.WithContext(r.Context()).Error("RenameTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		tag, err := service.Tag.FindByID(tagID)
+		tag, err := service.Tag.FindByID(r.Context(), tagID)
 		if err != nil {
-			l.Logger.Error("RenameTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Tag not found."))
+			l.WithContext(r.Context()).Error("RenameTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.New(e.TagNotFound, e.Msg[e.TagNotFound]))
 			return
 		}
 		oldName := tag.Name
 
-		go func() {
-			err := service.Business.RenameTag(oldName, req.Name)
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := service.Business.RenameTag(ctx, oldName, req.Name)
 			if err != nil {
-				l.Logger.Error("RenameTag failed", zap.Error(err))
+				l.WithContext(ctx).Error("RenameTag failed", zap.Error(err))
 			}
-		}()
+		})
 
 		tag = &types.Tag{
 			ID:   tagID,
 			Name: req.Name,
 		}
-		err = service.Tag.Rename(tag)
+		err = service.Tag.Rename(r.Context(), tag)
 		if err != nil {
-			l.Logger.Error("RenameTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			l.WithContext(r.Context()).Error("RenameTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		go func() {
+		auditReq := helper.AuditRequest(r)
+		goBackground(r.Context(), func(ctx context.Context) {
 			objID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
-			adminUser, err := service.AdminUser.FindByID(objID)
+			adminUser, err := service.AdminUser.FindByID(ctx, objID)
 			if err != nil {
-				l.Logger.Error("log.Admin.ModifyTag failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.Admin.ModifyTag failed", zap.Error(err))
 				return
 			}
+			err = service.Audit.Record(
+				ctx,
+				log.Audit.ModifyTag(adminUser, auditReq, tagID, oldName, req.Name),
+			)
+			if err != nil {
+				l.WithContext(ctx).Error("log.Audit.ModifyTag failed", zap.Error(err))
+			}
 			err = service.UserAction.Log(
+				ctx,
 				log.Admin.ModifyTag(adminUser, oldName, req.Name),
 			)
 			if err != nil {
-				l.Logger.Error("log.Admin.ModifyTag failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.Admin.ModifyTag failed", zap.Error(err))
 			}
-		}()
+		})
 
 		w.WriteHeader(http.StatusCreated)
 	}
@@ -330,45 +396,54 @@ func (h *tagHandler) deleteTag() func(http.ResponseWriter, *http.Request) {
 		id := vars["id"]
 		tagID, err := primitive.ObjectIDFromHex(id)
 		if err != nil {
-			l.Logger.Error("DeleteTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("DeleteTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		tag, err := service.Tag.FindByID(tagID)
+		tag, err := service.Tag.FindByID(r.Context(), tagID)
 		if err != nil {
-			l.Logger.Error("DeleteTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("DeleteTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		err = service.Tag.DeleteByID(tagID)
+		err = service.Tag.DeleteByID(r.Context(), tagID)
 		if err != nil {
-			l.Logger.Error("DeleteTag failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("DeleteTag failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		go func() {
-			err := service.Business.DeleteTag(tag.Name)
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := service.Business.DeleteTag(ctx, tag.Name)
 			if err != nil {
-				l.Logger.Error("DeleteTag failed", zap.Error(err))
+				l.WithContext(ctx).Error("DeleteTag failed", zap.Error(err))
 			}
-		}()
-		go func() {
+		})
+		auditReq := helper.AuditRequest(r)
+		goBackground(r.Context(), func(ctx context.Context) {
 			objID, _ := primitive.ObjectIDFromHex(r.Header.Get("userID"))
-			adminUser, err := service.AdminUser.FindByID(objID)
+			adminUser, err := service.AdminUser.FindByID(ctx, objID)
 			if err != nil {
-				l.Logger.Error("log.Admin.DeleteTag failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.Admin.DeleteTag failed", zap.Error(err))
 				return
 			}
+			err = service.Audit.Record(
+				ctx,
+				log.Audit.DeleteTag(adminUser, auditReq, tag),
+			)
+			if err != nil {
+				l.WithContext(ctx).Error("log.Audit.DeleteTag failed", zap.Error(err))
+			}
 			err = service.UserAction.Log(
+				ctx,
 				log.Admin.DeleteTag(adminUser, tag.Name),
 			)
 			if err != nil {
-				l.Logger.Error("log.Admin.DeleteTag failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.Admin.DeleteTag failed", zap.Error(err))
 			}
-		}()
+		})
 
 		w.WriteHeader(http.StatusOK)
 	}
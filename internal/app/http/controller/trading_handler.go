@@ -1,20 +1,24 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"sync"
 
 	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/global/constant"
 	"github.com/ic3network/mccs-alpha/internal/app/service"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/email"
 	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/ip"
+	"github.com/ic3network/mccs-alpha/internal/pkg/jsonerror"
 	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/ic3network/mccs-alpha/internal/pkg/log"
 	"github.com/ic3network/mccs-alpha/internal/pkg/recaptcha"
 	"github.com/ic3network/mccs-alpha/internal/pkg/template"
-	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
 
@@ -48,16 +52,30 @@ func (th *tradingHandler) RegisterRoutes(
 	})
 }
 
+// canApply reports whether the business can submit a trading membership
+// application: it is listed in the directory or the admins asked for more
+// information about its application.
+func (th *tradingHandler) canApply(ctx context.Context, business *types.Business) bool {
+	if business.Status == constant.Business.Accepted {
+		return true
+	}
+	if business.Status != constant.Trading.Pending {
+		return false
+	}
+	application, err := service.TradingApplication.FindOpenByBusinessID(ctx, business.ID)
+	return err == nil &&
+		application.Status == constant.TradingApplication.InfoRequested
+}
+
 func (th *tradingHandler) signupPage() func(http.ResponseWriter, *http.Request) {
 	t := template.NewView("member-signup")
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Check if the business has the status of "accepted".
-		business, err := BusinessHandler.FindByUserID(r.Header.Get("userID"))
-		if err != nil || business.Status != constant.Business.Accepted {
+		business, err := BusinessHandler.FindByUserID(r.Context(), r.Header.Get("userID"))
+		if err != nil || !th.canApply(r.Context(), business) {
 			http.Redirect(w, r, "/", http.StatusFound)
 			return
 		}
-		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		user, err := UserHandler.FindByID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
 			http.Redirect(w, r, "/", http.StatusFound)
 			return
@@ -79,7 +97,11 @@ func (th *tradingHandler) signupPage() func(http.ResponseWriter, *http.Request)
 			LastName:           user.LastName,
 			Telephone:          user.Telephone,
 		}
-		data.RecaptchaSitekey = viper.GetString("recaptcha.site_key")
+		data.RecaptchaSitekey = global.Config().Recaptcha.SiteKey
+		data.Agreement, err = service.Agreement.Latest(r.Context())
+		if err != nil {
+			l.WithContext(r.Context()).Error("TradingHandler.SignupPage failed", zap.Error(err))
+		}
 		t.Render(w, r, data, nil)
 	}
 }
@@ -91,16 +113,29 @@ func (th *tradingHandler) signup() func(http.ResponseWriter, *http.Request) {
 
 		// Validate user inputs.
 		data := helper.Trading.GetRegisterData(r)
-		data.RecaptchaSitekey = viper.GetString("recaptcha.site_key")
+		data.RecaptchaSitekey = global.Config().Recaptcha.SiteKey
 		errorMessages := data.Validate()
-		if viper.GetString("env") == "production" {
+		agreement, err := service.Agreement.Latest(r.Context())
+		if err != nil {
+			l.WithContext(r.Context()).Info("TradingHandler.Signup failed", zap.Error(err))
+			t.Error(w, r, data, err)
+			return
+		}
+		data.Agreement = agreement
+		if agreement != nil && data.AgreementVersion != agreement.Version {
+			errorMessages = append(
+				errorMessages,
+				"The Membership Agreement has been updated. Please read and accept the new version.",
+			)
+		}
+		if global.Config().IsProduction() {
 			isValid := recaptcha.Verify(*r)
 			if !isValid {
 				errorMessages = append(errorMessages, recaptcha.Error()...)
 			}
 		}
 		if len(errorMessages) > 0 {
-			l.Logger.Info(
+			l.WithContext(r.Context()).Info(
 				"TradingHandler.Signup failed",
 				zap.Strings("input invalid", errorMessages),
 			)
@@ -108,55 +143,96 @@ func (th *tradingHandler) signup() func(http.ResponseWriter, *http.Request) {
 			return
 		}
 
-		// Update business collection.
-		business, err := BusinessHandler.FindByUserID(r.Header.Get("userID"))
+		business, err := BusinessHandler.FindByUserID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Info("TradingHandler.Signup failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("TradingHandler.Signup failed", zap.Error(err))
 			t.Error(w, r, data, err)
 			return
 		}
-		err = service.Trading.UpdateBusiness(business.ID, data)
+		if !th.canApply(r.Context(), business) {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		user, err := UserHandler.FindByID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Info("TradingHandler.Signup failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("TradingHandler.Signup failed", zap.Error(err))
 			t.Error(w, r, data, err)
 			return
 		}
 
-		// Update user collection.
-		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		// The agreement is accepted before the application is recorded so
+		// an approved member can always trade.
+		if agreement != nil {
+			addr := ip.FromRequest(r)
+			err = service.Agreement.Accept(
+				r.Context(),
+				user.ID,
+				business.ID,
+				agreement.Version,
+				addr,
+			)
+			if err != nil {
+				l.WithContext(r.Context()).Error("TradingHandler.Signup failed", zap.Error(err))
+				t.Error(w, r, data, err)
+				return
+			}
+			u := *user
+			goBackground(r.Context(), func(ctx context.Context) {
+				err := service.UserAction.Log(
+					ctx,
+					log.User.AcceptAgreement(&u, agreement.Version, addr),
+				)
+				if err != nil {
+					l.WithContext(ctx).Error("log.User.AcceptAgreement failed", zap.Error(err))
+				}
+			})
+		}
+
+		// Record the application before the changes are saved.
+		err = service.TradingApplication.Submit(r.Context(), business, user, data)
 		if err != nil {
-			l.Logger.Info("TradingHandler.Signup failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("TradingHandler.Signup failed", zap.Error(err))
 			t.Error(w, r, data, err)
 			return
 		}
-		err = service.Trading.UpdateUser(user.ID, data)
+
+		// Update business collection.
+		err = service.Trading.UpdateBusiness(r.Context(), business.ID, data)
+		if err != nil {
+			l.WithContext(r.Context()).Info("TradingHandler.Signup failed", zap.Error(err))
+			t.Error(w, r, data, err)
+			return
+		}
+
+		// Update user collection.
+		err = service.Trading.UpdateUser(r.Context(), user.ID, data)
 		if err != nil {
-			l.Logger.Info("TradingHandler.Signup failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("TradingHandler.Signup failed", zap.Error(err))
 			t.Error(w, r, data, err)
 			return
 		}
 
 		// Send thank you email to the User's email address.
-		go func() {
+		goBackground(r.Context(), func(ctx context.Context) {
 			err := email.SendThankYouEmail(
 				data.FirstName,
 				data.LastName,
 				user.Email,
 			)
 			if err != nil {
-				l.Logger.Error("email.SendThankYouEmail failed", zap.Error(err))
+				l.WithContext(ctx).Error("email.SendThankYouEmail failed", zap.Error(err))
 			}
-		}()
+		})
 		// Send the to the OCN Admin email address.
-		go func() {
+		goBackground(r.Context(), func(ctx context.Context) {
 			err := email.SendNewMemberSignupEmail(data.BusinessName, user.Email)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"email.SendNewMemberSignupEmail failed",
 					zap.Error(err),
 				)
 			}
-		}()
+		})
 
 		http.Redirect(w, r, "/", http.StatusFound)
 	}
@@ -167,17 +243,17 @@ func (th *tradingHandler) isMember() func(http.ResponseWriter, *http.Request) {
 		IsMember bool
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
-		business, err := BusinessHandler.FindByUserID(r.Header.Get("userID"))
+		business, err := BusinessHandler.FindByUserID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Error("TradingHandler.IsMember failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("TradingHandler.IsMember failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		res := response{IsMember: business.Status == constant.Trading.Accepted}
 		js, err := json.Marshal(res)
 		if err != nil {
-			l.Logger.Error("TradingHandler.IsMember failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("TradingHandler.IsMember failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -12,13 +13,14 @@ import (
 	"github.com/ic3network/mccs-alpha/global/constant"
 	"github.com/ic3network/mccs-alpha/internal/app/service"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
 	"github.com/ic3network/mccs-alpha/internal/pkg/email"
 	"github.com/ic3network/mccs-alpha/internal/pkg/flash"
 	"github.com/ic3network/mccs-alpha/internal/pkg/jsonerror"
 	"github.com/ic3network/mccs-alpha/internal/pkg/l"
 	"github.com/ic3network/mccs-alpha/internal/pkg/log"
+	"github.com/ic3network/mccs-alpha/internal/pkg/metrics"
 	"github.com/ic3network/mccs-alpha/internal/pkg/template"
-	"github.com/unrolled/render"
 
 	"github.com/ic3network/mccs-alpha/internal/pkg/util"
 	"go.uber.org/zap"
@@ -84,11 +86,11 @@ func (tr *transactionHandler) getMaxNegBal(
 	res *response,
 ) error {
 	// Get the user max negative balance.
-	account, err := AccountHandler.FindByUserID(r.Header.Get("userID"))
+	account, err := AccountHandler.FindByUserID(r.Context(), r.Header.Get("userID"))
 	if err != nil {
 		return err
 	}
-	maxNegBalance, err := service.BalanceLimit.GetMaxNegBalance(account.ID)
+	maxNegBalance, err := service.BalanceLimit.GetMaxNegBalance(r.Context(), account.ID)
 	if err != nil {
 		return err
 	}
@@ -124,15 +126,19 @@ func (tr *transactionHandler) proposeTransactionPage() func(http.ResponseWriter,
 	t := template.NewView("transaction")
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Only allow access to Transfer screens for users with trading-accepted status
-		business, _ := BusinessHandler.FindByUserID(r.Header.Get("userID"))
+		business, _ := BusinessHandler.FindByUserID(r.Context(), r.Header.Get("userID"))
 		if business.Status != constant.Trading.Accepted {
 			http.Redirect(w, r, "/", http.StatusFound)
+			return
 		}
-
 		res := response{}
+		if !tr.agreementAccepted(w, r, t, res) {
+			return
+		}
+
 		err := tr.getMaxNegBal(r, &res)
 		if err != nil {
-			l.Logger.Info("Transfer failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("Transfer failed", zap.Error(err))
 			t.Error(w, r, res, err)
 			return
 		}
@@ -141,6 +147,43 @@ func (tr *transactionHandler) proposeTransactionPage() func(http.ResponseWriter,
 	}
 }
 
+// canTrade reports whether the user has accepted the membership agreement
+// when the latest version is required for trading.
+func (tr *transactionHandler) canTrade(r *http.Request) (bool, error) {
+	user, err := UserHandler.FindByID(r.Context(), r.Header.Get("userID"))
+	if err != nil {
+		return false, err
+	}
+	business, err := service.Business.FindByID(r.Context(), user.CompanyID)
+	if err != nil {
+		return false, err
+	}
+	return service.Agreement.CanTrade(r.Context(), user, business)
+}
+
+// agreementAccepted redirects the user to the membership agreement when it
+// has to be accepted before trading. A failed lookup shows the error page.
+// The response is written when it returns false.
+func (tr *transactionHandler) agreementAccepted(
+	w http.ResponseWriter,
+	r *http.Request,
+	t *template.View,
+	res response,
+) bool {
+	canTrade, err := tr.canTrade(r)
+	if err != nil {
+		l.WithContext(r.Context()).Error("Transfer failed", zap.Error(err))
+		t.Error(w, r, res, err)
+		return false
+	}
+	if !canTrade {
+		flash.Info(w, e.Msg[e.AgreementNotAccepted])
+		http.Redirect(w, r, "/membership-agreement", http.StatusFound)
+		return false
+	}
+	return true
+}
+
 type proposeInfo struct {
 	FromID,
 	FromEmail,
@@ -204,9 +247,13 @@ func (tr *transactionHandler) proposeTransaction() func(http.ResponseWriter, *ht
 		}
 
 		res := response{FormData: f}
+		if !tr.agreementAccepted(w, r, t, res) {
+			return
+		}
+
 		err := tr.getMaxNegBal(r, &res)
 		if err != nil {
-			l.Logger.Info("Transfer failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("Transfer failed", zap.Error(err))
 			t.Error(w, r, res, err)
 			return
 		}
@@ -235,22 +282,22 @@ func (tr *transactionHandler) proposeTransaction() func(http.ResponseWriter, *ht
 		}
 		f.Amount = amount
 
-		initiator, err := UserHandler.FindByID(r.Header.Get("userID"))
+		initiator, err := UserHandler.FindByID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Info("Transfer failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("Transfer failed", zap.Error(err))
 			t.Error(w, r, res, err)
 			return
 		}
 		// Decide the initiator and receiver.
-		initiatorBusiness, err := service.Business.FindByID(initiator.CompanyID)
+		initiatorBusiness, err := service.Business.FindByID(r.Context(), initiator.CompanyID)
 		if err != nil {
-			l.Logger.Info("Transfer failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("Transfer failed", zap.Error(err))
 			t.Error(w, r, res, err)
 			return
 		}
-		receiverBusiness, err := BusinessHandler.FindByEmail(f.Email)
+		receiverBusiness, err := BusinessHandler.FindByEmail(r.Context(), f.Email)
 		if err != nil {
-			l.Logger.Info("Transfer failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("Transfer failed", zap.Error(err))
 			t.Error(w, r, res, err)
 			return
 		}
@@ -288,6 +335,7 @@ func (tr *transactionHandler) proposeTransaction() func(http.ResponseWriter, *ht
 		}
 
 		transaction, err := service.Transaction.Propose(
+			r.Context(),
 			initiator.CompanyID.Hex(),
 			proposeInfo.FromID,
 			proposeInfo.FromEmail,
@@ -299,10 +347,11 @@ func (tr *transactionHandler) proposeTransaction() func(http.ResponseWriter, *ht
 			f.Description,
 		)
 		if err != nil {
-			l.Logger.Info("Proposed failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("Proposed failed", zap.Error(err))
 			t.Error(w, r, res, err)
 			return
 		}
+		metrics.Transfer(metrics.TransferProposed)
 		flash.Success(
 			w,
 			"You have proposed a transfer of "+fmt.Sprintf(
@@ -312,8 +361,8 @@ func (tr *transactionHandler) proposeTransaction() func(http.ResponseWriter, *ht
 		)
 		http.Redirect(w, r, "/#transactions", http.StatusFound)
 
-		go func() {
-			err := service.UserAction.Log(log.User.ProposeTransfer(
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := service.UserAction.Log(ctx, log.User.ProposeTransfer(
 				initiator,
 				proposeInfo.FromEmail,
 				proposeInfo.ToEmail,
@@ -321,18 +370,18 @@ func (tr *transactionHandler) proposeTransaction() func(http.ResponseWriter, *ht
 				f.Description,
 			))
 			if err != nil {
-				l.Logger.Error("log.User.Transfer failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.User.Transfer failed", zap.Error(err))
 			}
-		}()
-		go func() {
+		})
+		goBackground(r.Context(), func(ctx context.Context) {
 			err := email.Transaction.Initiate(f.Type, transaction)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"email.Transaction.Initiate failed",
 					zap.Error(err),
 				)
 			}
-		}()
+		})
 	}
 }
 
@@ -341,10 +390,10 @@ func (tr *transactionHandler) getBalance() func(http.ResponseWriter, *http.Reque
 		Balance float64
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
-		account, err := AccountHandler.FindByUserID(r.Header.Get("userID"))
+		account, err := AccountHandler.FindByUserID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Error("TransferHandler.getBalance failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("TransferHandler.getBalance failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		res := response{Balance: account.Balance}
@@ -359,22 +408,22 @@ func (tr *transactionHandler) pendingTransactions() func(http.ResponseWriter, *h
 		Transactions []*types.Transaction
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
-		account, err := AccountHandler.FindByUserID(r.Header.Get("userID"))
+		account, err := AccountHandler.FindByUserID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.pendingTransactions failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
-		transactions, err := service.Transaction.FindPendings(account.ID)
+		transactions, err := service.Transaction.FindPendings(r.Context(), account.ID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.pendingTransactions failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		res := response{Transactions: transactions}
@@ -389,22 +438,22 @@ func (tr *transactionHandler) recentTransactions() func(http.ResponseWriter, *ht
 		Transactions []*types.Transaction
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
-		account, err := AccountHandler.FindByUserID(r.Header.Get("userID"))
+		account, err := AccountHandler.FindByUserID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.recentTransactions failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
-		transactions, err := service.Transaction.FindRecent(account.ID)
+		transactions, err := service.Transaction.FindRecent(r.Context(), account.ID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.recentTransactions failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		res := response{Transactions: transactions}
@@ -457,40 +506,40 @@ func (tr *transactionHandler) cancelTransaction() func(http.ResponseWriter, *htt
 		decoder := json.NewDecoder(r.Body)
 		err := decoder.Decode(&req)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.cancelTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		account, err := AccountHandler.FindByUserID(r.Header.Get("userID"))
+		account, err := AccountHandler.FindByUserID(r.Context(), r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.cancelTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
-		transaction, err := service.Transaction.Find(req.TransactionID)
+		transaction, err := service.Transaction.Find(r.Context(), req.TransactionID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.cancelTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
 		shouldContinue, err := tr.isInitiatedStatus(w, transaction)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.cancelTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		if !shouldContinue {
@@ -498,33 +547,34 @@ func (tr *transactionHandler) cancelTransaction() func(http.ResponseWriter, *htt
 		}
 
 		if account.ID != transaction.InitiatedBy {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.cancelTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
-		err = service.Transaction.Cancel(req.TransactionID, req.Reason)
+		err = service.Transaction.Cancel(r.Context(), req.TransactionID, req.Reason)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.cancelTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		w.WriteHeader(http.StatusOK)
+		metrics.Transfer(metrics.TransferCancelled)
 
-		go func() {
+		goBackground(r.Context(), func(ctx context.Context) {
 			err := email.Transaction.Cancel(transaction, req.Reason)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"email.Transaction.Cancel failed",
 					zap.Error(err),
 				)
 			}
-		}()
+		})
 	}
 }
 
@@ -538,57 +588,58 @@ func (tr *transactionHandler) rejectTransaction() func(http.ResponseWriter, *htt
 		decoder := json.NewDecoder(r.Body)
 		err := decoder.Decode(&req)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.rejectTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		transaction, err := service.Transaction.Find(req.TransactionID)
+		transaction, err := service.Transaction.Find(r.Context(), req.TransactionID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.cancelTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
 		shouldContinue, err := tr.isInitiatedStatus(w, transaction)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.cancelTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		if !shouldContinue {
 			return
 		}
 
-		err = service.Transaction.Cancel(req.TransactionID, req.Reason)
+		err = service.Transaction.Cancel(r.Context(), req.TransactionID, req.Reason)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.rejectTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		w.WriteHeader(http.StatusOK)
+		metrics.Transfer(metrics.TransferRejected)
 
-		go func() {
+		goBackground(r.Context(), func(ctx context.Context) {
 			err := email.Transaction.Reject(transaction)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"email.Transaction.Reject failed",
 					zap.Error(err),
 				)
 			}
-		}()
+		})
 	}
 }
 
@@ -601,155 +652,171 @@ func (tr *transactionHandler) acceptTransaction() func(http.ResponseWriter, *htt
 		decoder := json.NewDecoder(r.Body)
 		err := decoder.Decode(&req)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.acceptTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		transaction, err := service.Transaction.Find(req.TransactionID)
+		transaction, err := service.Transaction.Find(r.Context(), req.TransactionID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.acceptTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
 		shouldContinue, err := tr.isInitiatedStatus(w, transaction)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.cancelTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		if !shouldContinue {
 			return
 		}
 
-		from, err := service.Account.FindByID(transaction.FromID)
+		canTrade, err := tr.canTrade(r)
+		if err != nil {
+			l.WithContext(r.Context()).Error(
+				"TransferHandler.acceptTransaction failed",
+				zap.Error(err),
+			)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
+			return
+		}
+		if !canTrade {
+			jsonerror.Write(w, r, http.StatusForbidden, jsonerror.New(e.AgreementNotAccepted, e.Msg[e.AgreementNotAccepted]))
+			return
+		}
+
+		from, err := service.Account.FindByID(r.Context(), transaction.FromID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.acceptTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
-		to, err := service.Account.FindByID(transaction.ToID)
+		to, err := service.Account.FindByID(r.Context(), transaction.ToID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.acceptTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
 		// Check the account balance.
 		exceed, err := service.BalanceLimit.IsExceedLimit(
+			r.Context(),
 			from.ID,
 			from.Balance-transaction.Amount,
 		)
 		if err != nil {
-			l.Logger.Info(
+			l.WithContext(r.Context()).Info(
 				"TransferHandler.acceptTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		if exceed {
 			reason := "The sender will exceed its credit limit so this transaction has been cancelled."
-			err = service.Transaction.Cancel(req.TransactionID, reason)
+			err = service.Transaction.Cancel(r.Context(), req.TransactionID, reason)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(r.Context()).Error(
 					"TransferHandler.acceptTransaction failed",
 					zap.Error(err),
 				)
-				w.WriteHeader(http.StatusInternalServerError)
+				jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 				return
 			}
-			err := jsonerror.New("1", reason)
-			render.New().JSON(w, http.StatusInternalServerError, err.Render())
-			go func() {
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.New(e.ExceedMaxNegBalance, reason))
+			goBackground(r.Context(), func(ctx context.Context) {
 				err := email.Transaction.CancelBySystem(transaction, reason)
 				if err != nil {
-					l.Logger.Error(
+					l.WithContext(ctx).Error(
 						"email.Transaction.Cancel failed",
 						zap.Error(err),
 					)
 				}
-			}()
+			})
 			return
 		}
 		exceed, err = service.BalanceLimit.IsExceedLimit(
+			r.Context(),
 			to.ID,
 			to.Balance+transaction.Amount,
 		)
 		if err != nil {
-			l.Logger.Info(
+			l.WithContext(r.Context()).Info(
 				"TransferHandler.acceptTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		if exceed {
 			reason := "The recipient will exceed its maximum positive balance threshold so this transaction has been cancelled."
-			err = service.Transaction.Cancel(req.TransactionID, reason)
+			err = service.Transaction.Cancel(r.Context(), req.TransactionID, reason)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(r.Context()).Error(
 					"TransferHandler.acceptTransaction failed",
 					zap.Error(err),
 				)
-				w.WriteHeader(http.StatusInternalServerError)
+				jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 				return
 			}
-			err := jsonerror.New("2", reason)
-			render.New().JSON(w, http.StatusInternalServerError, err.Render())
-			go func() {
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.New(e.ExceedMaxPosBalance, reason))
+			goBackground(r.Context(), func(ctx context.Context) {
 				err := email.Transaction.CancelBySystem(transaction, reason)
 				if err != nil {
-					l.Logger.Error(
+					l.WithContext(ctx).Error(
 						"email.Transaction.Cancel failed",
 						zap.Error(err),
 					)
 				}
-			}()
+			})
 			return
 		}
 
 		err = service.Transaction.Accept(
+			r.Context(),
 			transaction.ID,
 			from.ID,
 			to.ID,
 			transaction.Amount,
 		)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"TransferHandler.acceptTransaction failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		w.WriteHeader(http.StatusOK)
+		metrics.Transfer(metrics.TransferAccepted)
 
-		go func() {
+		goBackground(r.Context(), func(ctx context.Context) {
 			err := email.Transaction.Accept(transaction)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"email.Transaction.Accept failed",
 					zap.Error(err),
 				)
 			}
-		}()
+		})
 	}
 }
 
@@ -1,12 +1,14 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"sync"
 
 	"github.com/gofrs/uuid/v5"
 	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/service"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/cookie"
@@ -15,6 +17,7 @@ import (
 	"github.com/ic3network/mccs-alpha/internal/pkg/flash"
 	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
 	"github.com/ic3network/mccs-alpha/internal/pkg/ip"
+	"github.com/ic3network/mccs-alpha/internal/pkg/jsonerror"
 	"github.com/ic3network/mccs-alpha/internal/pkg/jwt"
 	"github.com/ic3network/mccs-alpha/internal/pkg/l"
 	"github.com/ic3network/mccs-alpha/internal/pkg/log"
@@ -22,7 +25,6 @@ import (
 	"github.com/ic3network/mccs-alpha/internal/pkg/template"
 	"github.com/ic3network/mccs-alpha/internal/pkg/util"
 	"github.com/ic3network/mccs-alpha/internal/pkg/validator"
-	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 )
@@ -76,24 +78,24 @@ func (u *userHandler) RegisterRoutes(
 	})
 }
 
-func (u *userHandler) FindByID(id string) (*types.User, error) {
+func (u *userHandler) FindByID(ctx context.Context, id string) (*types.User, error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, e.Wrap(err, "controller.User.FindByID failed")
 	}
-	user, err := service.User.FindByID(objID)
+	user, err := service.User.FindByID(ctx, objID)
 	if err != nil {
 		return nil, e.Wrap(err, "controller.User.FindByID failed")
 	}
 	return user, nil
 }
 
-func (u *userHandler) FindByBusinessID(id string) (*types.User, error) {
+func (u *userHandler) FindByBusinessID(ctx context.Context, id string) (*types.User, error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, e.Wrap(err, "controller.User.FindByBusinessID failed")
 	}
-	user, err := service.User.FindByBusinessID(objID)
+	user, err := service.User.FindByBusinessID(ctx, objID)
 	if err != nil {
 		return nil, e.Wrap(err, "controller.User.FindByBusinessID failed")
 	}
@@ -105,7 +107,7 @@ func (u *userHandler) signupPage() func(http.ResponseWriter, *http.Request) {
 	t := template.NewView("signup")
 	return func(w http.ResponseWriter, r *http.Request) {
 		d := helper.GetRegisterData(r)
-		d.RecaptchaSitekey = viper.GetString("recaptcha.site_key")
+		d.RecaptchaSitekey = global.Config().Recaptcha.SiteKey
 		t.Render(w, r, d, nil)
 	}
 }
@@ -116,17 +118,17 @@ func (u *userHandler) registerHandler() func(http.ResponseWriter, *http.Request)
 	return func(w http.ResponseWriter, r *http.Request) {
 		r.ParseForm()
 		d := helper.GetRegisterData(r)
-		d.RecaptchaSitekey = viper.GetString("recaptcha.site_key")
+		d.RecaptchaSitekey = global.Config().Recaptcha.SiteKey
 
 		errorMessages := validator.Register(d)
-		if service.User.UserEmailExists(d.User.Email) {
+		if service.User.UserEmailExists(r.Context(), d.User.Email) {
 			errorMessages = append(
 				errorMessages,
 				"Email address is already registered.",
 			)
 		}
 
-		if viper.GetString("env") == "production" {
+		if global.Config().IsProduction() {
 			isValid := recaptcha.Verify(*r)
 			if !isValid {
 				errorMessages = append(errorMessages, recaptcha.Error()...)
@@ -134,7 +136,7 @@ func (u *userHandler) registerHandler() func(http.ResponseWriter, *http.Request)
 		}
 
 		if len(errorMessages) > 0 {
-			l.Logger.Info(
+			l.WithContext(r.Context()).Info(
 				"RegisterHandler failed",
 				zap.Strings("input invalid", errorMessages),
 			)
@@ -142,50 +144,50 @@ func (u *userHandler) registerHandler() func(http.ResponseWriter, *http.Request)
 			return
 		}
 
-		bID, err := service.Business.Create(d.Business)
+		bID, err := service.Business.Create(r.Context(), d.Business)
 		if err != nil {
-			l.Logger.Error("RegisterHandler failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("RegisterHandler failed", zap.Error(err))
 			t.Error(w, r, d, err)
 			return
 		}
 
 		d.User.CompanyID = bID
-		err = service.User.Create(d.User)
+		err = service.User.Create(r.Context(), d.User)
 		if err != nil {
-			l.Logger.Error("RegisterHandler failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("RegisterHandler failed", zap.Error(err))
 			t.Error(w, r, d, err)
 			return
 		}
 
-		err = service.Account.Create(bID.Hex())
+		err = service.Account.Create(r.Context(), bID.Hex())
 		if err != nil {
-			l.Logger.Error("RegisterHandler failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("RegisterHandler failed", zap.Error(err))
 			t.Error(w, r, d, err)
 			return
 		}
 
 		token, err := jwt.NewJWTManager().Generate(d.User.ID.Hex(), false)
 		if err != nil {
-			l.Logger.Error("RegisterHandler failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("RegisterHandler failed", zap.Error(err))
 			http.Redirect(w, r, "/login", http.StatusFound)
 			return
 		}
 		http.SetCookie(w, cookie.CreateCookie(token))
 
-		go func() {
-			err := service.User.UpdateLoginInfo(d.User.ID, ip.FromRequest(r))
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := service.User.UpdateLoginInfo(ctx, d.User.ID, ip.FromRequest(r))
 			if err != nil {
-				l.Logger.Error("UpdateLoginInfo failed", zap.Error(err))
+				l.WithContext(ctx).Error("UpdateLoginInfo failed", zap.Error(err))
 			}
-		}()
-		go func() {
-			err := service.UserAction.Log(log.User.Signup(d.User, d.Business))
+		})
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := service.UserAction.Log(ctx, log.User.Signup(d.User, d.Business))
 			if err != nil {
-				l.Logger.Error("log.User.Signup failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.User.Signup failed", zap.Error(err))
 			}
-		}()
-		go func() {
-			if !viper.GetBool("receive_signup_notifications") {
+		})
+		goBackground(r.Context(), func(ctx context.Context) {
+			if !global.Config().ReceiveSignupNotifications {
 				return
 			}
 			err := email.SendSignupNotification(
@@ -193,18 +195,18 @@ func (u *userHandler) registerHandler() func(http.ResponseWriter, *http.Request)
 				d.User.Email,
 			)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"email.SendSignupNotification failed",
 					zap.Error(err),
 				)
 			}
-		}()
-		go func() {
+		})
+		goBackground(r.Context(), func(ctx context.Context) {
 			err := email.SendWelcomeEmail(d.Business.BusinessName, d.User)
 			if err != nil {
-				l.Logger.Error("email.SendWelcomeEmail failed", zap.Error(err))
+				l.WithContext(ctx).Error("email.SendWelcomeEmail failed", zap.Error(err))
 			}
-		}()
+		})
 
 		http.Redirect(w, r, "/", http.StatusFound)
 	}
@@ -221,7 +223,7 @@ func (u *userHandler) loginPage() func(http.ResponseWriter, *http.Request) {
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
 		t.Render(w, r, formData{
-			RecaptchaSitekey: viper.GetString("recaptcha.site_key"),
+			RecaptchaSitekey: global.Config().Recaptcha.SiteKey,
 			RedirectURL:      r.URL.Query().Get("redirect_login"),
 		}, nil)
 	}
@@ -241,14 +243,14 @@ func (u *userHandler) loginHandler() func(http.ResponseWriter, *http.Request) {
 		f := formData{
 			Email:            r.FormValue("email"),
 			Password:         r.FormValue("password"),
-			RecaptchaSitekey: viper.GetString("recaptcha.site_key"),
+			RecaptchaSitekey: global.Config().Recaptcha.SiteKey,
 			RedirectURL:      r.URL.Query().Get("redirect_login"),
 		}
 
-		if viper.GetString("env") == "production" {
+		if global.Config().IsProduction() {
 			isValid := recaptcha.Verify(*r)
 			if !isValid {
-				l.Logger.Error(
+				l.WithContext(r.Context()).Error(
 					"UpdateLoginAttempts failed",
 					zap.Strings("errs", recaptcha.Error()),
 				)
@@ -257,26 +259,26 @@ func (u *userHandler) loginHandler() func(http.ResponseWriter, *http.Request) {
 			}
 		}
 
-		user, err := service.User.Login(f.Email, f.Password)
+		user, err := service.User.Login(r.Context(), f.Email, f.Password)
 		if err != nil {
-			l.Logger.Info("LoginHandler failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("LoginHandler failed", zap.Error(err))
 
 			// Logic to update user login attempts.
 			passwordInvalid := e.IsPasswordInvalid(err)
 			if passwordInvalid {
-				err := service.User.UpdateLoginAttempts(f.Email)
+				err := service.User.UpdateLoginAttempts(r.Context(), f.Email)
 				if err != nil {
-					l.Logger.Error("UpdateLoginAttempts failed", zap.Error(err))
+					l.WithContext(r.Context()).Error("UpdateLoginAttempts failed", zap.Error(err))
 				}
 			}
 
 			t.Error(w, r, f, err)
 
-			go func() {
-				user, err := service.User.FindByEmail(f.Email)
+			goBackground(r.Context(), func(ctx context.Context) {
+				user, err := service.User.FindByEmail(ctx, f.Email)
 				if err != nil {
 					if !e.IsUserNotFound(err) {
-						l.Logger.Error(
+						l.WithContext(ctx).Error(
 							"log.User.LoginFailure failed",
 							zap.Error(err),
 						)
@@ -284,15 +286,16 @@ func (u *userHandler) loginHandler() func(http.ResponseWriter, *http.Request) {
 					return
 				}
 				err = service.UserAction.Log(
+					ctx,
 					log.User.LoginFailure(user, ip.FromRequest(r)),
 				)
 				if err != nil {
-					l.Logger.Error(
+					l.WithContext(ctx).Error(
 						"log.User.LoginFailure failed",
 						zap.Error(err),
 					)
 				}
-			}()
+			})
 			return
 		}
 
@@ -307,20 +310,21 @@ func (u *userHandler) loginHandler() func(http.ResponseWriter, *http.Request) {
 			)+" from "+user.CurrentLoginIP,
 		)
 
-		go func() {
-			err := service.User.UpdateLoginInfo(user.ID, ip.FromRequest(r))
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := service.User.UpdateLoginInfo(ctx, user.ID, ip.FromRequest(r))
 			if err != nil {
-				l.Logger.Error("UpdateLoginInfo failed", zap.Error(err))
+				l.WithContext(ctx).Error("UpdateLoginInfo failed", zap.Error(err))
 			}
-		}()
-		go func() {
+		})
+		goBackground(r.Context(), func(ctx context.Context) {
 			err := service.UserAction.Log(
+				ctx,
 				log.User.LoginSuccess(user, ip.FromRequest(r)),
 			)
 			if err != nil {
-				l.Logger.Error("log.User.LoginSuccess failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.User.LoginSuccess failed", zap.Error(err))
 			}
-		}()
+		})
 
 		http.Redirect(
 			w,
@@ -351,7 +355,7 @@ func (u *userHandler) lostPasswordPage() func(http.ResponseWriter, *http.Request
 		t.Render(
 			w,
 			r,
-			formData{RecaptchaSitekey: viper.GetString("recaptcha.site_key")},
+			formData{RecaptchaSitekey: global.Config().Recaptcha.SiteKey},
 			nil,
 		)
 	}
@@ -369,13 +373,13 @@ func (u *userHandler) lostPassword() func(http.ResponseWriter, *http.Request) {
 		r.ParseForm()
 		f := formData{
 			Email:            r.FormValue("email"),
-			RecaptchaSitekey: viper.GetString("recaptcha.site_key"),
+			RecaptchaSitekey: global.Config().Recaptcha.SiteKey,
 		}
 
-		if viper.GetString("env") == "production" {
+		if global.Config().IsProduction() {
 			isValid := recaptcha.Verify(*r)
 			if !isValid {
-				l.Logger.Info(
+				l.WithContext(r.Context()).Info(
 					"LostPassword failed",
 					zap.Strings("errs", recaptcha.Error()),
 				)
@@ -384,16 +388,16 @@ func (u *userHandler) lostPassword() func(http.ResponseWriter, *http.Request) {
 			}
 		}
 
-		user, err := service.User.FindByEmail(f.Email)
+		user, err := service.User.FindByEmail(r.Context(), f.Email)
 		if err != nil {
-			l.Logger.Info("LostPassword failed", zap.Error(err))
+			l.WithContext(r.Context()).Info("LostPassword failed", zap.Error(err))
 			t.Error(w, r, f, err)
 			return
 		}
 
 		receiver := user.FirstName + " " + user.LastName
-		lostPassword, err := service.Lostpassword.FindByEmail(f.Email)
-		if err == nil && !service.Lostpassword.TokenInvalid(lostPassword) {
+		lostPassword, err := service.Lostpassword.FindByEmail(r.Context(), f.Email)
+		if err == nil && !service.Lostpassword.TokenInvalid(r.Context(), lostPassword) {
 			email.SendResetEmail(receiver, f.Email, lostPassword.Token)
 			f.Success = true
 			t.Render(w, r, f, nil)
@@ -402,7 +406,7 @@ func (u *userHandler) lostPassword() func(http.ResponseWriter, *http.Request) {
 
 		uid, err := uuid.NewV4()
 		if err != nil {
-			l.Logger.Error("LostPassword failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("LostPassword failed", zap.Error(err))
 			t.Error(w, r, f, err)
 			return
 		}
@@ -411,21 +415,21 @@ func (u *userHandler) lostPassword() func(http.ResponseWriter, *http.Request) {
 			Email: user.Email,
 			Token: uid.String(),
 		}
-		err = service.Lostpassword.Create(lostPassword)
+		err = service.Lostpassword.Create(r.Context(), lostPassword)
 		if err != nil {
-			l.Logger.Error("LostPassword failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("LostPassword failed", zap.Error(err))
 			t.Error(w, r, f, err)
 			return
 		}
 
 		email.SendResetEmail(receiver, f.Email, uid.String())
 
-		go func() {
-			err := service.UserAction.Log(log.User.LostPassword(user))
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := service.UserAction.Log(ctx, log.User.LostPassword(user))
 			if err != nil {
-				l.Logger.Error("log.User.LostPassword failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.User.LostPassword failed", zap.Error(err))
 			}
-		}()
+		})
 
 		f.Success = true
 		t.Render(w, r, f, nil)
@@ -441,14 +445,14 @@ func (u *userHandler) passwordResetPage() func(http.ResponseWriter, *http.Reques
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		token := vars["token"]
-		lostPassword, err := service.Lostpassword.FindByToken(token)
+		lostPassword, err := service.Lostpassword.FindByToken(r.Context(), token)
 		if err != nil {
-			l.Logger.Error("PasswordResetPage failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("PasswordResetPage failed", zap.Error(err))
 			http.Redirect(w, r, "/lost-password", http.StatusFound)
 			return
 		}
-		if service.Lostpassword.TokenInvalid(lostPassword) {
-			l.Logger.Info("PasswordResetPage failed: token expired \n")
+		if service.Lostpassword.TokenInvalid(r.Context(), lostPassword) {
+			l.WithContext(r.Context()).Info("PasswordResetPage failed: token expired \n")
 			http.Redirect(w, r, "/lost-password", http.StatusFound)
 			return
 		}
@@ -479,7 +483,7 @@ func (u *userHandler) passwordReset() func(http.ResponseWriter, *http.Request) {
 			f.ConfirmPassword,
 		)
 		if len(errorMessages) > 0 {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"PasswordReset failed",
 				zap.Strings("input invalid", errorMessages),
 			)
@@ -487,41 +491,41 @@ func (u *userHandler) passwordReset() func(http.ResponseWriter, *http.Request) {
 			return
 		}
 
-		lost, err := service.Lostpassword.FindByToken(f.Token)
+		lost, err := service.Lostpassword.FindByToken(r.Context(), f.Token)
 		if err != nil {
-			l.Logger.Error("PasswordReset failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("PasswordReset failed", zap.Error(err))
 			t.Error(w, r, f, err)
 			return
 		}
 
-		err = service.User.ResetPassword(lost.Email, f.Password)
+		err = service.User.ResetPassword(r.Context(), lost.Email, f.Password)
 		if err != nil {
-			l.Logger.Error("PasswordReset failed", zap.Error(err))
+			l.WithContext(r.Context()).Error("PasswordReset failed", zap.Error(err))
 			t.Error(w, r, f, err)
 			return
 		}
 
-		go func() {
-			err := service.Lostpassword.SetTokenUsed(f.Token)
+		goBackground(r.Context(), func(ctx context.Context) {
+			err := service.Lostpassword.SetTokenUsed(ctx, f.Token)
 			if err != nil {
-				l.Logger.Error("SetTokenUsed failed", zap.Error(err))
+				l.WithContext(ctx).Error("SetTokenUsed failed", zap.Error(err))
 			}
-		}()
+		})
 
-		go func() {
-			user, err := service.User.FindByEmail(lost.Email)
+		goBackground(r.Context(), func(ctx context.Context) {
+			user, err := service.User.FindByEmail(ctx, lost.Email)
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(ctx).Error(
 					"BuildChangePasswordAction failed",
 					zap.Error(err),
 				)
 				return
 			}
-			service.UserAction.Log(log.User.ChangePassword(user))
+			service.UserAction.Log(ctx, log.User.ChangePassword(user))
 			if err != nil {
-				l.Logger.Error("log.User.ChangePassword failed", zap.Error(err))
+				l.WithContext(ctx).Error("log.User.ChangePassword failed", zap.Error(err))
 			}
-		}()
+		})
 
 		flash.Success(w, "Your password has been reset successfully!")
 		http.Redirect(w, r, "/login", http.StatusFound)
@@ -532,14 +536,14 @@ func (u *userHandler) toggleShowRecentMatchedTags() func(http.ResponseWriter, *h
 	return func(w http.ResponseWriter, r *http.Request) {
 		objID, err := primitive.ObjectIDFromHex(r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Error("ToggleShowRecentMatchedTags failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("ToggleShowRecentMatchedTags failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
-		err = service.User.ToggleShowRecentMatchedTags(objID)
+		err = service.User.ToggleShowRecentMatchedTags(r.Context(), objID)
 		if err != nil {
-			l.Logger.Error("ToggleShowRecentMatchedTags failed", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			l.WithContext(r.Context()).Error("ToggleShowRecentMatchedTags failed", zap.Error(err))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		w.WriteHeader(http.StatusOK)
@@ -556,47 +560,43 @@ func (u *userHandler) addToFavoriteBusinesses() func(http.ResponseWriter, *http.
 		err := decoder.Decode(&req)
 		if err != nil || req.ID == "" {
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(r.Context()).Error(
 					"AppServer AddToFavoriteBusinesses failed",
 					zap.Error(err),
 				)
 			} else {
-				l.Logger.Error("AppServer AddToFavoriteBusinesses failed", zap.String("error", "request business id is empty"))
+				l.WithContext(r.Context()).Error("AppServer AddToFavoriteBusinesses failed", zap.String("error", "request business id is empty"))
 			}
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		bID, err := primitive.ObjectIDFromHex(req.ID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"AppServer AddToFavoriteBusinesses failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
 		uID, err := primitive.ObjectIDFromHex(r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"AppServer AddToFavoriteBusinesses failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		err = service.User.AddToFavoriteBusinesses(uID, bID)
+		err = service.User.AddToFavoriteBusinesses(r.Context(), uID, bID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"AppServer AddToFavoriteBusinesses failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
@@ -614,47 +614,43 @@ func (u *userHandler) removeFromFavoriteBusinesses() func(http.ResponseWriter, *
 		err := decoder.Decode(&req)
 		if err != nil || req.ID == "" {
 			if err != nil {
-				l.Logger.Error(
+				l.WithContext(r.Context()).Error(
 					"AppServer RemoveFromFavoriteBusinesses failed",
 					zap.Error(err),
 				)
 			} else {
-				l.Logger.Error("AppServer RemoveFromFavoriteBusinesses failed", zap.String("error", "request business id is empty"))
+				l.WithContext(r.Context()).Error("AppServer RemoveFromFavoriteBusinesses failed", zap.String("error", "request business id is empty"))
 			}
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 		bID, err := primitive.ObjectIDFromHex(req.ID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"AppServer RemoveFromFavoriteBusinesses failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
 		uID, err := primitive.ObjectIDFromHex(r.Header.Get("userID"))
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"AppServer RemoveFromFavoriteBusinesses failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
-		err = service.User.RemoveFromFavoriteBusinesses(uID, bID)
+		err = service.User.RemoveFromFavoriteBusinesses(r.Context(), uID, bID)
 		if err != nil {
-			l.Logger.Error(
+			l.WithContext(r.Context()).Error(
 				"AppServer RemoveFromFavoriteBusinesses failed",
 				zap.Error(err),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something went wrong. Please try again later."))
+			jsonerror.Write(w, r, http.StatusInternalServerError, jsonerror.FromError(err))
 			return
 		}
 
@@ -0,0 +1,276 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignup(t *testing.T) {
+	h := newHarness(t)
+	b := h.newBrowser()
+
+	res := b.postForm("/signup", url.Values{
+		"business_name":    {"Corner Bakery"},
+		"offers":           {"bread,cakes"},
+		"wants":            {"flour"},
+		"location_city":    {"Bristol"},
+		"location_country": {"United Kingdom"},
+		"first_name":       {"Sam"},
+		"last_name":        {"Baker"},
+		"email":            {"sam@bakery.test"},
+		"confirm_email":    {"sam@bakery.test"},
+		"telephone":        {"0117 000 0001"},
+		"new_password":     {"Passw0rd!"},
+		"confirm_password": {"Passw0rd!"},
+		"terms":            {"on"},
+	})
+	require.Equal(t, http.StatusFound, res.Status, res.Body)
+	assert.Equal(t, "/", res.Location)
+
+	user, err := h.users.FindByEmail(context.Background(), "sam@bakery.test")
+	require.NoError(t, err)
+	business, err := h.businesses.FindByID(context.Background(), user.CompanyID)
+	require.NoError(t, err)
+	assert.Equal(t, "Corner Bakery", business.BusinessName)
+	assert.Equal(t, constant.Business.Pending, business.Status)
+	_, err = h.ledger.Accounts.FindByBusinessID(context.Background(), business.ID.Hex())
+	assert.NoError(t, err)
+
+	h.waitForEmail("sam@bakery.test", "Welcome to The Open Credit Network directory!")
+
+	// The signup logs the user in.
+	res = b.get("/")
+	require.Equal(t, http.StatusOK, res.Status)
+	assert.Contains(t, res.Body, "Corner Bakery")
+
+	// The email can only be registered once.
+	res = h.newBrowser().postForm("/signup", url.Values{
+		"business_name":    {"Second Bakery"},
+		"email":            {"sam@bakery.test"},
+		"confirm_email":    {"sam@bakery.test"},
+		"new_password":     {"Passw0rd!"},
+		"confirm_password": {"Passw0rd!"},
+		"terms":            {"on"},
+	})
+	require.Equal(t, http.StatusOK, res.Status)
+	assert.Contains(t, res.Body, "Email address is already registered.")
+}
+
+func TestTradingSignupAndApproval(t *testing.T) {
+	h := newHarness(t)
+	m := h.seedMember("Green Grocer", "gina@grocer.test", constant.Business.Accepted)
+	adminPassword := h.seedAdmin("admin@ocn.test")
+
+	b := h.newBrowser()
+	b.login("gina@grocer.test", m.Password)
+	res := b.get("/member-signup")
+	require.Equal(t, http.StatusOK, res.Status)
+	assert.Contains(t, res.Body, "Green Grocer")
+
+	res = b.postForm("/member-signup", url.Values{
+		"business_name":        {"Green Grocer"},
+		"inc_type":             {"Ltd"},
+		"description":          {"Fruit and vegetables."},
+		"location_address":     {"2 Market Street"},
+		"location_city":        {"Bristol"},
+		"location_region":      {"Avon"},
+		"location_postal_code": {"BS1 2AA"},
+		"location_country":     {"United Kingdom"},
+		"first_name":           {"Gina"},
+		"last_name":            {"Grocer"},
+		"telephone":            {"0117 000 0002"},
+		"authorised":           {"on"},
+	})
+	require.Equal(t, http.StatusFound, res.Status, res.Body)
+	assert.Equal(t, "/", res.Location)
+
+	business, err := h.businesses.FindByID(context.Background(), m.Business.ID)
+	require.NoError(t, err)
+	assert.Equal(t, constant.Trading.Pending, business.Status)
+	application, err := h.applications.FindOpenByBusinessID(context.Background(), m.Business.ID)
+	require.NoError(t, err)
+	assert.Equal(t, constant.TradingApplication.Pending, application.Status)
+	h.waitForEmail("gina@grocer.test", "Thank You for Your Application")
+
+	// The members cannot see the admin pages.
+	res = b.get("/admin/applications/" + application.ID.Hex())
+	assert.Equal(t, http.StatusForbidden, res.Status)
+
+	admin := h.newBrowser()
+	admin.adminLogin("admin@ocn.test", adminPassword)
+	res = admin.get("/admin/applications/" + application.ID.Hex())
+	require.Equal(t, http.StatusOK, res.Status)
+	assert.Contains(t, res.Body, "Green Grocer")
+
+	res = admin.postForm("/admin/applications/"+application.ID.Hex()+"/decision", url.Values{
+		"action":  {constant.ApplicationEvent.Approved},
+		"message": {"Welcome aboard."},
+	})
+	require.Equal(t, http.StatusFound, res.Status, res.Body)
+	assert.Equal(t, "/admin/applications/"+application.ID.Hex(), res.Location)
+
+	business, err = h.businesses.FindByID(context.Background(), m.Business.ID)
+	require.NoError(t, err)
+	assert.Equal(t, constant.Trading.Accepted, business.Status)
+	assert.False(t, business.MemberStartedAt.IsZero())
+	application, err = h.applications.FindByID(context.Background(), application.ID)
+	require.NoError(t, err)
+	assert.Equal(t, constant.TradingApplication.Approved, application.Status)
+
+	// The application is decided once.
+	err = h.applications.Decide(
+		context.Background(),
+		application.ID,
+		constant.TradingApplication.Rejected,
+		&types.TradingApplicationEvent{Action: constant.ApplicationEvent.Rejected},
+	)
+	require.IsType(t, e.Error{}, err)
+	assert.Equal(t, e.ApplicationDecided, err.(e.Error).Code)
+	admin.postForm("/admin/applications/"+application.ID.Hex()+"/decision", url.Values{
+		"action": {constant.ApplicationEvent.Rejected},
+	})
+	business, err = h.businesses.FindByID(context.Background(), m.Business.ID)
+	require.NoError(t, err)
+	assert.Equal(t, constant.Trading.Accepted, business.Status)
+
+	sent := h.waitForEmail(
+		"gina@grocer.test",
+		"Your Trading Membership Application Has Been Approved",
+	)
+	assert.Contains(t, sent.Text, "Approved")
+
+	var isMember struct{ IsMember bool }
+	b.getJSON("/api/is-trading-member", &isMember)
+	assert.True(t, isMember.IsMember)
+}
+
+// pendingTransactions returns the transfers waiting on either side.
+func pendingTransactions(b *browser) []*types.Transaction {
+	b.t.Helper()
+
+	var res struct{ Transactions []*types.Transaction }
+	b.getJSON("/api/pendingTransactions", &res)
+	return res.Transactions
+}
+
+func balance(b *browser) float64 {
+	b.t.Helper()
+
+	var res struct{ Balance float64 }
+	b.getJSON("/api/accountBalance", &res)
+	return res.Balance
+}
+
+func TestTransfers(t *testing.T) {
+	h := newHarness(t)
+	alice := h.seedMember("Alice Cafe", "alice@cafe.test", constant.Trading.Accepted)
+	bob := h.seedMember("Bob Bikes", "bob@bikes.test", constant.Trading.Accepted)
+
+	a := h.newBrowser()
+	a.login("alice@cafe.test", alice.Password)
+	bb := h.newBrowser()
+	bb.login("bob@bikes.test", bob.Password)
+
+	propose := func(amount string, description string) {
+		t.Helper()
+
+		res := a.postForm("/transaction", url.Values{
+			"transfer_type": {"send"},
+			"email_address": {"bob@bikes.test"},
+			"amount":        {amount},
+			"description":   {description},
+		})
+		require.Equal(t, http.StatusFound, res.Status, res.Body)
+		assert.Equal(t, "/#transactions", res.Location)
+	}
+
+	// Alice sends 40 credits and Bob accepts them.
+	propose("40", "Coffee beans")
+	sent := h.waitForEmail("bob@bikes.test", "OCN Transaction Requiring Your Approval")
+	assert.Contains(t, sent.Text, "Alice Cafe")
+
+	pendings := pendingTransactions(bb)
+	require.Len(t, pendings, 1)
+	assert.False(t, pendings[0].IsInitiator)
+	assert.Equal(t, 40.0, pendings[0].Amount)
+	require.Len(t, pendingTransactions(a), 1)
+	assert.True(t, pendingTransactions(a)[0].IsInitiator)
+
+	res := bb.postJSON("/api/acceptTransaction", map[string]interface{}{"id": pendings[0].ID})
+	require.Equal(t, http.StatusOK, res.Status, res.Body)
+	h.waitForEmail("alice@cafe.test", "OCN Transaction Accepted")
+	assert.Equal(t, -40.0, balance(a))
+	assert.Equal(t, 40.0, balance(bb))
+	assert.Empty(t, pendingTransactions(bb))
+
+	// Bob rejects the next transfer, the balances do not change.
+	propose("25.50", "Muffins")
+	pendings = pendingTransactions(bb)
+	require.Len(t, pendings, 1)
+	res = bb.postJSON("/api/rejectTransaction", map[string]interface{}{
+		"id":     pendings[0].ID,
+		"reason": "Not ordered",
+	})
+	require.Equal(t, http.StatusOK, res.Status, res.Body)
+	h.waitForEmail("alice@cafe.test", "OCN Transaction Rejected")
+	assert.Empty(t, pendingTransactions(a))
+	assert.Equal(t, -40.0, balance(a))
+	assert.Equal(t, 40.0, balance(bb))
+
+	// Alice cannot go over her credit limit of 100.
+	res = a.postForm("/transaction", url.Values{
+		"transfer_type": {"send"},
+		"email_address": {"bob@bikes.test"},
+		"amount":        {"70"},
+	})
+	require.Equal(t, http.StatusOK, res.Status)
+	assert.Contains(t, res.Body, "exceed its credit limit")
+	assert.Empty(t, pendingTransactions(a))
+}
+
+func TestLostPassword(t *testing.T) {
+	h := newHarness(t)
+	h.seedMember("Tool Library", "toby@tools.test", constant.Business.Accepted)
+
+	b := h.newBrowser()
+	res := b.postForm("/lost-password", url.Values{"email": {"toby@tools.test"}})
+	require.Equal(t, http.StatusOK, res.Status, res.Body)
+
+	sent := h.waitForEmail("toby@tools.test", "Password Reset")
+	token := regexp.MustCompile(`/password-resets/(\S+)`).FindStringSubmatch(sent.Text)
+	require.Len(t, token, 2, sent.Text)
+	lost, err := h.lostPassword.FindByEmail(context.Background(), "toby@tools.test")
+	require.NoError(t, err)
+	assert.Equal(t, lost.Token, token[1])
+
+	res = b.get("/password-resets/" + token[1])
+	require.Equal(t, http.StatusOK, res.Status)
+
+	res = b.postForm("/password-resets/"+token[1], url.Values{
+		"password":         {"N3w-password"},
+		"confirm_password": {"N3w-password"},
+	})
+	require.Equal(t, http.StatusFound, res.Status, res.Body)
+	assert.Equal(t, "/login", res.Location)
+
+	b.login("toby@tools.test", "N3w-password")
+	res = h.newBrowser().postForm("/login?redirect_login=/", url.Values{
+		"email":    {"toby@tools.test"},
+		"password": {"Passw0rd!"},
+	})
+	assert.Equal(t, http.StatusOK, res.Status)
+
+	// Unknown tokens go back to the lost password page.
+	res = b.get("/password-resets/unknown")
+	assert.Equal(t, http.StatusFound, res.Status)
+	assert.Equal(t, "/lost-password", res.Location)
+}
@@ -0,0 +1,397 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/http/controller"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/memory"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/search"
+	"github.com/ic3network/mccs-alpha/internal/app/service"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/bcrypt"
+	"github.com/ic3network/mccs-alpha/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/sendgrid/rest"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"github.com/stretchr/testify/require"
+)
+
+// The handlers register their routes once, so every test shares the router
+// and gets fresh stores instead.
+var (
+	router *mux.Router
+	outbox = &mailbox{}
+)
+
+func TestMain(m *testing.M) {
+	global.Init()
+	// The templates and static files are loaded relative to the root.
+	if err := os.Chdir(global.App.RootDir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := useTestKeys(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	email.UseSender(outbox)
+
+	router = mux.NewRouter().StrictSlash(true)
+	RegisterRoutes(router)
+	os.Exit(m.Run())
+}
+
+// useTestKeys signs the tokens with a new key pair, the test config only
+// has placeholders.
+func useTestKeys() error {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+	c := *global.Config()
+	c.JWT.PrivateKey = string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}))
+	c.JWT.PublicKey = string(pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: public,
+	}))
+	global.SetConfig(&c)
+	return nil
+}
+
+// sentEmail is an email captured by the mailbox.
+type sentEmail struct {
+	To      string
+	Subject string
+	Text    string
+}
+
+// mailbox captures the emails instead of sending them through SendGrid.
+type mailbox struct {
+	mu     sync.Mutex
+	emails []sentEmail
+}
+
+func (m *mailbox) Send(message *mail.SGMailV3) (*rest.Response, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sent := sentEmail{Subject: message.Subject}
+	if len(message.Personalizations) > 0 && len(message.Personalizations[0].To) > 0 {
+		sent.To = message.Personalizations[0].To[0].Address
+	}
+	if len(message.Content) > 0 {
+		sent.Text = message.Content[0].Value
+	}
+	m.emails = append(m.emails, sent)
+	return &rest.Response{StatusCode: http.StatusAccepted}, nil
+}
+
+func (m *mailbox) find(to string, subject string) (sentEmail, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, sent := range m.emails {
+		if sent.To == to && sent.Subject == subject {
+			return sent, true
+		}
+	}
+	return sentEmail{}, false
+}
+
+func (m *mailbox) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.emails = nil
+}
+
+// harness runs the app against in-memory stores.
+type harness struct {
+	t      *testing.T
+	server *httptest.Server
+
+	ledger       *memory.Ledger
+	users        *memory.Users
+	businesses   *memory.Businesses
+	applications *memory.TradingApplications
+	adminUsers   *memory.AdminUsers
+	lostPassword *memory.LostPasswords
+}
+
+// newHarness switches the services to empty stores and starts the server.
+// The previous services are restored when the test ends.
+func newHarness(t *testing.T) *harness {
+	h := &harness{
+		t:            t,
+		ledger:       memory.NewLedger(),
+		users:        memory.NewUsers(),
+		businesses:   memory.NewBusinesses(),
+		applications: memory.NewTradingApplications(),
+		adminUsers:   memory.NewAdminUsers(),
+		lostPassword: memory.NewLostPasswords(),
+	}
+
+	searchBusiness, searchUser, searchTag := search.Business, search.User, search.Tag
+	search.UseMemory()
+	t.Cleanup(func() {
+		search.Business, search.User, search.Tag = searchBusiness, searchUser, searchTag
+	})
+	changes := memory.NewOutbox()
+	swap(t, &service.Account, service.NewAccount(h.ledger.Accounts))
+	swap(t, &service.BalanceLimit, service.NewBalanceLimit(h.ledger.Accounts, h.ledger.BalanceLimits))
+	swap(t, &service.Transaction, service.NewTransaction(
+		h.ledger.Accounts,
+		h.ledger.BalanceLimits,
+		h.ledger.Transactions,
+	))
+	swap(t, &service.User, service.NewUser(h.users, search.User, changes))
+	swap(t, &service.Business, service.NewBusiness(h.businesses, search.Business, changes))
+	swap(t, &service.Tag, service.NewTag(memory.NewTags(), search.Business, search.Tag, changes))
+	swap(t, &service.Trading, service.NewTrading(h.businesses, h.users, changes))
+	swap(t, &service.TradingApplication, service.NewTradingApplication(h.applications))
+	swap(t, &service.Agreement, service.NewAgreement(
+		memory.NewAgreements(),
+		memory.NewAgreementAcceptances(),
+	))
+	swap(t, &service.AdminUser, service.NewAdminUser(h.adminUsers))
+	swap(t, &service.AdminTag, service.NewAdminTag(memory.NewAdminTags()))
+	swap(t, &service.Lostpassword, service.NewLostpassword(h.lostPassword))
+	swap(t, &service.UserAction, service.NewUserAction(memory.NewUserActions()))
+	swap(t, &service.Audit, service.NewAudit(memory.NewAuditEvents()))
+
+	// The handlers' background work ends before the services are restored.
+	t.Cleanup(func() {
+		require.NoError(t, controller.WaitBackground(context.Background()))
+	})
+	outbox.reset()
+	h.server = httptest.NewServer(router)
+	t.Cleanup(h.server.Close)
+	return h
+}
+
+// swap sets the service for the test and restores the previous one when
+// it ends.
+func swap[T any](t *testing.T, service *T, v T) {
+	previous := *service
+	*service = v
+	t.Cleanup(func() { *service = previous })
+}
+
+// member is a seeded business with its user and account.
+type member struct {
+	Business *types.Business
+	User     *types.User
+	Account  *types.Account
+	Password string
+}
+
+// seedMember creates a business with the given status, its user and its
+// account. Trading members can go 100 credits negative.
+func (h *harness) seedMember(name string, userEmail string, status string) *member {
+	h.t.Helper()
+
+	data := &types.BusinessData{
+		BusinessName:       name,
+		IncType:            "Ltd",
+		Offers:             helper.ToTagFields([]string{"bread"}),
+		Wants:              helper.ToTagFields([]string{"flour"}),
+		Description:        name + " in the test network.",
+		LocationAddress:    "1 High Street",
+		LocationCity:       "Bristol",
+		LocationRegion:     "Avon",
+		LocationPostalCode: "BS1 1AA",
+		LocationCountry:    "United Kingdom",
+	}
+	bID, err := service.Business.Create(context.Background(), data)
+	require.NoError(h.t, err)
+	if status != constant.Business.Pending {
+		data.Status = status
+		require.NoError(h.t, service.Business.UpdateBusiness(context.Background(), bID, data, true))
+	}
+
+	password := "Passw0rd!"
+	require.NoError(h.t, service.User.Create(context.Background(), &types.User{
+		FirstName: "Pat",
+		LastName:  name,
+		Email:     userEmail,
+		Telephone: "0117 000 0000",
+		Password:  password,
+		CompanyID: bID,
+	}))
+	require.NoError(h.t, service.Account.Create(context.Background(), bID.Hex()))
+
+	account, err := service.Account.FindByBusinessID(context.Background(), bID.Hex())
+	require.NoError(h.t, err)
+	if status == constant.Trading.Accepted {
+		require.NoError(h.t, h.ledger.BalanceLimits.Update(context.Background(), account.ID, 500, 100))
+	}
+
+	business, err := service.Business.FindByID(context.Background(), bID)
+	require.NoError(h.t, err)
+	user, err := service.User.FindByEmail(context.Background(), userEmail)
+	require.NoError(h.t, err)
+	return &member{Business: business, User: user, Account: account, Password: password}
+}
+
+// seedAdmin creates an admin user and returns its password.
+func (h *harness) seedAdmin(adminEmail string) string {
+	h.t.Helper()
+
+	password := "Adm1n-password"
+	hashed, err := bcrypt.Hash(password)
+	require.NoError(h.t, err)
+	h.adminUsers.Add(context.Background(), &types.AdminUser{
+		Email:    adminEmail,
+		Name:     "Admin",
+		Password: hashed,
+	})
+	return password
+}
+
+// waitForEmail waits for the email, most of them are sent after the
+// response.
+func (h *harness) waitForEmail(to string, subject string) sentEmail {
+	h.t.Helper()
+
+	var sent sentEmail
+	require.Eventually(h.t, func() bool {
+		var ok bool
+		sent, ok = outbox.find(to, subject)
+		return ok
+	}, 2*time.Second, 10*time.Millisecond, "no %q email to %s", subject, to)
+	return sent
+}
+
+// response is what the browser got back.
+type response struct {
+	Status   int
+	Location string
+	Body     string
+}
+
+// browser keeps the cookies of one visitor and does not follow redirects.
+type browser struct {
+	t      *testing.T
+	base   string
+	client *http.Client
+}
+
+func (h *harness) newBrowser() *browser {
+	jar, err := cookiejar.New(nil)
+	require.NoError(h.t, err)
+	return &browser{
+		t:    h.t,
+		base: h.server.URL,
+		client: &http.Client{
+			Jar: jar,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (b *browser) do(req *http.Request) *response {
+	b.t.Helper()
+
+	res, err := b.client.Do(req)
+	require.NoError(b.t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(b.t, err)
+	return &response{
+		Status:   res.StatusCode,
+		Location: res.Header.Get("Location"),
+		Body:     string(body),
+	}
+}
+
+func (b *browser) get(path string) *response {
+	b.t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, b.base+path, nil)
+	require.NoError(b.t, err)
+	return b.do(req)
+}
+
+func (b *browser) postForm(path string, form url.Values) *response {
+	b.t.Helper()
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		b.base+path,
+		strings.NewReader(form.Encode()),
+	)
+	require.NoError(b.t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return b.do(req)
+}
+
+func (b *browser) postJSON(path string, body interface{}) *response {
+	b.t.Helper()
+
+	js, err := json.Marshal(body)
+	require.NoError(b.t, err)
+	req, err := http.NewRequest(http.MethodPost, b.base+path, bytes.NewReader(js))
+	require.NoError(b.t, err)
+	req.Header.Set("Content-Type", "application/json")
+	return b.do(req)
+}
+
+// getJSON decodes the response of a JSON API into v.
+func (b *browser) getJSON(path string, v interface{}) {
+	b.t.Helper()
+
+	res := b.get(path)
+	require.Equal(b.t, http.StatusOK, res.Status, res.Body)
+	require.NoError(b.t, json.Unmarshal([]byte(res.Body), v))
+}
+
+// login signs the user in and checks the session cookie works.
+func (b *browser) login(userEmail string, password string) {
+	b.t.Helper()
+
+	res := b.postForm("/login?redirect_login=/", url.Values{
+		"email":    {userEmail},
+		"password": {password},
+	})
+	require.Equal(b.t, http.StatusFound, res.Status, res.Body)
+	require.Equal(b.t, "/", res.Location)
+}
+
+// adminLogin signs the admin in.
+func (b *browser) adminLogin(adminEmail string, password string) {
+	b.t.Helper()
+
+	res := b.postForm("/admin/login", url.Values{
+		"email":    {adminEmail},
+		"password": {password},
+	})
+	require.Equal(b.t, http.StatusFound, res.Status, res.Body)
+}
@@ -10,24 +10,64 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/ic3network/mccs-alpha/internal/pkg/ip"
 	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/ic3network/mccs-alpha/internal/pkg/metrics"
 )
 
-// Logging middleware logs messages.
+// statusRecorder keeps the status code written by the handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// Flush lets the streamed responses, e.g. the exports, reach the client.
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// isMonitoringRequest reports whether the request is a health check, a
+// metrics scrape or a static file, which are neither logged nor traced.
+func isMonitoringRequest(uri string) bool {
+	return uri == "/health" || uri == "/ram" || uri == "/cpu" ||
+		uri == "/disk" || uri == "/es-lag" || uri == "/metrics" ||
+		uri == "/livez" || uri == "/readyz" ||
+		strings.HasPrefix(uri, "/static")
+}
+
+// routeTemplate returns the path template of the matched route, so the ids
+// in the paths are not recorded as separate routes.
+func routeTemplate(r *http.Request) string {
+	if current := mux.CurrentRoute(r); current != nil {
+		if tpl, err := current.GetPathTemplate(); err == nil {
+			return tpl
+		}
+	}
+	return "unknown"
+}
+
+// Logging middleware logs messages and records the request metrics.
 func Logging() mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			startTime := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
 
 			defer func() {
+				elapse := time.Now().Sub(startTime)
+				route := routeTemplate(r)
+				metrics.ObserveRequest(route, r.Method, recorder.status, elapse)
+
 				uri := r.RequestURI
-				// Skip for the health check and static requests.
-				if uri == "/health" || uri == "/ram" || uri == "/cpu" ||
-					uri == "/disk" ||
-					strings.HasPrefix(uri, "/static") {
+				if isMonitoringRequest(uri) {
 					return
 				}
-				elapse := time.Now().Sub(startTime)
-				l.Logger.Info("request",
+				l.WithContext(r.Context()).Info("request",
 					zap.String("ip", ip.FromRequest(r)),
 					zap.String("method", r.Method),
 					zap.String("uri", uri),
@@ -35,7 +75,7 @@ func Logging() mux.MiddlewareFunc {
 					zap.Duration("responseTime", elapse))
 			}()
 
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(recorder, r)
 		})
 	}
 }
@@ -16,7 +16,7 @@ func Recover() mux.MiddlewareFunc {
 				if err := recover(); err != nil {
 					buf := make([]byte, 1024)
 					runtime.Stack(buf, false)
-					l.Logger.Error(
+					l.WithContext(r.Context()).Error(
 						"recover, error",
 						zap.Any("err", err),
 						zap.ByteString("method", buf),
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/requestid"
+)
+
+// RequestID gives every request an ID, kept from the X-Request-ID header
+// when the proxy sets one. The ID is echoed in the response, added to the
+// log lines and shown on the error pages.
+func RequestID() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := requestid.New(r.Header.Get(helper.CorrelationIDHeader))
+			r.Header.Set(helper.CorrelationIDHeader, id)
+			w.Header().Set(helper.CorrelationIDHeader, id)
+			next.ServeHTTP(w, r.WithContext(requestid.NewContext(r.Context(), id)))
+		})
+	}
+}
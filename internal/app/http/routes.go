@@ -11,6 +11,8 @@ import (
 func RegisterRoutes(r *mux.Router) {
 	public := r.PathPrefix("/").Subrouter()
 	public.Use(
+		middleware.RequestID(),
+		middleware.Tracing(),
 		middleware.Recover(),
 		middleware.NoCache(),
 		middleware.Logging(),
@@ -18,6 +20,8 @@ func RegisterRoutes(r *mux.Router) {
 	)
 	private := r.PathPrefix("/").Subrouter()
 	private.Use(
+		middleware.RequestID(),
+		middleware.Tracing(),
 		middleware.Recover(),
 		middleware.NoCache(),
 		middleware.Logging(),
@@ -26,6 +30,8 @@ func RegisterRoutes(r *mux.Router) {
 	)
 	adminPublic := r.PathPrefix("/admin").Subrouter()
 	adminPublic.Use(
+		middleware.RequestID(),
+		middleware.Tracing(),
 		middleware.Recover(),
 		middleware.NoCache(),
 		middleware.Logging(),
@@ -33,6 +39,8 @@ func RegisterRoutes(r *mux.Router) {
 	)
 	adminPrivate := r.PathPrefix("/admin").Subrouter()
 	adminPrivate.Use(
+		middleware.RequestID(),
+		middleware.Tracing(),
 		middleware.Recover(),
 		middleware.NoCache(),
 		middleware.Logging(),
@@ -117,6 +125,42 @@ func RegisterRoutes(r *mux.Router) {
 		adminPublic,
 		adminPrivate,
 	)
+	controller.AdminAnalyticsHandler.RegisterRoutes(
+		public,
+		private,
+		adminPublic,
+		adminPrivate,
+	)
+	controller.AdminDirectoryHandler.RegisterRoutes(
+		public,
+		private,
+		adminPublic,
+		adminPrivate,
+	)
+	controller.AdminAccountFlagHandler.RegisterRoutes(
+		public,
+		private,
+		adminPublic,
+		adminPrivate,
+	)
+	controller.AdminTradingApplicationHandler.RegisterRoutes(
+		public,
+		private,
+		adminPublic,
+		adminPrivate,
+	)
+	controller.AdminAgreementHandler.RegisterRoutes(
+		public,
+		private,
+		adminPublic,
+		adminPrivate,
+	)
+	controller.AdminJobHandler.RegisterRoutes(
+		public,
+		private,
+		adminPublic,
+		adminPrivate,
+	)
 	controller.LogHandler.RegisterRoutes(
 		public,
 		private,
@@ -124,6 +168,12 @@ func RegisterRoutes(r *mux.Router) {
 		adminPrivate,
 	)
 
+	controller.AgreementHandler.RegisterRoutes(
+		public,
+		private,
+		adminPublic,
+		adminPrivate,
+	)
 	controller.AccountHandler.RegisterRoutes(
 		public,
 		private,
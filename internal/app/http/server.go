@@ -1,27 +1,31 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha/internal/app/http/controller"
 	"github.com/ic3network/mccs-alpha/internal/pkg/l"
-	"go.uber.org/zap"
 )
 
 // AppServer contains the information to run a server.
-type appServer struct{}
+type appServer struct {
+	srv *http.Server
+}
 
 var AppServer = &appServer{}
 
-// Run will start the http server.
-func (a *appServer) Run(port string) {
+// Run will start the http server. It returns nil once the server is shut down.
+func (a *appServer) Run(port string) error {
 	r := mux.NewRouter().StrictSlash(true)
 	// New Implementation
 	RegisterRoutes(r)
 
-	srv := &http.Server{
+	a.srv = &http.Server{
 		Addr:         fmt.Sprintf("0.0.0.0:%s", port),
 		WriteTimeout: time.Second * 15,
 		ReadTimeout:  time.Second * 15,
@@ -31,7 +35,22 @@ func (a *appServer) Run(port string) {
 
 	l.Logger.Info("app is running at localhost:" + port)
 
-	if err := srv.ListenAndServe(); err != nil {
-		l.Logger.Fatal("ListenAndServe failed", zap.Error(err))
+	err := a.srv.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops accepting connections and waits for the in-flight requests
+// and the work they started in the background until the context is done.
+func (a *appServer) Shutdown(ctx context.Context) error {
+	if a.srv == nil {
+		return nil
+	}
+	err := a.srv.Shutdown(ctx)
+	if err != nil {
+		return err
 	}
+	return controller.WaitBackground(ctx)
 }
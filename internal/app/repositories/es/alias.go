@@ -0,0 +1,139 @@
+package es
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/olivere/elastic/v7"
+)
+
+// Indexes returns the aliases used by the application.
+func Indexes() []string {
+	return indexes
+}
+
+// CreateIndex creates a new versioned index for the alias
+// with the current mapping. The alias is not moved.
+func CreateIndex(alias string) (string, error) {
+	index, err := createIndex(client, alias)
+	if err != nil {
+		return "", e.Wrap(err, "es CreateIndex failed")
+	}
+	return index, nil
+}
+
+// IndicesByAlias returns the physical indexes the alias points to.
+func IndicesByAlias(alias string) ([]string, error) {
+	res, err := client.Aliases().Do(context.Background())
+	if err != nil {
+		return nil, e.Wrap(err, "es IndicesByAlias failed")
+	}
+	return res.IndicesByAlias(alias), nil
+}
+
+// Versions returns all the versioned indexes of the alias, oldest first.
+func Versions(alias string) ([]string, error) {
+	names, err := client.IndexNames()
+	if err != nil {
+		return nil, e.Wrap(err, "es Versions failed")
+	}
+	versions := make([]string, 0, len(names))
+	for _, name := range names {
+		version := strings.TrimPrefix(name, alias+"_")
+		if version != name && isIndexVersion(version) {
+			versions = append(versions, name)
+		}
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// SwapAlias atomically points the alias to the index.
+// If the alias name is still taken by a concrete index (created before
+// aliases were introduced), that index is removed in the same request.
+func SwapAlias(alias, index string) error {
+	ctx := context.Background()
+
+	current, err := IndicesByAlias(alias)
+	if err != nil {
+		return e.Wrap(err, "es SwapAlias failed")
+	}
+
+	actions := []elastic.AliasAction{
+		elastic.NewAliasAddAction(alias).Index(index),
+	}
+	if len(current) == 0 {
+		legacy, err := client.IndexExists(alias).Do(ctx)
+		if err != nil {
+			return e.Wrap(err, "es SwapAlias failed")
+		}
+		if legacy {
+			actions = append(actions, elastic.NewAliasRemoveIndexAction(alias))
+		}
+	}
+	for _, name := range current {
+		if name != index {
+			actions = append(actions, elastic.NewAliasRemoveAction(alias).Index(name))
+		}
+	}
+
+	_, err = client.Alias().Action(actions...).Do(ctx)
+	if err != nil {
+		return e.Wrap(err, "es SwapAlias failed")
+	}
+	return nil
+}
+
+// PruneVersions deletes the versioned indexes of the alias but the newest
+// keep ones and returns the deleted ones. The indexes the alias points to
+// are never deleted.
+func PruneVersions(alias string, keep int) ([]string, error) {
+	versions, err := Versions(alias)
+	if err != nil {
+		return nil, e.Wrap(err, "es PruneVersions failed")
+	}
+	current, err := IndicesByAlias(alias)
+	if err != nil {
+		return nil, e.Wrap(err, "es PruneVersions failed")
+	}
+
+	deleted := []string{}
+	for _, index := range prunable(versions, current, keep) {
+		err := DeleteIndex(index)
+		if err != nil {
+			return deleted, e.Wrap(err, "es PruneVersions failed")
+		}
+		deleted = append(deleted, index)
+	}
+	return deleted, nil
+}
+
+// prunable returns the versions older than the newest keep ones that the
+// alias does not point to. The versions are sorted oldest first.
+func prunable(versions []string, current []string, keep int) []string {
+	if len(versions) <= keep {
+		return nil
+	}
+	inUse := make(map[string]bool, len(current))
+	for _, index := range current {
+		inUse[index] = true
+	}
+	old := []string{}
+	for _, index := range versions[:len(versions)-keep] {
+		if !inUse[index] {
+			old = append(old, index)
+		}
+	}
+	return old
+}
+
+// DeleteIndex deletes a versioned index.
+func DeleteIndex(index string) error {
+	_, err := client.DeleteIndex(index).Do(context.Background())
+	if err != nil {
+		return e.Wrap(err, "es DeleteIndex failed")
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package es
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexVersion(t *testing.T) {
+	first := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	second := first.Add(time.Millisecond)
+
+	assert.Equal(t, "20200102030405000000006", indexVersion(first))
+	assert.NotEqual(t, indexVersion(first), indexVersion(second), "the same second")
+	assert.True(t, isIndexVersion(indexVersion(first)))
+	assert.True(t, isIndexVersion("20200102030405"), "the versions with only the seconds")
+	assert.False(t, isIndexVersion("backup"))
+
+	versions := []string{
+		"businesses_" + indexVersion(second),
+		"businesses_20200102030405",
+		"businesses_" + indexVersion(first),
+	}
+	sort.Strings(versions)
+	assert.Equal(t, []string{
+		"businesses_20200102030405",
+		"businesses_" + indexVersion(first),
+		"businesses_" + indexVersion(second),
+	}, versions, "the versions sort in creation order")
+}
+
+func TestPrunable(t *testing.T) {
+	versions := []string{"tags_1", "tags_2", "tags_3", "tags_4"}
+
+	tests := []struct {
+		name     string
+		current  []string
+		keep     int
+		expected []string
+	}{
+		{
+			"should delete the old versions",
+			[]string{"tags_4"},
+			2,
+			[]string{"tags_1", "tags_2"},
+		},
+		{
+			"should not delete the version the alias points to",
+			[]string{"tags_1"},
+			2,
+			[]string{"tags_2"},
+		},
+		{
+			"should not delete anything when there are few versions",
+			[]string{"tags_4"},
+			4,
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, prunable(versions, tt.current, tt.keep))
+		})
+	}
+}
@@ -3,17 +3,16 @@ package es
 import (
 	"context"
 	"encoding/json"
+	"sort"
 	"time"
 
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/global/constant"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
-	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
 	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
 	"github.com/ic3network/mccs-alpha/internal/pkg/util"
 	"github.com/olivere/elastic/v7"
-	"github.com/spf13/viper"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type business struct {
@@ -28,25 +27,13 @@ func (es *business) Register(client *elastic.Client) {
 	es.index = "businesses"
 }
 
-func (es *business) Create(
-	id primitive.ObjectID,
-	data *types.BusinessData,
-) error {
-	body := types.BusinessESRecord{
-		BusinessID:      id.Hex(),
-		BusinessName:    data.BusinessName,
-		Offers:          data.Offers,
-		Wants:           data.Wants,
-		LocationCity:    data.LocationCity,
-		LocationCountry: data.LocationCountry,
-		Status:          constant.Business.Pending,
-		AdminTags:       data.AdminTags,
-	}
+// Index writes the whole record of a business.
+func (es *business) Index(ctx context.Context, r *types.BusinessESRecord) error {
 	_, err := es.c.Index().
 		Index(es.index).
-		Id(id.Hex()).
-		BodyJson(body).
-		Do(context.Background())
+		Id(r.BusinessID).
+		BodyJson(r).
+		Do(ctx)
 	if err != nil {
 		return err
 	}
@@ -96,17 +83,64 @@ func matchTags(q *elastic.BoolQuery, c *types.SearchCriteria) {
 	}
 }
 
+// facet is a keyword field counted by Find.
+type facet struct {
+	name     string
+	field    string
+	selected func(c *types.SearchCriteria) []string
+	counts   func(f *types.BusinessFacets, counts []*types.FacetCount)
+}
+
+var facets = []*facet{
+	{
+		name:     "adminTags",
+		field:    "adminTags.keyword",
+		selected: func(c *types.SearchCriteria) []string { return c.AdminTags },
+		counts:   func(f *types.BusinessFacets, counts []*types.FacetCount) { f.AdminTags = counts },
+	},
+	{
+		name:     "locationCountry",
+		field:    "locationCountry.keyword",
+		selected: func(c *types.SearchCriteria) []string { return c.LocationCountries },
+		counts:   func(f *types.BusinessFacets, counts []*types.FacetCount) { f.LocationCountries = counts },
+	},
+	{
+		name:     "locationCity",
+		field:    "locationCity.keyword",
+		selected: func(c *types.SearchCriteria) []string { return c.LocationCities },
+		counts:   func(f *types.BusinessFacets, counts []*types.FacetCount) { f.LocationCities = counts },
+	},
+	{
+		name:     "status",
+		field:    "status.keyword",
+		selected: func(c *types.SearchCriteria) []string { return c.FacetStatuses },
+		counts:   func(f *types.BusinessFacets, counts []*types.FacetCount) { f.Statuses = counts },
+	},
+}
+
+// facetFilters returns a filter for every facet with selected values.
+func facetFilters(c *types.SearchCriteria) map[string]elastic.Query {
+	filters := map[string]elastic.Query{}
+	for _, f := range facets {
+		if selected := f.selected(c); len(selected) != 0 {
+			filters[f.name] = elastic.NewTermsQuery(f.field, toInterfaces(selected)...)
+		}
+	}
+	return filters
+}
+
 func (es *business) Find(
+	ctx context.Context,
 	c *types.SearchCriteria,
 	page int64,
-) ([]string, int, int, error) {
+) (*types.SearchBusinessResult, error) {
 	if page < 0 || page == 0 {
-		return nil, 0, 0, e.New(e.InvalidPageNumber, "find business failed")
+		return nil, e.New(e.InvalidPageNumber, "find business failed")
 	}
 
 	var ids []string
-	size := viper.GetInt("page_size")
-	from := viper.GetInt("page_size") * (int(page) - 1)
+	size := global.Config().PageSize
+	from := global.Config().PageSize * (int(page) - 1)
 
 	q := elastic.NewBoolQuery()
 
@@ -136,161 +170,224 @@ func (es *business) Find(
 
 	matchTags(q, c)
 
-	res, err := es.c.Search().
+	// The facets filter the hits after the aggregations are computed, and
+	// each facet is counted with the filters of the other facets only.
+	filters := facetFilters(c)
+	postFilter := elastic.NewBoolQuery()
+	for _, filter := range filters {
+		postFilter.Filter(filter)
+	}
+
+	search := es.c.Search().
 		Index(es.index).
 		From(from).
 		Size(size).
 		Query(q).
-		Do(context.Background())
+		PostFilter(postFilter)
+	for _, f := range facets {
+		others := elastic.NewBoolQuery()
+		for name, filter := range filters {
+			if name != f.name {
+				others.Filter(filter)
+			}
+		}
+		search.Aggregation(f.name, elastic.NewFilterAggregation().
+			Filter(others).
+			SubAggregation("values", elastic.NewTermsAggregation().
+				Field(f.field).
+				Size(global.Config().FacetSize)))
+	}
 
+	res, err := search.Do(ctx)
 	if err != nil {
-		return nil, 0, 0, e.Wrap(err, "BusinessES Find failed")
+		return nil, e.Wrap(err, "BusinessES Find failed")
 	}
 
 	for _, hit := range res.Hits.Hits {
 		var record types.BusinessESRecord
 		err := json.Unmarshal(hit.Source, &record)
 		if err != nil {
-			return nil, 0, 0, e.Wrap(err, "BusinessES Find failed")
+			return nil, e.Wrap(err, "BusinessES Find failed")
 		}
 		ids = append(ids, record.BusinessID)
 	}
 
 	numberOfResults := res.Hits.TotalHits.Value
-	totalPages := pagination.Pages(numberOfResults, viper.GetInt64("page_size"))
-
-	return ids, int(numberOfResults), totalPages, nil
+	totalPages := pagination.Pages(numberOfResults, int64(global.Config().PageSize))
+
+	return &types.SearchBusinessResult{
+		IDs:             ids,
+		NumberOfResults: int(numberOfResults),
+		TotalPages:      totalPages,
+		Facets:          facetCounts(res.Aggregations, c),
+	}, nil
 }
 
-func (es *business) UpdateBusiness(
-	id primitive.ObjectID,
-	data *types.BusinessData,
-) error {
-	params := map[string]interface{}{
-		"businessName":    data.BusinessName,
-		"locationCity":    data.LocationCity,
-		"locationCountry": data.LocationCountry,
-		"offersAdded":     helper.ToTagFields(data.OffersAdded),
-		"wantsAdded":      helper.ToTagFields(data.WantsAdded),
-		"offersRemoved":   data.OffersRemoved,
-		"wantsRemoved":    data.WantsRemoved,
-	}
-	if data.Status != "" {
-		params["status"] = data.Status
-	}
-	params["adminTags"] = data.AdminTags
-
-	script := elastic.
-		NewScript(`
-			ctx._source.businessName = params.businessName;
-			ctx._source.locationCity = params.locationCity;
-			ctx._source.locationCountry = params.locationCountry;
-
-			if (params.status !== null) {
-				ctx._source.status = params.status;
-			}
-
-			if (params.adminTags !== null) {
-				if (params.adminTags.length !== 0) {
-					ctx._source.adminTags = params.adminTags;
-				} else {
-					ctx._source.adminTags = [];
-				}
-			}
-
-			if (params.offersRemoved !== null && params.offersRemoved.length !== 0) {
-				for (int i = 0; i < ctx._source.offers.length; i++) {
-					if (params.offersRemoved.contains(ctx._source.offers[i].name)) {
-						ctx._source.offers.remove(i);
-						i--
-					}
-				}
-			}
-			if (params.wantsRemoved !== null && params.wantsRemoved.length !== 0) {
-				for (int i = 0; i < ctx._source.wants.length; i++) {
-					if (params.wantsRemoved.contains(ctx._source.wants[i].name)) {
-						ctx._source.wants.remove(i);
-						i--
+func facetCounts(aggs elastic.Aggregations, c *types.SearchCriteria) *types.BusinessFacets {
+	result := &types.BusinessFacets{}
+	for _, f := range facets {
+		selected := map[string]bool{}
+		for _, value := range f.selected(c) {
+			selected[value] = true
+		}
+		counts := []*types.FacetCount{}
+		if filtered, ok := aggs.Filter(f.name); ok {
+			if terms, ok := filtered.Terms("values"); ok {
+				for _, bucket := range terms.Buckets {
+					value, ok := bucket.Key.(string)
+					if !ok || value == "" {
+						continue
 					}
+					counts = append(counts, &types.FacetCount{
+						Value:    value,
+						Count:    int(bucket.DocCount),
+						Selected: selected[value],
+					})
 				}
 			}
+		}
+		f.counts(result, counts)
+	}
+	return result
+}
 
-			if (params.offersAdded !== null && params.offersAdded.length !== 0) {
-				for (int i = 0; i < params.offersAdded.length; i++) {
-					ctx._source.offers.add(params.offersAdded[i]);
-				}
-			}
-			if (params.wantsAdded !== null && params.wantsAdded.length !== 0) {
-				for (int i = 0; i < params.wantsAdded.length; i++) {
-					ctx._source.wants.add(params.wantsAdded[i]);
-				}
-			}
-		`).
-		Params(params)
+// FindSimilar scores the trading members by the number of tags they share
+// with the criteria.
+func (es *business) FindSimilar(
+	ctx context.Context,
+	c *types.SimilarCriteria,
+	size int,
+) ([]string, error) {
+	if len(c.Offers) == 0 && len(c.Wants) == 0 {
+		return []string{}, nil
+	}
 
-	_, err := es.c.Update().
-		Index(es.index).
-		Id(id.Hex()).
-		Script(script).
-		Do(context.Background())
-	if err != nil {
-		return err
+	q := elastic.NewBoolQuery().
+		Filter(elastic.NewMatchQuery("status", constant.Trading.Accepted)).
+		MinimumNumberShouldMatch(1)
+	if len(c.ExcludeIDs) != 0 {
+		q.MustNot(elastic.NewIdsQuery().Ids(c.ExcludeIDs...))
+	}
+	// Each shared tag adds one to the score.
+	if len(c.Offers) != 0 {
+		q.Should(elastic.NewNestedQuery(
+			"offers",
+			elastic.NewTermsQuery("offers.name.keyword", toInterfaces(c.Offers)...),
+		).ScoreMode("sum"))
+	}
+	if len(c.Wants) != 0 {
+		q.Should(elastic.NewNestedQuery(
+			"wants",
+			elastic.NewTermsQuery("wants.name.keyword", toInterfaces(c.Wants)...),
+		).ScoreMode("sum"))
 	}
-	return nil
-}
 
-func (es *business) UpdateTradingInfo(
-	id primitive.ObjectID,
-	data *types.TradingRegisterData,
-) error {
-	doc := map[string]interface{}{
-		"businessName":    data.BusinessName,
-		"locationCity":    data.LocationCity,
-		"locationCountry": data.LocationCountry,
-		"status":          constant.Trading.Pending,
-	}
-	_, err := es.c.Update().
+	res, err := es.c.Search().
 		Index(es.index).
-		Id(id.Hex()).
-		Doc(doc).
-		Do(context.Background())
+		Size(size).
+		Query(q).
+		Do(ctx)
 	if err != nil {
-		return err
+		return nil, e.Wrap(err, "BusinessES FindSimilar failed")
 	}
-	return nil
+
+	ids := make([]string, 0, len(res.Hits.Hits))
+	for _, hit := range res.Hits.Hits {
+		ids = append(ids, hit.Id)
+	}
+	return ids, nil
 }
 
-func (es *business) UpdateAllTagsCreatedAt(
-	id primitive.ObjectID,
-	t time.Time,
-) error {
-	params := map[string]interface{}{
-		"createdAt": t,
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		result = append(result, v)
 	}
+	return result
+}
 
-	script := elastic.
-		NewScript(`
-			for (int i = 0; i < ctx._source.offers.length; i++) {
-				ctx._source.offers[i].createdAt = params.createdAt
-			}
-			for (int i = 0; i < ctx._source.wants.length; i++) {
-				ctx._source.wants[i].createdAt = params.createdAt
-			}
-		`).
-		Params(params)
+// SuggestTags returns the offers or wants of trading members starting
+// with the prefix, ranked by the number of members using them and then by
+// how recently they were added.
+func (es *business) SuggestTags(
+	ctx context.Context,
+	tagType string,
+	prefix string,
+	size int,
+) ([]*types.TagSuggestion, error) {
+	// The prefix can start the whole tag or any of its parts, so both
+	// "bre" and "fresh-b" suggest "fresh-bread".
+	tagQuery := elastic.NewBoolQuery().
+		Should(elastic.NewPrefixQuery(tagType+".name.keyword", prefix)).
+		Should(elastic.NewMatchQuery(tagType+".name.suggest", prefix).Operator("and"))
+
+	q := elastic.NewBoolQuery().
+		Filter(elastic.NewMatchQuery("status", constant.Trading.Accepted)).
+		Filter(elastic.NewNestedQuery(tagType, tagQuery))
+
+	// Fetch more candidates than needed so that tags used by the same
+	// number of members can be ordered by recency.
+	names := elastic.NewTermsAggregation().
+		Field(tagType+".name.keyword").
+		Size(size*3).
+		SubAggregation("lastAddedAt", elastic.NewMaxAggregation().Field(tagType+".createdAt"))
+	agg := elastic.NewNestedAggregation().
+		Path(tagType).
+		SubAggregation("matched", elastic.NewFilterAggregation().
+			Filter(tagQuery).
+			SubAggregation("names", names))
 
-	_, err := es.c.Update().
+	res, err := es.c.Search().
 		Index(es.index).
-		Id(id.Hex()).
-		Script(script).
-		Do(context.Background())
+		Size(0).
+		Query(q).
+		Aggregation("tags", agg).
+		Do(ctx)
 	if err != nil {
-		return err
+		return nil, e.Wrap(err, "BusinessES SuggestTags failed")
 	}
-	return nil
+
+	suggestions := []*types.TagSuggestion{}
+	nested, ok := res.Aggregations.Nested("tags")
+	if !ok {
+		return suggestions, nil
+	}
+	matched, ok := nested.Filter("matched")
+	if !ok {
+		return suggestions, nil
+	}
+	terms, ok := matched.Terms("names")
+	if !ok {
+		return suggestions, nil
+	}
+	for _, bucket := range terms.Buckets {
+		name, ok := bucket.Key.(string)
+		if !ok {
+			continue
+		}
+		suggestion := &types.TagSuggestion{
+			Name:  name,
+			Count: int(bucket.DocCount),
+		}
+		if max, ok := bucket.Max("lastAddedAt"); ok && max.Value != nil {
+			suggestion.LastAddedAt = time.Unix(0, int64(*max.Value)*int64(time.Millisecond)).UTC()
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Count != suggestions[j].Count {
+			return suggestions[i].Count > suggestions[j].Count
+		}
+		return suggestions[i].LastAddedAt.After(suggestions[j].LastAddedAt)
+	})
+	if len(suggestions) > size {
+		suggestions = suggestions[:size]
+	}
+	return suggestions, nil
 }
 
-func (es *business) RenameTag(old string, new string) error {
+func (es *business) RenameTag(ctx context.Context, old string, new string) error {
 	query := elastic.NewBoolQuery()
 	query.Should(elastic.NewMatchQuery("offers.name", old))
 	query.Should(elastic.NewMatchQuery("wants.name", old))
@@ -311,14 +408,14 @@ func (es *business) RenameTag(old string, new string) error {
 	_, err := es.c.UpdateByQuery(es.index).
 		Query(query).
 		Script(script).
-		Do(context.Background())
+		Do(ctx)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (es *business) RenameAdminTag(old string, new string) error {
+func (es *business) RenameAdminTag(ctx context.Context, old string, new string) error {
 	query := elastic.NewMatchQuery("adminTags", old)
 	script := elastic.
 		NewScript(`
@@ -331,25 +428,26 @@ func (es *business) RenameAdminTag(old string, new string) error {
 	_, err := es.c.UpdateByQuery(es.index).
 		Query(query).
 		Script(script).
-		Do(context.Background())
+		Do(ctx)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (es *business) Delete(id string) error {
+// Delete is a no-op when the record doesn't exist.
+func (es *business) Delete(ctx context.Context, id string) error {
 	_, err := es.c.Delete().
 		Index(es.index).
 		Id(id).
-		Do(context.Background())
-	if err != nil {
+		Do(ctx)
+	if err != nil && !elastic.IsNotFound(err) {
 		return err
 	}
 	return nil
 }
 
-func (es *business) DeleteTag(name string) error {
+func (es *business) DeleteTag(ctx context.Context, name string) error {
 	query := elastic.NewBoolQuery()
 	query.Should(elastic.NewMatchQuery("offers.name", name))
 	query.Should(elastic.NewMatchQuery("wants.name", name))
@@ -372,14 +470,14 @@ func (es *business) DeleteTag(name string) error {
 	_, err := es.c.UpdateByQuery(es.index).
 		Query(query).
 		Script(script).
-		Do(context.Background())
+		Do(ctx)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (es *business) DeleteAdminTags(name string) error {
+func (es *business) DeleteAdminTags(ctx context.Context, name string) error {
 	query := elastic.NewMatchQuery("adminTags", name)
 	script := elastic.
 		NewScript(`
@@ -391,7 +489,7 @@ func (es *business) DeleteAdminTags(name string) error {
 	_, err := es.c.UpdateByQuery(es.index).
 		Query(query).
 		Script(script).
-		Do(context.Background())
+		Do(ctx)
 	if err != nil {
 		return err
 	}
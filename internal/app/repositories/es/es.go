@@ -1,18 +1,28 @@
 package es
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"time"
 
 	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/pkg/metrics"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 	"github.com/olivere/elastic/v7"
-	"github.com/spf13/viper"
 )
 
 var client *elastic.Client
 
 func init() {
 	global.Init()
+	// The in-process search backend runs without Elasticsearch.
+	// TODO: set up test docker environment.
+	if global.Config().IsTest() ||
+		global.Config().IsMemorySearch() {
+		return
+	}
 	client = New()
 	registerCollections(client)
 }
@@ -30,8 +40,11 @@ func New() *elastic.Client {
 
 	for {
 		client, err = elastic.NewClient(
-			elastic.SetURL(viper.GetString("es.url")),
+			elastic.SetURL(global.Config().ES.URL),
 			elastic.SetSniff(false),
+			elastic.SetHttpClient(&http.Client{
+				Transport: &tracing.ESTransport{Next: &metrics.ESTransport{}},
+			}),
 		)
 		if err != nil {
 			log.Printf("ElasticSearch connection error: %+v \n", err)
@@ -49,3 +62,26 @@ func New() *elastic.Client {
 func Client() *elastic.Client {
 	return client
 }
+
+// Ping checks that the Elasticsearch cluster is reachable and not red.
+func Ping(ctx context.Context) error {
+	if client == nil {
+		return errors.New("elasticsearch is not connected")
+	}
+	health, err := client.ClusterHealth().Do(ctx)
+	if err != nil {
+		return err
+	}
+	if health.Status == "red" {
+		return errors.New("elasticsearch cluster status is red")
+	}
+	return nil
+}
+
+// Close stops the background processes of the client.
+func Close() {
+	if client == nil {
+		return
+	}
+	client.Stop()
+}
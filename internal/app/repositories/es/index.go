@@ -2,7 +2,10 @@ package es
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/olivere/elastic/v7"
 )
@@ -13,10 +16,13 @@ func checkIndexes(client *elastic.Client) {
 	}
 }
 
-func checkIndex(client *elastic.Client, index string) {
+// checkIndex makes sure the alias points to a physical index.
+// A concrete index created before aliases were introduced is left
+// untouched until es-reindex migrates it behind an alias.
+func checkIndex(client *elastic.Client, alias string) {
 	ctx := context.Background()
 
-	exists, err := client.IndexExists(index).Do(ctx)
+	exists, err := client.IndexExists(alias).Do(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -25,23 +31,68 @@ func checkIndex(client *elastic.Client, index string) {
 		return
 	}
 
-	createIndex, err := client.CreateIndex(index).
-		BodyString(indexMappings[index]).
-		Do(ctx)
+	index, err := createIndex(client, alias)
+	if err != nil {
+		panic(err)
+	}
+	_, err = client.Alias().Add(index, alias).Do(ctx)
 	if err != nil {
 		panic(err)
 	}
+	log.Println("Successfully created " + index + " index")
+}
+
+// createIndex creates a new versioned physical index for the alias.
+func createIndex(client *elastic.Client, alias string) (string, error) {
+	index := alias + "_" + indexVersion(time.Now())
+
+	createIndex, err := client.CreateIndex(index).
+		BodyString(indexMappings[alias]).
+		Do(context.Background())
+	if err != nil {
+		return "", err
+	}
 	if !createIndex.Acknowledged {
-		panic("CreateIndex " + index + " was not acknowledged.")
-	} else {
-		log.Println("Successfully created " + index + " index")
+		return "", errors.New("CreateIndex " + index + " was not acknowledged.")
+	}
+	return index, nil
+}
+
+// indexVersionLayout is appended to the alias to name the physical index,
+// e.g. businesses_20060102150405 followed by the nanoseconds. Versions sort
+// in creation order. The indexes created before the nanoseconds were added
+// only have the seconds.
+const indexVersionLayout = "20060102150405"
+
+// indexVersion returns the version of an index created at t. Two reindex
+// runs in the same second get different versions.
+func indexVersion(t time.Time) string {
+	t = t.UTC()
+	return t.Format(indexVersionLayout) + fmt.Sprintf("%09d", t.Nanosecond())
+}
+
+// isIndexVersion reports whether the suffix of an index name is a version.
+func isIndexVersion(version string) bool {
+	if len(version) != len(indexVersionLayout) &&
+		len(version) != len(indexVersionLayout)+9 {
+		return false
 	}
+	for _, c := range version {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
 }
 
+// indexes are the aliases the repositories read from and write to.
 var indexes = []string{"businesses", "users", "tags"}
 
 // Notes:
-// 1. Using nested fields for arrays of objects.
+// 1. Mappings are keyed by alias.
+// 2. Using nested fields for arrays of objects.
+// 3. The "suggest" fields of offers and wants autocomplete tags. Existing
+// indexes get them after running es-reindex.
 var indexMappings = map[string]string{
 	"businesses": `
 	{
@@ -55,6 +106,39 @@ var indexMappings = map[string]string{
 							"lowercase",
 							"asciifolding"
 						]
+					},
+					"tag_suggest_analyzer": {
+						"type": "custom",
+						"tokenizer": "tag_part_tokenizer",
+						"filter": [
+							"lowercase",
+							"asciifolding",
+							"tag_edge_ngram"
+						]
+					},
+					"tag_suggest_search_analyzer": {
+						"type": "custom",
+						"tokenizer": "tag_part_tokenizer",
+						"filter": [
+							"lowercase",
+							"asciifolding"
+						]
+					}
+				},
+				"tokenizer": {
+					"tag_part_tokenizer": {
+						"type": "char_group",
+						"tokenize_on_chars": [
+							"whitespace",
+							"-"
+						]
+					}
+				},
+				"filter": {
+					"tag_edge_ngram": {
+						"type": "edge_ngram",
+						"min_gram": 1,
+						"max_gram": 20
 					}
 				}
 			}
@@ -129,6 +213,11 @@ var indexMappings = map[string]string{
 								"keyword": {
 									"type": "keyword",
 									"ignore_above": 256
+								},
+								"suggest": {
+									"type": "text",
+									"analyzer": "tag_suggest_analyzer",
+									"search_analyzer": "tag_suggest_search_analyzer"
 								}
 							}
 						}
@@ -147,6 +236,11 @@ var indexMappings = map[string]string{
 								"keyword": {
 									"type": "keyword",
 									"ignore_above": 256
+								},
+								"suggest": {
+									"type": "text",
+									"analyzer": "tag_suggest_analyzer",
+									"search_analyzer": "tag_suggest_search_analyzer"
 								}
 							}
 						}
@@ -0,0 +1,67 @@
+package es
+
+import (
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// NewRecord decodes a MongoDB document of the collection
+// and builds its ES id and record.
+func NewRecord(collection string, raw bson.Raw) (string, interface{}, error) {
+	switch collection {
+	case "businesses":
+		var b types.Business
+		if err := bson.Unmarshal(raw, &b); err != nil {
+			return "", nil, e.Wrap(err, "es NewRecord failed")
+		}
+		return b.ID.Hex(), NewBusinessRecord(&b), nil
+	case "users":
+		var u types.User
+		if err := bson.Unmarshal(raw, &u); err != nil {
+			return "", nil, e.Wrap(err, "es NewRecord failed")
+		}
+		return u.ID.Hex(), NewUserRecord(&u), nil
+	case "tags":
+		var t types.Tag
+		if err := bson.Unmarshal(raw, &t); err != nil {
+			return "", nil, e.Wrap(err, "es NewRecord failed")
+		}
+		return t.ID.Hex(), NewTagRecord(&t), nil
+	}
+	return "", nil, e.New(e.InternalServerError, "es NewRecord: unknown collection "+collection)
+}
+
+// NewBusinessRecord builds the ES document of a business.
+func NewBusinessRecord(b *types.Business) *types.BusinessESRecord {
+	return &types.BusinessESRecord{
+		BusinessID:      b.ID.Hex(),
+		BusinessName:    b.BusinessName,
+		Offers:          b.Offers,
+		Wants:           b.Wants,
+		LocationCity:    b.LocationCity,
+		LocationCountry: b.LocationCountry,
+		Status:          b.Status,
+		AdminTags:       b.AdminTags,
+	}
+}
+
+// NewUserRecord builds the ES document of a user.
+func NewUserRecord(u *types.User) *types.UserESRecord {
+	return &types.UserESRecord{
+		UserID:    u.ID.Hex(),
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Email:     u.Email,
+	}
+}
+
+// NewTagRecord builds the ES document of a tag.
+func NewTagRecord(t *types.Tag) *types.TagESRecord {
+	return &types.TagESRecord{
+		TagID:        t.ID.Hex(),
+		Name:         t.Name,
+		OfferAddedAt: t.OfferAddedAt,
+		WantAddedAt:  t.WantAddedAt,
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
 	"github.com/olivere/elastic/v7"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type tag struct {
@@ -23,133 +22,26 @@ func (es *tag) Register(client *elastic.Client) {
 	es.index = "tags"
 }
 
-func (es *tag) Create(id primitive.ObjectID, name string) error {
-	body := types.TagESRecord{
-		TagID: id.Hex(),
-		Name:  name,
-	}
+// Index writes the whole record of a tag.
+func (es *tag) Index(ctx context.Context, r *types.TagESRecord) error {
 	_, err := es.c.Index().
 		Index(es.index).
-		Id(id.Hex()).
-		BodyJson(body).
-		Do(context.Background())
+		Id(r.TagID).
+		BodyJson(r).
+		Do(ctx)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (es *tag) UpdateOffer(id string, name string) error {
-	exists, err := es.c.Exists().Index(es.index).Id(id).Do(context.TODO())
-	if err != nil {
-		return e.Wrap(err, "TagES UpdateOffer failed")
-	}
-	if !exists {
-		body := types.TagESRecord{
-			TagID:        id,
-			Name:         name,
-			OfferAddedAt: time.Now(),
-		}
-		_, err = es.c.Index().
-			Index(es.index).
-			Id(id).
-			BodyJson(body).
-			Do(context.Background())
-		if err != nil {
-			return e.Wrap(err, "TagES UpdateOffer failed")
-		}
-		return nil
-	}
-
-	params := map[string]interface{}{
-		"offerAddedAt": time.Now(),
-	}
-	script := elastic.
-		NewScript(`
-			ctx._source.offerAddedAt = params.offerAddedAt;
-		`).
-		Params(params)
-
-	_, err = es.c.Update().
-		Index(es.index).
-		Id(id).
-		Script(script).
-		Do(context.Background())
-	if err != nil {
-		return e.Wrap(err, "TagES UpdateOffer failed")
-	}
-	return nil
-}
-
-func (es *tag) UpdateWant(id string, name string) error {
-	exists, err := es.c.Exists().Index(es.index).Id(id).Do(context.TODO())
-	if err != nil {
-		return e.Wrap(err, "TagES UpdateWant failed")
-	}
-	if !exists {
-		body := types.TagESRecord{
-			TagID:       id,
-			Name:        name,
-			WantAddedAt: time.Now(),
-		}
-		_, err = es.c.Index().
-			Index(es.index).
-			Id(id).
-			BodyJson(body).
-			Do(context.Background())
-		if err != nil {
-			return e.Wrap(err, "TagES UpdateWant failed")
-		}
-		return nil
-	}
-
-	params := map[string]interface{}{
-		"wantAddedAt": time.Now(),
-	}
-	script := elastic.
-		NewScript(`
-			ctx._source.wantAddedAt = params.wantAddedAt;
-		`).
-		Params(params)
-
-	_, err = es.c.Update().
-		Index(es.index).
-		Id(id).
-		Script(script).
-		Do(context.Background())
-	if err != nil {
-		return e.Wrap(err, "TagES UpdateWant failed")
-	}
-	return nil
-}
-
-func (es *tag) Rename(t *types.Tag) error {
-	params := map[string]interface{}{
-		"name": t.Name,
-	}
-	script := elastic.
-		NewScript(`
-			ctx._source.name = params.name;
-		`).
-		Params(params)
-
-	_, err := es.c.Update().
-		Index(es.index).
-		Id(t.ID.Hex()).
-		Script(script).
-		Do(context.Background())
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func (es *tag) DeleteByID(id string) error {
+// DeleteByID is a no-op when the record doesn't exist.
+func (es *tag) DeleteByID(ctx context.Context, id string) error {
 	_, err := es.c.Delete().
 		Index(es.index).
 		Id(id).
-		Do(context.Background())
-	if err != nil {
+		Do(ctx)
+	if err != nil && !elastic.IsNotFound(err) {
 		return err
 	}
 	return nil
@@ -157,6 +49,7 @@ func (es *tag) DeleteByID(id string) error {
 
 // MatchOffer matches wants for the given offer.
 func (es *tag) MatchOffer(
+	ctx context.Context,
 	offer string,
 	lastLoginDate time.Time,
 ) ([]string, error) {
@@ -164,7 +57,7 @@ func (es *tag) MatchOffer(
 	res, err := es.c.Search().
 		Index(es.index).
 		Query(q).
-		Do(context.Background())
+		Do(ctx)
 
 	if err != nil {
 		return nil, e.Wrap(err, "TagES MatchOffer failed")
@@ -185,6 +78,7 @@ func (es *tag) MatchOffer(
 
 // MatchWant matches offers for the given want.
 func (es *tag) MatchWant(
+	ctx context.Context,
 	want string,
 	lastLoginDate time.Time,
 ) ([]string, error) {
@@ -192,7 +86,7 @@ func (es *tag) MatchWant(
 	res, err := es.c.Search().
 		Index(es.index).
 		Query(q).
-		Do(context.Background())
+		Do(ctx)
 
 	if err != nil {
 		return nil, e.Wrap(err, "TagES MatchWant failed")
@@ -4,12 +4,11 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
 	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
 	"github.com/olivere/elastic/v7"
-	"github.com/spf13/viper"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type user struct {
@@ -24,19 +23,13 @@ func (es *user) Register(client *elastic.Client) {
 	es.index = "users"
 }
 
-// Create creates an UserESRecord in Elasticsearch.
-func (es *user) Create(u *types.User) error {
-	body := types.UserESRecord{
-		UserID:    u.ID.Hex(),
-		FirstName: u.FirstName,
-		LastName:  u.LastName,
-		Email:     u.Email,
-	}
+// Index writes the whole record of a user.
+func (es *user) Index(ctx context.Context, r *types.UserESRecord) error {
 	_, err := es.c.Index().
 		Index(es.index).
-		Id(u.ID.Hex()).
-		BodyJson(body).
-		Do(context.Background())
+		Id(r.UserID).
+		BodyJson(r).
+		Do(ctx)
 	if err != nil {
 		return err
 	}
@@ -44,14 +37,14 @@ func (es *user) Create(u *types.User) error {
 }
 
 // Find finds users from Elasticsearch.
-func (es *user) Find(u *types.User, page int64) ([]string, int, int, error) {
+func (es *user) Find(ctx context.Context, u *types.User, page int64) ([]string, int, int, error) {
 	if page < 0 || page == 0 {
 		return nil, 0, 0, e.New(e.InvalidPageNumber, "find user failed")
 	}
 
 	var ids []string
-	size := viper.GetInt("page_size")
-	from := viper.GetInt("page_size") * (int(page) - 1)
+	size := global.Config().PageSize
+	from := global.Config().PageSize * (int(page) - 1)
 
 	q := elastic.NewBoolQuery()
 
@@ -67,7 +60,7 @@ func (es *user) Find(u *types.User, page int64) ([]string, int, int, error) {
 		From(from).
 		Size(size).
 		Query(q).
-		Do(context.Background())
+		Do(ctx)
 
 	if err != nil {
 		return nil, 0, 0, e.Wrap(err, "find user failed")
@@ -83,54 +76,18 @@ func (es *user) Find(u *types.User, page int64) ([]string, int, int, error) {
 	}
 
 	numberOfResults := res.Hits.TotalHits.Value
-	totalPages := pagination.Pages(numberOfResults, viper.GetInt64("page_size"))
+	totalPages := pagination.Pages(numberOfResults, int64(global.Config().PageSize))
 
 	return ids, int(numberOfResults), totalPages, nil
 }
 
-func (es *user) Update(u *types.User) error {
-	doc := map[string]interface{}{
-		"email":     u.Email,
-		"firstName": u.FirstName,
-		"lastName":  u.LastName,
-	}
-
-	_, err := es.c.Update().
-		Index(es.index).
-		Id(u.ID.Hex()).
-		Doc(doc).
-		Do(context.Background())
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func (es *user) UpdateTradingInfo(
-	id primitive.ObjectID,
-	data *types.TradingRegisterData,
-) error {
-	doc := map[string]interface{}{
-		"firstName": data.FirstName,
-		"lastName":  data.LastName,
-	}
-	_, err := es.c.Update().
-		Index(es.index).
-		Id(id.Hex()).
-		Doc(doc).
-		Do(context.Background())
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func (es *user) Delete(id string) error {
+// Delete is a no-op when the record doesn't exist.
+func (es *user) Delete(ctx context.Context, id string) error {
 	_, err := es.c.Delete().
 		Index(es.index).
 		Id(id).
-		Do(context.Background())
-	if err != nil {
+		Do(ctx)
+	if err != nil && !elastic.IsNotFound(err) {
 		return err
 	}
 	return nil
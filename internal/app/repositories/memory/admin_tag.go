@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AdminTags implements the mongo.AdminTag queries.
+type AdminTags struct {
+	mu   sync.Mutex
+	tags []*types.AdminTag
+}
+
+// NewAdminTags returns an empty admin tag collection.
+func NewAdminTags() *AdminTags {
+	return &AdminTags{}
+}
+
+// Create adds the tag unless a tag with the name exists, deleted or not.
+func (a *AdminTags) Create(ctx context.Context, name string) error {
+	if name == "" || len(strings.TrimSpace(name)) == 0 {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, tag := range a.tags {
+		if tag.Name == name {
+			return nil
+		}
+	}
+	a.tags = append(a.tags, &types.AdminTag{
+		ID:        primitive.NewObjectID(),
+		Name:      name,
+		CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+// matching returns copies of the tags that are not deleted.
+func (a *AdminTags) matching(match func(tag *types.AdminTag) bool) []*types.AdminTag {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var results []*types.AdminTag
+	for _, tag := range a.tags {
+		if tag.DeletedAt.IsZero() && match(tag) {
+			copied := *tag
+			results = append(results, &copied)
+		}
+	}
+	return results
+}
+
+func (a *AdminTags) FindByName(ctx context.Context, name string) (*types.AdminTag, error) {
+	results := a.matching(func(tag *types.AdminTag) bool { return tag.Name == name })
+	if len(results) == 0 {
+		return nil, e.New(e.BusinessNotFound, "Admin tag not found")
+	}
+	return results[0], nil
+}
+
+func (a *AdminTags) FindByID(ctx context.Context, id primitive.ObjectID) (*types.AdminTag, error) {
+	results := a.matching(func(tag *types.AdminTag) bool { return tag.ID == id })
+	if len(results) == 0 {
+		return nil, e.New(e.BusinessNotFound, "Admin tag not found")
+	}
+	return results[0], nil
+}
+
+// FindTags matches the name as a case insensitive regular expression.
+func (a *AdminTags) FindTags(ctx context.Context, name string, page int64) (*types.FindAdminTagResult, error) {
+	if page < 0 || page == 0 {
+		return nil, e.New(e.InvalidPageNumber, "AdminTagMongo FindTags failed")
+	}
+	re, err := regexp.Compile("(?i)" + name)
+	if err != nil {
+		return nil, e.Wrap(err, "AdminTagMongo FindTags failed")
+	}
+
+	matched := a.matching(func(tag *types.AdminTag) bool { return re.MatchString(tag.Name) })
+	pageSize := int64(global.Config().PageSize)
+	start, end := pageBounds(len(matched), pageSize, page)
+	return &types.FindAdminTagResult{
+		AdminTags:       matched[start:end],
+		NumberOfResults: len(matched),
+		TotalPages:      pagination.Pages(int64(len(matched)), pageSize),
+	}, nil
+}
+
+func (a *AdminTags) TagStartWith(ctx context.Context, prefix string) ([]string, error) {
+	re, err := regexp.Compile("(?i)^" + prefix)
+	if err != nil {
+		return nil, e.Wrap(err, "mongo.AdminTag.FindTagStartWith failed")
+	}
+	var results []string
+	for _, tag := range a.matching(func(tag *types.AdminTag) bool { return re.MatchString(tag.Name) }) {
+		results = append(results, tag.Name)
+	}
+	return results, nil
+}
+
+func (a *AdminTags) GetAll(ctx context.Context) ([]*types.AdminTag, error) {
+	return a.matching(func(*types.AdminTag) bool { return true }), nil
+}
+
+func (a *AdminTags) Update(ctx context.Context, t *types.AdminTag) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, tag := range a.tags {
+		if tag.ID == t.ID {
+			tag.Name = t.Name
+			tag.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (a *AdminTags) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, tag := range a.tags {
+		if tag.ID == id {
+			tag.DeletedAt = time.Now()
+			tag.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
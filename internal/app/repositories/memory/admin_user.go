@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AdminUsers implements the mongo.AdminUser queries.
+type AdminUsers struct {
+	mu    sync.Mutex
+	users []*types.AdminUser
+}
+
+// NewAdminUsers returns an empty admin user collection.
+func NewAdminUsers() *AdminUsers {
+	return &AdminUsers{}
+}
+
+// Add stores the admin user, the password has to be hashed already. The
+// app has no admin signup, the admins are created in the database.
+func (a *AdminUsers) Add(ctx context.Context, user *types.AdminUser) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	user.ID = primitive.NewObjectID()
+	user.Email = strings.ToLower(user.Email)
+	user.CreatedAt = time.Now()
+	copied := *user
+	a.users = append(a.users, &copied)
+}
+
+func (a *AdminUsers) find(match func(user *types.AdminUser) bool) (*types.AdminUser, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, user := range a.users {
+		if user.DeletedAt.IsZero() && match(user) {
+			copied := *user
+			copied.Roles = append([]string(nil), user.Roles...)
+			return &copied, nil
+		}
+	}
+	return nil, e.New(e.UserNotFound, "admin user not found")
+}
+
+func (a *AdminUsers) FindByEmail(ctx context.Context, email string) (*types.AdminUser, error) {
+	email = strings.ToLower(email)
+	if email == "" {
+		return &types.AdminUser{}, e.New(e.UserNotFound, "admin user not found")
+	}
+	return a.find(func(user *types.AdminUser) bool { return user.Email == email })
+}
+
+func (a *AdminUsers) FindByID(ctx context.Context, id primitive.ObjectID) (*types.AdminUser, error) {
+	return a.find(func(user *types.AdminUser) bool { return user.ID == id })
+}
+
+func (a *AdminUsers) GetLoginInfo(ctx context.Context, id primitive.ObjectID) (*types.LoginInfo, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, user := range a.users {
+		if user.ID == id {
+			return &types.LoginInfo{
+				CurrentLoginIP:   user.CurrentLoginIP,
+				CurrentLoginDate: user.CurrentLoginDate,
+				LastLoginIP:      user.LastLoginIP,
+				LastLoginDate:    user.LastLoginDate,
+			}, nil
+		}
+	}
+	return nil, e.Wrap(e.New(e.UserNotFound, "admin user not found"), "AdminUserMongo GetLoginInfo failed")
+}
+
+func (a *AdminUsers) UpdateLoginInfo(
+	ctx context.Context,
+	id primitive.ObjectID,
+	i *types.LoginInfo,
+) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, user := range a.users {
+		if user.ID == id {
+			user.CurrentLoginIP = i.CurrentLoginIP
+			user.CurrentLoginDate = time.Now()
+			user.LastLoginIP = i.LastLoginIP
+			user.LastLoginDate = i.LastLoginDate
+			user.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Agreements implements the mongo.Agreement queries.
+type Agreements struct {
+	mu         sync.Mutex
+	agreements []*types.Agreement
+}
+
+// NewAgreements returns a collection without any published version.
+func NewAgreements() *Agreements {
+	return &Agreements{}
+}
+
+// Publish stores the agreement as the next version.
+func (a *Agreements) Publish(ctx context.Context, agreement *types.Agreement) (*types.Agreement, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	agreement.ID = primitive.NewObjectID()
+	agreement.Version = len(a.agreements) + 1
+	agreement.CreatedAt = time.Now()
+	copied := *agreement
+	a.agreements = append(a.agreements, &copied)
+	return agreement, nil
+}
+
+// FindLatest returns the latest version, nil when no version has been
+// published yet.
+func (a *Agreements) FindLatest(ctx context.Context) (*types.Agreement, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.agreements) == 0 {
+		return nil, nil
+	}
+	copied := *a.agreements[len(a.agreements)-1]
+	return &copied, nil
+}
+
+func (a *Agreements) FindByVersion(ctx context.Context, version int) (*types.Agreement, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, agreement := range a.agreements {
+		if agreement.Version == version {
+			copied := *agreement
+			return &copied, nil
+		}
+	}
+	return nil, e.New(e.AgreementNotFound, "Agreement not found")
+}
+
+// FindAll returns all the versions, the latest first.
+func (a *Agreements) FindAll(ctx context.Context) ([]*types.Agreement, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	results := []*types.Agreement{}
+	for i := len(a.agreements) - 1; i >= 0; i-- {
+		copied := *a.agreements[i]
+		results = append(results, &copied)
+	}
+	return results, nil
+}
+
+// AgreementAcceptances implements the mongo.AgreementAcceptance queries.
+type AgreementAcceptances struct {
+	mu          sync.Mutex
+	acceptances []*types.AgreementAcceptance
+}
+
+// NewAgreementAcceptances returns an empty acceptance collection.
+func NewAgreementAcceptances() *AgreementAcceptances {
+	return &AgreementAcceptances{}
+}
+
+// Create records the acceptance. Acceptances are never modified.
+func (a *AgreementAcceptances) Create(ctx context.Context, acceptance *types.AgreementAcceptance) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	acceptance.ID = primitive.NewObjectID()
+	acceptance.CreatedAt = time.Now()
+	copied := *acceptance
+	a.acceptances = append(a.acceptances, &copied)
+	return nil
+}
+
+// HasAccepted reports whether the user has accepted the version.
+func (a *AgreementAcceptances) HasAccepted(ctx context.Context, userID primitive.ObjectID, version int) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, acceptance := range a.acceptances {
+		if acceptance.UserID == userID && acceptance.Version == version {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CountByVersion returns the number of acceptances of every version.
+func (a *AgreementAcceptances) CountByVersion(ctx context.Context) (map[int]int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	counts := map[int]int{}
+	for _, acceptance := range a.acceptances {
+		counts[acceptance.Version]++
+	}
+	return counts, nil
+}
+
+// FindByVersion returns the acceptances of the version, the latest
+// first.
+func (a *AgreementAcceptances) FindByVersion(
+	ctx context.Context,
+	version int,
+	page int64,
+) (*types.FindAgreementAcceptanceResult, error) {
+	if page < 0 || page == 0 {
+		return nil, e.New(e.InvalidPageNumber, "AgreementAcceptanceMongo FindByVersion failed")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	matched := []*types.AgreementAcceptance{}
+	for i := len(a.acceptances) - 1; i >= 0; i-- {
+		if a.acceptances[i].Version == version {
+			copied := *a.acceptances[i]
+			matched = append(matched, &copied)
+		}
+	}
+
+	pageSize := int64(global.Config().PageSize)
+	start, end := pageBounds(len(matched), pageSize, page)
+	return &types.FindAgreementAcceptanceResult{
+		AgreementAcceptances: matched[start:end],
+		NumberOfResults:      len(matched),
+		TotalPages:           pagination.Pages(int64(len(matched)), pageSize),
+	}, nil
+}
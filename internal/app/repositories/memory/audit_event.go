@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditEvents implements the mongo.AuditEvent queries, the trail is
+// append-only.
+type AuditEvents struct {
+	mu     sync.Mutex
+	events []*types.AuditEvent
+}
+
+// NewAuditEvents returns an empty audit trail.
+func NewAuditEvents() *AuditEvents {
+	return &AuditEvents{}
+}
+
+func (a *AuditEvents) Create(ctx context.Context, event *types.AuditEvent) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	event.ID = primitive.NewObjectID()
+	event.CreatedAt = time.Now()
+	copied := *event
+	a.events = append(a.events, &copied)
+	return nil
+}
+
+func matchAuditEvent(event *types.AuditEvent, c *types.AuditSearchCriteria) bool {
+	switch {
+	case c.ActorEmail != "" &&
+		!strings.Contains(strings.ToLower(event.ActorEmail), strings.ToLower(c.ActorEmail)):
+		return false
+	case c.TargetType != "" && event.TargetType != c.TargetType:
+		return false
+	case c.TargetID != "" && event.TargetID != c.TargetID:
+		return false
+	case !c.DateFrom.IsZero() && event.CreatedAt.Before(c.DateFrom):
+		return false
+	case !c.DateTo.IsZero() && event.CreatedAt.After(c.DateTo):
+		return false
+	}
+	return true
+}
+
+// matching returns copies of the matching events, the oldest first.
+func (a *AuditEvents) matching(c *types.AuditSearchCriteria) []*types.AuditEvent {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	results := []*types.AuditEvent{}
+	for _, event := range a.events {
+		if matchAuditEvent(event, c) {
+			copied := *event
+			results = append(results, &copied)
+		}
+	}
+	return results
+}
+
+// Find returns the matching events, the latest first.
+func (a *AuditEvents) Find(
+	ctx context.Context,
+	c *types.AuditSearchCriteria,
+	page int64,
+) ([]*types.AuditEvent, int, error) {
+	if page < 0 || page == 0 {
+		return nil, 0, e.New(e.InvalidPageNumber, "AuditEventMongo Find failed")
+	}
+
+	matched := a.matching(c)
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+
+	pageSize := int64(global.Config().PageSize)
+	start, end := pageBounds(len(matched), pageSize, page)
+	return matched[start:end], pagination.Pages(int64(len(matched)), pageSize), nil
+}
+
+// ForEach calls fn with every matching event, the oldest first. It stops
+// at the first error fn returns.
+func (a *AuditEvents) ForEach(
+	ctx context.Context,
+	c *types.AuditSearchCriteria,
+	fn func(*types.AuditEvent) error,
+) error {
+	for _, event := range a.matching(c) {
+		if err := fn(event); err != nil {
+			return e.Wrap(err, "AuditEventMongo ForEach failed")
+		}
+	}
+	return nil
+}
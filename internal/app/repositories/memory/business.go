@@ -0,0 +1,295 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Businesses implements the mongo.Business queries.
+type Businesses struct {
+	mu         sync.Mutex
+	businesses []*types.Business
+}
+
+// NewBusinesses returns an empty business collection.
+func NewBusinesses() *Businesses {
+	return &Businesses{}
+}
+
+func copyTags(tags []*types.TagField) []*types.TagField {
+	if tags == nil {
+		return nil
+	}
+	copied := make([]*types.TagField, 0, len(tags))
+	for _, t := range tags {
+		tag := *t
+		copied = append(copied, &tag)
+	}
+	return copied
+}
+
+func copyBusiness(b *types.Business) *types.Business {
+	copied := *b
+	copied.Offers = copyTags(b.Offers)
+	copied.Wants = copyTags(b.Wants)
+	copied.AdminTags = append([]string(nil), b.AdminTags...)
+	return &copied
+}
+
+// find returns the business unless it is deleted.
+func (b *Businesses) find(id primitive.ObjectID) *types.Business {
+	for _, business := range b.businesses {
+		if business.ID == id && business.DeletedAt.IsZero() {
+			return business
+		}
+	}
+	return nil
+}
+
+func (b *Businesses) FindByID(ctx context.Context, id primitive.ObjectID) (*types.Business, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	business := b.find(id)
+	if business == nil {
+		return nil, e.New(e.BusinessNotFound, "business not found")
+	}
+	return copyBusiness(business), nil
+}
+
+// FindByIDs returns the businesses in the order of the ids.
+func (b *Businesses) FindByIDs(
+	ctx context.Context,
+	ids []string,
+) ([]*types.Business, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var results []*types.Business
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, e.Wrap(err, "find business failed")
+		}
+		if business := b.find(objectID); business != nil {
+			results = append(results, copyBusiness(business))
+		}
+	}
+	return results, nil
+}
+
+// Create stores the business as pending, without admin tags.
+func (b *Businesses) Create(ctx context.Context, data *types.BusinessData) (primitive.ObjectID, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	business := &types.Business{
+		ID:                 primitive.NewObjectID(),
+		CreatedAt:          time.Now(),
+		BusinessName:       data.BusinessName,
+		BusinessPhone:      data.BusinessPhone,
+		IncType:            data.IncType,
+		CompanyNumber:      data.CompanyNumber,
+		Website:            data.Website,
+		Turnover:           data.Turnover,
+		Offers:             copyTags(data.Offers),
+		Wants:              copyTags(data.Wants),
+		Description:        data.Description,
+		LocationAddress:    data.LocationAddress,
+		LocationCity:       data.LocationCity,
+		LocationRegion:     data.LocationRegion,
+		LocationPostalCode: data.LocationPostalCode,
+		LocationCountry:    data.LocationCountry,
+		Status:             constant.Business.Pending,
+	}
+	b.businesses = append(b.businesses, business)
+	return business.ID, nil
+}
+
+// update applies the change to the business, even a deleted one, like the
+// UpdateOne calls.
+func (b *Businesses) update(id primitive.ObjectID, change func(b *types.Business)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, business := range b.businesses {
+		if business.ID == id {
+			change(business)
+			return
+		}
+	}
+}
+
+// updateAll applies the change to every business.
+func (b *Businesses) updateAll(change func(b *types.Business)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, business := range b.businesses {
+		change(business)
+	}
+}
+
+func (b *Businesses) UpdateTradingInfo(
+	ctx context.Context,
+	id primitive.ObjectID,
+	data *types.TradingRegisterData,
+) error {
+	b.update(id, func(business *types.Business) {
+		business.BusinessName = data.BusinessName
+		business.IncType = data.IncType
+		business.CompanyNumber = data.CompanyNumber
+		business.BusinessPhone = data.BusinessPhone
+		business.Website = data.Website
+		business.Turnover = data.Turnover
+		business.Description = data.Description
+		business.LocationAddress = data.LocationAddress
+		business.LocationCity = data.LocationCity
+		business.LocationRegion = data.LocationRegion
+		business.LocationPostalCode = data.LocationPostalCode
+		business.LocationCountry = data.LocationCountry
+		business.Status = constant.Trading.Pending
+		business.UpdatedAt = time.Now()
+	})
+	return nil
+}
+
+func (b *Businesses) UpdateBusiness(
+	ctx context.Context,
+	id primitive.ObjectID,
+	data *types.BusinessData,
+	isAdmin bool,
+) error {
+	b.update(id, func(business *types.Business) {
+		business.BusinessName = data.BusinessName
+		business.BusinessPhone = data.BusinessPhone
+		business.IncType = data.IncType
+		business.CompanyNumber = data.CompanyNumber
+		business.Website = data.Website
+		business.Turnover = data.Turnover
+		business.Description = data.Description
+		business.LocationAddress = data.LocationAddress
+		business.LocationCity = data.LocationCity
+		business.LocationRegion = data.LocationRegion
+		business.LocationPostalCode = data.LocationPostalCode
+		business.LocationCountry = data.LocationCountry
+		business.UpdatedAt = time.Now()
+		if data.Status != "" {
+			business.Status = data.Status
+		}
+		if isAdmin {
+			business.AdminTags = append([]string(nil), data.AdminTags...)
+		}
+		business.Offers = append(business.Offers, helper.ToTagFields(data.OffersAdded)...)
+		business.Wants = append(business.Wants, helper.ToTagFields(data.WantsAdded)...)
+		business.Offers = removeTags(business.Offers, data.OffersRemoved...)
+		business.Wants = removeTags(business.Wants, data.WantsRemoved...)
+	})
+	return nil
+}
+
+// removeTags removes the tags with the names.
+func removeTags(tags []*types.TagField, names ...string) []*types.TagField {
+	removed := map[string]bool{}
+	for _, name := range names {
+		removed[name] = true
+	}
+	kept := make([]*types.TagField, 0, len(tags))
+	for _, t := range tags {
+		if !removed[t.Name] {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+func (b *Businesses) SetMemberStartedAt(ctx context.Context, id primitive.ObjectID) error {
+	b.update(id, func(business *types.Business) {
+		business.MemberStartedAt = time.Now()
+	})
+	return nil
+}
+
+func (b *Businesses) UpdateAllTagsCreatedAt(
+	ctx context.Context,
+	id primitive.ObjectID,
+	t time.Time,
+) error {
+	b.update(id, func(business *types.Business) {
+		for _, tag := range business.Offers {
+			tag.CreatedAt = t
+		}
+		for _, tag := range business.Wants {
+			tag.CreatedAt = t
+		}
+	})
+	return nil
+}
+
+func (b *Businesses) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	b.update(id, func(business *types.Business) {
+		business.DeletedAt = time.Now()
+	})
+	return nil
+}
+
+func (b *Businesses) RenameTag(ctx context.Context, old string, new string) error {
+	b.updateAll(func(business *types.Business) {
+		for _, tags := range [][]*types.TagField{business.Offers, business.Wants} {
+			for _, tag := range tags {
+				if tag.Name == old {
+					tag.Name = new
+					business.UpdatedAt = time.Now()
+				}
+			}
+		}
+	})
+	return nil
+}
+
+func (b *Businesses) RenameAdminTag(ctx context.Context, old string, new string) error {
+	b.updateAll(func(business *types.Business) {
+		for i, tag := range business.AdminTags {
+			if tag == old {
+				business.AdminTags[i] = new
+				business.UpdatedAt = time.Now()
+			}
+		}
+	})
+	return nil
+}
+
+func (b *Businesses) DeleteTag(ctx context.Context, name string) error {
+	b.updateAll(func(business *types.Business) {
+		offers := removeTags(business.Offers, name)
+		wants := removeTags(business.Wants, name)
+		if len(offers) != len(business.Offers) || len(wants) != len(business.Wants) {
+			business.Offers, business.Wants = offers, wants
+			business.UpdatedAt = time.Now()
+		}
+	})
+	return nil
+}
+
+func (b *Businesses) DeleteAdminTags(ctx context.Context, name string) error {
+	b.updateAll(func(business *types.Business) {
+		kept := business.AdminTags[:0]
+		for _, tag := range business.AdminTags {
+			if tag != name {
+				kept = append(kept, tag)
+			}
+		}
+		if len(kept) != len(business.AdminTags) {
+			business.UpdatedAt = time.Now()
+		}
+		business.AdminTags = kept
+	})
+	return nil
+}
@@ -0,0 +1,442 @@
+// Package memory keeps the repositories in memory for the tests. They follow
+// the queries of the pg and mongo packages, including their error codes.
+package memory
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
+	"github.com/jinzhu/gorm"
+	"github.com/segmentio/ksuid"
+)
+
+var errDuplicateKey = errors.New("duplicate key value violates unique constraint")
+
+// Ledger holds the accounts, balance limits, journals and postings, like the
+// PostgreSQL tables. Accounts, BalanceLimits and Transactions share it.
+type Ledger struct {
+	mu       sync.Mutex
+	accounts []*types.Account
+	limits   map[uint]*types.BalanceLimit
+	journals []*types.Journal
+	postings []*types.Posting
+
+	Accounts      *Accounts
+	BalanceLimits *BalanceLimits
+	Transactions  *Transactions
+}
+
+// NewLedger returns an empty ledger.
+func NewLedger() *Ledger {
+	l := &Ledger{limits: map[uint]*types.BalanceLimit{}}
+	l.Accounts = &Accounts{l}
+	l.BalanceLimits = &BalanceLimits{l}
+	l.Transactions = &Transactions{l}
+	return l
+}
+
+func (l *Ledger) findAccount(id uint) *types.Account {
+	for _, a := range l.accounts {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+func (l *Ledger) findJournal(id uint) *types.Journal {
+	for _, j := range l.journals {
+		if j.ID == id {
+			return j
+		}
+	}
+	return nil
+}
+
+// post adds the postings of a transfer and updates the balances.
+func (l *Ledger) post(journalID, fromID, toID uint, amount float64) {
+	now := time.Now()
+	for _, p := range []*types.Posting{
+		{AccountID: fromID, JournalID: journalID, Amount: -amount},
+		{AccountID: toID, JournalID: journalID, Amount: amount},
+	} {
+		p.ID = uint(len(l.postings) + 1)
+		p.CreatedAt = now
+		l.postings = append(l.postings, p)
+	}
+	if a := l.findAccount(fromID); a != nil {
+		a.Balance -= amount
+	}
+	if a := l.findAccount(toID); a != nil {
+		a.Balance += amount
+	}
+}
+
+// Accounts implements the pg.Account queries.
+type Accounts struct {
+	l *Ledger
+}
+
+// Create creates the account with the default balance limits.
+func (a *Accounts) Create(ctx context.Context, bID string) error {
+	a.l.mu.Lock()
+	defer a.l.mu.Unlock()
+
+	for _, existing := range a.l.accounts {
+		if existing.BusinessID == bID {
+			return e.Wrap(errDuplicateKey, "pg.Account.Create failed")
+		}
+	}
+	account := &types.Account{BusinessID: bID}
+	account.ID = uint(len(a.l.accounts) + 1)
+	account.CreatedAt = time.Now()
+	a.l.accounts = append(a.l.accounts, account)
+	a.l.limits[account.ID] = &types.BalanceLimit{
+		AccountID: account.ID,
+		MaxNegBal: global.Config().Transaction.MaxNegBal,
+		MaxPosBal: global.Config().Transaction.MaxPosBal,
+	}
+	return nil
+}
+
+func (a *Accounts) FindByID(ctx context.Context, accountID uint) (*types.Account, error) {
+	a.l.mu.Lock()
+	defer a.l.mu.Unlock()
+
+	account := a.l.findAccount(accountID)
+	if account == nil {
+		return nil, e.Wrap(gorm.ErrRecordNotFound, "pg.Account.FindByID")
+	}
+	copied := *account
+	return &copied, nil
+}
+
+func (a *Accounts) FindByBusinessID(ctx context.Context, businessID string) (*types.Account, error) {
+	a.l.mu.Lock()
+	defer a.l.mu.Unlock()
+
+	for _, account := range a.l.accounts {
+		if account.BusinessID == businessID {
+			copied := *account
+			return &copied, nil
+		}
+	}
+	return nil, e.New(e.UserNotFound, "user not found")
+}
+
+// DeleteByBusinessID deletes the account and its balance limits.
+func (a *Accounts) DeleteByBusinessID(ctx context.Context, bID string) error {
+	a.l.mu.Lock()
+	defer a.l.mu.Unlock()
+
+	for i, account := range a.l.accounts {
+		if account.BusinessID == bID {
+			delete(a.l.limits, account.ID)
+			a.l.accounts = append(a.l.accounts[:i], a.l.accounts[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// BalanceLimits implements the pg.BalanceLimit queries.
+type BalanceLimits struct {
+	l *Ledger
+}
+
+func (b *BalanceLimits) FindByAccountID(
+	ctx context.Context,
+	accountID uint,
+) (*types.BalanceLimit, error) {
+	b.l.mu.Lock()
+	defer b.l.mu.Unlock()
+
+	limit, ok := b.l.limits[accountID]
+	if !ok {
+		return nil, e.Wrap(gorm.ErrRecordNotFound, "pg.BalanceLimit.FindByAccountID failed")
+	}
+	copied := *limit
+	return &copied, nil
+}
+
+// Update stores both limits as positive numbers.
+func (b *BalanceLimits) Update(
+	ctx context.Context,
+	id uint,
+	maxPosBal float64,
+	maxNegBal float64,
+) error {
+	b.l.mu.Lock()
+	defer b.l.mu.Unlock()
+
+	if limit, ok := b.l.limits[id]; ok {
+		limit.MaxPosBal = math.Abs(maxPosBal)
+		limit.MaxNegBal = math.Abs(maxNegBal)
+	}
+	return nil
+}
+
+// Transactions implements the pg.Transaction queries.
+type Transactions struct {
+	l *Ledger
+}
+
+// Create makes a completed transfer directly.
+func (t *Transactions) Create(
+	ctx context.Context,
+	fromID uint,
+	fromEmail string,
+	fromBusinessName string,
+
+	toID uint,
+	toEmail string,
+	toBusinessName string,
+
+	amount float64,
+	desc string,
+) error {
+	t.l.mu.Lock()
+	defer t.l.mu.Unlock()
+
+	j := t.addJournal(0, fromID, fromEmail, fromBusinessName,
+		toID, toEmail, toBusinessName, amount, desc)
+	j.Status = constant.Transaction.Completed
+	t.l.post(j.ID, fromID, toID, amount)
+	return nil
+}
+
+func (t *Transactions) addJournal(
+	initiatedBy uint,
+	fromID uint,
+	fromEmail string,
+	fromBusinessName string,
+	toID uint,
+	toEmail string,
+	toBusinessName string,
+	amount float64,
+	desc string,
+) *types.Journal {
+	j := &types.Journal{
+		TransactionID:    ksuid.New().String(),
+		InitiatedBy:      initiatedBy,
+		FromID:           fromID,
+		FromEmail:        fromEmail,
+		FromBusinessName: fromBusinessName,
+		ToID:             toID,
+		ToEmail:          toEmail,
+		ToBusinessName:   toBusinessName,
+		Amount:           amount,
+		Description:      desc,
+		Type:             constant.Journal.Transfer,
+		Status:           constant.Transaction.Initiated,
+	}
+	j.ID = uint(len(t.l.journals) + 1)
+	j.CreatedAt = time.Now()
+	j.UpdatedAt = j.CreatedAt
+	t.l.journals = append(t.l.journals, j)
+	return j
+}
+
+func (t *Transactions) Propose(
+	ctx context.Context,
+	initiatedBy uint,
+
+	fromID uint,
+	fromEmail string,
+	fromBusinessName string,
+
+	toID uint,
+	toEmail string,
+	toBusinessName string,
+
+	amount float64,
+	desc string,
+) (*types.Transaction, error) {
+	t.l.mu.Lock()
+	defer t.l.mu.Unlock()
+
+	j := t.addJournal(initiatedBy, fromID, fromEmail, fromBusinessName,
+		toID, toEmail, toBusinessName, amount, desc)
+	// Like pg, the journal ID is not returned.
+	return &types.Transaction{
+		TransactionID:    j.TransactionID,
+		InitiatedBy:      initiatedBy,
+		FromID:           fromID,
+		FromEmail:        fromEmail,
+		FromBusinessName: fromBusinessName,
+		ToID:             toID,
+		ToEmail:          toEmail,
+		ToBusinessName:   toBusinessName,
+		Amount:           amount,
+		Description:      desc,
+		Status:           j.Status,
+	}, nil
+}
+
+func toTransaction(j *types.Journal) *types.Transaction {
+	return &types.Transaction{
+		ID:               j.ID,
+		TransactionID:    j.TransactionID,
+		InitiatedBy:      j.InitiatedBy,
+		FromID:           j.FromID,
+		FromEmail:        j.FromEmail,
+		FromBusinessName: j.FromBusinessName,
+		ToID:             j.ToID,
+		ToEmail:          j.ToEmail,
+		ToBusinessName:   j.ToBusinessName,
+		Amount:           j.Amount,
+		Description:      j.Description,
+		Status:           j.Status,
+		CreatedAt:        j.CreatedAt,
+	}
+}
+
+func (t *Transactions) Find(
+	ctx context.Context,
+	transactionID uint,
+) (*types.Transaction, error) {
+	t.l.mu.Lock()
+	defer t.l.mu.Unlock()
+
+	j := t.l.findJournal(transactionID)
+	if j == nil {
+		return nil, e.Wrap(gorm.ErrRecordNotFound, "pg.Transaction.Find failed")
+	}
+	return toTransaction(j), nil
+}
+
+func (t *Transactions) Cancel(ctx context.Context, transactionID uint, reason string) error {
+	t.l.mu.Lock()
+	defer t.l.mu.Unlock()
+
+	if j := t.l.findJournal(transactionID); j != nil {
+		j.Status = constant.Transaction.Cancelled
+		j.CancellationReason = reason
+		j.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (t *Transactions) Accept(
+	ctx context.Context,
+	transactionID uint,
+	fromID uint,
+	toID uint,
+	amount float64,
+) error {
+	t.l.mu.Lock()
+	defer t.l.mu.Unlock()
+
+	t.l.post(transactionID, fromID, toID, amount)
+	if j := t.l.findJournal(transactionID); j != nil {
+		j.Status = constant.Transaction.Completed
+		j.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// FindPendings returns the newest transactions first.
+func (t *Transactions) FindPendings(ctx context.Context, id uint) ([]*types.Transaction, error) {
+	t.l.mu.Lock()
+	defer t.l.mu.Unlock()
+
+	var result []*types.Transaction
+	for i := len(t.l.journals) - 1; i >= 0; i-- {
+		j := t.l.journals[i]
+		if (j.FromID != id && j.ToID != id) ||
+			j.Status != constant.Transaction.Initiated {
+			continue
+		}
+		transaction := toTransaction(j)
+		transaction.IsInitiator = j.InitiatedBy == id
+		result = append(result, transaction)
+	}
+	return result, nil
+}
+
+// postings returns the postings of the account, the newest first, with
+// their journals.
+func (t *Transactions) postings(
+	id uint,
+	match func(p *types.Posting) bool,
+) []*types.Transaction {
+	var result []*types.Transaction
+	for i := len(t.l.postings) - 1; i >= 0; i-- {
+		p := t.l.postings[i]
+		if p.AccountID != id || !match(p) {
+			continue
+		}
+		j := t.l.findJournal(p.JournalID)
+		if j == nil {
+			continue
+		}
+		result = append(result, &types.Transaction{
+			TransactionID:    j.TransactionID,
+			FromEmail:        j.FromEmail,
+			ToEmail:          j.ToEmail,
+			FromBusinessName: j.FromBusinessName,
+			ToBusinessName:   j.ToBusinessName,
+			Description:      j.Description,
+			Amount:           p.Amount,
+			CreatedAt:        p.CreatedAt,
+		})
+	}
+	return result
+}
+
+// FindRecent finds the recent 3 completed transactions.
+func (t *Transactions) FindRecent(ctx context.Context, id uint) ([]*types.Transaction, error) {
+	t.l.mu.Lock()
+	defer t.l.mu.Unlock()
+
+	result := t.postings(id, func(*types.Posting) bool { return true })
+	if len(result) > 3 {
+		result = result[:3]
+	}
+	return result, nil
+}
+
+// FindInRange finds the completed transactions in specific time range.
+func (t *Transactions) FindInRange(
+	ctx context.Context,
+	id uint,
+	dateFrom time.Time,
+	dateTo time.Time,
+	page int,
+) ([]*types.Transaction, int, error) {
+	t.l.mu.Lock()
+	defer t.l.mu.Unlock()
+
+	if dateFrom.IsZero() {
+		dateFrom = constant.Date.DefaultFrom
+	}
+	if dateTo.IsZero() {
+		dateTo = constant.Date.DefaultTo
+	}
+	// Add 24 hours to include the end date.
+	dateTo = dateTo.Add(24 * time.Hour)
+
+	result := t.postings(id, func(p *types.Posting) bool {
+		return !p.CreatedAt.Before(dateFrom) && !p.CreatedAt.After(dateTo)
+	})
+	pageSize := global.Config().PageSize
+	totalPages := pagination.Pages(int64(len(result)), int64(pageSize))
+
+	offset := pageSize * (page - 1)
+	if offset < 0 || offset >= len(result) {
+		return nil, totalPages, nil
+	}
+	end := offset + pageSize
+	if end > len(result) {
+		end = len(result)
+	}
+	return result[offset:end], totalPages, nil
+}
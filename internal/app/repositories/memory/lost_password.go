@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LostPasswords implements the mongo.LostPassword queries.
+type LostPasswords struct {
+	mu     sync.Mutex
+	tokens []*types.LostPassword
+}
+
+// NewLostPasswords returns an empty token collection.
+func NewLostPasswords() *LostPasswords {
+	return &LostPasswords{}
+}
+
+// Create replaces the token of the email.
+func (l *LostPasswords) Create(ctx context.Context, lostPassword *types.LostPassword) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, stored := range l.tokens {
+		if stored.Email == lostPassword.Email {
+			stored.Token = lostPassword.Token
+			stored.TokenUsed = false
+			stored.CreatedAt = time.Now()
+			return nil
+		}
+	}
+	l.tokens = append(l.tokens, &types.LostPassword{
+		ID:        primitive.NewObjectID(),
+		Email:     lostPassword.Email,
+		Token:     lostPassword.Token,
+		CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+func (l *LostPasswords) find(match func(lp *types.LostPassword) bool) (*types.LostPassword, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, stored := range l.tokens {
+		if match(stored) {
+			copied := *stored
+			return &copied, nil
+		}
+	}
+	return nil, e.New(e.TokenInvalid, "token not found")
+}
+
+func (l *LostPasswords) FindByToken(ctx context.Context, token string) (*types.LostPassword, error) {
+	if token == "" {
+		return nil, e.New(e.TokenInvalid, "token not found")
+	}
+	return l.find(func(lp *types.LostPassword) bool { return lp.Token == token })
+}
+
+func (l *LostPasswords) FindByEmail(ctx context.Context, email string) (*types.LostPassword, error) {
+	if email == "" {
+		return nil, e.New(e.TokenInvalid, "token not found")
+	}
+	return l.find(func(lp *types.LostPassword) bool { return lp.Email == email })
+}
+
+func (l *LostPasswords) SetTokenUsed(ctx context.Context, token string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, stored := range l.tokens {
+		if stored.Token == token {
+			stored.TokenUsed = true
+		}
+	}
+	return nil
+}
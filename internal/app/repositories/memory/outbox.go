@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Outbox keeps the recorded events, so the tests can check which documents
+// would be indexed.
+type Outbox struct {
+	mu     sync.Mutex
+	events []*types.OutboxEvent
+}
+
+// NewOutbox returns an empty outbox.
+func NewOutbox() *Outbox {
+	return &Outbox{}
+}
+
+// Add records a change to be applied to Elasticsearch.
+func (o *Outbox) Add(ctx context.Context, event *types.OutboxEvent) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	event.ID = primitive.NewObjectID()
+	event.CreatedAt = time.Now()
+	copied := *event
+	o.events = append(o.events, &copied)
+	return nil
+}
+
+// WithTransaction runs fn, the fakes have nothing to roll back.
+func (o *Outbox) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// Events returns the recorded events, the oldest first.
+func (o *Outbox) Events() []*types.OutboxEvent {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return append([]*types.OutboxEvent(nil), o.events...)
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tags implements the mongo.Tag queries.
+type Tags struct {
+	mu   sync.Mutex
+	tags []*types.Tag
+}
+
+// NewTags returns an empty tag collection.
+func NewTags() *Tags {
+	return &Tags{}
+}
+
+// upsert returns the tag with the name, deleted or not, creating it when
+// missing.
+func (t *Tags) upsert(name string) *types.Tag {
+	for _, tag := range t.tags {
+		if tag.Name == name {
+			return tag
+		}
+	}
+	tag := &types.Tag{
+		ID:        primitive.NewObjectID(),
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+	t.tags = append(t.tags, tag)
+	return tag
+}
+
+func (t *Tags) Create(ctx context.Context, name string) (primitive.ObjectID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.upsert(name).ID, nil
+}
+
+func (t *Tags) UpdateOffer(ctx context.Context, name string) (primitive.ObjectID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tag := t.upsert(name)
+	tag.OfferAddedAt = time.Now()
+	tag.UpdatedAt = time.Now()
+	return tag.ID, nil
+}
+
+func (t *Tags) UpdateWant(ctx context.Context, name string) (primitive.ObjectID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tag := t.upsert(name)
+	tag.WantAddedAt = time.Now()
+	tag.UpdatedAt = time.Now()
+	return tag.ID, nil
+}
+
+func (t *Tags) find(match func(tag *types.Tag) bool) (*types.Tag, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, tag := range t.tags {
+		if tag.DeletedAt.IsZero() && match(tag) {
+			copied := *tag
+			return &copied, nil
+		}
+	}
+	return nil, e.New(e.BusinessNotFound, "Tag not found")
+}
+
+func (t *Tags) FindByName(ctx context.Context, name string) (*types.Tag, error) {
+	return t.find(func(tag *types.Tag) bool { return tag.Name == name })
+}
+
+func (t *Tags) FindByID(ctx context.Context, id primitive.ObjectID) (*types.Tag, error) {
+	return t.find(func(tag *types.Tag) bool { return tag.ID == id })
+}
+
+// FindTags matches the name as a case insensitive regular expression.
+func (t *Tags) FindTags(ctx context.Context, name string, page int64) (*types.FindTagResult, error) {
+	if page < 0 || page == 0 {
+		return nil, e.New(e.InvalidPageNumber, "TagMongo FindTags failed")
+	}
+	re, err := regexp.Compile("(?i)" + name)
+	if err != nil {
+		return nil, e.Wrap(err, "TagMongo FindTags failed")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var matched []*types.Tag
+	for _, tag := range t.tags {
+		if tag.DeletedAt.IsZero() && re.MatchString(tag.Name) {
+			copied := *tag
+			matched = append(matched, &copied)
+		}
+	}
+
+	pageSize := int64(global.Config().PageSize)
+	var results []*types.Tag
+	for i := pageSize * (page - 1); i < int64(len(matched)) && i < pageSize*page; i++ {
+		results = append(results, matched[i])
+	}
+	return &types.FindTagResult{
+		Tags:            results,
+		NumberOfResults: len(matched),
+		TotalPages:      pagination.Pages(int64(len(matched)), pageSize),
+	}, nil
+}
+
+func (t *Tags) Rename(ctx context.Context, tag *types.Tag) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, stored := range t.tags {
+		if stored.ID == tag.ID {
+			stored.Name = tag.Name
+			stored.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (t *Tags) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, tag := range t.tags {
+		if tag.ID == id {
+			tag.DeletedAt = time.Now()
+			tag.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TradingApplications implements the mongo.TradingApplication queries.
+type TradingApplications struct {
+	mu           sync.Mutex
+	applications []*types.TradingApplication
+}
+
+// NewTradingApplications returns an empty application collection.
+func NewTradingApplications() *TradingApplications {
+	return &TradingApplications{}
+}
+
+func copyApplication(a *types.TradingApplication) *types.TradingApplication {
+	copied := *a
+	if a.Data != nil {
+		data := *a.Data
+		copied.Data = &data
+	}
+	copied.Changes = append([]string(nil), a.Changes...)
+	copied.Notes = append([]*types.TradingApplicationNote(nil), a.Notes...)
+	copied.Events = append([]*types.TradingApplicationEvent(nil), a.Events...)
+	return &copied
+}
+
+func isOpen(a *types.TradingApplication) bool {
+	return a.Status == constant.TradingApplication.Pending ||
+		a.Status == constant.TradingApplication.InfoRequested
+}
+
+func (t *TradingApplications) findOpen(businessID primitive.ObjectID) *types.TradingApplication {
+	for _, a := range t.applications {
+		if a.BusinessID == businessID && isOpen(a) {
+			return a
+		}
+	}
+	return nil
+}
+
+func (t *TradingApplications) findByID(id primitive.ObjectID) *types.TradingApplication {
+	for _, a := range t.applications {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+// Submit opens an application for the business or submits its open
+// application again.
+func (t *TradingApplications) Submit(
+	ctx context.Context,
+	a *types.TradingApplication,
+	event *types.TradingApplicationEvent,
+) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	stored := t.findOpen(a.BusinessID)
+	if stored == nil {
+		stored = &types.TradingApplication{
+			ID:         primitive.NewObjectID(),
+			BusinessID: a.BusinessID,
+			CreatedAt:  now,
+		}
+		t.applications = append(t.applications, stored)
+	}
+	stored.UserID = a.UserID
+	stored.Status = constant.TradingApplication.Pending
+	stored.SubmittedAt = now
+	stored.Data = a.Data
+	stored.Changes = a.Changes
+	stored.UpdatedAt = now
+	stored.Events = append(stored.Events, event)
+	return nil
+}
+
+func (t *TradingApplications) FindByID(ctx context.Context, id primitive.ObjectID) (*types.TradingApplication, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	a := t.findByID(id)
+	if a == nil {
+		return nil, e.New(e.BusinessNotFound, "Trading application not found")
+	}
+	return copyApplication(a), nil
+}
+
+// FindOpenByBusinessID returns the open application of the business.
+func (t *TradingApplications) FindOpenByBusinessID(ctx context.Context, id primitive.ObjectID) (*types.TradingApplication, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	a := t.findOpen(id)
+	if a == nil {
+		return nil, e.New(e.BusinessNotFound, "Trading application not found")
+	}
+	return copyApplication(a), nil
+}
+
+// FindByBusinessID returns all the applications of the business, the
+// oldest first.
+func (t *TradingApplications) FindByBusinessID(ctx context.Context, id primitive.ObjectID) ([]*types.TradingApplication, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	results := []*types.TradingApplication{}
+	for _, a := range t.applications {
+		if a.BusinessID == id {
+			results = append(results, copyApplication(a))
+		}
+	}
+	return results, nil
+}
+
+// Find returns the applications with the statuses, the longest waiting
+// first.
+func (t *TradingApplications) Find(
+	ctx context.Context,
+	statuses []string,
+	page int64,
+) (*types.FindTradingApplicationResult, error) {
+	if page < 0 || page == 0 {
+		return nil, e.New(e.InvalidPageNumber, "TradingApplicationMongo Find failed")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	matched := []*types.TradingApplication{}
+	for _, a := range t.applications {
+		for _, status := range statuses {
+			if a.Status == status {
+				matched = append(matched, copyApplication(a))
+				break
+			}
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].SubmittedAt.Before(matched[j].SubmittedAt)
+	})
+
+	pageSize := int64(global.Config().PageSize)
+	start, end := pageBounds(len(matched), pageSize, page)
+	return &types.FindTradingApplicationResult{
+		TradingApplications: matched[start:end],
+		NumberOfResults:     len(matched),
+		TotalPages:          pagination.Pages(int64(len(matched)), pageSize),
+	}, nil
+}
+
+func (t *TradingApplications) AddNote(
+	ctx context.Context,
+	id primitive.ObjectID,
+	note *types.TradingApplicationNote,
+) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if a := t.findByID(id); a != nil {
+		a.Notes = append(a.Notes, note)
+		a.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// Decide sets the status of the application and records the decision.
+func (t *TradingApplications) Decide(
+	ctx context.Context,
+	id primitive.ObjectID,
+	status string,
+	event *types.TradingApplicationEvent,
+) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	a := t.findByID(id)
+	if a == nil || (a.Status != constant.TradingApplication.Pending &&
+		a.Status != constant.TradingApplication.InfoRequested) {
+		return e.New(e.ApplicationDecided, "TradingApplicationMongo Decide failed")
+	}
+	a.Status = status
+	a.UpdatedAt = event.CreatedAt
+	if status == constant.TradingApplication.Approved ||
+		status == constant.TradingApplication.Rejected {
+		a.DecidedAt = event.CreatedAt
+	}
+	a.Events = append(a.Events, event)
+	return nil
+}
+
+// Reopen takes back the last decision.
+func (t *TradingApplications) Reopen(ctx context.Context, id primitive.ObjectID, status string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if a := t.findByID(id); a != nil {
+		a.Status = status
+		a.UpdatedAt = time.Now()
+		a.DecidedAt = time.Time{}
+		if len(a.Events) > 0 {
+			a.Events = a.Events[:len(a.Events)-1]
+		}
+	}
+	return nil
+}
+
+// pageBounds returns the slice bounds of the page, an empty range past the
+// last page.
+func pageBounds(n int, pageSize int64, page int64) (int, int) {
+	start := pageSize * (page - 1)
+	if start > int64(n) {
+		start = int64(n)
+	}
+	end := start + pageSize
+	if end > int64(n) {
+		end = int64(n)
+	}
+	return int(start), int(end)
+}
@@ -0,0 +1,297 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Users implements the mongo.User queries.
+type Users struct {
+	mu    sync.Mutex
+	users []*types.User
+}
+
+// NewUsers returns an empty user collection.
+func NewUsers() *Users {
+	return &Users{}
+}
+
+// find returns the user unless it is deleted.
+func (u *Users) find(match func(user *types.User) bool) *types.User {
+	for _, user := range u.users {
+		if user.DeletedAt.IsZero() && match(user) {
+			return user
+		}
+	}
+	return nil
+}
+
+func (u *Users) findByID(id primitive.ObjectID) *types.User {
+	return u.find(func(user *types.User) bool { return user.ID == id })
+}
+
+func copyUser(user *types.User) *types.User {
+	copied := *user
+	copied.FavoriteBusinesses = append(
+		[]primitive.ObjectID(nil),
+		user.FavoriteBusinesses...,
+	)
+	return &copied
+}
+
+func (u *Users) FindByID(ctx context.Context, id primitive.ObjectID) (*types.User, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	user := u.findByID(id)
+	if user == nil {
+		return nil, e.New(e.UserNotFound, "user not found")
+	}
+	return copyUser(user), nil
+}
+
+func (u *Users) FindByEmail(ctx context.Context, email string) (*types.User, error) {
+	email = strings.ToLower(email)
+	if email == "" {
+		return &types.User{}, e.New(e.UserNotFound, "user not found")
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	user := u.find(func(user *types.User) bool { return user.Email == email })
+	if user == nil {
+		return nil, e.New(e.UserNotFound, "user not found")
+	}
+	return copyUser(user), nil
+}
+
+func (u *Users) FindByBusinessID(ctx context.Context, id primitive.ObjectID) (*types.User, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	user := u.find(func(user *types.User) bool { return user.CompanyID == id })
+	if user == nil {
+		return nil, e.New(e.UserNotFound, "user not found")
+	}
+	return copyUser(user), nil
+}
+
+// FindByIDs returns the users in the order of the ids.
+func (u *Users) FindByIDs(ctx context.Context, ids []string) ([]*types.User, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var results []*types.User
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, e.Wrap(err, "find user failed")
+		}
+		if user := u.findByID(objectID); user != nil {
+			results = append(results, copyUser(user))
+		}
+	}
+	return results, nil
+}
+
+// FindByDailyNotification only returns the fields used by the daily email.
+func (u *Users) FindByDailyNotification(ctx context.Context) ([]*types.User, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var users []*types.User
+	for _, user := range u.users {
+		if !user.DeletedAt.IsZero() || !user.DailyNotification {
+			continue
+		}
+		users = append(users, &types.User{
+			ID:                       user.ID,
+			Email:                    user.Email,
+			CompanyID:                user.CompanyID,
+			LastNotificationSentDate: user.LastNotificationSentDate,
+			DailyNotification:        user.DailyNotification,
+		})
+	}
+	return users, nil
+}
+
+// Create only stores the fields of the signup form.
+func (u *Users) Create(ctx context.Context, user *types.User) error {
+	user.Email = strings.ToLower(user.Email)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	user.ID = primitive.NewObjectID()
+	u.users = append(u.users, &types.User{
+		ID:        user.ID,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Email:     user.Email,
+		Password:  user.Password,
+		Telephone: user.Telephone,
+		CompanyID: user.CompanyID,
+		CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+// update applies the change to the user, even a deleted one, like the
+// UpdateOne calls.
+func (u *Users) update(id primitive.ObjectID, change func(user *types.User)) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for _, user := range u.users {
+		if user.ID == id {
+			change(user)
+			user.UpdatedAt = time.Now()
+			return
+		}
+	}
+}
+
+func (u *Users) UpdateTradingInfo(
+	ctx context.Context,
+	id primitive.ObjectID,
+	data *types.TradingRegisterData,
+) error {
+	u.update(id, func(user *types.User) {
+		user.FirstName = data.FirstName
+		user.LastName = data.LastName
+		user.Telephone = data.Telephone
+	})
+	return nil
+}
+
+func (u *Users) UpdatePassword(ctx context.Context, user *types.User) error {
+	u.update(user.ID, func(stored *types.User) {
+		stored.Password = user.Password
+	})
+	return nil
+}
+
+func (u *Users) UpdateUserInfo(ctx context.Context, user *types.User) error {
+	user.Email = strings.ToLower(user.Email)
+	u.update(user.ID, func(stored *types.User) {
+		stored.Email = user.Email
+		stored.FirstName = user.FirstName
+		stored.LastName = user.LastName
+		stored.Telephone = user.Telephone
+		stored.DailyNotification = user.DailyNotification
+	})
+	return nil
+}
+
+func (u *Users) AdminUpdateUser(ctx context.Context, user *types.User) error {
+	return u.UpdateUserInfo(ctx, user)
+}
+
+// UpdateLoginAttempts starts the lock when lockUser is true.
+func (u *Users) UpdateLoginAttempts(
+	ctx context.Context,
+	email string,
+	attempts int,
+	lockUser bool,
+) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for _, user := range u.users {
+		if user.Email != email {
+			continue
+		}
+		user.LoginAttempts = attempts
+		user.UpdatedAt = time.Now()
+		if lockUser {
+			user.LastLoginFailDate = time.Now()
+		}
+		return nil
+	}
+	return nil
+}
+
+func (u *Users) GetLoginInfo(ctx context.Context, id primitive.ObjectID) (*types.LoginInfo, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for _, user := range u.users {
+		if user.ID == id {
+			return &types.LoginInfo{
+				CurrentLoginIP:   user.CurrentLoginIP,
+				CurrentLoginDate: user.CurrentLoginDate,
+				LastLoginIP:      user.LastLoginIP,
+				LastLoginDate:    user.LastLoginDate,
+			}, nil
+		}
+	}
+	return nil, e.Wrap(e.New(e.UserNotFound, "user not found"), "UserMongo GetLoginInfo failed")
+}
+
+func (u *Users) UpdateLoginInfo(
+	ctx context.Context,
+	id primitive.ObjectID,
+	i *types.LoginInfo,
+) error {
+	u.update(id, func(user *types.User) {
+		user.CurrentLoginIP = i.CurrentLoginIP
+		user.CurrentLoginDate = time.Now()
+		user.LastNotificationSentDate = time.Now()
+		user.LastLoginIP = i.LastLoginIP
+		user.LastLoginDate = i.LastLoginDate
+	})
+	return nil
+}
+
+func (u *Users) UpdateLastNotificationSentDate(ctx context.Context, id primitive.ObjectID) error {
+	u.update(id, func(user *types.User) {
+		user.LastNotificationSentDate = time.Now()
+	})
+	return nil
+}
+
+func (u *Users) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	u.update(id, func(user *types.User) {
+		user.DeletedAt = time.Now()
+	})
+	return nil
+}
+
+func (u *Users) ToggleShowRecentMatchedTags(ctx context.Context, id primitive.ObjectID) error {
+	u.update(id, func(user *types.User) {
+		user.ShowRecentMatchedTags = !user.ShowRecentMatchedTags
+	})
+	return nil
+}
+
+func (u *Users) AddToFavoriteBusinesses(ctx context.Context, uID, bID primitive.ObjectID) error {
+	u.update(uID, func(user *types.User) {
+		for _, id := range user.FavoriteBusinesses {
+			if id == bID {
+				return
+			}
+		}
+		user.FavoriteBusinesses = append(user.FavoriteBusinesses, bID)
+	})
+	return nil
+}
+
+func (u *Users) RemoveFromFavoriteBusinesses(ctx context.Context, uID, bID primitive.ObjectID) error {
+	u.update(uID, func(user *types.User) {
+		favorites := user.FavoriteBusinesses[:0]
+		for _, id := range user.FavoriteBusinesses {
+			if id != bID {
+				favorites = append(favorites, id)
+			}
+		}
+		user.FavoriteBusinesses = favorites
+	})
+	return nil
+}
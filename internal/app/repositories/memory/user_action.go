@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserActions implements the mongo.UserAction queries.
+type UserActions struct {
+	mu      sync.Mutex
+	actions []*types.UserAction
+}
+
+// NewUserActions returns an empty action log.
+func NewUserActions() *UserActions {
+	return &UserActions{}
+}
+
+func (u *UserActions) Log(ctx context.Context, a *types.UserAction) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.actions = append(u.actions, &types.UserAction{
+		ID:            primitive.NewObjectID(),
+		CreatedAt:     time.Now(),
+		UserID:        a.UserID,
+		Email:         a.Email,
+		Action:        a.Action,
+		ActionDetails: a.ActionDetails,
+		Category:      a.Category,
+		IPAddress:     a.IPAddress,
+	})
+	return nil
+}
+
+// matchDetails matches any word of the search, like the text index.
+func matchDetails(details string, search string) bool {
+	details = strings.ToLower(details)
+	for _, word := range strings.Fields(strings.ToLower(search)) {
+		if strings.Contains(details, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchUserAction(a *types.UserAction, c *types.UserActionSearchCriteria) bool {
+	switch {
+	case !a.DeletedAt.IsZero():
+		return false
+	case c.Email != "" && !strings.Contains(strings.ToLower(a.Email), strings.ToLower(c.Email)):
+		return false
+	case c.Category != "" && a.Category != c.Category:
+		return false
+	case c.Action != "" && a.Action != c.Action:
+		return false
+	case c.IPAddress != "" && a.IPAddress != c.IPAddress:
+		return false
+	case c.Details != "" && !matchDetails(a.ActionDetails, c.Details):
+		return false
+	case !c.DateFrom.IsZero() && a.CreatedAt.Before(c.DateFrom):
+		return false
+	case !c.DateTo.IsZero() && a.CreatedAt.After(c.DateTo):
+		return false
+	}
+	return true
+}
+
+// Find returns the matching actions, the latest first.
+func (u *UserActions) Find(
+	ctx context.Context,
+	c *types.UserActionSearchCriteria,
+	page int64,
+) ([]*types.UserAction, int, error) {
+	if page < 0 || page == 0 {
+		return nil, 0, e.New(
+			e.InvalidPageNumber,
+			"mongo.userAction.Find failed",
+		)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var matched []*types.UserAction
+	for i := len(u.actions) - 1; i >= 0; i-- {
+		if matchUserAction(u.actions[i], c) {
+			copied := *u.actions[i]
+			matched = append(matched, &copied)
+		}
+	}
+
+	pageSize := int64(global.Config().PageSize)
+	start, end := pageBounds(len(matched), pageSize, page)
+	return matched[start:end], pagination.Pages(int64(len(matched)), pageSize), nil
+}
+
+// Actions returns the distinct actions that have been logged.
+func (u *UserActions) Actions(ctx context.Context) ([]string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	seen := map[string]bool{}
+	actions := []string{}
+	for _, a := range u.actions {
+		if a.Action != "" && !seen[a.Action] {
+			seen[a.Action] = true
+			actions = append(actions, a.Action)
+		}
+	}
+	sort.Strings(actions)
+	return actions, nil
+}
@@ -0,0 +1,205 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type accountFlag struct {
+	c *mongo.Collection
+}
+
+var AccountFlag = &accountFlag{}
+
+func (a *accountFlag) Register(db *mongo.Database) {
+	a.c = db.Collection("accountFlags")
+}
+
+// Flag opens a flag for the business or refreshes the reasons and the
+// balance of its open flag.
+func (a *accountFlag) Flag(ctx context.Context, f *types.AccountFlag) error {
+	now := time.Now()
+	filter := bson.M{
+		"businessID": f.BusinessID,
+		"status":     constant.FlagStatus.Open,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"accountID":     f.AccountID,
+			"reasons":       f.Reasons,
+			"balance":       f.Balance,
+			"lastPostingAt": f.LastPostingAt,
+			"updatedAt":     now,
+		},
+		"$setOnInsert": bson.M{
+			"createdAt": now,
+		},
+	}
+	_, err := a.c.UpdateOne(
+		ctx,
+		filter,
+		update,
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return e.Wrap(err, "AccountFlagMongo Flag failed")
+	}
+	return nil
+}
+
+func (a *accountFlag) FindByID(ctx context.Context, id primitive.ObjectID) (*types.AccountFlag, error) {
+	flag := types.AccountFlag{}
+	err := a.c.FindOne(ctx, bson.M{"_id": id}).Decode(&flag)
+	if err != nil {
+		return nil, e.New(e.AccountFlagNotFound, "Account flag not found")
+	}
+	return &flag, nil
+}
+
+// Find returns the flags with the status, the most recently updated first.
+// All the flags are returned when the status is empty.
+func (a *accountFlag) Find(
+	ctx context.Context,
+	status string,
+	page int64,
+) (*types.FindAccountFlagResult, error) {
+	if page < 0 || page == 0 {
+		return nil, e.New(e.InvalidPageNumber, "AccountFlagMongo Find failed")
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64(global.Config().PageSize) * (page - 1))
+	findOptions.SetLimit(int64(global.Config().PageSize))
+	findOptions.SetSort(bson.M{"updatedAt": -1})
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	results, err := a.find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, e.Wrap(err, "AccountFlagMongo Find failed")
+	}
+
+	totalCount, err := a.c.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, e.Wrap(err, "AccountFlagMongo Find failed")
+	}
+	totalPages := pagination.Pages(totalCount, int64(global.Config().PageSize))
+
+	return &types.FindAccountFlagResult{
+		AccountFlags:    results,
+		NumberOfResults: int(totalCount),
+		TotalPages:      totalPages,
+	}, nil
+}
+
+// FindOpen returns all the open flags.
+func (a *accountFlag) FindOpen(ctx context.Context) ([]*types.AccountFlag, error) {
+	findOptions := options.Find().SetSort(bson.M{"createdAt": 1})
+	results, err := a.find(ctx, bson.M{"status": constant.FlagStatus.Open}, findOptions)
+	if err != nil {
+		return nil, e.Wrap(err, "AccountFlagMongo FindOpen failed")
+	}
+	return results, nil
+}
+
+// FindResolvedSince returns the flags an admin resolved after the given
+// time.
+func (a *accountFlag) FindResolvedSince(ctx context.Context, t time.Time) ([]*types.AccountFlag, error) {
+	filter := bson.M{
+		"status":       constant.FlagStatus.Resolved,
+		"resolvedAt":   bson.M{"$gte": t},
+		"autoResolved": bson.M{"$ne": true},
+	}
+	results, err := a.find(ctx, filter, options.Find())
+	if err != nil {
+		return nil, e.Wrap(err, "AccountFlagMongo FindResolvedSince failed")
+	}
+	return results, nil
+}
+
+func (a *accountFlag) find(
+	ctx context.Context,
+	filter bson.M,
+	findOptions *options.FindOptions,
+) ([]*types.AccountFlag, error) {
+	cur, err := a.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	results := []*types.AccountFlag{}
+	for cur.Next(ctx) {
+		var elem types.AccountFlag
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (a *accountFlag) AddNote(ctx context.Context, id primitive.ObjectID, note *types.AccountFlagNote) error {
+	update := bson.M{
+		"$push": bson.M{"notes": note},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	_, err := a.c.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return e.Wrap(err, "AccountFlagMongo AddNote failed")
+	}
+	return nil
+}
+
+// Resolve closes the flag.
+func (a *accountFlag) Resolve(ctx context.Context, id primitive.ObjectID) error {
+	now := time.Now()
+	update := bson.M{"$set": bson.M{
+		"status":     constant.FlagStatus.Resolved,
+		"resolvedAt": now,
+		"updatedAt":  now,
+	}}
+	_, err := a.c.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return e.Wrap(err, "AccountFlagMongo Resolve failed")
+	}
+	return nil
+}
+
+// ResolveCleared closes the open flags of the businesses which are not
+// flagged anymore and returns the number of closed flags.
+func (a *accountFlag) ResolveCleared(ctx context.Context, flagged []primitive.ObjectID) (int, error) {
+	now := time.Now()
+	filter := bson.M{
+		"status":     constant.FlagStatus.Open,
+		"businessID": bson.M{"$nin": flagged},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":       constant.FlagStatus.Resolved,
+		"autoResolved": true,
+		"resolvedAt":   now,
+		"updatedAt":    now,
+	}}
+	res, err := a.c.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, e.Wrap(err, "AccountFlagMongo ResolveCleared failed")
+	}
+	return int(res.ModifiedCount), nil
+}
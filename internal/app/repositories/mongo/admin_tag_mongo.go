@@ -5,10 +5,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
 	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
-	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -25,7 +25,7 @@ func (a *adminTag) Register(db *mongo.Database) {
 	a.c = db.Collection("adminTags")
 }
 
-func (a *adminTag) Create(name string) error {
+func (a *adminTag) Create(ctx context.Context, name string) error {
 	if name == "" || len(strings.TrimSpace(name)) == 0 {
 		return nil
 	}
@@ -35,7 +35,7 @@ func (a *adminTag) Create(name string) error {
 		"$setOnInsert": bson.M{"name": name, "createdAt": time.Now()},
 	}
 	_, err := a.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 		options.Update().SetUpsert(true),
@@ -43,26 +43,26 @@ func (a *adminTag) Create(name string) error {
 	return err
 }
 
-func (a *adminTag) FindByName(name string) (*types.AdminTag, error) {
+func (a *adminTag) FindByName(ctx context.Context, name string) (*types.AdminTag, error) {
 	adminTag := types.AdminTag{}
 	filter := bson.M{
 		"name":      name,
 		"deletedAt": bson.M{"$exists": false},
 	}
-	err := a.c.FindOne(context.Background(), filter).Decode(&adminTag)
+	err := a.c.FindOne(ctx, filter).Decode(&adminTag)
 	if err != nil {
 		return nil, e.New(e.BusinessNotFound, "Admin tag not found")
 	}
 	return &adminTag, nil
 }
 
-func (a *adminTag) FindByID(id primitive.ObjectID) (*types.AdminTag, error) {
+func (a *adminTag) FindByID(ctx context.Context, id primitive.ObjectID) (*types.AdminTag, error) {
 	adminTag := types.AdminTag{}
 	filter := bson.M{
 		"_id":       id,
 		"deletedAt": bson.M{"$exists": false},
 	}
-	err := a.c.FindOne(context.Background(), filter).Decode(&adminTag)
+	err := a.c.FindOne(ctx, filter).Decode(&adminTag)
 	if err != nil {
 		return nil, e.New(e.BusinessNotFound, "Admin tag not found")
 	}
@@ -70,6 +70,7 @@ func (a *adminTag) FindByID(id primitive.ObjectID) (*types.AdminTag, error) {
 }
 
 func (a *adminTag) FindTags(
+	ctx context.Context,
 	name string,
 	page int64,
 ) (*types.FindAdminTagResult, error) {
@@ -80,20 +81,20 @@ func (a *adminTag) FindTags(
 	var results []*types.AdminTag
 
 	findOptions := options.Find()
-	findOptions.SetSkip(viper.GetInt64("page_size") * (page - 1))
-	findOptions.SetLimit(viper.GetInt64("page_size"))
+	findOptions.SetSkip(int64(global.Config().PageSize) * (page - 1))
+	findOptions.SetLimit(int64(global.Config().PageSize))
 
 	filter := bson.M{
 		"name":      primitive.Regex{Pattern: name, Options: "i"},
 		"deletedAt": bson.M{"$exists": false},
 	}
 
-	cur, err := a.c.Find(context.TODO(), filter, findOptions)
+	cur, err := a.c.Find(ctx, filter, findOptions)
 	if err != nil {
 		return nil, e.Wrap(err, "AdminTagMongo FindTags failed")
 	}
 
-	for cur.Next(context.TODO()) {
+	for cur.Next(ctx) {
 		var elem types.AdminTag
 		err := cur.Decode(&elem)
 		if err != nil {
@@ -104,14 +105,14 @@ func (a *adminTag) FindTags(
 	if err := cur.Err(); err != nil {
 		return nil, e.Wrap(err, "AdminTagMongo FindTags failed")
 	}
-	cur.Close(context.TODO())
+	cur.Close(ctx)
 
 	// Calculate the total page.
-	totalCount, err := a.c.CountDocuments(context.TODO(), filter)
+	totalCount, err := a.c.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, e.Wrap(err, "AdminTagMongo FindTags failed")
 	}
-	totalPages := pagination.Pages(totalCount, viper.GetInt64("page_size"))
+	totalPages := pagination.Pages(totalCount, int64(global.Config().PageSize))
 
 	return &types.FindAdminTagResult{
 		AdminTags:       results,
@@ -120,7 +121,7 @@ func (a *adminTag) FindTags(
 	}, nil
 }
 
-func (a *adminTag) TagStartWith(prefix string) ([]string, error) {
+func (a *adminTag) TagStartWith(ctx context.Context, prefix string) ([]string, error) {
 	var results []string
 
 	filter := bson.M{
@@ -128,12 +129,12 @@ func (a *adminTag) TagStartWith(prefix string) ([]string, error) {
 		"deletedAt": bson.M{"$exists": false},
 	}
 
-	cur, err := a.c.Find(context.TODO(), filter)
+	cur, err := a.c.Find(ctx, filter)
 	if err != nil {
 		return nil, e.Wrap(err, "mongo.AdminTag.FindTagStartWith failed")
 	}
 
-	for cur.Next(context.TODO()) {
+	for cur.Next(ctx) {
 		var elem types.AdminTag
 		err := cur.Decode(&elem)
 		if err != nil {
@@ -144,24 +145,24 @@ func (a *adminTag) TagStartWith(prefix string) ([]string, error) {
 	if err := cur.Err(); err != nil {
 		return nil, e.Wrap(err, "mongo.AdminTag.FindTagStartWith failed")
 	}
-	cur.Close(context.TODO())
+	cur.Close(ctx)
 
 	return results, nil
 }
 
-func (a *adminTag) GetAll() ([]*types.AdminTag, error) {
+func (a *adminTag) GetAll(ctx context.Context) ([]*types.AdminTag, error) {
 	var results []*types.AdminTag
 
 	filter := bson.M{
 		"deletedAt": bson.M{"$exists": false},
 	}
 
-	cur, err := a.c.Find(context.TODO(), filter)
+	cur, err := a.c.Find(ctx, filter)
 	if err != nil {
 		return nil, e.Wrap(err, "AdminTagMongo GetAll failed")
 	}
 
-	for cur.Next(context.TODO()) {
+	for cur.Next(ctx) {
 		var elem types.AdminTag
 		err := cur.Decode(&elem)
 		if err != nil {
@@ -172,19 +173,19 @@ func (a *adminTag) GetAll() ([]*types.AdminTag, error) {
 	if err := cur.Err(); err != nil {
 		return nil, e.Wrap(err, "AdminTagMongo GetAll failed")
 	}
-	cur.Close(context.TODO())
+	cur.Close(ctx)
 
 	return results, nil
 }
 
-func (a *adminTag) Update(t *types.AdminTag) error {
+func (a *adminTag) Update(ctx context.Context, t *types.AdminTag) error {
 	filter := bson.M{"_id": t.ID}
 	update := bson.M{"$set": bson.M{
 		"name":      t.Name,
 		"updatedAt": time.Now(),
 	}}
 	_, err := a.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -194,14 +195,14 @@ func (a *adminTag) Update(t *types.AdminTag) error {
 	return nil
 }
 
-func (a *adminTag) DeleteByID(id primitive.ObjectID) error {
+func (a *adminTag) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
 	filter := bson.M{"_id": id}
 	update := bson.M{"$set": bson.M{
 		"deletedAt": time.Now(),
 		"updatedAt": time.Now(),
 	}}
 	_, err := a.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
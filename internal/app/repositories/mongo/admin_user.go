@@ -23,7 +23,7 @@ func (a *adminUser) Register(db *mongo.Database) {
 	a.c = db.Collection("adminUsers")
 }
 
-func (a *adminUser) FindByEmail(email string) (*types.AdminUser, error) {
+func (a *adminUser) FindByEmail(ctx context.Context, email string) (*types.AdminUser, error) {
 	email = strings.ToLower(email)
 
 	if email == "" {
@@ -34,20 +34,20 @@ func (a *adminUser) FindByEmail(email string) (*types.AdminUser, error) {
 		"email":     email,
 		"deletedAt": bson.M{"$exists": false},
 	}
-	err := a.c.FindOne(context.Background(), filter).Decode(&user)
+	err := a.c.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		return nil, e.New(e.UserNotFound, "admin user not found")
 	}
 	return &user, nil
 }
 
-func (a *adminUser) FindByID(id primitive.ObjectID) (*types.AdminUser, error) {
+func (a *adminUser) FindByID(ctx context.Context, id primitive.ObjectID) (*types.AdminUser, error) {
 	adminUser := types.AdminUser{}
 	filter := bson.M{
 		"_id":       id,
 		"deletedAt": bson.M{"$exists": false},
 	}
-	err := a.c.FindOne(context.Background(), filter).Decode(&adminUser)
+	err := a.c.FindOne(ctx, filter).Decode(&adminUser)
 	if err != nil {
 		return nil, e.New(e.UserNotFound, "admin user not found")
 	}
@@ -55,6 +55,7 @@ func (a *adminUser) FindByID(id primitive.ObjectID) (*types.AdminUser, error) {
 }
 
 func (a *adminUser) GetLoginInfo(
+	ctx context.Context,
 	id primitive.ObjectID,
 ) (*types.LoginInfo, error) {
 	loginInfo := &types.LoginInfo{}
@@ -67,7 +68,7 @@ func (a *adminUser) GetLoginInfo(
 	}
 	findOneOptions := options.FindOne()
 	findOneOptions.SetProjection(projection)
-	err := a.c.FindOne(context.Background(), filter, findOneOptions).
+	err := a.c.FindOne(ctx, filter, findOneOptions).
 		Decode(&loginInfo)
 	if err != nil {
 		return nil, e.Wrap(err, "AdminUserMongo GetLoginInfo failed")
@@ -76,6 +77,7 @@ func (a *adminUser) GetLoginInfo(
 }
 
 func (a *adminUser) UpdateLoginInfo(
+	ctx context.Context,
 	id primitive.ObjectID,
 	i *types.LoginInfo,
 ) error {
@@ -88,7 +90,7 @@ func (a *adminUser) UpdateLoginInfo(
 		"updatedAt":        time.Now(),
 	}}
 	_, err := a.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
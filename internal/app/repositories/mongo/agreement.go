@@ -0,0 +1,91 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type agreement struct {
+	c *mongo.Collection
+}
+
+var Agreement = &agreement{}
+
+func (a *agreement) Register(db *mongo.Database) {
+	a.c = db.Collection("agreements")
+}
+
+// Publish stores the text as the next version of the agreement.
+func (a *agreement) Publish(ctx context.Context, agreement *types.Agreement) (*types.Agreement, error) {
+	latest, err := a.FindLatest(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementMongo Publish failed")
+	}
+	agreement.Version = 1
+	if latest != nil {
+		agreement.Version = latest.Version + 1
+	}
+	agreement.CreatedAt = time.Now()
+
+	res, err := a.c.InsertOne(ctx, agreement)
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementMongo Publish failed")
+	}
+	agreement.ID = res.InsertedID.(primitive.ObjectID)
+	return agreement, nil
+}
+
+// FindLatest returns the latest version, nil when no version has been
+// published yet.
+func (a *agreement) FindLatest(ctx context.Context) (*types.Agreement, error) {
+	agreement := types.Agreement{}
+	findOptions := options.FindOne().SetSort(bson.M{"version": -1})
+	err := a.c.FindOne(ctx, bson.M{}, findOptions).Decode(&agreement)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementMongo FindLatest failed")
+	}
+	return &agreement, nil
+}
+
+func (a *agreement) FindByVersion(ctx context.Context, version int) (*types.Agreement, error) {
+	agreement := types.Agreement{}
+	err := a.c.FindOne(ctx, bson.M{"version": version}).Decode(&agreement)
+	if err != nil {
+		return nil, e.New(e.AgreementNotFound, "Agreement not found")
+	}
+	return &agreement, nil
+}
+
+// FindAll returns all the versions, the latest first.
+func (a *agreement) FindAll(ctx context.Context) ([]*types.Agreement, error) {
+	findOptions := options.Find().SetSort(bson.M{"version": -1})
+	cur, err := a.c.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementMongo FindAll failed")
+	}
+	defer cur.Close(ctx)
+
+	results := []*types.Agreement{}
+	for cur.Next(ctx) {
+		var elem types.Agreement
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, e.Wrap(err, "AgreementMongo FindAll failed")
+		}
+		results = append(results, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, e.Wrap(err, "AgreementMongo FindAll failed")
+	}
+	return results, nil
+}
@@ -0,0 +1,125 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type agreementAcceptance struct {
+	c *mongo.Collection
+}
+
+var AgreementAcceptance = &agreementAcceptance{}
+
+func (a *agreementAcceptance) Register(db *mongo.Database) {
+	a.c = db.Collection("agreementAcceptances")
+}
+
+// Create records the acceptance. Acceptances are never modified.
+func (a *agreementAcceptance) Create(ctx context.Context, acceptance *types.AgreementAcceptance) error {
+	acceptance.CreatedAt = time.Now()
+	_, err := a.c.InsertOne(ctx, acceptance)
+	if err != nil {
+		return e.Wrap(err, "AgreementAcceptanceMongo Create failed")
+	}
+	return nil
+}
+
+// HasAccepted reports whether the user has accepted the version.
+func (a *agreementAcceptance) HasAccepted(ctx context.Context, userID primitive.ObjectID, version int) (bool, error) {
+	count, err := a.c.CountDocuments(
+		ctx,
+		bson.M{"userID": userID, "version": version},
+	)
+	if err != nil {
+		return false, e.Wrap(err, "AgreementAcceptanceMongo HasAccepted failed")
+	}
+	return count > 0, nil
+}
+
+// CountByVersion returns the number of acceptances of every version.
+func (a *agreementAcceptance) CountByVersion(ctx context.Context) (map[int]int, error) {
+	pipeline := []bson.M{
+		{"$group": bson.M{"_id": "$version", "count": bson.M{"$sum": 1}}},
+	}
+	cur, err := a.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementAcceptanceMongo CountByVersion failed")
+	}
+	defer cur.Close(ctx)
+
+	counts := map[int]int{}
+	for cur.Next(ctx) {
+		var elem struct {
+			Version int `bson:"_id"`
+			Count   int `bson:"count"`
+		}
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, e.Wrap(err, "AgreementAcceptanceMongo CountByVersion failed")
+		}
+		counts[elem.Version] = elem.Count
+	}
+	if err := cur.Err(); err != nil {
+		return nil, e.Wrap(err, "AgreementAcceptanceMongo CountByVersion failed")
+	}
+	return counts, nil
+}
+
+// FindByVersion returns the acceptances of the version, the latest
+// first.
+func (a *agreementAcceptance) FindByVersion(
+	ctx context.Context,
+	version int,
+	page int64,
+) (*types.FindAgreementAcceptanceResult, error) {
+	if page < 0 || page == 0 {
+		return nil, e.New(e.InvalidPageNumber, "AgreementAcceptanceMongo FindByVersion failed")
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64(global.Config().PageSize) * (page - 1))
+	findOptions.SetLimit(int64(global.Config().PageSize))
+	findOptions.SetSort(bson.M{"createdAt": -1})
+
+	filter := bson.M{"version": version}
+	cur, err := a.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementAcceptanceMongo FindByVersion failed")
+	}
+	defer cur.Close(ctx)
+
+	results := []*types.AgreementAcceptance{}
+	for cur.Next(ctx) {
+		var elem types.AgreementAcceptance
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, e.Wrap(err, "AgreementAcceptanceMongo FindByVersion failed")
+		}
+		results = append(results, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, e.Wrap(err, "AgreementAcceptanceMongo FindByVersion failed")
+	}
+
+	totalCount, err := a.c.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementAcceptanceMongo FindByVersion failed")
+	}
+	totalPages := pagination.Pages(totalCount, int64(global.Config().PageSize))
+
+	return &types.FindAgreementAcceptanceResult{
+		AgreementAcceptances: results,
+		NumberOfResults:      int(totalCount),
+		TotalPages:           totalPages,
+	}, nil
+}
@@ -0,0 +1,135 @@
+package mongo
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// auditEvent only inserts and reads, the trail is append-only.
+type auditEvent struct {
+	c *mongo.Collection
+}
+
+var AuditEvent = &auditEvent{}
+
+func (a *auditEvent) Register(db *mongo.Database) {
+	a.c = db.Collection("auditEvents")
+}
+
+func (a *auditEvent) Create(ctx context.Context, event *types.AuditEvent) error {
+	event.ID = primitive.NilObjectID
+	event.CreatedAt = time.Now()
+	_, err := a.c.InsertOne(ctx, event)
+	if err != nil {
+		return e.Wrap(err, "AuditEventMongo Create failed")
+	}
+	return nil
+}
+
+func auditFilter(c *types.AuditSearchCriteria) bson.M {
+	filter := bson.M{}
+	if c.ActorEmail != "" {
+		filter["actorEmail"] = primitive.Regex{Pattern: regexp.QuoteMeta(c.ActorEmail), Options: "i"}
+	}
+	if c.TargetType != "" {
+		filter["targetType"] = c.TargetType
+	}
+	if c.TargetID != "" {
+		filter["targetID"] = c.TargetID
+	}
+	if !c.DateFrom.IsZero() || !c.DateTo.IsZero() {
+		createdAt := bson.M{}
+		if !c.DateFrom.IsZero() {
+			createdAt["$gte"] = c.DateFrom
+		}
+		if !c.DateTo.IsZero() {
+			createdAt["$lte"] = c.DateTo
+		}
+		filter["createdAt"] = createdAt
+	}
+	return filter
+}
+
+// Find returns the matching events, the latest first.
+func (a *auditEvent) Find(
+	ctx context.Context,
+	c *types.AuditSearchCriteria,
+	page int64,
+) ([]*types.AuditEvent, int, error) {
+	if page < 0 || page == 0 {
+		return nil, 0, e.New(e.InvalidPageNumber, "AuditEventMongo Find failed")
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64(global.Config().PageSize) * (page - 1))
+	findOptions.SetLimit(int64(global.Config().PageSize))
+	findOptions.SetSort(bson.M{"createdAt": -1})
+
+	filter := auditFilter(c)
+	results := []*types.AuditEvent{}
+	err := a.each(ctx, filter, findOptions, func(event *types.AuditEvent) error {
+		results = append(results, event)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, e.Wrap(err, "AuditEventMongo Find failed")
+	}
+
+	totalCount, err := a.c.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, e.Wrap(err, "AuditEventMongo Find failed")
+	}
+	totalPages := pagination.Pages(totalCount, int64(global.Config().PageSize))
+
+	return results, totalPages, nil
+}
+
+// ForEach calls fn with every matching event, the oldest first. It stops
+// at the first error fn returns.
+func (a *auditEvent) ForEach(
+	ctx context.Context,
+	c *types.AuditSearchCriteria,
+	fn func(*types.AuditEvent) error,
+) error {
+	findOptions := options.Find().SetSort(bson.M{"createdAt": 1})
+	err := a.each(ctx, auditFilter(c), findOptions, fn)
+	if err != nil {
+		return e.Wrap(err, "AuditEventMongo ForEach failed")
+	}
+	return nil
+}
+
+func (a *auditEvent) each(
+	ctx context.Context,
+	filter bson.M,
+	findOptions *options.FindOptions,
+	fn func(*types.AuditEvent) error,
+) error {
+	cur, err := a.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var elem types.AuditEvent
+		err := cur.Decode(&elem)
+		if err != nil {
+			return err
+		}
+		if err := fn(&elem); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
@@ -0,0 +1,54 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAuditFilter(t *testing.T) {
+	from := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		criteria *types.AuditSearchCriteria
+		expected bson.M
+	}{
+		{
+			"should match everything without criteria",
+			&types.AuditSearchCriteria{},
+			bson.M{},
+		},
+		{
+			"should match the actor case-insensitively and literally",
+			&types.AuditSearchCriteria{ActorEmail: "a.b+c@ocn.test"},
+			bson.M{"actorEmail": primitive.Regex{Pattern: `a\.b\+c@ocn\.test`, Options: "i"}},
+		},
+		{
+			"should match the target",
+			&types.AuditSearchCriteria{TargetType: "user", TargetID: "abc"},
+			bson.M{"targetType": "user", "targetID": "abc"},
+		},
+		{
+			"should match an open date range",
+			&types.AuditSearchCriteria{DateFrom: from},
+			bson.M{"createdAt": bson.M{"$gte": from}},
+		},
+		{
+			"should match a date range",
+			&types.AuditSearchCriteria{DateFrom: from, DateTo: to},
+			bson.M{"createdAt": bson.M{"$gte": from, "$lte": to}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, auditFilter(tt.criteria))
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type business struct {
@@ -23,8 +24,7 @@ func (b *business) Register(db *mongo.Database) {
 	b.c = db.Collection("businesses")
 }
 
-func (b *business) FindByID(id primitive.ObjectID) (*types.Business, error) {
-	ctx := context.Background()
+func (b *business) FindByID(ctx context.Context, id primitive.ObjectID) (*types.Business, error) {
 	business := types.Business{}
 	filter := bson.M{
 		"_id":       id,
@@ -38,6 +38,7 @@ func (b *business) FindByID(id primitive.ObjectID) (*types.Business, error) {
 }
 
 func (b *business) UpdateTradingInfo(
+	ctx context.Context,
 	id primitive.ObjectID,
 	data *types.TradingRegisterData,
 ) error {
@@ -59,7 +60,7 @@ func (b *business) UpdateTradingInfo(
 		"updatedAt":          time.Now(),
 	}}
 	_, err := b.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -70,6 +71,7 @@ func (b *business) UpdateTradingInfo(
 }
 
 func (b *business) UpdateBusiness(
+	ctx context.Context,
 	id primitive.ObjectID,
 	data *types.BusinessData,
 	isAdmin bool,
@@ -129,20 +131,20 @@ func (b *business) UpdateBusiness(
 		writes = append(writes, model)
 	}
 
-	_, err := b.c.BulkWrite(context.Background(), writes)
+	_, err := b.c.BulkWrite(ctx, writes)
 	if err != nil {
 		return e.Wrap(err, "businessMongo updateBusiness failed")
 	}
 	return nil
 }
 
-func (b *business) SetMemberStartedAt(id primitive.ObjectID) error {
+func (b *business) SetMemberStartedAt(ctx context.Context, id primitive.ObjectID) error {
 	filter := bson.M{"_id": id}
 	update := bson.M{"$set": bson.M{
 		"memberStartedAt": time.Now(),
 	}}
 	_, err := b.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -152,8 +154,69 @@ func (b *business) SetMemberStartedAt(id primitive.ObjectID) error {
 	return nil
 }
 
+// periodFormats name the periods like pg.Analytics.TradesByPeriod.
+var periodFormats = map[string]string{
+	"day":   "%Y-%m-%d",
+	"week":  "%G-W%V",
+	"month": "%Y-%m",
+}
+
+// CountNewMembers counts the businesses that became trading members in
+// each period.
+func (b *business) CountNewMembers(
+	ctx context.Context,
+	interval string,
+	from time.Time,
+	to time.Time,
+) ([]*types.PeriodCount, error) {
+	format, ok := periodFormats[interval]
+	if !ok {
+		return nil, e.New(e.InvalidInterval, "BusinessMongo CountNewMembers failed")
+	}
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{
+				"memberStartedAt": bson.M{"$gte": from, "$lte": to},
+				"deletedAt":       bson.M{"$exists": false},
+			},
+		},
+		{
+			"$group": bson.M{
+				"_id": bson.M{"$dateToString": bson.M{
+					"format": format,
+					"date":   "$memberStartedAt",
+				}},
+				"count": bson.M{"$sum": 1},
+			},
+		},
+		{
+			"$sort": bson.M{"_id": 1},
+		},
+	}
+	cur, err := b.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, e.Wrap(err, "BusinessMongo CountNewMembers failed")
+	}
+	defer cur.Close(ctx)
+
+	var result []*types.PeriodCount
+	for cur.Next(ctx) {
+		var elem types.PeriodCount
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, e.Wrap(err, "BusinessMongo CountNewMembers failed")
+		}
+		result = append(result, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, e.Wrap(err, "BusinessMongo CountNewMembers failed")
+	}
+	return result, nil
+}
+
 // Create creates a business record in the table.
 func (b *business) Create(
+	ctx context.Context,
 	data *types.BusinessData,
 ) (primitive.ObjectID, error) {
 	doc := bson.M{
@@ -174,7 +237,7 @@ func (b *business) Create(
 		"status":             constant.Business.Pending,
 		"createdAt":          time.Now(),
 	}
-	res, err := b.c.InsertOne(context.Background(), doc)
+	res, err := b.c.InsertOne(ctx, doc)
 	if err != nil {
 		return primitive.ObjectID{}, err
 	}
@@ -182,6 +245,7 @@ func (b *business) Create(
 }
 
 func (b *business) UpdateAllTagsCreatedAt(
+	ctx context.Context,
 	id primitive.ObjectID,
 	t time.Time,
 ) error {
@@ -190,14 +254,14 @@ func (b *business) UpdateAllTagsCreatedAt(
 		"offers.$[].createdAt": t,
 		"wants.$[].createdAt":  t,
 	}}
-	_, err := b.c.UpdateMany(context.Background(), filter, update)
+	_, err := b.c.UpdateMany(ctx, filter, update)
 	if err != nil {
 		return e.Wrap(err, "businessMongo UpdateAllTagsCreatedAt failed")
 	}
 	return nil
 }
 
-func (b *business) FindByIDs(ids []string) ([]*types.Business, error) {
+func (b *business) FindByIDs(ctx context.Context, ids []string) ([]*types.Business, error) {
 	var results []*types.Business
 
 	objectIDs, err := toObjectIDs(ids)
@@ -206,12 +270,12 @@ func (b *business) FindByIDs(ids []string) ([]*types.Business, error) {
 	}
 
 	pipeline := newFindByIDsPipeline(objectIDs)
-	cur, err := b.c.Aggregate(context.TODO(), pipeline)
+	cur, err := b.c.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, e.Wrap(err, "find business failed")
 	}
 
-	for cur.Next(context.TODO()) {
+	for cur.Next(ctx) {
 		var elem types.Business
 		err := cur.Decode(&elem)
 		if err != nil {
@@ -222,16 +286,41 @@ func (b *business) FindByIDs(ids []string) ([]*types.Business, error) {
 	if err := cur.Err(); err != nil {
 		return nil, e.Wrap(err, "find business failed")
 	}
-	cur.Close(context.TODO())
+	cur.Close(ctx)
+
+	return results, nil
+}
+
+// FindAll returns all the businesses sorted by name.
+func (b *business) FindAll(ctx context.Context) ([]*types.Business, error) {
+	filter := bson.M{"deletedAt": bson.M{"$exists": false}}
+	findOptions := options.Find().SetSort(bson.M{"businessName": 1})
+	cur, err := b.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, e.Wrap(err, "BusinessMongo FindAll failed")
+	}
+	defer cur.Close(ctx)
 
+	results := []*types.Business{}
+	for cur.Next(ctx) {
+		var elem types.Business
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, e.Wrap(err, "BusinessMongo FindAll failed")
+		}
+		results = append(results, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, e.Wrap(err, "BusinessMongo FindAll failed")
+	}
 	return results, nil
 }
 
-func (b *business) DeleteByID(id primitive.ObjectID) error {
+func (b *business) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
 	filter := bson.M{"_id": id}
 	update := bson.M{"$set": bson.M{"deletedAt": time.Now()}}
 	_, err := b.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -241,19 +330,19 @@ func (b *business) DeleteByID(id primitive.ObjectID) error {
 	return nil
 }
 
-func (b *business) RenameTag(old string, new string) error {
-	err := b.updateOffers(old, new)
+func (b *business) RenameTag(ctx context.Context, old string, new string) error {
+	err := b.updateOffers(ctx, old, new)
 	if err != nil {
 		return err
 	}
-	err = b.updateWants(old, new)
+	err = b.updateWants(ctx, old, new)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (b *business) updateOffers(old string, new string) error {
+func (b *business) updateOffers(ctx context.Context, old string, new string) error {
 	filter := bson.M{"offers.name": old}
 	update := bson.M{
 		"$set": bson.M{
@@ -261,14 +350,14 @@ func (b *business) updateOffers(old string, new string) error {
 			"updatedAt":     time.Now(),
 		},
 	}
-	_, err := b.c.UpdateMany(context.Background(), filter, update)
+	_, err := b.c.UpdateMany(ctx, filter, update)
 	if err != nil {
 		return e.Wrap(err, "updateOffers failed")
 	}
 	return nil
 }
 
-func (b *business) updateWants(old string, new string) error {
+func (b *business) updateWants(ctx context.Context, old string, new string) error {
 	filter := bson.M{"wants.name": old}
 	update := bson.M{
 		"$set": bson.M{
@@ -276,21 +365,21 @@ func (b *business) updateWants(old string, new string) error {
 			"updatedAt":    time.Now(),
 		},
 	}
-	_, err := b.c.UpdateMany(context.Background(), filter, update)
+	_, err := b.c.UpdateMany(ctx, filter, update)
 	if err != nil {
 		return e.Wrap(err, "updateWants failed")
 	}
 	return nil
 }
 
-func (b *business) RenameAdminTag(old string, new string) error {
+func (b *business) RenameAdminTag(ctx context.Context, old string, new string) error {
 	// Push the new tag tag name.
 	filter := bson.M{"adminTags": old}
 	update := bson.M{
 		"$push": bson.M{"adminTags": new},
 		"$set":  bson.M{"updatedAt": time.Now()},
 	}
-	_, err := b.c.UpdateMany(context.Background(), filter, update)
+	_, err := b.c.UpdateMany(ctx, filter, update)
 	if err != nil {
 		return e.Wrap(err, "RenameAdminTag failed")
 	}
@@ -300,14 +389,14 @@ func (b *business) RenameAdminTag(old string, new string) error {
 		"$pull": bson.M{"adminTags": old},
 		"$set":  bson.M{"updatedAt": time.Now()},
 	}
-	_, err = b.c.UpdateMany(context.Background(), filter, update)
+	_, err = b.c.UpdateMany(ctx, filter, update)
 	if err != nil {
 		return e.Wrap(err, "RenameAdminTag failed")
 	}
 	return nil
 }
 
-func (b *business) DeleteTag(name string) error {
+func (b *business) DeleteTag(ctx context.Context, name string) error {
 	filter := bson.M{
 		"$or": []interface{}{
 			bson.M{"offers.name": name},
@@ -324,7 +413,7 @@ func (b *business) DeleteTag(name string) error {
 		},
 	}
 	_, err := b.c.UpdateMany(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -334,7 +423,7 @@ func (b *business) DeleteTag(name string) error {
 	return nil
 }
 
-func (b *business) DeleteAdminTags(name string) error {
+func (b *business) DeleteAdminTags(ctx context.Context, name string) error {
 	filter := bson.M{
 		"$or": []interface{}{
 			bson.M{"adminTags": name},
@@ -349,7 +438,7 @@ func (b *business) DeleteAdminTags(name string) error {
 		},
 	}
 	_, err := b.c.UpdateMany(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -0,0 +1,84 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type jobLock struct {
+	c *mongo.Collection
+}
+
+var JobLock = &jobLock{}
+
+func (j *jobLock) Register(db *mongo.Database) {
+	j.c = db.Collection("jobLocks")
+}
+
+// Acquire locks the job for the owner until the ttl passes. A scheduled run
+// passes its slot, so the job is not run again for the same slot by another
+// instance once the lock is released. It returns false when the job is locked
+// or has already run for the slot.
+func (j *jobLock) Acquire(
+	ctx context.Context,
+	job string,
+	owner string,
+	slot string,
+	ttl time.Duration,
+) (bool, error) {
+	now := time.Now()
+	filter := bson.M{"_id": job, "lockedUntil": bson.M{"$lt": now}}
+	set := bson.M{"owner": owner, "lockedUntil": now.Add(ttl)}
+	if slot != "" {
+		filter["lastSlot"] = bson.M{"$ne": slot}
+		set["lastSlot"] = slot
+	}
+
+	// The job document is inserted on the first run. When it exists but
+	// does not match the filter, the upsert fails with a duplicate key.
+	_, err := j.c.UpdateOne(
+		ctx,
+		filter,
+		bson.M{"$set": set},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, e.Wrap(err, "JobLockMongo Acquire failed")
+	}
+	return true, nil
+}
+
+// Extend keeps the lock of a job running longer than the ttl. It returns
+// false when the owner does not hold the lock anymore.
+func (j *jobLock) Extend(ctx context.Context, job string, owner string, ttl time.Duration) (bool, error) {
+	res, err := j.c.UpdateOne(
+		ctx,
+		bson.M{"_id": job, "owner": owner},
+		bson.M{"$set": bson.M{"lockedUntil": time.Now().Add(ttl)}},
+	)
+	if err != nil {
+		return false, e.Wrap(err, "JobLockMongo Extend failed")
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// Release unlocks the job. The last slot is kept.
+func (j *jobLock) Release(ctx context.Context, job string, owner string) error {
+	_, err := j.c.UpdateOne(
+		ctx,
+		bson.M{"_id": job, "owner": owner},
+		bson.M{"$set": bson.M{"lockedUntil": time.Time{}}},
+	)
+	if err != nil {
+		return e.Wrap(err, "JobLockMongo Release failed")
+	}
+	return nil
+}
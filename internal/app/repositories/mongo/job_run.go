@@ -0,0 +1,140 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type jobRun struct {
+	c *mongo.Collection
+}
+
+var JobRun = &jobRun{}
+
+func (j *jobRun) Register(db *mongo.Database) {
+	j.c = db.Collection("jobRuns")
+}
+
+// Create records the start of a run.
+func (j *jobRun) Create(ctx context.Context, run *types.JobRun) error {
+	run.StartedAt = time.Now()
+	run.Status = constant.JobStatus.Running
+	res, err := j.c.InsertOne(ctx, run)
+	if err != nil {
+		return e.Wrap(err, "JobRunMongo Create failed")
+	}
+	run.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// Finish records the outcome of a run.
+func (j *jobRun) Finish(ctx context.Context, run *types.JobRun) error {
+	run.EndedAt = time.Now()
+	update := bson.M{"$set": bson.M{
+		"endedAt": run.EndedAt,
+		"status":  run.Status,
+		"items":   run.Items,
+		"error":   run.Error,
+	}}
+	_, err := j.c.UpdateOne(ctx, bson.M{"_id": run.ID}, update)
+	if err != nil {
+		return e.Wrap(err, "JobRunMongo Finish failed")
+	}
+	return nil
+}
+
+// FindByJob returns the runs of the job, the latest first.
+func (j *jobRun) FindByJob(ctx context.Context, job string, page int64) (*types.FindJobRunResult, error) {
+	if page < 0 || page == 0 {
+		return nil, e.New(e.InvalidPageNumber, "JobRunMongo FindByJob failed")
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64(global.Config().PageSize) * (page - 1))
+	findOptions.SetLimit(int64(global.Config().PageSize))
+	findOptions.SetSort(bson.M{"startedAt": -1})
+
+	filter := bson.M{"job": job}
+	cur, err := j.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, e.Wrap(err, "JobRunMongo FindByJob failed")
+	}
+	defer cur.Close(ctx)
+
+	results := []*types.JobRun{}
+	for cur.Next(ctx) {
+		var elem types.JobRun
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, e.Wrap(err, "JobRunMongo FindByJob failed")
+		}
+		results = append(results, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, e.Wrap(err, "JobRunMongo FindByJob failed")
+	}
+
+	totalCount, err := j.c.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, e.Wrap(err, "JobRunMongo FindByJob failed")
+	}
+	totalPages := pagination.Pages(totalCount, int64(global.Config().PageSize))
+
+	return &types.FindJobRunResult{
+		JobRuns:         results,
+		NumberOfResults: int(totalCount),
+		TotalPages:      totalPages,
+	}, nil
+}
+
+// Latest returns the latest run of every job.
+func (j *jobRun) Latest(ctx context.Context) (map[string]*types.JobRun, error) {
+	pipeline := []bson.M{
+		{"$sort": bson.M{"startedAt": -1}},
+		{"$group": bson.M{"_id": "$job", "run": bson.M{"$first": "$$ROOT"}}},
+	}
+	cur, err := j.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, e.Wrap(err, "JobRunMongo Latest failed")
+	}
+	defer cur.Close(ctx)
+
+	latest := map[string]*types.JobRun{}
+	for cur.Next(ctx) {
+		var elem struct {
+			Run *types.JobRun `bson:"run"`
+		}
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, e.Wrap(err, "JobRunMongo Latest failed")
+		}
+		latest[elem.Run.Job] = elem.Run
+	}
+	if err := cur.Err(); err != nil {
+		return nil, e.Wrap(err, "JobRunMongo Latest failed")
+	}
+	return latest, nil
+}
+
+// EnsureIndexes creates the index of the job history. Creating an existing
+// index does nothing.
+func (j *jobRun) EnsureIndexes() error {
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "job", Value: 1}, {Key: "startedAt", Value: -1}}},
+	}
+	_, err := j.c.Indexes().CreateMany(context.Background(), models)
+	if err != nil {
+		return e.Wrap(err, "mongo.jobRun.EnsureIndexes failed")
+	}
+	return nil
+}
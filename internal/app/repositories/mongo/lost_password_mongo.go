@@ -23,7 +23,7 @@ func (l *lostPassword) Register(db *mongo.Database) {
 }
 
 // Create creates a lost password record in the table
-func (l *lostPassword) Create(lostPassword *types.LostPassword) error {
+func (l *lostPassword) Create(ctx context.Context, lostPassword *types.LostPassword) error {
 	filter := bson.M{"email": lostPassword.Email}
 	update := bson.M{"$set": bson.M{
 		"email":     lostPassword.Email,
@@ -32,7 +32,7 @@ func (l *lostPassword) Create(lostPassword *types.LostPassword) error {
 		"createdAt": time.Now(),
 	}}
 	_, err := l.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 		options.Update().SetUpsert(true),
@@ -40,12 +40,12 @@ func (l *lostPassword) Create(lostPassword *types.LostPassword) error {
 	return err
 }
 
-func (l *lostPassword) FindByToken(token string) (*types.LostPassword, error) {
+func (l *lostPassword) FindByToken(ctx context.Context, token string) (*types.LostPassword, error) {
 	if token == "" {
 		return nil, e.New(e.TokenInvalid, "token not found")
 	}
 	lostPassword := types.LostPassword{}
-	err := l.c.FindOne(context.Background(), types.LostPassword{Token: token}).
+	err := l.c.FindOne(ctx, types.LostPassword{Token: token}).
 		Decode(&lostPassword)
 	if err != nil {
 		return nil, e.New(e.TokenInvalid, "token not found")
@@ -53,12 +53,12 @@ func (l *lostPassword) FindByToken(token string) (*types.LostPassword, error) {
 	return &lostPassword, nil
 }
 
-func (l *lostPassword) FindByEmail(email string) (*types.LostPassword, error) {
+func (l *lostPassword) FindByEmail(ctx context.Context, email string) (*types.LostPassword, error) {
 	if email == "" {
 		return nil, e.New(e.TokenInvalid, "token not found")
 	}
 	lostPassword := types.LostPassword{}
-	err := l.c.FindOne(context.Background(), types.LostPassword{Email: email}).
+	err := l.c.FindOne(ctx, types.LostPassword{Email: email}).
 		Decode(&lostPassword)
 	if err != nil {
 		return nil, e.New(e.TokenInvalid, "token not found")
@@ -66,9 +66,9 @@ func (l *lostPassword) FindByEmail(email string) (*types.LostPassword, error) {
 	return &lostPassword, nil
 }
 
-func (l *lostPassword) SetTokenUsed(token string) error {
+func (l *lostPassword) SetTokenUsed(ctx context.Context, token string) error {
 	filter := bson.M{"token": token}
 	update := bson.M{"$set": bson.M{"tokenUsed": true}}
-	_, err := l.c.UpdateOne(context.Background(), filter, update)
+	_, err := l.c.UpdateOne(ctx, filter, update)
 	return err
 }
@@ -2,13 +2,16 @@ package mongo
 
 import (
 	"context"
+	"errors"
 	"log"
 	"time"
 
 	"github.com/ic3network/mccs-alpha/global"
-	"github.com/spf13/viper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/metrics"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 var db *mongo.Database
@@ -16,7 +19,7 @@ var db *mongo.Database
 func init() {
 	global.Init()
 	// TODO: set up test docker environment.
-	if viper.GetString("env") == "test" {
+	if global.Config().IsTest() {
 		return
 	}
 	db = New()
@@ -31,14 +34,26 @@ func registerCollections(db *mongo.Database) {
 	Tag.Register(db)
 	AdminTag.Register(db)
 	LostPassword.Register(db)
+	Outbox.Register(db)
+	AccountFlag.Register(db)
+	TradingApplication.Register(db)
+	Agreement.Register(db)
+	AgreementAcceptance.Register(db)
+	AuditEvent.Register(db)
+	JobLock.Register(db)
+	JobRun.Register(db)
+	UserActionArchive.Register(db)
 }
 
 // New returns an initialized JWT instance.
 func New() *mongo.Database {
-	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
 	client, err := mongo.NewClient(
-		options.Client().ApplyURI(viper.GetString("mongo.url")),
+		options.Client().
+			ApplyURI(global.Config().Mongo.URL).
+			SetMonitor(tracing.MongoMonitor(metrics.MongoMonitor())),
 	)
 	if err != nil {
 		log.Fatal(err)
@@ -55,7 +70,7 @@ func New() *mongo.Database {
 		log.Fatal(err)
 	}
 
-	db := client.Database(viper.GetString("mongo.database"))
+	db := client.Database(global.Config().Mongo.Database)
 	return db
 }
 
@@ -63,3 +78,19 @@ func New() *mongo.Database {
 func DB() *mongo.Database {
 	return db
 }
+
+// Ping checks the connection to the primary of MongoDB.
+func Ping(ctx context.Context) error {
+	if db == nil {
+		return errors.New("mongo is not connected")
+	}
+	return db.Client().Ping(ctx, readpref.Primary())
+}
+
+// Close disconnects from MongoDB once the in-flight operations are done.
+func Close(ctx context.Context) error {
+	if db == nil {
+		return nil
+	}
+	return db.Client().Disconnect(ctx)
+}
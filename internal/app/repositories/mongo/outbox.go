@@ -0,0 +1,227 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type outbox struct {
+	c *mongo.Collection
+}
+
+var Outbox = &outbox{}
+
+func (o *outbox) Register(db *mongo.Database) {
+	o.c = db.Collection("outbox")
+}
+
+// Add records a change to be applied to Elasticsearch.
+func (o *outbox) Add(ctx context.Context, event *types.OutboxEvent) error {
+	event.CreatedAt = time.Now()
+	res, err := o.c.InsertOne(ctx, event)
+	if err != nil {
+		return e.Wrap(err, "OutboxMongo Add failed")
+	}
+	event.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// WithTransaction runs fn in a session transaction, the writes fn makes
+// with its ctx are committed together with their outbox events, so the
+// indexer can't miss a write. It needs MongoDB to run as a replica set.
+func (o *outbox) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := o.c.Database().Client().StartSession()
+	if err != nil {
+		return e.Wrap(err, "OutboxMongo WithTransaction failed")
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// FindPending returns the events due to be applied, the oldest first.
+func (o *outbox) FindPending(ctx context.Context, limit int64) ([]*types.OutboxEvent, error) {
+	filter := bson.M{
+		"deadLetteredAt": bson.M{"$exists": false},
+		"$or": []bson.M{
+			{"nextAttemptAt": bson.M{"$exists": false}},
+			{"nextAttemptAt": bson.M{"$lte": time.Now()}},
+		},
+	}
+	findOptions := options.Find().
+		SetSort(bson.M{"_id": 1}).
+		SetLimit(limit)
+
+	events, err := o.find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, e.Wrap(err, "OutboxMongo FindPending failed")
+	}
+	return events, nil
+}
+
+// FindWaiting returns the events waiting for their next attempt, without
+// their arguments. The indexer holds back the later events of their
+// documents.
+func (o *outbox) FindWaiting(ctx context.Context) ([]*types.OutboxEvent, error) {
+	filter := bson.M{
+		"deadLetteredAt": bson.M{"$exists": false},
+		"nextAttemptAt":  bson.M{"$gt": time.Now()},
+	}
+	findOptions := options.Find().
+		SetSort(bson.M{"_id": 1}).
+		SetProjection(bson.M{"collection": 1, "documentID": 1, "nextAttemptAt": 1})
+
+	events, err := o.find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, e.Wrap(err, "OutboxMongo FindWaiting failed")
+	}
+	return events, nil
+}
+
+func (o *outbox) find(
+	ctx context.Context,
+	filter bson.M,
+	findOptions *options.FindOptions,
+) ([]*types.OutboxEvent, error) {
+	cur, err := o.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	events := []*types.OutboxEvent{}
+	for cur.Next(ctx) {
+		var event types.OutboxEvent
+		if err := cur.Decode(&event); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Retry schedules the next attempt of a failed event.
+func (o *outbox) Retry(
+	ctx context.Context,
+	id primitive.ObjectID,
+	attempts int,
+	next time.Time,
+	reason string,
+) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{
+		"attempts":      attempts,
+		"nextAttemptAt": next,
+		"lastError":     reason,
+	}}
+	_, err := o.c.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return e.Wrap(err, "OutboxMongo Retry failed")
+	}
+	return nil
+}
+
+// DeadLetter keeps the event the indexer gave up on, it is no longer
+// pending.
+func (o *outbox) DeadLetter(
+	ctx context.Context,
+	id primitive.ObjectID,
+	attempts int,
+	reason string,
+) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{
+		"$set": bson.M{
+			"attempts":       attempts,
+			"lastError":      reason,
+			"deadLetteredAt": time.Now(),
+		},
+		"$unset": bson.M{"nextAttemptAt": ""},
+	}
+	_, err := o.c.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return e.Wrap(err, "OutboxMongo DeadLetter failed")
+	}
+	return nil
+}
+
+// DeleteByID removes an applied event.
+func (o *outbox) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	_, err := o.c.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return e.Wrap(err, "OutboxMongo DeleteByID failed")
+	}
+	return nil
+}
+
+func (o *outbox) Stats(ctx context.Context) (*types.OutboxStats, error) {
+	pending := bson.M{"deadLetteredAt": bson.M{"$exists": false}}
+	count, err := o.c.CountDocuments(ctx, pending)
+	if err != nil {
+		return nil, e.Wrap(err, "OutboxMongo Stats failed")
+	}
+	dead, err := o.c.CountDocuments(ctx, bson.M{"deadLetteredAt": bson.M{"$exists": true}})
+	if err != nil {
+		return nil, e.Wrap(err, "OutboxMongo Stats failed")
+	}
+	stats := &types.OutboxStats{Pending: count, DeadLetters: dead}
+	if count == 0 {
+		return stats, nil
+	}
+
+	oldest := types.OutboxEvent{}
+	findOptions := options.FindOne().SetSort(bson.M{"_id": 1})
+	err = o.c.FindOne(ctx, pending, findOptions).Decode(&oldest)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, e.Wrap(err, "OutboxMongo Stats failed")
+	}
+	stats.OldestCreatedAt = oldest.CreatedAt
+	return stats, nil
+}
+
+// EnsureIndexes creates the index of the dead letters. Creating an existing
+// index does nothing. It also creates the collection, which MongoDB 4.0
+// can't do in the transactions adding the events.
+func (o *outbox) EnsureIndexes() error {
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deadLetteredAt", Value: 1}}},
+	}
+	_, err := o.c.Indexes().CreateMany(context.Background(), models)
+	if err != nil {
+		return e.Wrap(err, "mongo.outbox.EnsureIndexes failed")
+	}
+	return nil
+}
+
+// FindDocument returns the raw document of the collection, including the
+// soft-deleted ones. It returns nil when the document doesn't exist.
+func (o *outbox) FindDocument(ctx context.Context, collection, id string) (bson.Raw, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, e.Wrap(err, "OutboxMongo FindDocument failed")
+	}
+	raw, err := o.c.Database().
+		Collection(collection).
+		FindOne(ctx, bson.M{"_id": objID}).
+		DecodeBytes()
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, e.Wrap(err, "OutboxMongo FindDocument failed")
+	}
+	return raw, nil
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
 	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
-	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -25,14 +25,14 @@ func (t *tag) Register(db *mongo.Database) {
 }
 
 // Create creates a tag record in the table
-func (t *tag) Create(name string) (primitive.ObjectID, error) {
+func (t *tag) Create(ctx context.Context, name string) (primitive.ObjectID, error) {
 	filter := bson.M{"name": name}
 	update := bson.M{"$setOnInsert": bson.M{
 		"name":      name,
 		"createdAt": time.Now(),
 	}}
 	res, err := t.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 		options.Update().SetUpsert(true),
@@ -43,7 +43,7 @@ func (t *tag) Create(name string) (primitive.ObjectID, error) {
 	return res.UpsertedID.(primitive.ObjectID), nil
 }
 
-func (t *tag) UpdateOffer(name string) (primitive.ObjectID, error) {
+func (t *tag) UpdateOffer(ctx context.Context, name string) (primitive.ObjectID, error) {
 	filter := bson.M{"name": name}
 	update := bson.M{
 		"$set": bson.M{
@@ -56,7 +56,7 @@ func (t *tag) UpdateOffer(name string) (primitive.ObjectID, error) {
 		},
 	}
 	res := t.c.FindOneAndUpdate(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 		options.FindOneAndUpdate().
@@ -75,7 +75,7 @@ func (t *tag) UpdateOffer(name string) (primitive.ObjectID, error) {
 	return tag.ID, nil
 }
 
-func (t *tag) UpdateWant(name string) (primitive.ObjectID, error) {
+func (t *tag) UpdateWant(ctx context.Context, name string) (primitive.ObjectID, error) {
 	filter := bson.M{"name": name}
 	update := bson.M{
 		"$set": bson.M{
@@ -88,7 +88,7 @@ func (t *tag) UpdateWant(name string) (primitive.ObjectID, error) {
 		},
 	}
 	res := t.c.FindOneAndUpdate(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 		options.FindOneAndUpdate().
@@ -107,33 +107,33 @@ func (t *tag) UpdateWant(name string) (primitive.ObjectID, error) {
 	return tag.ID, nil
 }
 
-func (t *tag) FindByName(name string) (*types.Tag, error) {
+func (t *tag) FindByName(ctx context.Context, name string) (*types.Tag, error) {
 	tag := types.Tag{}
 	filter := bson.M{
 		"name":      name,
 		"deletedAt": bson.M{"$exists": false},
 	}
-	err := t.c.FindOne(context.Background(), filter).Decode(&tag)
+	err := t.c.FindOne(ctx, filter).Decode(&tag)
 	if err != nil {
 		return nil, e.New(e.BusinessNotFound, "Tag not found")
 	}
 	return &tag, nil
 }
 
-func (t *tag) FindByID(id primitive.ObjectID) (*types.Tag, error) {
+func (t *tag) FindByID(ctx context.Context, id primitive.ObjectID) (*types.Tag, error) {
 	tag := types.Tag{}
 	filter := bson.M{
 		"_id":       id,
 		"deletedAt": bson.M{"$exists": false},
 	}
-	err := t.c.FindOne(context.Background(), filter).Decode(&tag)
+	err := t.c.FindOne(ctx, filter).Decode(&tag)
 	if err != nil {
 		return nil, e.New(e.BusinessNotFound, "Tag not found")
 	}
 	return &tag, nil
 }
 
-func (t *tag) FindTags(name string, page int64) (*types.FindTagResult, error) {
+func (t *tag) FindTags(ctx context.Context, name string, page int64) (*types.FindTagResult, error) {
 	if page < 0 || page == 0 {
 		return nil, e.New(e.InvalidPageNumber, "TagMongo FindTags failed")
 	}
@@ -141,20 +141,20 @@ func (t *tag) FindTags(name string, page int64) (*types.FindTagResult, error) {
 	var results []*types.Tag
 
 	findOptions := options.Find()
-	findOptions.SetSkip(viper.GetInt64("page_size") * (page - 1))
-	findOptions.SetLimit(viper.GetInt64("page_size"))
+	findOptions.SetSkip(int64(global.Config().PageSize) * (page - 1))
+	findOptions.SetLimit(int64(global.Config().PageSize))
 
 	filter := bson.M{
 		"name":      primitive.Regex{Pattern: name, Options: "i"},
 		"deletedAt": bson.M{"$exists": false},
 	}
 
-	cur, err := t.c.Find(context.TODO(), filter, findOptions)
+	cur, err := t.c.Find(ctx, filter, findOptions)
 	if err != nil {
 		return nil, e.Wrap(err, "TagMongo FindTags failed")
 	}
 
-	for cur.Next(context.TODO()) {
+	for cur.Next(ctx) {
 		var elem types.Tag
 		err := cur.Decode(&elem)
 		if err != nil {
@@ -165,13 +165,13 @@ func (t *tag) FindTags(name string, page int64) (*types.FindTagResult, error) {
 	if err := cur.Err(); err != nil {
 		return nil, e.Wrap(err, "TagMongo FindTags failed")
 	}
-	cur.Close(context.TODO())
+	cur.Close(ctx)
 
-	totalCount, err := t.c.CountDocuments(context.TODO(), filter)
+	totalCount, err := t.c.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, e.Wrap(err, "TagMongo FindTags failed")
 	}
-	totalPages := pagination.Pages(totalCount, viper.GetInt64("page_size"))
+	totalPages := pagination.Pages(totalCount, int64(global.Config().PageSize))
 
 	return &types.FindTagResult{
 		Tags:            results,
@@ -180,14 +180,14 @@ func (t *tag) FindTags(name string, page int64) (*types.FindTagResult, error) {
 	}, nil
 }
 
-func (t *tag) Rename(tag *types.Tag) error {
+func (t *tag) Rename(ctx context.Context, tag *types.Tag) error {
 	filter := bson.M{"_id": tag.ID}
 	update := bson.M{"$set": bson.M{
 		"name":      tag.Name,
 		"updatedAt": time.Now(),
 	}}
 	_, err := t.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -197,14 +197,14 @@ func (t *tag) Rename(tag *types.Tag) error {
 	return nil
 }
 
-func (t *tag) DeleteByID(id primitive.ObjectID) error {
+func (t *tag) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
 	filter := bson.M{"_id": id}
 	update := bson.M{"$set": bson.M{
 		"deletedAt": time.Now(),
 		"updatedAt": time.Now(),
 	}}
 	_, err := t.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -0,0 +1,233 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type tradingApplication struct {
+	c *mongo.Collection
+}
+
+var TradingApplication = &tradingApplication{}
+
+func (t *tradingApplication) Register(db *mongo.Database) {
+	t.c = db.Collection("tradingApplications")
+}
+
+// openStatuses are the statuses of the applications waiting for a
+// decision or for the applicant.
+var openStatuses = []string{
+	constant.TradingApplication.Pending,
+	constant.TradingApplication.InfoRequested,
+}
+
+// Submit opens an application for the business or submits its open
+// application again.
+func (t *tradingApplication) Submit(
+	ctx context.Context,
+	a *types.TradingApplication,
+	event *types.TradingApplicationEvent,
+) error {
+	now := time.Now()
+	filter := bson.M{
+		"businessID": a.BusinessID,
+		"status":     bson.M{"$in": openStatuses},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"userID":      a.UserID,
+			"status":      constant.TradingApplication.Pending,
+			"submittedAt": now,
+			"data":        a.Data,
+			"changes":     a.Changes,
+			"updatedAt":   now,
+		},
+		"$setOnInsert": bson.M{
+			"businessID": a.BusinessID,
+			"createdAt":  now,
+		},
+		"$push": bson.M{"events": event},
+	}
+	_, err := t.c.UpdateOne(
+		ctx,
+		filter,
+		update,
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return e.Wrap(err, "TradingApplicationMongo Submit failed")
+	}
+	return nil
+}
+
+func (t *tradingApplication) FindByID(ctx context.Context, id primitive.ObjectID) (*types.TradingApplication, error) {
+	a := types.TradingApplication{}
+	err := t.c.FindOne(ctx, bson.M{"_id": id}).Decode(&a)
+	if err != nil {
+		return nil, e.New(e.BusinessNotFound, "Trading application not found")
+	}
+	return &a, nil
+}
+
+// FindOpenByBusinessID returns the open application of the business.
+func (t *tradingApplication) FindOpenByBusinessID(ctx context.Context, id primitive.ObjectID) (*types.TradingApplication, error) {
+	a := types.TradingApplication{}
+	filter := bson.M{
+		"businessID": id,
+		"status":     bson.M{"$in": openStatuses},
+	}
+	err := t.c.FindOne(ctx, filter).Decode(&a)
+	if err != nil {
+		return nil, e.New(e.BusinessNotFound, "Trading application not found")
+	}
+	return &a, nil
+}
+
+// FindByBusinessID returns all the applications of the business, the
+// oldest first.
+func (t *tradingApplication) FindByBusinessID(ctx context.Context, id primitive.ObjectID) ([]*types.TradingApplication, error) {
+	findOptions := options.Find().SetSort(bson.M{"createdAt": 1})
+	results, err := t.find(ctx, bson.M{"businessID": id}, findOptions)
+	if err != nil {
+		return nil, e.Wrap(err, "TradingApplicationMongo FindByBusinessID failed")
+	}
+	return results, nil
+}
+
+// Find returns the applications with the statuses, the longest waiting
+// first.
+func (t *tradingApplication) Find(
+	ctx context.Context,
+	statuses []string,
+	page int64,
+) (*types.FindTradingApplicationResult, error) {
+	if page < 0 || page == 0 {
+		return nil, e.New(e.InvalidPageNumber, "TradingApplicationMongo Find failed")
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64(global.Config().PageSize) * (page - 1))
+	findOptions.SetLimit(int64(global.Config().PageSize))
+	findOptions.SetSort(bson.M{"submittedAt": 1})
+
+	filter := bson.M{"status": bson.M{"$in": statuses}}
+	results, err := t.find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, e.Wrap(err, "TradingApplicationMongo Find failed")
+	}
+
+	totalCount, err := t.c.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, e.Wrap(err, "TradingApplicationMongo Find failed")
+	}
+	totalPages := pagination.Pages(totalCount, int64(global.Config().PageSize))
+
+	return &types.FindTradingApplicationResult{
+		TradingApplications: results,
+		NumberOfResults:     int(totalCount),
+		TotalPages:          totalPages,
+	}, nil
+}
+
+func (t *tradingApplication) find(
+	ctx context.Context,
+	filter bson.M,
+	findOptions *options.FindOptions,
+) ([]*types.TradingApplication, error) {
+	cur, err := t.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	results := []*types.TradingApplication{}
+	for cur.Next(ctx) {
+		var elem types.TradingApplication
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (t *tradingApplication) AddNote(
+	ctx context.Context,
+	id primitive.ObjectID,
+	note *types.TradingApplicationNote,
+) error {
+	update := bson.M{
+		"$push": bson.M{"notes": note},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	_, err := t.c.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return e.Wrap(err, "TradingApplicationMongo AddNote failed")
+	}
+	return nil
+}
+
+// Decide sets the status of the open application and records the decision.
+// Only one of the admins deciding at the same time succeeds, the others get
+// ApplicationDecided.
+func (t *tradingApplication) Decide(
+	ctx context.Context,
+	id primitive.ObjectID,
+	status string,
+	event *types.TradingApplicationEvent,
+) error {
+	set := bson.M{
+		"status":    status,
+		"updatedAt": event.CreatedAt,
+	}
+	if status == constant.TradingApplication.Approved ||
+		status == constant.TradingApplication.Rejected {
+		set["decidedAt"] = event.CreatedAt
+	}
+	update := bson.M{
+		"$set":  set,
+		"$push": bson.M{"events": event},
+	}
+	filter := bson.M{
+		"_id":    id,
+		"status": bson.M{"$in": openStatuses},
+	}
+	res, err := t.c.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return e.Wrap(err, "TradingApplicationMongo Decide failed")
+	}
+	if res.MatchedCount == 0 {
+		return e.New(e.ApplicationDecided, "TradingApplicationMongo Decide failed")
+	}
+	return nil
+}
+
+// Reopen takes back the last decision, e.g. when the business could not be
+// updated, and gives the application its previous status.
+func (t *tradingApplication) Reopen(ctx context.Context, id primitive.ObjectID, status string) error {
+	update := bson.M{
+		"$set":   bson.M{"status": status, "updatedAt": time.Now()},
+		"$unset": bson.M{"decidedAt": ""},
+		"$pop":   bson.M{"events": 1},
+	}
+	_, err := t.c.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return e.Wrap(err, "TradingApplicationMongo Reopen failed")
+	}
+	return nil
+}
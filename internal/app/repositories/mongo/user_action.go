@@ -2,12 +2,14 @@ package mongo
 
 import (
 	"context"
+	"regexp"
+	"sort"
 	"time"
 
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
 	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
-	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -24,8 +26,7 @@ func (u *userAction) Register(db *mongo.Database) {
 	u.c = db.Collection("userActions")
 }
 
-func (u *userAction) Log(a *types.UserAction) error {
-	ctx := context.Background()
+func (u *userAction) Log(ctx context.Context, a *types.UserAction) error {
 	doc := bson.M{
 		"userID":        a.UserID,
 		"email":         a.Email,
@@ -34,6 +35,9 @@ func (u *userAction) Log(a *types.UserAction) error {
 		"category":      a.Category,
 		"createdAt":     time.Now(),
 	}
+	if a.IPAddress != "" {
+		doc["ipAddress"] = a.IPAddress
+	}
 	_, err := u.c.InsertOne(ctx, doc)
 	if err != nil {
 		return err
@@ -42,10 +46,10 @@ func (u *userAction) Log(a *types.UserAction) error {
 }
 
 func (u *userAction) Find(
+	ctx context.Context,
 	c *types.UserActionSearchCriteria,
 	page int64,
 ) ([]*types.UserAction, int, error) {
-	ctx := context.Background()
 	if page < 0 || page == 0 {
 		return nil, 0, e.New(
 			e.InvalidPageNumber,
@@ -56,20 +60,29 @@ func (u *userAction) Find(
 	var results []*types.UserAction
 
 	findOptions := options.Find()
-	findOptions.SetSkip(viper.GetInt64("page_size") * (page - 1))
-	findOptions.SetLimit(viper.GetInt64("page_size"))
+	findOptions.SetSkip(int64(global.Config().PageSize) * (page - 1))
+	findOptions.SetLimit(int64(global.Config().PageSize))
 	findOptions.SetSort(bson.M{"createdAt": -1})
 
 	filter := bson.M{
 		"deletedAt": bson.M{"$exists": false},
 	}
 	if c.Email != "" {
-		pattern := c.Email
+		pattern := regexp.QuoteMeta(c.Email)
 		filter["email"] = primitive.Regex{Pattern: pattern, Options: "i"}
 	}
 	if c.Category != "" {
 		filter["category"] = c.Category
 	}
+	if c.Action != "" {
+		filter["action"] = c.Action
+	}
+	if c.IPAddress != "" {
+		filter["ipAddress"] = c.IPAddress
+	}
+	if c.Details != "" {
+		filter["$text"] = bson.M{"$search": c.Details}
+	}
 
 	// Should not overwrite each others.
 	if !c.DateFrom.IsZero() || !c.DateTo.IsZero() {
@@ -105,7 +118,84 @@ func (u *userAction) Find(
 	if err != nil {
 		return nil, 0, e.Wrap(err, "mongo.userAction.Find failed")
 	}
-	totalPages := pagination.Pages(totalCount, viper.GetInt64("page_size"))
+	totalPages := pagination.Pages(totalCount, int64(global.Config().PageSize))
 
 	return results, totalPages, nil
 }
+
+// Actions returns the distinct actions that have been logged.
+func (u *userAction) Actions(ctx context.Context) ([]string, error) {
+	values, err := u.c.Distinct(ctx, "action", bson.M{})
+	if err != nil {
+		return nil, e.Wrap(err, "mongo.userAction.Actions failed")
+	}
+	actions := make([]string, 0, len(values))
+	for _, v := range values {
+		if action, ok := v.(string); ok && action != "" {
+			actions = append(actions, action)
+		}
+	}
+	sort.Strings(actions)
+	return actions, nil
+}
+
+// ForEachBefore calls fn with every action logged before t, the oldest
+// first. It stops at the first error fn returns.
+func (u *userAction) ForEachBefore(
+	ctx context.Context,
+	t time.Time,
+	fn func(*types.UserAction) error,
+) error {
+	findOptions := options.Find().SetSort(bson.M{"createdAt": 1})
+	cur, err := u.c.Find(ctx, bson.M{"createdAt": bson.M{"$lt": t}}, findOptions)
+	if err != nil {
+		return e.Wrap(err, "mongo.userAction.ForEachBefore failed")
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var elem types.UserAction
+		err := cur.Decode(&elem)
+		if err != nil {
+			return e.Wrap(err, "mongo.userAction.ForEachBefore failed")
+		}
+		if err := fn(&elem); err != nil {
+			return err
+		}
+	}
+	if err := cur.Err(); err != nil {
+		return e.Wrap(err, "mongo.userAction.ForEachBefore failed")
+	}
+	return nil
+}
+
+// DeleteBefore removes the actions logged before t and returns how many
+// were removed.
+func (u *userAction) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	res, err := u.c.DeleteMany(
+		ctx,
+		bson.M{"createdAt": bson.M{"$lt": t}},
+	)
+	if err != nil {
+		return 0, e.Wrap(err, "mongo.userAction.DeleteBefore failed")
+	}
+	return res.DeletedCount, nil
+}
+
+// EnsureIndexes creates the indexes of the search filters. Creating an
+// existing index does nothing.
+func (u *userAction) EnsureIndexes() error {
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "email", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "category", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "action", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "ipAddress", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "actionDetails", Value: "text"}}},
+	}
+	_, err := u.c.Indexes().CreateMany(context.Background(), models)
+	if err != nil {
+		return e.Wrap(err, "mongo.userAction.EnsureIndexes failed")
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package mongo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type userActionArchive struct {
+	db *mongo.Database
+}
+
+// UserActionArchive keeps the user action archives in GridFS, so every
+// instance running the retention job writes to the same place.
+var UserActionArchive = &userActionArchive{}
+
+func (u *userActionArchive) Register(db *mongo.Database) {
+	u.db = db
+}
+
+// Upload stores the file under its base name and returns that name.
+func (u *userActionArchive) Upload(ctx context.Context, path string) (string, error) {
+	bucket, err := gridfs.NewBucket(
+		u.db,
+		options.GridFSBucket().SetName("userActionArchives"),
+	)
+	if err != nil {
+		return "", e.Wrap(err, "UserActionArchiveMongo Upload failed")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", e.Wrap(err, "UserActionArchiveMongo Upload failed")
+	}
+	defer f.Close()
+
+	name := filepath.Base(path)
+	_, err = bucket.UploadFromStream(name, f)
+	if err != nil {
+		return "", e.Wrap(err, "UserActionArchiveMongo Upload failed")
+	}
+	return name, nil
+}
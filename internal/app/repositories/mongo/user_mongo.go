@@ -23,8 +23,7 @@ func (u *user) Register(db *mongo.Database) {
 	u.c = db.Collection("users")
 }
 
-func (u *user) FindByID(id primitive.ObjectID) (*types.User, error) {
-	ctx := context.Background()
+func (u *user) FindByID(ctx context.Context, id primitive.ObjectID) (*types.User, error) {
 	user := types.User{}
 	filter := bson.M{
 		"_id":       id,
@@ -37,7 +36,7 @@ func (u *user) FindByID(id primitive.ObjectID) (*types.User, error) {
 	return &user, nil
 }
 
-func (u *user) FindByEmail(email string) (*types.User, error) {
+func (u *user) FindByEmail(ctx context.Context, email string) (*types.User, error) {
 	email = strings.ToLower(email)
 	if email == "" {
 		return &types.User{}, e.New(e.UserNotFound, "user not found")
@@ -48,20 +47,20 @@ func (u *user) FindByEmail(email string) (*types.User, error) {
 		"email":     email,
 		"deletedAt": bson.M{"$exists": false},
 	}
-	err := u.c.FindOne(context.Background(), filter).Decode(&user)
+	err := u.c.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		return nil, e.New(e.UserNotFound, "user not found")
 	}
 	return &user, nil
 }
 
-func (u *user) FindByBusinessID(id primitive.ObjectID) (*types.User, error) {
+func (u *user) FindByBusinessID(ctx context.Context, id primitive.ObjectID) (*types.User, error) {
 	user := types.User{}
 	filter := bson.M{
 		"companyID": id,
 		"deletedAt": bson.M{"$exists": false},
 	}
-	err := u.c.FindOne(context.Background(), filter).Decode(&user)
+	err := u.c.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		return nil, e.New(e.UserNotFound, "user not found")
 	}
@@ -69,6 +68,7 @@ func (u *user) FindByBusinessID(id primitive.ObjectID) (*types.User, error) {
 }
 
 func (u *user) UpdateTradingInfo(
+	ctx context.Context,
 	id primitive.ObjectID,
 	data *types.TradingRegisterData,
 ) error {
@@ -80,7 +80,7 @@ func (u *user) UpdateTradingInfo(
 		"updatedAt": time.Now(),
 	}}
 	_, err := u.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -91,7 +91,7 @@ func (u *user) UpdateTradingInfo(
 }
 
 // Create creates a user record in the table
-func (u *user) Create(user *types.User) error {
+func (u *user) Create(ctx context.Context, user *types.User) error {
 	user.Email = strings.ToLower(user.Email)
 
 	doc := bson.M{
@@ -103,7 +103,7 @@ func (u *user) Create(user *types.User) error {
 		"companyID": user.CompanyID,
 		"createdAt": time.Now(),
 	}
-	res, err := u.c.InsertOne(context.Background(), doc)
+	res, err := u.c.InsertOne(ctx, doc)
 	if err != nil {
 		return err
 	}
@@ -111,7 +111,7 @@ func (u *user) Create(user *types.User) error {
 	return nil
 }
 
-func (u *user) FindByDailyNotification() ([]*types.User, error) {
+func (u *user) FindByDailyNotification(ctx context.Context) ([]*types.User, error) {
 	filter := bson.M{
 		"dailyNotification": true,
 		"deletedAt":         bson.M{"$exists": false},
@@ -125,13 +125,13 @@ func (u *user) FindByDailyNotification() ([]*types.User, error) {
 	}
 	findOptions := options.Find()
 	findOptions.SetProjection(projection)
-	cur, err := u.c.Find(context.TODO(), filter, findOptions)
+	cur, err := u.c.Find(ctx, filter, findOptions)
 	if err != nil {
 		return nil, e.Wrap(err, "UserMongo FindByDailyNotification failed")
 	}
 
 	var users []*types.User
-	for cur.Next(context.TODO()) {
+	for cur.Next(ctx) {
 		var elem types.User
 		err := cur.Decode(&elem)
 		if err != nil {
@@ -142,12 +142,12 @@ func (u *user) FindByDailyNotification() ([]*types.User, error) {
 	if err := cur.Err(); err != nil {
 		return nil, e.Wrap(err, "UserMongo FindByDailyNotification failed")
 	}
-	cur.Close(context.TODO())
+	cur.Close(ctx)
 
 	return users, nil
 }
 
-func (u *user) FindByIDs(ids []string) ([]*types.User, error) {
+func (u *user) FindByIDs(ctx context.Context, ids []string) ([]*types.User, error) {
 	var results []*types.User
 
 	objectIDs, err := toObjectIDs(ids)
@@ -156,12 +156,12 @@ func (u *user) FindByIDs(ids []string) ([]*types.User, error) {
 	}
 
 	pipeline := newFindByIDsPipeline(objectIDs)
-	cur, err := u.c.Aggregate(context.TODO(), pipeline)
+	cur, err := u.c.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, e.Wrap(err, "find user failed")
 	}
 
-	for cur.Next(context.TODO()) {
+	for cur.Next(ctx) {
 		var elem types.User
 		err := cur.Decode(&elem)
 		if err != nil {
@@ -172,18 +172,42 @@ func (u *user) FindByIDs(ids []string) ([]*types.User, error) {
 	if err := cur.Err(); err != nil {
 		return nil, e.Wrap(err, "find user failed")
 	}
-	cur.Close(context.TODO())
+	cur.Close(ctx)
+
+	return results, nil
+}
+
+// FindAll returns all the users.
+func (u *user) FindAll(ctx context.Context) ([]*types.User, error) {
+	filter := bson.M{"deletedAt": bson.M{"$exists": false}}
+	cur, err := u.c.Find(ctx, filter)
+	if err != nil {
+		return nil, e.Wrap(err, "UserMongo FindAll failed")
+	}
+	defer cur.Close(ctx)
 
+	results := []*types.User{}
+	for cur.Next(ctx) {
+		var elem types.User
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, e.Wrap(err, "UserMongo FindAll failed")
+		}
+		results = append(results, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, e.Wrap(err, "UserMongo FindAll failed")
+	}
 	return results, nil
 }
 
-func (u *user) UpdatePassword(user *types.User) error {
+func (u *user) UpdatePassword(ctx context.Context, user *types.User) error {
 	filter := bson.M{"_id": user.ID}
 	update := bson.M{
 		"$set": bson.M{"password": user.Password, "updatedAt": time.Now()},
 	}
 	_, err := u.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -193,7 +217,7 @@ func (u *user) UpdatePassword(user *types.User) error {
 	return nil
 }
 
-func (u *user) UpdateUserInfo(user *types.User) error {
+func (u *user) UpdateUserInfo(ctx context.Context, user *types.User) error {
 	user.Email = strings.ToLower(user.Email)
 
 	filter := bson.M{"_id": user.ID}
@@ -206,7 +230,7 @@ func (u *user) UpdateUserInfo(user *types.User) error {
 		"updatedAt":         time.Now(),
 	}}
 	_, err := u.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -216,7 +240,7 @@ func (u *user) UpdateUserInfo(user *types.User) error {
 	return nil
 }
 
-func (u *user) AdminUpdateUser(user *types.User) error {
+func (u *user) AdminUpdateUser(ctx context.Context, user *types.User) error {
 	user.Email = strings.ToLower(user.Email)
 
 	filter := bson.M{"_id": user.ID}
@@ -229,7 +253,7 @@ func (u *user) AdminUpdateUser(user *types.User) error {
 		"updatedAt":         time.Now(),
 	}}
 	_, err := u.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -240,6 +264,7 @@ func (u *user) AdminUpdateUser(user *types.User) error {
 }
 
 func (u *user) UpdateLoginAttempts(
+	ctx context.Context,
 	email string,
 	attempts int,
 	lockUser bool,
@@ -254,7 +279,7 @@ func (u *user) UpdateLoginAttempts(
 	}
 	update := bson.M{"$set": set}
 	_, err := u.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -264,7 +289,7 @@ func (u *user) UpdateLoginAttempts(
 	return nil
 }
 
-func (u *user) GetLoginInfo(id primitive.ObjectID) (*types.LoginInfo, error) {
+func (u *user) GetLoginInfo(ctx context.Context, id primitive.ObjectID) (*types.LoginInfo, error) {
 	loginInfo := &types.LoginInfo{}
 	filter := bson.M{"_id": id}
 	projection := bson.M{
@@ -275,7 +300,7 @@ func (u *user) GetLoginInfo(id primitive.ObjectID) (*types.LoginInfo, error) {
 	}
 	findOneOptions := options.FindOne()
 	findOneOptions.SetProjection(projection)
-	err := u.c.FindOne(context.Background(), filter, findOneOptions).
+	err := u.c.FindOne(ctx, filter, findOneOptions).
 		Decode(&loginInfo)
 	if err != nil {
 		return nil, e.Wrap(err, "UserMongo GetLoginInfo failed")
@@ -284,6 +309,7 @@ func (u *user) GetLoginInfo(id primitive.ObjectID) (*types.LoginInfo, error) {
 }
 
 func (u *user) UpdateLoginInfo(
+	ctx context.Context,
 	id primitive.ObjectID,
 	i *types.LoginInfo,
 ) error {
@@ -297,7 +323,7 @@ func (u *user) UpdateLoginInfo(
 		"updatedAt":                time.Now(),
 	}}
 	_, err := u.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -307,14 +333,14 @@ func (u *user) UpdateLoginInfo(
 	return nil
 }
 
-func (u *user) UpdateLastNotificationSentDate(id primitive.ObjectID) error {
+func (u *user) UpdateLastNotificationSentDate(ctx context.Context, id primitive.ObjectID) error {
 	filter := bson.M{"_id": id}
 	update := bson.M{"$set": bson.M{
 		"lastNotificationSentDate": time.Now(),
 		"updatedAt":                time.Now(),
 	}}
 	_, err := u.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -324,14 +350,14 @@ func (u *user) UpdateLastNotificationSentDate(id primitive.ObjectID) error {
 	return nil
 }
 
-func (u *user) DeleteByID(id primitive.ObjectID) error {
+func (u *user) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
 	filter := bson.M{"_id": id}
 	update := bson.M{"$set": bson.M{
 		"deletedAt": time.Now(),
 		"updatedAt": time.Now(),
 	}}
 	_, err := u.c.UpdateOne(
-		context.Background(),
+		ctx,
 		filter,
 		update,
 	)
@@ -343,7 +369,7 @@ func (u *user) DeleteByID(id primitive.ObjectID) error {
 
 // APIs
 
-func (u *user) ToggleShowRecentMatchedTags(id primitive.ObjectID) error {
+func (u *user) ToggleShowRecentMatchedTags(ctx context.Context, id primitive.ObjectID) error {
 	var res struct {
 		ShowRecentMatchedTags bool `bson:"showRecentMatchedTags,omitempty"`
 	}
@@ -352,7 +378,7 @@ func (u *user) ToggleShowRecentMatchedTags(id primitive.ObjectID) error {
 	projection := bson.M{"showRecentMatchedTags": 1}
 	findOneOptions := options.FindOne()
 	findOneOptions.SetProjection(projection)
-	err := u.c.FindOne(context.Background(), filter, findOneOptions).
+	err := u.c.FindOne(ctx, filter, findOneOptions).
 		Decode(&res)
 	if err != nil {
 		return e.Wrap(err, "UserMongo ToggleShowRecentMatchedTags failed")
@@ -362,27 +388,27 @@ func (u *user) ToggleShowRecentMatchedTags(id primitive.ObjectID) error {
 	update := bson.M{
 		"$set": bson.M{"showRecentMatchedTags": !res.ShowRecentMatchedTags},
 	}
-	_, err = u.c.UpdateOne(context.Background(), filter, update)
+	_, err = u.c.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return e.Wrap(err, "UserMongo ToggleShowRecentMatchedTags failed")
 	}
 	return nil
 }
 
-func (u *user) AddToFavoriteBusinesses(uID, bID primitive.ObjectID) error {
+func (u *user) AddToFavoriteBusinesses(ctx context.Context, uID, bID primitive.ObjectID) error {
 	filter := bson.M{"_id": uID}
 	update := bson.M{"$addToSet": bson.M{"favoriteBusinesses": bID}}
-	_, err := u.c.UpdateOne(context.Background(), filter, update)
+	_, err := u.c.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return e.Wrap(err, "UserMongo AddToFavoriteBusinesses failed")
 	}
 	return nil
 }
 
-func (u *user) RemoveFromFavoriteBusinesses(uID, bID primitive.ObjectID) error {
+func (u *user) RemoveFromFavoriteBusinesses(ctx context.Context, uID, bID primitive.ObjectID) error {
 	filter := bson.M{"_id": uID}
 	update := bson.M{"$pull": bson.M{"favoriteBusinesses": bID}}
-	_, err := u.c.UpdateOne(context.Background(), filter, update)
+	_, err := u.c.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return e.Wrap(err, "UserMongo RemoveFromFavoriteBusinesses failed")
 	}
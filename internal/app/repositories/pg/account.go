@@ -1,16 +1,19 @@
 package pg
 
 import (
+	"context"
+
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/jinzhu/gorm"
 )
 
 type account struct{}
 
 var Account = &account{}
 
-func (a *account) Create(bID string) error {
-	tx := db.Begin()
+func (a *account) Create(ctx context.Context, bID string) error {
+	tx := withContext(ctx).Begin()
 
 	account := &types.Account{BusinessID: bID, Balance: 0}
 	err := tx.Create(account).Error
@@ -18,7 +21,7 @@ func (a *account) Create(bID string) error {
 		tx.Rollback()
 		return e.Wrap(err, "pg.Account.Create failed")
 	}
-	err = BalanceLimit.Create(tx, account.ID)
+	err = BalanceLimit.Create(ctx, tx, account.ID)
 	if err != nil {
 		tx.Rollback()
 		return e.Wrap(err, "pg.Account.Create failed")
@@ -27,9 +30,9 @@ func (a *account) Create(bID string) error {
 	return tx.Commit().Error
 }
 
-func (a *account) FindByID(accountID uint) (*types.Account, error) {
+func (a *account) FindByID(ctx context.Context, accountID uint) (*types.Account, error) {
 	var result types.Account
-	err := db.Raw(`
+	err := withContext(ctx).Raw(`
 	SELECT A.id, A.business_id, A.balance
 	FROM accounts AS A
 	WHERE A.id = ?
@@ -41,11 +44,43 @@ func (a *account) FindByID(accountID uint) (*types.Account, error) {
 	return &result, nil
 }
 
-func (a *account) FindByBusinessID(businessID string) (*types.Account, error) {
+func (a *account) FindByBusinessID(ctx context.Context, businessID string) (*types.Account, error) {
 	account := new(types.Account)
-	err := db.Where("business_id = ?", businessID).First(account).Error
+	err := withContext(ctx).Where("business_id = ?", businessID).First(account).Error
 	if err != nil {
 		return nil, e.New(e.UserNotFound, "user not found")
 	}
 	return account, nil
 }
+
+// DeleteByBusinessID deletes the account and its balance limits. It is for
+// undoing the creation of an account, the postings are not deleted.
+func (a *account) DeleteByBusinessID(ctx context.Context, bID string) error {
+	tx := withContext(ctx).Begin()
+
+	account := new(types.Account)
+	err := tx.Where("business_id = ?", bID).First(account).Error
+	if gorm.IsRecordNotFoundError(err) {
+		tx.Rollback()
+		return nil
+	}
+	if err != nil {
+		tx.Rollback()
+		return e.Wrap(err, "pg.Account.DeleteByBusinessID failed")
+	}
+	err = tx.Unscoped().
+		Where("account_id = ?", account.ID).
+		Delete(&types.BalanceLimit{}).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return e.Wrap(err, "pg.Account.DeleteByBusinessID failed")
+	}
+	err = tx.Unscoped().Delete(account).Error
+	if err != nil {
+		tx.Rollback()
+		return e.Wrap(err, "pg.Account.DeleteByBusinessID failed")
+	}
+
+	return tx.Commit().Error
+}
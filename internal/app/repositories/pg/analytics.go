@@ -0,0 +1,176 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+)
+
+type analytics struct{}
+
+var Analytics = &analytics{}
+
+// periodFormats name the periods like mongo.Business.CountNewMembers.
+var periodFormats = map[string]string{
+	"day":   `YYYY-MM-DD`,
+	"week":  `IYYY-"W"IW`,
+	"month": `YYYY-MM`,
+}
+
+// TradesByPeriod counts the completed trades and sums their amounts for
+// each period with trades. A trade has one positive posting.
+func (a *analytics) TradesByPeriod(
+	ctx context.Context,
+	interval string,
+	from time.Time,
+	to time.Time,
+) ([]*types.PeriodCount, error) {
+	format, ok := periodFormats[interval]
+	if !ok {
+		return nil, e.New(e.InvalidInterval, "pg.Analytics.TradesByPeriod failed")
+	}
+	var result []*types.PeriodCount
+	err := withContext(ctx).Raw(`
+	SELECT to_char(P.created_at AT TIME ZONE 'UTC', ?) AS period, COUNT(*) AS count, SUM(P.amount) AS volume
+	FROM postings AS P
+	WHERE P.amount > 0 AND P.created_at BETWEEN ? AND ? AND P.deleted_at IS NULL
+	GROUP BY period
+	ORDER BY period
+	`, format, from, to).Scan(&result).Error
+	if err != nil {
+		return nil, e.Wrap(err, "pg.Analytics.TradesByPeriod failed")
+	}
+	return result, nil
+}
+
+// CountAccounts returns the number of accounts.
+func (a *analytics) CountAccounts(ctx context.Context) (int, error) {
+	var count int
+	err := withContext(ctx).Model(&types.Account{}).Count(&count).Error
+	if err != nil {
+		return 0, e.Wrap(err, "pg.Analytics.CountAccounts failed")
+	}
+	return count, nil
+}
+
+// CountActiveAccounts returns the number of accounts with postings in the
+// date range.
+func (a *analytics) CountActiveAccounts(ctx context.Context, from time.Time, to time.Time) (int, error) {
+	var result struct {
+		Count int
+	}
+	err := withContext(ctx).Raw(`
+	SELECT COUNT(DISTINCT P.account_id) AS count
+	FROM postings AS P
+	WHERE P.created_at BETWEEN ? AND ? AND P.deleted_at IS NULL
+	`, from, to).Scan(&result).Error
+	if err != nil {
+		return 0, e.Wrap(err, "pg.Analytics.CountActiveAccounts failed")
+	}
+	return result.Count, nil
+}
+
+// MoneySupply returns the sum of the positive balances.
+func (a *analytics) MoneySupply(ctx context.Context) (float64, error) {
+	var result struct {
+		Supply float64
+	}
+	err := withContext(ctx).Raw(`
+	SELECT COALESCE(SUM(A.balance), 0) AS supply
+	FROM accounts AS A
+	WHERE A.balance > 0 AND A.deleted_at IS NULL
+	`).Scan(&result).Error
+	if err != nil {
+		return 0, e.Wrap(err, "pg.Analytics.MoneySupply failed")
+	}
+	return result.Supply, nil
+}
+
+// OutstandingCredit returns the sum of the negative balances as a positive number.
+func (a *analytics) OutstandingCredit(ctx context.Context) (float64, error) {
+	var result struct {
+		Credit float64
+	}
+	err := withContext(ctx).Raw(`
+	SELECT COALESCE(-SUM(A.balance), 0) AS credit
+	FROM accounts AS A
+	WHERE A.balance < 0 AND A.deleted_at IS NULL
+	`).Scan(&result).Error
+	if err != nil {
+		return 0, e.Wrap(err, "pg.Analytics.OutstandingCredit failed")
+	}
+	return result.Credit, nil
+}
+
+// Balances returns the balance and limits of every account.
+func (a *analytics) Balances(ctx context.Context) ([]*types.AccountBalance, error) {
+	var result []*types.AccountBalance
+	err := withContext(ctx).Raw(`
+	SELECT A.id AS account_id, A.business_id, A.balance, B.max_neg_bal, B.max_pos_bal
+	FROM accounts AS A
+	JOIN balance_limits AS B ON B.account_id = A.id
+	WHERE A.deleted_at IS NULL AND B.deleted_at IS NULL
+	`).Scan(&result).Error
+	if err != nil {
+		return nil, e.Wrap(err, "pg.Analytics.Balances failed")
+	}
+	return result, nil
+}
+
+// TopTraders returns the accounts with the highest trade volume in the
+// date range. The volume adds what an account sent and received.
+func (a *analytics) TopTraders(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+	limit int,
+) ([]*types.TopTrader, error) {
+	var result []*types.TopTrader
+	err := withContext(ctx).Raw(`
+	SELECT P.account_id, A.business_id, COUNT(*) AS trade_count, SUM(ABS(P.amount)) AS trade_volume
+	FROM postings AS P
+	JOIN accounts AS A ON A.id = P.account_id
+	WHERE P.created_at BETWEEN ? AND ? AND P.deleted_at IS NULL
+	GROUP BY P.account_id, A.business_id
+	ORDER BY trade_volume DESC
+	LIMIT ?
+	`, from, to, limit).Scan(&result).Error
+	if err != nil {
+		return nil, e.Wrap(err, "pg.Analytics.TopTraders failed")
+	}
+	return result, nil
+}
+
+// Activities returns the balance, limits and last posting of every account.
+func (a *analytics) Activities(ctx context.Context) ([]*types.AccountActivity, error) {
+	var result []*types.AccountActivity
+	err := withContext(ctx).Raw(`
+	SELECT A.id AS account_id, A.business_id, A.created_at, A.balance, B.max_neg_bal, B.max_pos_bal,
+		(SELECT MAX(P.created_at) FROM postings AS P WHERE P.account_id = A.id AND P.deleted_at IS NULL) AS last_posting_at
+	FROM accounts AS A
+	JOIN balance_limits AS B ON B.account_id = A.id
+	WHERE A.deleted_at IS NULL AND B.deleted_at IS NULL
+	`).Scan(&result).Error
+	if err != nil {
+		return nil, e.Wrap(err, "pg.Analytics.Activities failed")
+	}
+	return result, nil
+}
+
+// WeeklyNets sums the postings of every account for each of the weeks
+// before the given time.
+func (a *analytics) WeeklyNets(ctx context.Context, to time.Time, weeks int) ([]*types.WeeklyNet, error) {
+	var result []*types.WeeklyNet
+	err := withContext(ctx).Raw(`
+	SELECT P.account_id, FLOOR(EXTRACT(EPOCH FROM (? - P.created_at)) / 604800) AS week, SUM(P.amount) AS net
+	FROM postings AS P
+	WHERE P.created_at > ? AND P.created_at <= ? AND P.deleted_at IS NULL
+	GROUP BY P.account_id, week
+	`, to, to.AddDate(0, 0, -7*weeks), to).Scan(&result).Error
+	if err != nil {
+		return nil, e.Wrap(err, "pg.Analytics.WeeklyNets failed")
+	}
+	return result, nil
+}
@@ -1,23 +1,24 @@
 package pg
 
 import (
+	"context"
 	"math"
 
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
 	"github.com/jinzhu/gorm"
-	"github.com/spf13/viper"
 )
 
 type balanceLimit struct{}
 
 var BalanceLimit = balanceLimit{}
 
-func (b balanceLimit) Create(tx *gorm.DB, accountID uint) error {
+func (b balanceLimit) Create(ctx context.Context, tx *gorm.DB, accountID uint) error {
 	balance := &types.BalanceLimit{
 		AccountID: accountID,
-		MaxNegBal: viper.GetFloat64("transaction.maxNegBal"),
-		MaxPosBal: viper.GetFloat64("transaction.maxPosBal"),
+		MaxNegBal: global.Config().Transaction.MaxNegBal,
+		MaxPosBal: global.Config().Transaction.MaxPosBal,
 	}
 	err := tx.Create(balance).Error
 	if err != nil {
@@ -27,10 +28,11 @@ func (b balanceLimit) Create(tx *gorm.DB, accountID uint) error {
 }
 
 func (b balanceLimit) FindByAccountID(
+	ctx context.Context,
 	accountID uint,
 ) (*types.BalanceLimit, error) {
 	balance := new(types.BalanceLimit)
-	err := db.Where("account_id = ?", accountID).First(balance).Error
+	err := withContext(ctx).Where("account_id = ?", accountID).First(balance).Error
 	if err != nil {
 		return nil, e.Wrap(err, "pg.BalanceLimit.FindByAccountID failed")
 	}
@@ -38,6 +40,7 @@ func (b balanceLimit) FindByAccountID(
 }
 
 func (b balanceLimit) Update(
+	ctx context.Context,
 	id uint,
 	maxPosBal float64,
 	maxNegBal float64,
@@ -48,7 +51,7 @@ func (b balanceLimit) Update(
 		maxNegBal = math.Abs(maxNegBal)
 	}
 
-	err := db.
+	err := withContext(ctx).
 		Model(&types.BalanceLimit{}).
 		Where("account_id = ?", id).
 		Updates(map[string]interface{}{
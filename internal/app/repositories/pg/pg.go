@@ -1,15 +1,19 @@
 package pg
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/metrics"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/postgres"
-	"github.com/spf13/viper"
 )
 
 var db *gorm.DB
@@ -17,7 +21,7 @@ var db *gorm.DB
 func init() {
 	global.Init()
 	// TODO: set up test docker environment.
-	if viper.GetString("env") == "test" {
+	if global.Config().IsTest() {
 		return
 	}
 	db = New()
@@ -41,16 +45,41 @@ func New() *gorm.DB {
 	}
 
 	autoMigrate(db)
+	metrics.RegisterGormCallbacks(db)
+	tracing.RegisterGormCallbacks(db)
 
 	return db
 }
 
+// withContext passes the request context to the gorm callbacks, so the
+// queries are traced as part of the request.
+func withContext(ctx context.Context) *gorm.DB {
+	return db.Set(tracing.GormContextKey, ctx)
+}
+
+// Ping checks the connection to PostgreSQL.
+func Ping(ctx context.Context) error {
+	if db == nil {
+		return errors.New("postgres is not connected")
+	}
+	return db.DB().PingContext(ctx)
+}
+
+// Close closes the connection pool.
+func Close() error {
+	if db == nil {
+		return nil
+	}
+	return db.Close()
+}
+
 func connectionInfo() string {
-	password := viper.GetString("psql.password")
-	host := viper.GetString("psql.host")
-	port := viper.GetString("psql.port")
-	user := viper.GetString("psql.user")
-	dbName := viper.GetString("psql.db")
+	c := global.Config().Psql
+	password := c.Password
+	host := c.Host
+	port := strconv.Itoa(c.Port)
+	user := c.User
+	dbName := c.DB
 
 	if password == "" {
 		return fmt.Sprintf("host=%s port=%s user=%s dbname=%s "+
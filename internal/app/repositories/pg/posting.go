@@ -1,6 +1,7 @@
 package pg
 
 import (
+	"context"
 	"time"
 
 	"github.com/ic3network/mccs-alpha/internal/app/types"
@@ -12,11 +13,12 @@ type posting struct{}
 var Posting = &posting{}
 
 func (t *posting) FindInRange(
+	ctx context.Context,
 	from time.Time,
 	to time.Time,
 ) ([]*types.Posting, error) {
 	var result []*types.Posting
-	err := db.Raw(`
+	err := withContext(ctx).Raw(`
 	SELECT P.amount, P.created_at
 	FROM postings AS P
 	WHERE P.created_at BETWEEN ? AND ?
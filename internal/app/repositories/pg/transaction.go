@@ -1,23 +1,25 @@
 package pg
 
 import (
+	"context"
 	"time"
 
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/global/constant"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
 	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
 	"github.com/jinzhu/gorm"
 	"github.com/segmentio/ksuid"
-	"github.com/spf13/viper"
 )
 
 type transaction struct{}
 
 var Transaction = &transaction{}
 
-// Create makes a transaction directly.
+// Create makes a transaction directly and returns its transaction ID.
 func (t *transaction) Create(
+	ctx context.Context,
 	fromID uint,
 	fromEmail string,
 	fromBusinessName string,
@@ -28,8 +30,8 @@ func (t *transaction) Create(
 
 	amount float64,
 	desc string,
-) error {
-	tx := db.Begin()
+) (string, error) {
+	tx := withContext(ctx).Begin()
 
 	journalRecord := &types.Journal{
 		TransactionID:    ksuid.New().String(),
@@ -47,7 +49,7 @@ func (t *transaction) Create(
 	err := tx.Create(journalRecord).Error
 	if err != nil {
 		tx.Rollback()
-		return e.Wrap(err, "pg.Transaction.Create")
+		return "", e.Wrap(err, "pg.Transaction.Create")
 	}
 
 	journalID := journalRecord.ID
@@ -62,14 +64,14 @@ func (t *transaction) Create(
 	).Error
 	if err != nil {
 		tx.Rollback()
-		return e.Wrap(err, "pg.Transaction.Create")
+		return "", e.Wrap(err, "pg.Transaction.Create")
 	}
 	err = tx.Create(
 		&types.Posting{AccountID: toID, JournalID: journalID, Amount: amount},
 	).Error
 	if err != nil {
 		tx.Rollback()
-		return e.Wrap(err, "pg.Transaction.Create")
+		return "", e.Wrap(err, "pg.Transaction.Create")
 	}
 
 	// Update accounts' balance.
@@ -79,7 +81,7 @@ func (t *transaction) Create(
 		Error
 	if err != nil {
 		tx.Rollback()
-		return e.Wrap(err, "pg.Transaction.Create")
+		return "", e.Wrap(err, "pg.Transaction.Create")
 	}
 	err = tx.Model(&types.Account{}).
 		Where("id = ?", toID).
@@ -87,7 +89,69 @@ func (t *transaction) Create(
 		Error
 	if err != nil {
 		tx.Rollback()
-		return e.Wrap(err, "pg.Transaction.Create")
+		return "", e.Wrap(err, "pg.Transaction.Create")
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return "", e.Wrap(err, "pg.Transaction.Create")
+	}
+	return journalRecord.TransactionID, nil
+}
+
+// ImportedIDs returns the transaction IDs of the journals sent from the
+// accounts, so an import can skip the ones it stored already.
+func (t *transaction) ImportedIDs(ctx context.Context, accountIDs []uint) (map[string]bool, error) {
+	var ids []string
+	err := withContext(ctx).Model(&types.Journal{}).
+		Where("from_id IN (?)", accountIDs).
+		Pluck("transaction_id", &ids).
+		Error
+	if err != nil {
+		return nil, e.Wrap(err, "pg.Transaction.ImportedIDs")
+	}
+	imported := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		imported[id] = true
+	}
+	return imported, nil
+}
+
+// Import stores a journal with its own dates, e.g. a generated history.
+// A completed journal gets its postings at UpdatedAt and moves the balances,
+// like Accept.
+func (t *transaction) Import(ctx context.Context, j *types.Journal) error {
+	tx := withContext(ctx).Begin()
+
+	err := tx.Create(j).Error
+	if err != nil {
+		tx.Rollback()
+		return e.Wrap(err, "pg.Transaction.Import")
+	}
+	if j.Status != constant.Transaction.Completed {
+		return tx.Commit().Error
+	}
+
+	postings := []*types.Posting{
+		{AccountID: j.FromID, JournalID: j.ID, Amount: -j.Amount},
+		{AccountID: j.ToID, JournalID: j.ID, Amount: j.Amount},
+	}
+	for _, p := range postings {
+		p.CreatedAt = j.UpdatedAt
+		p.UpdatedAt = j.UpdatedAt
+		err = tx.Create(p).Error
+		if err != nil {
+			tx.Rollback()
+			return e.Wrap(err, "pg.Transaction.Import")
+		}
+		err = tx.Model(&types.Account{}).
+			Where("id = ?", p.AccountID).
+			Update("balance", gorm.Expr("balance + ?", p.Amount)).
+			Error
+		if err != nil {
+			tx.Rollback()
+			return e.Wrap(err, "pg.Transaction.Import")
+		}
 	}
 
 	return tx.Commit().Error
@@ -95,6 +159,7 @@ func (t *transaction) Create(
 
 // Propose proposes a transaction.
 func (t *transaction) Propose(
+	ctx context.Context,
 	initiatedBy uint,
 
 	fromID uint,
@@ -122,7 +187,7 @@ func (t *transaction) Propose(
 		Type:             constant.Journal.Transfer,
 		Status:           constant.Transaction.Initiated,
 	}
-	err := db.Create(journalRecord).Error
+	err := withContext(ctx).Create(journalRecord).Error
 	if err != nil {
 		return nil, e.Wrap(err, "pg.Transaction.Create failed")
 	}
@@ -142,9 +207,9 @@ func (t *transaction) Propose(
 }
 
 // Find finds a transaction.
-func (t *transaction) Find(transactionID uint) (*types.Transaction, error) {
+func (t *transaction) Find(ctx context.Context, transactionID uint) (*types.Transaction, error) {
 	var result types.Transaction
-	err := db.Raw(`
+	err := withContext(ctx).Raw(`
 	SELECT
 		J.id, J.transaction_id, J.initiated_by, J.from_id, J.from_email, J.from_business_name,
 		J.to_id, J.to_email, J.to_business_name, J.amount, J.status
@@ -160,8 +225,8 @@ func (t *transaction) Find(transactionID uint) (*types.Transaction, error) {
 }
 
 // Cancel cancels a transaction.
-func (t *transaction) Cancel(transactionID uint, reason string) error {
-	err := db.Exec(`
+func (t *transaction) Cancel(ctx context.Context, transactionID uint, reason string) error {
+	err := withContext(ctx).Exec(`
 	UPDATE journals
 	SET status=?, cancellation_reason = ?, updated_at=?
 	WHERE id=?
@@ -175,12 +240,13 @@ func (t *transaction) Cancel(transactionID uint, reason string) error {
 
 // Accept accepts a transaction.
 func (t *transaction) Accept(
+	ctx context.Context,
 	transactionID uint,
 	fromID uint,
 	toID uint,
 	amount float64,
 ) error {
-	tx := db.Begin()
+	tx := withContext(ctx).Begin()
 
 	// Create postings.
 	err := tx.Create(&types.Posting{
@@ -235,9 +301,9 @@ func (t *transaction) Accept(
 }
 
 // FindPendings finds the pending transactions.
-func (t *transaction) FindPendings(id uint) ([]*types.Transaction, error) {
+func (t *transaction) FindPendings(ctx context.Context, id uint) ([]*types.Transaction, error) {
 	var result []*types.Transaction
-	err := db.Raw(`
+	err := withContext(ctx).Raw(`
 	SELECT
 		J.id, J.transaction_id, CAST((CASE WHEN J.initiated_by = ? THEN 1 ELSE 0 END) AS BIT) AS "is_initiator",
 		J.id, J.initiated_by, J.from_id, J.from_email, J.to_id, J.from_business_name, J.to_business_name,
@@ -254,9 +320,9 @@ func (t *transaction) FindPendings(id uint) ([]*types.Transaction, error) {
 }
 
 // FindRecent finds the recent 3 completed transactions.
-func (t *transaction) FindRecent(id uint) ([]*types.Transaction, error) {
+func (t *transaction) FindRecent(ctx context.Context, id uint) ([]*types.Transaction, error) {
 	var result []*types.Transaction
-	err := db.Raw(`
+	err := withContext(ctx).Raw(`
 	SELECT J.transaction_id, J.from_email, J.to_email, J.from_business_name, J.to_business_name, J.description, P.amount, P.created_at
 	FROM postings AS P
 	INNER JOIN journals AS J ON J."id" = P."journal_id"
@@ -273,13 +339,14 @@ func (t *transaction) FindRecent(id uint) ([]*types.Transaction, error) {
 
 // FindInRange finds the completed transactions in specific time range.
 func (t *transaction) FindInRange(
+	ctx context.Context,
 	id uint,
 	dateFrom time.Time,
 	dateTo time.Time,
 	page int,
 ) ([]*types.Transaction, int, error) {
-	limit := viper.GetInt("page_size")
-	offset := viper.GetInt("page_size") * (page - 1)
+	limit := global.Config().PageSize
+	offset := global.Config().PageSize * (page - 1)
 
 	if dateFrom.IsZero() {
 		dateFrom = constant.Date.DefaultFrom
@@ -292,7 +359,7 @@ func (t *transaction) FindInRange(
 	dateTo = dateTo.Add(24 * time.Hour)
 
 	var result []*types.Transaction
-	err := db.Raw(`
+	err := withContext(ctx).Raw(`
 	SELECT J.transaction_id, J.from_email, J.to_email, J.from_business_name, J.to_business_name, J.description, P.amount, P.created_at
 	FROM postings AS P
 	INNER JOIN journals AS J ON J."id" = P."journal_id"
@@ -302,10 +369,10 @@ func (t *transaction) FindInRange(
 	`, id, dateFrom, dateTo, limit, offset).Scan(&result).Error
 
 	var numberOfResults int64
-	db.Model(&types.Posting{}).
+	withContext(ctx).Model(&types.Posting{}).
 		Where("account_id = ? AND (created_at BETWEEN ? AND ?)", id, dateFrom, dateTo).
 		Count(&numberOfResults)
-	totalPages := pagination.Pages(numberOfResults, viper.GetInt64("page_size"))
+	totalPages := pagination.Pages(numberOfResults, int64(global.Config().PageSize))
 
 	if err != nil {
 		return nil, 0, e.Wrap(err, "pg.Transaction.Find failed")
@@ -0,0 +1,188 @@
+package search
+
+import (
+	"context"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/es"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// The in-process backend mirrors the Elasticsearch queries in es/helper.go.
+// A field matches a text when one of its terms is within the fuzzy edit
+// distance of a term of the text, starts with the text or contains it.
+// Each of the three adds its boost to the score.
+
+var (
+	// Boosts of newFuzzyWildcardQuery.
+	fieldBoosts = [3]float64{3, 2, 1}
+	// Boosts of newFuzzyWildcardTimeQueryForTag.
+	tagBoosts = [3]float64{2, 1.5, 1}
+	// newTagQuery doesn't boost.
+	matchBoosts = [3]float64{1, 1, 1}
+)
+
+// textTerms splits a text field like the standard analyzer.
+func textTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// tagTerms splits a tag field like the tag_analyzer.
+func tagTerms(text string) []string {
+	return strings.Fields(strings.ToLower(text))
+}
+
+// score returns zero when the text matches none of the field terms.
+func score(
+	fieldTerms []string,
+	queryTerms []string,
+	text string,
+	boosts [3]float64,
+) float64 {
+	text = strings.ToLower(text)
+	var fuzzyMatched, prefixMatched, containsMatched bool
+	for _, t := range fieldTerms {
+		for _, q := range queryTerms {
+			if fuzzy(q, t) {
+				fuzzyMatched = true
+			}
+		}
+		if strings.HasPrefix(t, text) {
+			prefixMatched = true
+		}
+		if strings.Contains(t, text) {
+			containsMatched = true
+		}
+	}
+
+	s := 0.0
+	if fuzzyMatched {
+		s += boosts[0]
+	}
+	if prefixMatched {
+		s += boosts[1]
+	}
+	if containsMatched {
+		s += boosts[2]
+	}
+	return s
+}
+
+// matchTerm is a match query without fuzziness.
+func matchTerm(fieldTerms []string, queryTerms []string) bool {
+	for _, t := range fieldTerms {
+		for _, q := range queryTerms {
+			if t == q {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fuzzy uses the "auto" fuzziness of Elasticsearch: terms up to two
+// characters must match exactly, up to five may have one edit and longer
+// ones two edits.
+func fuzzy(query, term string) bool {
+	q, t := []rune(query), []rune(term)
+	maxEdits := 2
+	if len(q) <= 2 {
+		maxEdits = 0
+	} else if len(q) <= 5 {
+		maxEdits = 1
+	}
+	if abs(len(q)-len(t)) > maxEdits {
+		return false
+	}
+	return editDistance(q, t) <= maxEdits
+}
+
+// editDistance counts insertions, deletions, substitutions and
+// transpositions of adjacent characters.
+func editDistance(a, b []rune) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func min(first int, rest ...int) int {
+	m := first
+	for _, n := range rest {
+		if n < m {
+			m = n
+		}
+	}
+	return m
+}
+
+// load fills the in-process indexes from MongoDB.
+func load() {
+	if global.Config().IsTest() {
+		return
+	}
+
+	ctx := context.Background()
+	// Don't incluse deleted item.
+	filter := bson.M{
+		"deletedAt": bson.M{"$exists": false},
+	}
+	for _, collection := range es.Indexes() {
+		cur, err := mongo.DB().Collection(collection).Find(ctx, filter)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for cur.Next(ctx) {
+			_, record, err := es.NewRecord(collection, cur.Current)
+			if err != nil {
+				log.Fatal(err)
+			}
+			switch r := record.(type) {
+			case *types.BusinessESRecord:
+				err = Business.Index(ctx, r)
+			case *types.UserESRecord:
+				err = User.Index(ctx, r)
+			case *types.TagESRecord:
+				err = Tag.Index(ctx, r)
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		if err := cur.Err(); err != nil {
+			log.Fatal(err)
+		}
+		cur.Close(ctx)
+	}
+}
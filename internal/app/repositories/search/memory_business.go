@@ -0,0 +1,522 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
+)
+
+type memoryBusiness struct {
+	mu      sync.RWMutex
+	records map[string]*types.BusinessESRecord
+}
+
+func newMemoryBusiness() *memoryBusiness {
+	return &memoryBusiness{
+		records: map[string]*types.BusinessESRecord{},
+	}
+}
+
+func (m *memoryBusiness) Index(ctx context.Context, r *types.BusinessESRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *r
+	copied.Offers = copyTags(r.Offers)
+	copied.Wants = copyTags(r.Wants)
+	copied.AdminTags = append([]string{}, r.AdminTags...)
+	m.records[r.BusinessID] = &copied
+	return nil
+}
+
+func (m *memoryBusiness) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, id)
+	return nil
+}
+
+// memoryFacet follows the facets of es.Business.Find.
+type memoryFacet struct {
+	values   func(r *types.BusinessESRecord) []string
+	selected func(c *types.SearchCriteria) []string
+	counts   func(f *types.BusinessFacets, counts []*types.FacetCount)
+}
+
+var memoryFacets = []*memoryFacet{
+	{
+		values:   func(r *types.BusinessESRecord) []string { return r.AdminTags },
+		selected: func(c *types.SearchCriteria) []string { return c.AdminTags },
+		counts:   func(f *types.BusinessFacets, counts []*types.FacetCount) { f.AdminTags = counts },
+	},
+	{
+		values:   func(r *types.BusinessESRecord) []string { return []string{r.LocationCountry} },
+		selected: func(c *types.SearchCriteria) []string { return c.LocationCountries },
+		counts:   func(f *types.BusinessFacets, counts []*types.FacetCount) { f.LocationCountries = counts },
+	},
+	{
+		values:   func(r *types.BusinessESRecord) []string { return []string{r.LocationCity} },
+		selected: func(c *types.SearchCriteria) []string { return c.LocationCities },
+		counts:   func(f *types.BusinessFacets, counts []*types.FacetCount) { f.LocationCities = counts },
+	},
+	{
+		values:   func(r *types.BusinessESRecord) []string { return []string{r.Status} },
+		selected: func(c *types.SearchCriteria) []string { return c.FacetStatuses },
+		counts:   func(f *types.BusinessFacets, counts []*types.FacetCount) { f.Statuses = counts },
+	},
+}
+
+// match reports whether the record has one of the selected values.
+func (f *memoryFacet) match(r *types.BusinessESRecord, c *types.SearchCriteria) bool {
+	selected := f.selected(c)
+	if len(selected) == 0 {
+		return true
+	}
+	for _, value := range f.values(r) {
+		for _, s := range selected {
+			if value == s {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Find follows es.Business.Find. The businesses are ranked by score and
+// trading members come first among equal matches.
+func (m *memoryBusiness) Find(
+	ctx context.Context,
+	c *types.SearchCriteria,
+	page int64,
+) (*types.SearchBusinessResult, error) {
+	if page < 0 || page == 0 {
+		return nil, e.New(e.InvalidPageNumber, "find business failed")
+	}
+
+	m.mu.RLock()
+	type hit struct {
+		id    string
+		score float64
+	}
+	hits := make([]hit, 0, len(m.records))
+	counts := make([]map[string]int, len(memoryFacets))
+	for i := range counts {
+		counts[i] = map[string]int{}
+	}
+	for id, r := range m.records {
+		s, ok := matchBusiness(r, c)
+		if !ok {
+			continue
+		}
+		matched := make([]bool, len(memoryFacets))
+		all := true
+		for i, f := range memoryFacets {
+			matched[i] = f.match(r, c)
+			all = all && matched[i]
+		}
+		if all {
+			hits = append(hits, hit{id, s})
+		}
+		// A facet is counted with the selection of the other facets only.
+		for i, f := range memoryFacets {
+			others := true
+			for j := range memoryFacets {
+				if j != i && !matched[j] {
+					others = false
+					break
+				}
+			}
+			if !others {
+				continue
+			}
+			for _, value := range f.values(r) {
+				if value != "" {
+					counts[i][value]++
+				}
+			}
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].id < hits[j].id
+	})
+
+	size := global.Config().PageSize
+	from := size * (int(page) - 1)
+	ids := []string{}
+	for i := from; i < len(hits) && i < from+size; i++ {
+		ids = append(ids, hits[i].id)
+	}
+
+	numberOfResults := int64(len(hits))
+	totalPages := pagination.Pages(numberOfResults, int64(global.Config().PageSize))
+
+	facets := &types.BusinessFacets{}
+	for i, f := range memoryFacets {
+		f.counts(facets, facetCounts(counts[i], f.selected(c), global.Config().FacetSize))
+	}
+
+	return &types.SearchBusinessResult{
+		IDs:             ids,
+		NumberOfResults: int(numberOfResults),
+		TotalPages:      totalPages,
+		Facets:          facets,
+	}, nil
+}
+
+// facetCounts orders the values like a terms aggregation.
+func facetCounts(counts map[string]int, selected []string, size int) []*types.FacetCount {
+	result := make([]*types.FacetCount, 0, len(counts))
+	for value, count := range counts {
+		fc := &types.FacetCount{Value: value, Count: count}
+		for _, s := range selected {
+			if s == value {
+				fc.Selected = true
+			}
+		}
+		result = append(result, fc)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Value < result[j].Value
+	})
+	if len(result) > size {
+		result = result[:size]
+	}
+	return result
+}
+
+func matchBusiness(r *types.BusinessESRecord, c *types.SearchCriteria) (float64, bool) {
+	s := 0.0
+	hasMust := false
+	isTrading := matchTerm(textTerms(r.Status), textTerms(constant.Trading.Accepted))
+	if isTrading {
+		s++
+	}
+
+	if c.ShowUserFavoritesOnly {
+		hasMust = true
+		found := false
+		for _, id := range c.FavoriteBusinesses {
+			if id.Hex() == r.BusinessID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, false
+		}
+	}
+
+	if len(c.Statuses) != 0 {
+		hasMust = true
+		found := false
+		for _, status := range c.Statuses {
+			if matchTerm(textTerms(r.Status), textTerms(status)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, false
+		}
+	}
+
+	if c.BusinessName != "" {
+		hasMust = true
+		ss := score(textTerms(r.BusinessName), textTerms(c.BusinessName), c.BusinessName, fieldBoosts)
+		if ss == 0 {
+			return 0, false
+		}
+		s += ss
+	}
+	if c.LocationCountry != "" {
+		hasMust = true
+		if !matchTerm(textTerms(r.LocationCountry), textTerms(c.LocationCountry)) {
+			return 0, false
+		}
+	}
+	if c.LocationCity != "" {
+		hasMust = true
+		ss := score(textTerms(r.LocationCity), textTerms(c.LocationCity), c.LocationCity, fieldBoosts)
+		if ss == 0 {
+			return 0, false
+		}
+		s += ss
+	}
+
+	if c.AdminTag != "" {
+		hasMust = true
+		adminTerms := []string{}
+		for _, t := range r.AdminTags {
+			adminTerms = append(adminTerms, tagTerms(t)...)
+		}
+		if !matchTerm(adminTerms, tagTerms(c.AdminTag)) {
+			return 0, false
+		}
+	}
+
+	// "Tag Added After" will associate with "tags".
+	if (c.TagType == constant.OFFERS || c.TagType == constant.WANTS) &&
+		len(c.Tags) != 0 {
+		hasMust = true
+		tags := r.Offers
+		if c.TagType == constant.WANTS {
+			tags = r.Wants
+		}
+		ss := matchTags(tags, c)
+		if ss == 0 {
+			return 0, false
+		}
+		s += ss
+	}
+
+	// Without any required clause, the "tradingAccepted" one becomes
+	// required, as in Elasticsearch.
+	if !hasMust && !isTrading {
+		return 0, false
+	}
+	return s, true
+}
+
+// matchTags weights the searched tags so the earlier ones rank higher.
+func matchTags(tags []*types.TagField, c *types.SearchCriteria) float64 {
+	s := 0.0
+	weighted := 2.0
+	for _, searched := range c.Tags {
+		best := 0.0
+		for _, t := range tags {
+			if t.CreatedAt.Before(c.CreatedOnOrAfter) {
+				continue
+			}
+			ss := score(tagTerms(t.Name), tagTerms(searched.Name), searched.Name, tagBoosts)
+			if ss > best {
+				best = ss
+			}
+		}
+		s += best * weighted
+		weighted *= 0.9
+	}
+	return s
+}
+
+// FindSimilar follows es.Business.FindSimilar.
+func (m *memoryBusiness) FindSimilar(
+	ctx context.Context,
+	c *types.SimilarCriteria,
+	size int,
+) ([]string, error) {
+	excluded := map[string]bool{}
+	for _, id := range c.ExcludeIDs {
+		excluded[id] = true
+	}
+
+	m.mu.RLock()
+	type hit struct {
+		id    string
+		score int
+	}
+	hits := []hit{}
+	for id, r := range m.records {
+		if excluded[id] ||
+			!matchTerm(textTerms(r.Status), textTerms(constant.Trading.Accepted)) {
+			continue
+		}
+		s := sharedTags(r.Offers, c.Offers) + sharedTags(r.Wants, c.Wants)
+		if s != 0 {
+			hits = append(hits, hit{id, s})
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].id < hits[j].id
+	})
+	ids := []string{}
+	for i := 0; i < len(hits) && i < size; i++ {
+		ids = append(ids, hits[i].id)
+	}
+	return ids, nil
+}
+
+func sharedTags(tags []*types.TagField, names []string) int {
+	shared := 0
+	for _, t := range tags {
+		for _, name := range names {
+			if t.Name == name {
+				shared++
+				break
+			}
+		}
+	}
+	return shared
+}
+
+// SuggestTags follows es.Business.SuggestTags.
+func (m *memoryBusiness) SuggestTags(
+	ctx context.Context,
+	tagType string,
+	prefix string,
+	size int,
+) ([]*types.TagSuggestion, error) {
+	m.mu.RLock()
+	found := map[string]*types.TagSuggestion{}
+	for _, r := range m.records {
+		if !matchTerm(textTerms(r.Status), textTerms(constant.Trading.Accepted)) {
+			continue
+		}
+		tags := r.Offers
+		if tagType == constant.WANTS {
+			tags = r.Wants
+		}
+		for _, t := range tags {
+			if !suggestTag(t.Name, prefix) {
+				continue
+			}
+			suggestion, ok := found[t.Name]
+			if !ok {
+				suggestion = &types.TagSuggestion{Name: t.Name}
+				found[t.Name] = suggestion
+			}
+			suggestion.Count++
+			if t.CreatedAt.After(suggestion.LastAddedAt) {
+				suggestion.LastAddedAt = t.CreatedAt
+			}
+		}
+	}
+	m.mu.RUnlock()
+
+	suggestions := make([]*types.TagSuggestion, 0, len(found))
+	for _, suggestion := range found {
+		suggestions = append(suggestions, suggestion)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Count != suggestions[j].Count {
+			return suggestions[i].Count > suggestions[j].Count
+		}
+		if !suggestions[i].LastAddedAt.Equal(suggestions[j].LastAddedAt) {
+			return suggestions[i].LastAddedAt.After(suggestions[j].LastAddedAt)
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	if len(suggestions) > size {
+		suggestions = suggestions[:size]
+	}
+	return suggestions, nil
+}
+
+// suggestTag reports whether the prefix starts the tag or every part of
+// the prefix starts a part of the tag, like the tag_suggest_analyzer.
+func suggestTag(name string, prefix string) bool {
+	name = strings.ToLower(name)
+	prefix = strings.ToLower(prefix)
+	if strings.HasPrefix(name, prefix) {
+		return true
+	}
+	split := func(r rune) bool {
+		return r == '-' || r == ' '
+	}
+	parts := strings.FieldsFunc(name, split)
+	prefixParts := strings.FieldsFunc(prefix, split)
+	if len(prefixParts) == 0 {
+		return false
+	}
+	for _, p := range prefixParts {
+		matched := false
+		for _, part := range parts {
+			if strings.HasPrefix(part, p) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *memoryBusiness) RenameTag(ctx context.Context, old string, new string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.records {
+		for _, t := range append(append([]*types.TagField{}, r.Offers...), r.Wants...) {
+			if t.Name == old {
+				t.Name = new
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memoryBusiness) RenameAdminTag(ctx context.Context, old string, new string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.records {
+		for i, t := range r.AdminTags {
+			if t == old {
+				r.AdminTags[i] = new
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memoryBusiness) DeleteTag(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.records {
+		r.Offers = removeTag(r.Offers, name)
+		r.Wants = removeTag(r.Wants, name)
+	}
+	return nil
+}
+
+func (m *memoryBusiness) DeleteAdminTags(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.records {
+		adminTags := make([]string, 0, len(r.AdminTags))
+		for _, t := range r.AdminTags {
+			if t != name {
+				adminTags = append(adminTags, t)
+			}
+		}
+		r.AdminTags = adminTags
+	}
+	return nil
+}
+
+func removeTag(tags []*types.TagField, name string) []*types.TagField {
+	kept := make([]*types.TagField, 0, len(tags))
+	for _, t := range tags {
+		if t.Name != name {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+func copyTags(tags []*types.TagField) []*types.TagField {
+	copied := make([]*types.TagField, 0, len(tags))
+	for _, t := range tags {
+		tag := *t
+		copied = append(copied, &tag)
+	}
+	return copied
+}
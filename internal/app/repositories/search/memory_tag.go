@@ -0,0 +1,100 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+)
+
+// matchSize is the default number of hits Elasticsearch returns.
+const matchSize = 10
+
+type memoryTag struct {
+	mu      sync.RWMutex
+	records map[string]*types.TagESRecord
+}
+
+func newMemoryTag() *memoryTag {
+	return &memoryTag{
+		records: map[string]*types.TagESRecord{},
+	}
+}
+
+func (m *memoryTag) Index(ctx context.Context, r *types.TagESRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *r
+	m.records[r.TagID] = &copied
+	return nil
+}
+
+func (m *memoryTag) DeleteByID(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, id)
+	return nil
+}
+
+// MatchOffer matches wants for the given offer.
+func (m *memoryTag) MatchOffer(
+	ctx context.Context,
+	offer string,
+	lastLoginDate time.Time,
+) ([]string, error) {
+	return m.match(offer, lastLoginDate, func(r *types.TagESRecord) time.Time {
+		return r.WantAddedAt
+	}), nil
+}
+
+// MatchWant matches offers for the given want.
+func (m *memoryTag) MatchWant(
+	ctx context.Context,
+	want string,
+	lastLoginDate time.Time,
+) ([]string, error) {
+	return m.match(want, lastLoginDate, func(r *types.TagESRecord) time.Time {
+		return r.OfferAddedAt
+	}), nil
+}
+
+// match follows newTagQuery.
+func (m *memoryTag) match(
+	tag string,
+	lastLoginDate time.Time,
+	addedAt func(r *types.TagESRecord) time.Time,
+) []string {
+	m.mu.RLock()
+	type hit struct {
+		name  string
+		score float64
+	}
+	hits := []hit{}
+	for _, r := range m.records {
+		t := addedAt(r)
+		// Tags never added as an offer/want have the zero time.
+		if !t.After(time.Time{}) || t.Before(lastLoginDate) {
+			continue
+		}
+		s := score(tagTerms(r.Name), tagTerms(tag), tag, matchBoosts)
+		if s != 0 {
+			hits = append(hits, hit{r.Name, s})
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].name < hits[j].name
+	})
+
+	matchTags := make([]string, 0, 8)
+	for i := 0; i < len(hits) && i < matchSize; i++ {
+		matchTags = append(matchTags, hits[i].name)
+	}
+	return matchTags
+}
@@ -0,0 +1,380 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFuzzy(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		term     string
+		expected bool
+	}{
+		{"should match the same term", "ox", "ox", true},
+		{"should not allow edits for short terms", "ox", "ax", false},
+		{"should allow one edit for medium terms", "bread", "braed", true},
+		{"should not allow two edits for medium terms", "bread", "brxxd", false},
+		{"should allow two edits for long terms", "plumbing", "plumbnig", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, fuzzy(tt.query, tt.term))
+		})
+	}
+}
+
+func TestMemoryBusinessFind(t *testing.T) {
+	now := time.Now()
+	b := newMemoryBusiness()
+	records := []*types.BusinessESRecord{
+		{
+			BusinessID:      "1",
+			BusinessName:    "Green Bakery",
+			Status:          constant.Trading.Accepted,
+			LocationCountry: "Scotland",
+			Offers:          []*types.TagField{{Name: "bread", CreatedAt: now}},
+		},
+		{
+			BusinessID:      "2",
+			BusinessName:    "Blue Plumbing",
+			Status:          constant.Business.Accepted,
+			LocationCountry: "Wales",
+			Offers:          []*types.TagField{{Name: "plumbing", CreatedAt: now}},
+			AdminTags:       []string{"Cardiff"},
+		},
+		{
+			BusinessID:   "3",
+			BusinessName: "Pending Bakery",
+			Status:       constant.Business.Pending,
+			Offers:       []*types.TagField{{Name: "bread", CreatedAt: now.AddDate(0, -1, 0)}},
+		},
+	}
+	for _, r := range records {
+		assert.NoError(t, b.Index(context.Background(), r))
+	}
+	accepted := []string{
+		constant.Business.Accepted,
+		constant.Trading.Accepted,
+	}
+
+	tests := []struct {
+		name     string
+		criteria *types.SearchCriteria
+		expected []string
+	}{
+		{
+			"should only show trading members without criteria",
+			&types.SearchCriteria{},
+			[]string{"1"},
+		},
+		{
+			"should filter by status",
+			&types.SearchCriteria{Statuses: accepted},
+			[]string{"1", "2"},
+		},
+		{
+			"should match business names with a typo",
+			&types.SearchCriteria{Statuses: accepted, BusinessName: "bakry"},
+			[]string{"1"},
+		},
+		{
+			"should match business names by prefix",
+			&types.SearchCriteria{Statuses: accepted, BusinessName: "plum"},
+			[]string{"2"},
+		},
+		{
+			"should match the country",
+			&types.SearchCriteria{Statuses: accepted, LocationCountry: "wales"},
+			[]string{"2"},
+		},
+		{
+			"should match the admin tag",
+			&types.SearchCriteria{AdminTag: "cardiff"},
+			[]string{"2"},
+		},
+		{
+			"should match offers added after the date",
+			&types.SearchCriteria{
+				TagType:          constant.OFFERS,
+				Tags:             []*types.TagField{{Name: "bread"}},
+				CreatedOnOrAfter: now.AddDate(0, 0, -7),
+			},
+			[]string{"1"},
+		},
+		{
+			"should only show favorites",
+			&types.SearchCriteria{
+				Statuses:              accepted,
+				ShowUserFavoritesOnly: true,
+				FavoriteBusinesses:    []primitive.ObjectID{},
+			},
+			[]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := b.Find(context.Background(), tt.criteria, 1)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, result.IDs)
+			assert.Equal(t, len(tt.expected), result.NumberOfResults)
+		})
+	}
+}
+
+func TestMemoryBusinessRenameTag(t *testing.T) {
+	b := newMemoryBusiness()
+	r := &types.BusinessESRecord{
+		BusinessID: "1",
+		Status:     constant.Trading.Accepted,
+		Offers:     []*types.TagField{{Name: "bread"}},
+		Wants:      []*types.TagField{{Name: "flour"}},
+	}
+	assert.NoError(t, b.Index(context.Background(), r))
+
+	assert.NoError(t, b.RenameTag(context.Background(), "bread", "sourdough"))
+	assert.NoError(t, b.DeleteTag(context.Background(), "flour"))
+
+	assert.Equal(t, "sourdough", b.records["1"].Offers[0].Name)
+	assert.Empty(t, b.records["1"].Wants)
+	// The indexed record is a copy.
+	assert.Equal(t, "bread", r.Offers[0].Name)
+}
+
+func TestMemoryTagMatch(t *testing.T) {
+	lastLogin := time.Now().AddDate(0, 0, -1)
+	tag := newMemoryTag()
+	records := []*types.TagESRecord{
+		{TagID: "1", Name: "bread", WantAddedAt: time.Now()},
+		{TagID: "2", Name: "breadcrumbs", WantAddedAt: time.Now()},
+		{TagID: "3", Name: "bread-rolls", WantAddedAt: lastLogin.AddDate(0, 0, -1)},
+		{TagID: "4", Name: "bred", OfferAddedAt: time.Now()},
+	}
+	for _, r := range records {
+		assert.NoError(t, tag.Index(context.Background(), r))
+	}
+
+	matches, err := tag.MatchOffer(context.Background(), "bread", lastLogin)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bread", "breadcrumbs"}, matches)
+
+	matches, err = tag.MatchWant(context.Background(), "bread", lastLogin)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bred"}, matches)
+}
+
+func TestMemoryBusinessSuggestTags(t *testing.T) {
+	now := time.Now()
+	b := newMemoryBusiness()
+	records := []*types.BusinessESRecord{
+		{
+			BusinessID: "1",
+			Status:     constant.Trading.Accepted,
+			Offers: []*types.TagField{
+				{Name: "bread", CreatedAt: now.AddDate(0, -1, 0)},
+				{Name: "fresh-bread", CreatedAt: now},
+			},
+			Wants: []*types.TagField{{Name: "flour", CreatedAt: now}},
+		},
+		{
+			BusinessID: "2",
+			Status:     constant.Trading.Accepted,
+			Offers:     []*types.TagField{{Name: "bread", CreatedAt: now.AddDate(0, -2, 0)}},
+		},
+		{
+			BusinessID: "3",
+			Status:     constant.Trading.Accepted,
+			Offers:     []*types.TagField{{Name: "breakfast", CreatedAt: now.AddDate(0, 0, -1)}},
+		},
+		{
+			BusinessID: "4",
+			Status:     constant.Business.Accepted,
+			Offers:     []*types.TagField{{Name: "brewing", CreatedAt: now}},
+		},
+	}
+	for _, r := range records {
+		assert.NoError(t, b.Index(context.Background(), r))
+	}
+
+	tests := []struct {
+		name     string
+		tagType  string
+		prefix   string
+		size     int
+		expected []string
+	}{
+		{
+			"should rank by members and then recency",
+			constant.OFFERS, "bre", 10,
+			[]string{"bread", "fresh-bread", "breakfast"},
+		},
+		{
+			"should limit the size",
+			constant.OFFERS, "bre", 1,
+			[]string{"bread"},
+		},
+		{
+			"should match the parts of a tag",
+			constant.OFFERS, "fresh-b", 10,
+			[]string{"fresh-bread"},
+		},
+		{
+			"should only suggest the tag type",
+			constant.WANTS, "f", 10,
+			[]string{"flour"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions, err := b.SuggestTags(context.Background(), tt.tagType, tt.prefix, tt.size)
+			assert.NoError(t, err)
+			names := []string{}
+			for _, s := range suggestions {
+				names = append(names, s.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestMemoryBusinessFindSimilar(t *testing.T) {
+	b := newMemoryBusiness()
+	records := []*types.BusinessESRecord{
+		{
+			BusinessID: "1",
+			Status:     constant.Trading.Accepted,
+			Offers:     []*types.TagField{{Name: "bread"}, {Name: "cakes"}},
+		},
+		{
+			BusinessID: "2",
+			Status:     constant.Trading.Accepted,
+			Offers:     []*types.TagField{{Name: "bread"}},
+			Wants:      []*types.TagField{{Name: "flour"}},
+		},
+		{
+			BusinessID: "3",
+			Status:     constant.Business.Accepted,
+			Offers:     []*types.TagField{{Name: "bread"}, {Name: "cakes"}},
+		},
+		{
+			BusinessID: "4",
+			Status:     constant.Trading.Accepted,
+			Offers:     []*types.TagField{{Name: "plumbing"}},
+		},
+	}
+	for _, r := range records {
+		assert.NoError(t, b.Index(context.Background(), r))
+	}
+
+	tests := []struct {
+		name     string
+		criteria *types.SimilarCriteria
+		expected []string
+	}{
+		{
+			"should rank trading members by shared offers",
+			&types.SimilarCriteria{Offers: []string{"bread", "cakes"}},
+			[]string{"1", "2"},
+		},
+		{
+			"should add shared offers and wants",
+			&types.SimilarCriteria{Offers: []string{"cakes"}, Wants: []string{"flour"}},
+			[]string{"1", "2"},
+		},
+		{
+			"should exclude the ids",
+			&types.SimilarCriteria{Offers: []string{"bread"}, ExcludeIDs: []string{"1"}},
+			[]string{"2"},
+		},
+		{
+			"should not match without tags",
+			&types.SimilarCriteria{},
+			[]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := b.FindSimilar(context.Background(), tt.criteria, 5)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestMemoryBusinessFacets(t *testing.T) {
+	b := newMemoryBusiness()
+	records := []*types.BusinessESRecord{
+		{
+			BusinessID:      "1",
+			Status:          constant.Trading.Accepted,
+			LocationCountry: "Scotland",
+			LocationCity:    "Glasgow",
+			AdminTags:       []string{"Food"},
+		},
+		{
+			BusinessID:      "2",
+			Status:          constant.Business.Accepted,
+			LocationCountry: "Scotland",
+			LocationCity:    "Edinburgh",
+			AdminTags:       []string{"Food", "Retail"},
+		},
+		{
+			BusinessID:      "3",
+			Status:          constant.Trading.Accepted,
+			LocationCountry: "Wales",
+			LocationCity:    "Cardiff",
+		},
+	}
+	for _, r := range records {
+		assert.NoError(t, b.Index(context.Background(), r))
+	}
+	accepted := []string{
+		constant.Business.Accepted,
+		constant.Trading.Accepted,
+	}
+
+	result, err := b.Find(context.Background(), &types.SearchCriteria{
+		Statuses:          accepted,
+		LocationCountries: []string{"Scotland", "Wales"},
+		AdminTags:         []string{"Food"},
+	}, 1)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"1", "2"}, result.IDs)
+	// The selected countries don't narrow the country counts.
+	assert.Equal(t, []*types.FacetCount{
+		{Value: "Scotland", Count: 2, Selected: true},
+	}, result.Facets.LocationCountries)
+	assert.Equal(t, []*types.FacetCount{
+		{Value: "Food", Count: 2, Selected: true},
+		{Value: "Retail", Count: 1},
+	}, result.Facets.AdminTags)
+	assert.Equal(t, []*types.FacetCount{
+		{Value: "Edinburgh", Count: 1},
+		{Value: "Glasgow", Count: 1},
+	}, result.Facets.LocationCities)
+
+	result, err = b.Find(context.Background(), &types.SearchCriteria{
+		Statuses:          accepted,
+		LocationCountries: []string{"Scotland"},
+	}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.FacetCount{
+		{Value: "Scotland", Count: 2, Selected: true},
+		{Value: "Wales", Count: 1},
+	}, result.Facets.LocationCountries)
+	assert.Equal(t, []*types.FacetCount{
+		{Value: constant.Business.Accepted, Count: 1},
+		{Value: constant.Trading.Accepted, Count: 1},
+	}, result.Facets.Statuses)
+}
@@ -0,0 +1,90 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/pagination"
+)
+
+type memoryUser struct {
+	mu      sync.RWMutex
+	records map[string]*types.UserESRecord
+}
+
+func newMemoryUser() *memoryUser {
+	return &memoryUser{
+		records: map[string]*types.UserESRecord{},
+	}
+}
+
+func (m *memoryUser) Index(ctx context.Context, r *types.UserESRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *r
+	m.records[r.UserID] = &copied
+	return nil
+}
+
+func (m *memoryUser) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, id)
+	return nil
+}
+
+// Find follows es.User.Find.
+func (m *memoryUser) Find(ctx context.Context, u *types.User, page int64) ([]string, int, int, error) {
+	if page < 0 || page == 0 {
+		return nil, 0, 0, e.New(e.InvalidPageNumber, "find user failed")
+	}
+
+	m.mu.RLock()
+	type hit struct {
+		id    string
+		score float64
+	}
+	hits := make([]hit, 0, len(m.records))
+	for id, r := range m.records {
+		s := 0.0
+		if u.LastName != "" {
+			ss := score(textTerms(r.LastName), textTerms(u.LastName), u.LastName, fieldBoosts)
+			if ss == 0 {
+				continue
+			}
+			s += ss
+		}
+		if u.Email != "" {
+			ss := score(textTerms(r.Email), textTerms(u.Email), u.Email, fieldBoosts)
+			if ss == 0 {
+				continue
+			}
+			s += ss
+		}
+		hits = append(hits, hit{id, s})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].id < hits[j].id
+	})
+
+	size := global.Config().PageSize
+	from := size * (int(page) - 1)
+	ids := []string{}
+	for i := from; i < len(hits) && i < from+size; i++ {
+		ids = append(ids, hits[i].id)
+	}
+
+	numberOfResults := int64(len(hits))
+	totalPages := pagination.Pages(numberOfResults, int64(global.Config().PageSize))
+
+	return ids, int(numberOfResults), totalPages, nil
+}
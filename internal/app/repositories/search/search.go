@@ -0,0 +1,65 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/es"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+)
+
+// BusinessSearcher finds businesses for the directory.
+type BusinessSearcher interface {
+	Find(ctx context.Context, c *types.SearchCriteria, page int64) (*types.SearchBusinessResult, error)
+	FindSimilar(ctx context.Context, c *types.SimilarCriteria, size int) ([]string, error)
+	Index(ctx context.Context, r *types.BusinessESRecord) error
+	Delete(ctx context.Context, id string) error
+	RenameTag(ctx context.Context, old string, new string) error
+	RenameAdminTag(ctx context.Context, old string, new string) error
+	DeleteTag(ctx context.Context, name string) error
+	DeleteAdminTags(ctx context.Context, name string) error
+	SuggestTags(ctx context.Context, tagType string, prefix string, size int) ([]*types.TagSuggestion, error)
+}
+
+// UserSearcher finds users for the admin user search.
+type UserSearcher interface {
+	Find(ctx context.Context, u *types.User, page int64) ([]string, int, int, error)
+	Index(ctx context.Context, r *types.UserESRecord) error
+	Delete(ctx context.Context, id string) error
+}
+
+// TagSearcher matches offers and wants for the daily email.
+type TagSearcher interface {
+	MatchOffer(ctx context.Context, offer string, lastLoginDate time.Time) ([]string, error)
+	MatchWant(ctx context.Context, want string, lastLoginDate time.Time) ([]string, error)
+	Index(ctx context.Context, r *types.TagESRecord) error
+	DeleteByID(ctx context.Context, id string) error
+}
+
+var (
+	Business BusinessSearcher
+	User     UserSearcher
+	Tag      TagSearcher
+)
+
+func init() {
+	global.Init()
+	if global.Config().IsMemorySearch() {
+		UseMemory()
+		load()
+		return
+	}
+	Business = es.Business
+	User = es.User
+	Tag = es.Tag
+}
+
+// UseMemory switches to the in-process search backend with empty indexes.
+// It is meant for small networks running without Elasticsearch and for
+// tests.
+func UseMemory() {
+	Business = newMemoryBusiness()
+	User = newMemoryUser()
+	Tag = newMemoryTag()
+}
@@ -1,34 +1,69 @@
 package service
 
 import (
+	"context"
+
 	"github.com/ic3network/mccs-alpha/internal/app/repositories/pg"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 )
 
-type account struct{}
+type account struct {
+	accounts AccountRepository
+}
+
+var Account = NewAccount(pg.Account)
 
-var Account = &account{}
+// NewAccount returns the account service using the given repository.
+func NewAccount(accounts AccountRepository) *account {
+	return &account{accounts: accounts}
+}
 
-func (a *account) Create(bID string) error {
-	err := pg.Account.Create(bID)
+func (a *account) Create(ctx context.Context, bID string) (err error) {
+	ctx, span := tracing.Start(ctx, "AccountService Create")
+	defer func() { tracing.End(span, err) }()
+
+	err = a.accounts.Create(ctx, bID)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (a *account) FindByID(accountID uint) (*types.Account, error) {
-	account, err := pg.Account.FindByID(accountID)
+func (a *account) FindByID(ctx context.Context, accountID uint) (_ *types.Account, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService FindByID")
+	defer func() { tracing.End(span, err) }()
+
+	account, err := a.accounts.FindByID(ctx, accountID)
 	if err != nil {
 		return nil, err
 	}
 	return account, nil
 }
 
-func (a *account) FindByBusinessID(businessID string) (*types.Account, error) {
-	account, err := pg.Account.FindByBusinessID(businessID)
+func (a *account) FindByBusinessID(
+	ctx context.Context,
+	businessID string,
+) (_ *types.Account, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService FindByBusinessID")
+	defer func() { tracing.End(span, err) }()
+
+	account, err := a.accounts.FindByBusinessID(ctx, businessID)
 	if err != nil {
 		return nil, err
 	}
 	return account, nil
 }
+
+// DeleteByBusinessID deletes a new account, e.g. when the import of its
+// business fails.
+func (a *account) DeleteByBusinessID(ctx context.Context, bID string) (err error) {
+	ctx, span := tracing.Start(ctx, "AccountService DeleteByBusinessID")
+	defer func() { tracing.End(span, err) }()
+
+	err = a.accounts.DeleteByBusinessID(ctx, bID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
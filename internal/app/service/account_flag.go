@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type accountFlag struct{}
+
+var AccountFlag = &accountFlag{}
+
+func (a *accountFlag) Find(
+	ctx context.Context,
+	status string,
+	page int64,
+) (_ *types.FindAccountFlagResult, err error) {
+	ctx, span := tracing.Start(ctx, "AccountFlagService Find")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := mongo.AccountFlag.Find(ctx, status, page)
+	if err != nil {
+		return nil, e.Wrap(err, "AccountFlagService Find failed")
+	}
+	return result, nil
+}
+
+func (a *accountFlag) FindByID(
+	ctx context.Context,
+	id primitive.ObjectID,
+) (_ *types.AccountFlag, err error) {
+	ctx, span := tracing.Start(ctx, "AccountFlagService FindByID")
+	defer func() { tracing.End(span, err) }()
+
+	flag, err := mongo.AccountFlag.FindByID(ctx, id)
+	if err != nil {
+		return nil, e.Wrap(err, "AccountFlagService FindByID failed")
+	}
+	return flag, nil
+}
+
+func (a *accountFlag) AddNote(
+	ctx context.Context,
+	id primitive.ObjectID,
+	email string,
+	text string,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "AccountFlagService AddNote")
+	defer func() { tracing.End(span, err) }()
+
+	err = mongo.AccountFlag.AddNote(ctx, id, &types.AccountFlagNote{
+		CreatedAt: time.Now(),
+		Email:     email,
+		Text:      text,
+	})
+	if err != nil {
+		return e.Wrap(err, "AccountFlagService AddNote failed")
+	}
+	return nil
+}
+
+func (a *accountFlag) Resolve(ctx context.Context, id primitive.ObjectID) (err error) {
+	ctx, span := tracing.Start(ctx, "AccountFlagService Resolve")
+	defer func() { tracing.End(span, err) }()
+
+	err = mongo.AccountFlag.Resolve(ctx, id)
+	if err != nil {
+		return e.Wrap(err, "AccountFlagService Resolve failed")
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package accountcheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/pg"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// rules are the thresholds of the account check.
+type rules struct {
+	// Accounts without postings for dormantDays are dormant.
+	dormantDays int
+	// Accounts using limitThreshold of a limit are near the limit.
+	limitThreshold float64
+	// Accounts losing balance every week for trendWeeks are declining.
+	trendWeeks int
+}
+
+func loadRules() *rules {
+	return &rules{
+		dormantDays:    global.Config().AccountCheck.DormantDays,
+		limitThreshold: global.Config().AccountCheck.LimitThreshold,
+		trendWeeks:     global.Config().AccountCheck.TrendWeeks,
+	}
+}
+
+// Run flags the dormant and at-risk accounts and returns the number of
+// flagged accounts. A flag resolved by an admin is not opened again for
+// "account_check.snooze_days" unless the reasons change. The open flags of
+// the accounts which don't match any rule anymore are resolved.
+func Run(ctx context.Context) (int, error) {
+	now := time.Now()
+	r := loadRules()
+
+	activities, err := pg.Analytics.Activities(ctx)
+	if err != nil {
+		return 0, e.Wrap(err, "accountcheck failed")
+	}
+	nets, err := pg.Analytics.WeeklyNets(ctx, now, r.trendWeeks)
+	if err != nil {
+		return 0, e.Wrap(err, "accountcheck failed")
+	}
+
+	resolved, err := mongo.AccountFlag.FindResolvedSince(
+		ctx,
+		now.AddDate(0, 0, -global.Config().AccountCheck.SnoozeDays),
+	)
+	if err != nil {
+		return 0, e.Wrap(err, "accountcheck failed")
+	}
+	snoozed := map[string]bool{}
+	for _, f := range resolved {
+		snoozed[f.BusinessID.Hex()+"/"+reasonsKey(f.Reasons)] = true
+	}
+
+	detected := detect(now, activities, nets, r)
+	businessIDs := make([]primitive.ObjectID, 0, len(detected))
+	for _, f := range detected {
+		businessIDs = append(businessIDs, f.BusinessID)
+	}
+	cleared, err := mongo.AccountFlag.ResolveCleared(ctx, businessIDs)
+	if err != nil {
+		return 0, e.Wrap(err, "accountcheck failed")
+	}
+	if cleared > 0 {
+		l.Logger.Info("accountcheck resolved flags", zap.Int("flags", cleared))
+	}
+
+	flagged, failed := 0, 0
+	for _, f := range detected {
+		if snoozed[f.BusinessID.Hex()+"/"+reasonsKey(f.Reasons)] {
+			continue
+		}
+		err := mongo.AccountFlag.Flag(ctx, f)
+		if err != nil {
+			l.Logger.Error("accountcheck failed", zap.Error(err))
+			failed++
+			continue
+		}
+		flagged++
+	}
+	if failed > 0 {
+		return flagged, fmt.Errorf("accountcheck failed to flag %d accounts", failed)
+	}
+	return flagged, nil
+}
+
+// detect returns a flag for every account matching at least one rule.
+func detect(
+	now time.Time,
+	activities []*types.AccountActivity,
+	nets []*types.WeeklyNet,
+	r *rules,
+) []*types.AccountFlag {
+	weekly := map[uint]map[int]float64{}
+	for _, n := range nets {
+		if weekly[n.AccountID] == nil {
+			weekly[n.AccountID] = map[int]float64{}
+		}
+		weekly[n.AccountID][n.Week] = n.Net
+	}
+
+	flags := []*types.AccountFlag{}
+	for _, a := range activities {
+		businessID, err := primitive.ObjectIDFromHex(a.BusinessID)
+		if err != nil {
+			continue
+		}
+
+		reasons := []string{}
+		lastActive := a.CreatedAt
+		if a.LastPostingAt != nil {
+			lastActive = *a.LastPostingAt
+		}
+		if lastActive.Before(now.AddDate(0, 0, -r.dormantDays)) {
+			reasons = append(reasons, constant.AccountFlag.Dormant)
+		}
+		if a.Balance < 0 && a.MaxNegBal > 0 &&
+			-a.Balance >= a.MaxNegBal*r.limitThreshold {
+			reasons = append(reasons, constant.AccountFlag.NearMaxNeg)
+		}
+		if a.Balance > 0 && a.MaxPosBal > 0 &&
+			a.Balance >= a.MaxPosBal*r.limitThreshold {
+			reasons = append(reasons, constant.AccountFlag.NearMaxPos)
+		}
+		if isDeclining(weekly[a.AccountID], r.trendWeeks) {
+			reasons = append(reasons, constant.AccountFlag.Declining)
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+
+		flag := &types.AccountFlag{
+			BusinessID: businessID,
+			AccountID:  a.AccountID,
+			Reasons:    reasons,
+			Balance:    a.Balance,
+		}
+		if a.LastPostingAt != nil {
+			flag.LastPostingAt = *a.LastPostingAt
+		}
+		flags = append(flags, flag)
+	}
+	return flags
+}
+
+// isDeclining reports whether the balance went down in each of the weeks.
+func isDeclining(nets map[int]float64, weeks int) bool {
+	if weeks <= 0 {
+		return false
+	}
+	for week := 0; week < weeks; week++ {
+		net, ok := nets[week]
+		if !ok || net >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func reasonsKey(reasons []string) string {
+	sorted := make([]string, len(reasons))
+	copy(sorted, reasons)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// SendSummary emails the open flags to the admins and returns the number
+// of flags in the summary.
+func SendSummary(ctx context.Context) (int, error) {
+	flags, err := mongo.AccountFlag.FindOpen(ctx)
+	if err != nil {
+		return 0, e.Wrap(err, "accountcheck SendSummary failed")
+	}
+
+	ids := make([]string, 0, len(flags))
+	for _, f := range flags {
+		ids = append(ids, f.BusinessID.Hex())
+	}
+	businesses, err := mongo.Business.FindByIDs(ctx, ids)
+	if err != nil {
+		return 0, e.Wrap(err, "accountcheck SendSummary failed")
+	}
+	names := make(map[string]string, len(businesses))
+	for _, b := range businesses {
+		names[b.ID.Hex()] = b.BusinessName
+	}
+
+	err = email.AccountFlag.Summary(flags, names)
+	if err != nil {
+		return 0, e.Wrap(err, "sending account flag summary email failed")
+	}
+	return len(flags), nil
+}
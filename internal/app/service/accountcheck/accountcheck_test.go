@@ -0,0 +1,95 @@
+package accountcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDetect(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	recent := now.AddDate(0, 0, -10)
+	old := now.AddDate(0, 0, -100)
+	r := &rules{dormantDays: 90, limitThreshold: 0.9, trendWeeks: 2}
+
+	tests := []struct {
+		name     string
+		activity *types.AccountActivity
+		nets     []*types.WeeklyNet
+		expected []string
+	}{
+		{
+			name: "active account",
+			activity: &types.AccountActivity{
+				CreatedAt: old, Balance: 10, MaxNegBal: 100, MaxPosBal: 100, LastPostingAt: &recent,
+			},
+		},
+		{
+			name: "no postings since created",
+			activity: &types.AccountActivity{
+				CreatedAt: old, MaxNegBal: 100, MaxPosBal: 100,
+			},
+			expected: []string{constant.AccountFlag.Dormant},
+		},
+		{
+			name: "new account without postings",
+			activity: &types.AccountActivity{
+				CreatedAt: recent, MaxNegBal: 100, MaxPosBal: 100,
+			},
+		},
+		{
+			name: "near max negative balance",
+			activity: &types.AccountActivity{
+				CreatedAt: old, Balance: -95, MaxNegBal: 100, MaxPosBal: 100, LastPostingAt: &recent,
+			},
+			expected: []string{constant.AccountFlag.NearMaxNeg},
+		},
+		{
+			name: "near max positive balance and dormant",
+			activity: &types.AccountActivity{
+				CreatedAt: old, Balance: 90, MaxNegBal: 100, MaxPosBal: 100, LastPostingAt: &old,
+			},
+			expected: []string{constant.AccountFlag.Dormant, constant.AccountFlag.NearMaxPos},
+		},
+		{
+			name: "declining",
+			activity: &types.AccountActivity{
+				CreatedAt: old, MaxNegBal: 100, MaxPosBal: 100, LastPostingAt: &recent,
+			},
+			nets:     []*types.WeeklyNet{{Week: 0, Net: -5}, {Week: 1, Net: -1}},
+			expected: []string{constant.AccountFlag.Declining},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.activity.AccountID = 1
+			test.activity.BusinessID = primitive.NewObjectID().Hex()
+			for _, n := range test.nets {
+				n.AccountID = 1
+			}
+			flags := detect(now, []*types.AccountActivity{test.activity}, test.nets, r)
+			if test.expected == nil {
+				assert.Empty(t, flags)
+				return
+			}
+			assert.Len(t, flags, 1)
+			assert.Equal(t, test.expected, flags[0].Reasons)
+		})
+	}
+}
+
+func TestIsDeclining(t *testing.T) {
+	assert.True(t, isDeclining(map[int]float64{0: -1, 1: -2, 2: 3}, 2))
+	assert.False(t, isDeclining(map[int]float64{0: -1, 1: 2}, 2))
+	assert.False(t, isDeclining(map[int]float64{0: -1}, 2))
+	assert.False(t, isDeclining(map[int]float64{}, 0))
+}
+
+func TestReasonsKey(t *testing.T) {
+	assert.Equal(t, reasonsKey([]string{"b", "a"}), reasonsKey([]string{"a", "b"}))
+}
@@ -1,34 +1,56 @@
 package service
 
 import (
+	"context"
+
 	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-type adminTag struct{}
+type adminTag struct {
+	adminTags AdminTagRepository
+}
+
+var AdminTag = NewAdminTag(mongo.AdminTag)
 
-var AdminTag = &adminTag{}
+// NewAdminTag returns the admin tag service using the given repository.
+func NewAdminTag(adminTags AdminTagRepository) *adminTag {
+	return &adminTag{adminTags: adminTags}
+}
+
+func (a *adminTag) Create(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "AdminTagService Create")
+	defer func() { tracing.End(span, err) }()
 
-func (a *adminTag) Create(name string) error {
-	err := mongo.AdminTag.Create(name)
+	err = a.adminTags.Create(ctx, name)
 	if err != nil {
 		return e.Wrap(err, "create admin tag failed")
 	}
 	return nil
 }
 
-func (a *adminTag) FindByName(name string) (*types.AdminTag, error) {
-	adminTag, err := mongo.AdminTag.FindByName(name)
+func (a *adminTag) FindByName(ctx context.Context, name string) (_ *types.AdminTag, err error) {
+	ctx, span := tracing.Start(ctx, "AdminTagService FindByName")
+	defer func() { tracing.End(span, err) }()
+
+	adminTag, err := a.adminTags.FindByName(ctx, name)
 	if err != nil {
 		return nil, e.Wrap(err, "AdminTagService FindByName failed")
 	}
 	return adminTag, nil
 }
 
-func (a *adminTag) FindByID(id primitive.ObjectID) (*types.AdminTag, error) {
-	adminTag, err := mongo.AdminTag.FindByID(id)
+func (a *adminTag) FindByID(
+	ctx context.Context,
+	id primitive.ObjectID,
+) (_ *types.AdminTag, err error) {
+	ctx, span := tracing.Start(ctx, "AdminTagService FindByID")
+	defer func() { tracing.End(span, err) }()
+
+	adminTag, err := a.adminTags.FindByID(ctx, id)
 	if err != nil {
 		return nil, e.Wrap(err, "AdminTagService FindByID failed")
 	}
@@ -36,42 +58,58 @@ func (a *adminTag) FindByID(id primitive.ObjectID) (*types.AdminTag, error) {
 }
 
 func (a *adminTag) FindTags(
+	ctx context.Context,
 	name string,
 	page int64,
-) (*types.FindAdminTagResult, error) {
-	result, err := mongo.AdminTag.FindTags(name, page)
+) (_ *types.FindAdminTagResult, err error) {
+	ctx, span := tracing.Start(ctx, "AdminTagService FindTags")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := a.adminTags.FindTags(ctx, name, page)
 	if err != nil {
 		return nil, e.Wrap(err, "AdminTagService FindTags failed")
 	}
 	return result, nil
 }
 
-func (a *adminTag) TagStartWith(prefix string) ([]string, error) {
-	tags, err := mongo.AdminTag.TagStartWith(prefix)
+func (a *adminTag) TagStartWith(ctx context.Context, prefix string) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "AdminTagService TagStartWith")
+	defer func() { tracing.End(span, err) }()
+
+	tags, err := a.adminTags.TagStartWith(ctx, prefix)
 	if err != nil {
 		return nil, err
 	}
 	return tags, nil
 }
 
-func (a *adminTag) GetAll() ([]*types.AdminTag, error) {
-	adminTags, err := mongo.AdminTag.GetAll()
+func (a *adminTag) GetAll(ctx context.Context) (_ []*types.AdminTag, err error) {
+	ctx, span := tracing.Start(ctx, "AdminTagService GetAll")
+	defer func() { tracing.End(span, err) }()
+
+	adminTags, err := a.adminTags.GetAll(ctx)
 	if err != nil {
 		return nil, e.Wrap(err, "AdminTagService GetAll failed")
 	}
 	return adminTags, nil
 }
 
-func (a *adminTag) Update(tag *types.AdminTag) error {
-	err := mongo.AdminTag.Update(tag)
+func (a *adminTag) Update(ctx context.Context, tag *types.AdminTag) (err error) {
+	ctx, span := tracing.Start(ctx, "AdminTagService Update")
+	defer func() { tracing.End(span, err) }()
+
+	err = a.adminTags.Update(ctx, tag)
 	if err != nil {
 		return e.Wrap(err, "AdminTagService Update failed")
 	}
 	return nil
 }
 
-func (a *adminTag) DeleteByID(id primitive.ObjectID) error {
-	err := mongo.AdminTag.DeleteByID(id)
+func (a *adminTag) DeleteByID(ctx context.Context, id primitive.ObjectID) (err error) {
+	ctx, span := tracing.Start(ctx, "AdminTagService DeleteByID")
+	defer func() { tracing.End(span, err) }()
+
+	err = a.adminTags.DeleteByID(ctx, id)
 	if err != nil {
 		return e.Wrap(err, "AdminTagService DeleteByID failed")
 	}
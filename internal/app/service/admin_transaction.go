@@ -1,15 +1,21 @@
 package service
 
 import (
+	"context"
+
 	"github.com/ic3network/mccs-alpha/internal/app/repositories/pg"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 )
 
 type adminTransaction struct{}
 
 var AdminTransaction = &adminTransaction{}
 
+// Create transfers the amount between the businesses and returns the
+// transaction ID.
 func (a *adminTransaction) Create(
+	ctx context.Context,
 	fromID,
 	fromEmail,
 	fromBusinessName,
@@ -20,34 +26,38 @@ func (a *adminTransaction) Create(
 
 	amount float64,
 	description string,
-) error {
+) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "AdminTransactionService Create")
+	defer func() { tracing.End(span, err) }()
+
 	// Get the Account IDs using MongoIDs.
-	from, err := pg.Account.FindByBusinessID(fromID)
+	from, err := pg.Account.FindByBusinessID(ctx, fromID)
 	if err != nil {
-		return e.Wrap(err, "service.Account.MakeTransfer failed")
+		return "", e.Wrap(err, "service.Account.MakeTransfer failed")
 	}
-	to, err := pg.Account.FindByBusinessID(toID)
+	to, err := pg.Account.FindByBusinessID(ctx, toID)
 	if err != nil {
-		return e.Wrap(err, "service.Account.MakeTransfer failed")
+		return "", e.Wrap(err, "service.Account.MakeTransfer failed")
 	}
 
 	// Check the account balance.
-	exceed, err := BalanceLimit.IsExceedLimit(from.ID, from.Balance-amount)
+	exceed, err := BalanceLimit.IsExceedLimit(ctx, from.ID, from.Balance-amount)
 	if err != nil {
-		return e.Wrap(err, "service.Account.MakeTransfer failed")
+		return "", e.Wrap(err, "service.Account.MakeTransfer failed")
 	}
 	if exceed {
-		return e.New(e.ExceedMaxNegBalance, "max negative exceed")
+		return "", e.New(e.ExceedMaxNegBalance, "max negative exceed")
 	}
-	exceed, err = BalanceLimit.IsExceedLimit(to.ID, to.Balance+amount)
+	exceed, err = BalanceLimit.IsExceedLimit(ctx, to.ID, to.Balance+amount)
 	if err != nil {
-		return e.Wrap(err, "service.Account.MakeTransfer failed")
+		return "", e.Wrap(err, "service.Account.MakeTransfer failed")
 	}
 	if exceed {
-		return e.New(e.ExceedMaxPosBalance, "max positive exceed")
+		return "", e.New(e.ExceedMaxPosBalance, "max positive exceed")
 	}
 
-	err = pg.Transaction.Create(
+	transactionID, err := pg.Transaction.Create(
+		ctx,
 		from.ID,
 		fromEmail,
 		fromBusinessName,
@@ -58,7 +68,7 @@ func (a *adminTransaction) Create(
 		description,
 	)
 	if err != nil {
-		return e.Wrap(err, "service.Account.MakeTransfer failed")
+		return "", e.Wrap(err, "service.Account.MakeTransfer failed")
 	}
-	return nil
+	return transactionID, nil
 }
@@ -1,22 +1,36 @@
 package service
 
 import (
+	"context"
+
 	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/bcrypt"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-type adminUser struct{}
+type adminUser struct {
+	adminUsers AdminUserRepository
+}
+
+var AdminUser = NewAdminUser(mongo.AdminUser)
 
-var AdminUser = &adminUser{}
+// NewAdminUser returns the admin user service using the given repository.
+func NewAdminUser(adminUsers AdminUserRepository) *adminUser {
+	return &adminUser{adminUsers: adminUsers}
+}
 
 func (a *adminUser) Login(
+	ctx context.Context,
 	email string,
 	password string,
-) (*types.AdminUser, error) {
-	user, err := mongo.AdminUser.FindByEmail(email)
+) (_ *types.AdminUser, err error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService Login")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := a.adminUsers.FindByEmail(ctx, email)
 	if err != nil {
 		return &types.AdminUser{}, e.Wrap(err, "login admin user failed")
 	}
@@ -29,24 +43,40 @@ func (a *adminUser) Login(
 	return user, nil
 }
 
-func (a *adminUser) FindByID(id primitive.ObjectID) (*types.AdminUser, error) {
-	adminUser, err := mongo.AdminUser.FindByID(id)
+func (a *adminUser) FindByID(
+	ctx context.Context,
+	id primitive.ObjectID,
+) (_ *types.AdminUser, err error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService FindByID")
+	defer func() { tracing.End(span, err) }()
+
+	adminUser, err := a.adminUsers.FindByID(ctx, id)
 	if err != nil {
 		return nil, e.Wrap(err, "service.AdminUser.FindByID failed")
 	}
 	return adminUser, nil
 }
 
-func (a *adminUser) FindByEmail(email string) (*types.AdminUser, error) {
-	adminUser, err := mongo.AdminUser.FindByEmail(email)
+func (a *adminUser) FindByEmail(ctx context.Context, email string) (_ *types.AdminUser, err error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService FindByEmail")
+	defer func() { tracing.End(span, err) }()
+
+	adminUser, err := a.adminUsers.FindByEmail(ctx, email)
 	if err != nil {
 		return nil, e.Wrap(err, "service.AdminUser.FindByEmail failed")
 	}
 	return adminUser, nil
 }
 
-func (a *adminUser) UpdateLoginInfo(id primitive.ObjectID, ip string) error {
-	loginInfo, err := mongo.AdminUser.GetLoginInfo(id)
+func (a *adminUser) UpdateLoginInfo(
+	ctx context.Context,
+	id primitive.ObjectID,
+	ip string,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService UpdateLoginInfo")
+	defer func() { tracing.End(span, err) }()
+
+	loginInfo, err := a.adminUsers.GetLoginInfo(ctx, id)
 	if err != nil {
 		return e.Wrap(err, "service.AdminUser.UpdateLoginInfo failed")
 	}
@@ -57,7 +87,7 @@ func (a *adminUser) UpdateLoginInfo(id primitive.ObjectID, ip string) error {
 		LastLoginDate:  loginInfo.CurrentLoginDate,
 	}
 
-	err = mongo.AdminUser.UpdateLoginInfo(id, newLoginInfo)
+	err = a.adminUsers.UpdateLoginInfo(ctx, id, newLoginInfo)
 	if err != nil {
 		return e.Wrap(err, "AdminUserService UpdateLoginInfo failed")
 	}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type agreement struct {
+	agreements  AgreementRepository
+	acceptances AgreementAcceptanceRepository
+}
+
+var Agreement = NewAgreement(mongo.Agreement, mongo.AgreementAcceptance)
+
+// NewAgreement returns the agreement service using the given repositories.
+func NewAgreement(
+	agreements AgreementRepository,
+	acceptances AgreementAcceptanceRepository,
+) *agreement {
+	return &agreement{agreements: agreements, acceptances: acceptances}
+}
+
+// Publish stores the text as the next version of the membership agreement.
+func (a *agreement) Publish(
+	ctx context.Context,
+	text string,
+	adminEmail string,
+	requiredForTrading bool,
+) (_ *types.Agreement, err error) {
+	ctx, span := tracing.Start(ctx, "AgreementService Publish")
+	defer func() { tracing.End(span, err) }()
+
+	published, err := a.agreements.Publish(ctx, &types.Agreement{
+		Text:               text,
+		PublishedBy:        adminEmail,
+		RequiredForTrading: requiredForTrading,
+	})
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementService Publish failed")
+	}
+	return published, nil
+}
+
+// Latest returns the latest version, nil when no version has been
+// published yet.
+func (a *agreement) Latest(ctx context.Context) (_ *types.Agreement, err error) {
+	ctx, span := tracing.Start(ctx, "AgreementService Latest")
+	defer func() { tracing.End(span, err) }()
+
+	latest, err := a.agreements.FindLatest(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementService Latest failed")
+	}
+	return latest, nil
+}
+
+func (a *agreement) FindByVersion(
+	ctx context.Context,
+	version int,
+) (_ *types.Agreement, err error) {
+	ctx, span := tracing.Start(ctx, "AgreementService FindByVersion")
+	defer func() { tracing.End(span, err) }()
+
+	agreement, err := a.agreements.FindByVersion(ctx, version)
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementService FindByVersion failed")
+	}
+	return agreement, nil
+}
+
+func (a *agreement) FindAll(ctx context.Context) (_ []*types.Agreement, err error) {
+	ctx, span := tracing.Start(ctx, "AgreementService FindAll")
+	defer func() { tracing.End(span, err) }()
+
+	agreements, err := a.agreements.FindAll(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementService FindAll failed")
+	}
+	return agreements, nil
+}
+
+// Accept records that the user accepted the version on behalf of the
+// business.
+func (a *agreement) Accept(
+	ctx context.Context,
+	userID primitive.ObjectID,
+	businessID primitive.ObjectID,
+	version int,
+	ip string,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "AgreementService Accept")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = a.agreements.FindByVersion(ctx, version)
+	if err != nil {
+		return e.Wrap(err, "AgreementService Accept failed")
+	}
+	err = a.acceptances.Create(ctx, &types.AgreementAcceptance{
+		UserID:     userID,
+		BusinessID: businessID,
+		Version:    version,
+		IPAddress:  ip,
+	})
+	if err != nil {
+		return e.Wrap(err, "AgreementService Accept failed")
+	}
+	return nil
+}
+
+// Pending returns the latest version when the business is a trading member
+// and the user has not accepted it yet, nil otherwise.
+func (a *agreement) Pending(
+	ctx context.Context,
+	user *types.User,
+	business *types.Business,
+) (_ *types.Agreement, err error) {
+	ctx, span := tracing.Start(ctx, "AgreementService Pending")
+	defer func() { tracing.End(span, err) }()
+
+	if business.Status != constant.Trading.Accepted {
+		return nil, nil
+	}
+	latest, err := a.agreements.FindLatest(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementService Pending failed")
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	accepted, err := a.acceptances.HasAccepted(ctx, user.ID, latest.Version)
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementService Pending failed")
+	}
+	if accepted {
+		return nil, nil
+	}
+	return latest, nil
+}
+
+// CanTrade reports whether the user can make transfers, it is false when
+// the latest version has to be accepted before trading and the user has
+// not accepted it.
+func (a *agreement) CanTrade(
+	ctx context.Context,
+	user *types.User,
+	business *types.Business,
+) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "AgreementService CanTrade")
+	defer func() { tracing.End(span, err) }()
+
+	pending, err := a.Pending(ctx, user, business)
+	if err != nil {
+		return false, e.Wrap(err, "AgreementService CanTrade failed")
+	}
+	return pending == nil || !pending.RequiredForTrading, nil
+}
+
+// AcceptanceCounts returns the number of acceptances of every version.
+func (a *agreement) AcceptanceCounts(ctx context.Context) (_ map[int]int, err error) {
+	ctx, span := tracing.Start(ctx, "AgreementService AcceptanceCounts")
+	defer func() { tracing.End(span, err) }()
+
+	counts, err := a.acceptances.CountByVersion(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementService AcceptanceCounts failed")
+	}
+	return counts, nil
+}
+
+func (a *agreement) FindAcceptances(
+	ctx context.Context,
+	version int,
+	page int64,
+) (_ *types.FindAgreementAcceptanceResult, err error) {
+	ctx, span := tracing.Start(ctx, "AgreementService FindAcceptances")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := a.acceptances.FindByVersion(ctx, version, page)
+	if err != nil {
+		return nil, e.Wrap(err, "AgreementService FindAcceptances failed")
+	}
+	return result, nil
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/pg"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
+)
+
+type analytics struct{}
+
+var Analytics = &analytics{}
+
+// Trade builds the network-wide trade analytics of the date range.
+func (a *analytics) Trade(
+	ctx context.Context,
+	c *types.AnalyticsCriteria,
+) (_ *types.TradeAnalytics, err error) {
+	ctx, span := tracing.Start(ctx, "AnalyticsService Trade")
+	defer func() { tracing.End(span, err) }()
+
+	result := &types.TradeAnalytics{
+		Interval: c.Interval,
+		DateFrom: c.DateFrom,
+		DateTo:   c.DateTo,
+	}
+
+	trades, err := pg.Analytics.TradesByPeriod(ctx, c.Interval, c.DateFrom, c.DateTo)
+	if err != nil {
+		return nil, e.Wrap(err, "AnalyticsService Trade failed")
+	}
+	newMembers, err := mongo.Business.CountNewMembers(ctx, c.Interval, c.DateFrom, c.DateTo)
+	if err != nil {
+		return nil, e.Wrap(err, "AnalyticsService Trade failed")
+	}
+	result.Periods = mergePeriods(
+		periods(c.Interval, c.DateFrom, c.DateTo),
+		trades,
+		newMembers,
+	)
+	for _, p := range result.Periods {
+		result.TradeCount += p.TradeCount
+		result.TradeVolume += p.TradeVolume
+	}
+
+	result.TotalAccounts, err = pg.Analytics.CountAccounts(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "AnalyticsService Trade failed")
+	}
+	result.ActiveAccounts, err = pg.Analytics.CountActiveAccounts(ctx, c.DateFrom, c.DateTo)
+	if err != nil {
+		return nil, e.Wrap(err, "AnalyticsService Trade failed")
+	}
+	result.DormantAccounts = result.TotalAccounts - result.ActiveAccounts
+
+	result.MoneySupply, err = pg.Analytics.MoneySupply(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "AnalyticsService Trade failed")
+	}
+	if result.MoneySupply > 0 {
+		result.Velocity = result.TradeVolume / result.MoneySupply
+	}
+
+	balances, err := pg.Analytics.Balances(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "AnalyticsService Trade failed")
+	}
+	result.BalanceDistribution = balanceDistribution(balances)
+
+	result.TopTraders, err = pg.Analytics.TopTraders(
+		ctx,
+		c.DateFrom,
+		c.DateTo,
+		global.Config().AnalyticsTopTraders,
+	)
+	if err != nil {
+		return nil, e.Wrap(err, "AnalyticsService Trade failed")
+	}
+	ids := make([]string, 0, len(result.TopTraders))
+	for _, t := range result.TopTraders {
+		ids = append(ids, t.BusinessID)
+	}
+	businesses, err := mongo.Business.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, e.Wrap(err, "AnalyticsService Trade failed")
+	}
+	names := make(map[string]string, len(businesses))
+	for _, b := range businesses {
+		names[b.ID.Hex()] = b.BusinessName
+	}
+	for _, t := range result.TopTraders {
+		t.BusinessName = names[t.BusinessID]
+	}
+
+	return result, nil
+}
+
+// OutstandingCredit returns the credit extended to the accounts with a
+// negative balance.
+func (a *analytics) OutstandingCredit(ctx context.Context) (_ float64, err error) {
+	ctx, span := tracing.Start(ctx, "AnalyticsService OutstandingCredit")
+	defer func() { tracing.End(span, err) }()
+
+	credit, err := pg.Analytics.OutstandingCredit(ctx)
+	if err != nil {
+		return 0, e.Wrap(err, "AnalyticsService OutstandingCredit failed")
+	}
+	return credit, nil
+}
+
+// periods lists every period of the date range so that the periods
+// without trades are shown too.
+func periods(interval string, from time.Time, to time.Time) []string {
+	result := []string{}
+	seen := map[string]bool{}
+	for t := from.UTC(); !t.After(to.UTC()); t = t.AddDate(0, 0, 1) {
+		p := period(interval, t)
+		if !seen[p] {
+			seen[p] = true
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func period(interval string, t time.Time) string {
+	switch interval {
+	case "week":
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case "month":
+		return t.Format("2006-01")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+func mergePeriods(
+	periods []string,
+	trades []*types.PeriodCount,
+	newMembers []*types.PeriodCount,
+) []*types.AnalyticsPeriod {
+	result := make([]*types.AnalyticsPeriod, 0, len(periods))
+	byPeriod := make(map[string]*types.AnalyticsPeriod, len(periods))
+	for _, p := range periods {
+		ap := &types.AnalyticsPeriod{Period: p}
+		byPeriod[p] = ap
+		result = append(result, ap)
+	}
+	for _, t := range trades {
+		if ap, ok := byPeriod[t.Period]; ok {
+			ap.TradeCount = t.Count
+			ap.TradeVolume = t.Volume
+		}
+	}
+	for _, m := range newMembers {
+		if ap, ok := byPeriod[m.Period]; ok {
+			ap.NewMembers = m.Count
+		}
+	}
+	return result
+}
+
+// balanceBuckets are the shares of the limit used by a balance, from the
+// max negative balance to the max positive balance.
+var balanceBuckets = []string{
+	"Below max negative balance",
+	"-100% to -75%",
+	"-75% to -50%",
+	"-50% to -25%",
+	"-25% to 0%",
+	"0% to 25%",
+	"25% to 50%",
+	"50% to 75%",
+	"75% to 100%",
+	"Above max positive balance",
+}
+
+func balanceDistribution(balances []*types.AccountBalance) []*types.BalanceBucket {
+	result := make([]*types.BalanceBucket, 0, len(balanceBuckets))
+	for _, label := range balanceBuckets {
+		result = append(result, &types.BalanceBucket{Label: label})
+	}
+	for _, b := range balances {
+		result[balanceBucket(b)].Count++
+	}
+	return result
+}
+
+func balanceBucket(b *types.AccountBalance) int {
+	if b.Balance < 0 {
+		if b.MaxNegBal <= 0 || -b.Balance > b.MaxNegBal {
+			return 0
+		}
+		share := -b.Balance / b.MaxNegBal
+		switch {
+		case share > 0.75:
+			return 1
+		case share > 0.5:
+			return 2
+		case share > 0.25:
+			return 3
+		default:
+			return 4
+		}
+	}
+	if b.Balance > b.MaxPosBal {
+		return len(balanceBuckets) - 1
+	}
+	if b.MaxPosBal <= 0 {
+		return 5
+	}
+	share := b.Balance / b.MaxPosBal
+	switch {
+	case share < 0.25:
+		return 5
+	case share < 0.5:
+		return 6
+	case share < 0.75:
+		return 7
+	default:
+		return 8
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeriods(t *testing.T) {
+	from := time.Date(2019, 12, 30, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 1, 6, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		interval string
+		expected []string
+	}{
+		{"day", []string{
+			"2019-12-30", "2019-12-31", "2020-01-01", "2020-01-02",
+			"2020-01-03", "2020-01-04", "2020-01-05", "2020-01-06",
+		}},
+		{"week", []string{"2020-W01", "2020-W02"}},
+		{"month", []string{"2019-12", "2020-01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.interval, func(t *testing.T) {
+			assert.Equal(t, tt.expected, periods(tt.interval, from, to))
+		})
+	}
+}
+
+func TestMergePeriods(t *testing.T) {
+	merged := mergePeriods(
+		[]string{"2020-01", "2020-02"},
+		[]*types.PeriodCount{{Period: "2020-02", Count: 3, Volume: 150}},
+		[]*types.PeriodCount{{Period: "2020-01", Count: 2}},
+	)
+	assert.Equal(t, []*types.AnalyticsPeriod{
+		{Period: "2020-01", NewMembers: 2},
+		{Period: "2020-02", TradeCount: 3, TradeVolume: 150},
+	}, merged)
+}
+
+func TestBalanceBucket(t *testing.T) {
+	tests := []struct {
+		name     string
+		balance  float64
+		expected string
+	}{
+		{"should be below the max negative balance", -600, "Below max negative balance"},
+		{"should use most of the negative limit", -450, "-100% to -75%"},
+		{"should use a little of the negative limit", -100, "-25% to 0%"},
+		{"should count zero as positive", 0, "0% to 25%"},
+		{"should use half of the positive limit", 250, "50% to 75%"},
+		{"should reach the positive limit", 500, "75% to 100%"},
+		{"should be above the max positive balance", 501, "Above max positive balance"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &types.AccountBalance{Balance: tt.balance, MaxNegBal: 500, MaxPosBal: 500}
+			assert.Equal(t, tt.expected, balanceBuckets[balanceBucket(b)])
+		})
+	}
+}
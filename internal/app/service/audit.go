@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
+)
+
+type audit struct {
+	events AuditEventRepository
+}
+
+var Audit = NewAudit(mongo.AuditEvent)
+
+// NewAudit returns the audit service using the given repository.
+func NewAudit(events AuditEventRepository) *audit {
+	return &audit{events: events}
+}
+
+// Record adds the event to the audit trail, a nil event is skipped.
+func (a *audit) Record(ctx context.Context, event *types.AuditEvent) (err error) {
+	ctx, span := tracing.Start(ctx, "AuditService Record")
+	defer func() { tracing.End(span, err) }()
+
+	if event == nil {
+		return nil
+	}
+	err = a.events.Create(ctx, event)
+	if err != nil {
+		return e.Wrap(err, "AuditService Record failed")
+	}
+	return nil
+}
+
+func (a *audit) Find(
+	ctx context.Context,
+	c *types.AuditSearchCriteria,
+	page int64,
+) (_ []*types.AuditEvent, _ int, err error) {
+	ctx, span := tracing.Start(ctx, "AuditService Find")
+	defer func() { tracing.End(span, err) }()
+
+	events, totalPages, err := a.events.Find(ctx, c, page)
+	if err != nil {
+		return nil, 0, e.Wrap(err, "AuditService Find failed")
+	}
+	return events, totalPages, nil
+}
+
+// Export writes the matching events to w as JSON Lines, one event per
+// line, the oldest first.
+func (a *audit) Export(ctx context.Context, c *types.AuditSearchCriteria, w io.Writer) (err error) {
+	ctx, span := tracing.Start(ctx, "AuditService Export")
+	defer func() { tracing.End(span, err) }()
+
+	encoder := json.NewEncoder(w)
+	err = a.events.ForEach(ctx, c, func(event *types.AuditEvent) error {
+		return encoder.Encode(event)
+	})
+	if err != nil {
+		return e.Wrap(err, "AuditService Export failed")
+	}
+	return nil
+}
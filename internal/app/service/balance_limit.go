@@ -1,47 +1,79 @@
 package service
 
 import (
+	"context"
 	"math"
 
 	"github.com/ic3network/mccs-alpha/internal/app/repositories/pg"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 )
 
-type balanceLimit struct{}
+type balanceLimit struct {
+	accounts AccountRepository
+	limits   BalanceLimitRepository
+}
+
+var BalanceLimit = NewBalanceLimit(pg.Account, pg.BalanceLimit)
+
+// NewBalanceLimit returns the balance limit service using the given
+// repositories.
+func NewBalanceLimit(
+	accounts AccountRepository,
+	limits BalanceLimitRepository,
+) balanceLimit {
+	return balanceLimit{accounts: accounts, limits: limits}
+}
 
-var BalanceLimit = balanceLimit{}
+func (b balanceLimit) FindByAccountID(
+	ctx context.Context,
+	id uint,
+) (_ *types.BalanceLimit, err error) {
+	ctx, span := tracing.Start(ctx, "BalanceLimitService FindByAccountID")
+	defer func() { tracing.End(span, err) }()
 
-func (b balanceLimit) FindByAccountID(id uint) (*types.BalanceLimit, error) {
-	record, err := pg.BalanceLimit.FindByAccountID(id)
+	record, err := b.limits.FindByAccountID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	return record, nil
 }
 
-func (b balanceLimit) FindByBusinessID(id string) (*types.BalanceLimit, error) {
-	account, err := Account.FindByBusinessID(id)
+func (b balanceLimit) FindByBusinessID(
+	ctx context.Context,
+	id string,
+) (_ *types.BalanceLimit, err error) {
+	ctx, span := tracing.Start(ctx, "BalanceLimitService FindByBusinessID")
+	defer func() { tracing.End(span, err) }()
+
+	account, err := b.accounts.FindByBusinessID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	record, err := pg.BalanceLimit.FindByAccountID(account.ID)
+	record, err := b.limits.FindByAccountID(ctx, account.ID)
 	if err != nil {
 		return nil, err
 	}
 	return record, nil
 }
 
-func (b balanceLimit) GetMaxPosBalance(id uint) (float64, error) {
-	balanceLimitRecord, err := pg.BalanceLimit.FindByAccountID(id)
+func (b balanceLimit) GetMaxPosBalance(ctx context.Context, id uint) (_ float64, err error) {
+	ctx, span := tracing.Start(ctx, "BalanceLimitService GetMaxPosBalance")
+	defer func() { tracing.End(span, err) }()
+
+	balanceLimitRecord, err := b.limits.FindByAccountID(ctx, id)
 	if err != nil {
 		return 0, e.Wrap(err, "service.BalanceLimit.GetMaxPosBalance failed")
 	}
 	return balanceLimitRecord.MaxPosBal, nil
 }
 
-func (b balanceLimit) GetMaxNegBalance(id uint) (float64, error) {
-	balanceLimitRecord, err := pg.BalanceLimit.FindByAccountID(id)
+func (b balanceLimit) GetMaxNegBalance(ctx context.Context, id uint) (_ float64, err error) {
+	ctx, span := tracing.Start(ctx, "BalanceLimitService GetMaxNegBalance")
+	defer func() { tracing.End(span, err) }()
+
+	balanceLimitRecord, err := b.limits.FindByAccountID(ctx, id)
 	if err != nil {
 		return 0, e.Wrap(err, "service.BalanceLimit.GetMaxNegBalance failed")
 	}
@@ -49,8 +81,15 @@ func (b balanceLimit) GetMaxNegBalance(id uint) (float64, error) {
 }
 
 // IsExceedLimit checks whether or not the account exceeds the max positive or max negative limit.
-func (b balanceLimit) IsExceedLimit(id uint, balance float64) (bool, error) {
-	balanceLimitRecord, err := pg.BalanceLimit.FindByAccountID(id)
+func (b balanceLimit) IsExceedLimit(
+	ctx context.Context,
+	id uint,
+	balance float64,
+) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "BalanceLimitService IsExceedLimit")
+	defer func() { tracing.End(span, err) }()
+
+	balanceLimitRecord, err := b.limits.FindByAccountID(ctx, id)
 	if err != nil {
 		return false, e.Wrap(err, "service.BalanceLimit.FindByAccountID failed")
 	}
@@ -63,11 +102,15 @@ func (b balanceLimit) IsExceedLimit(id uint, balance float64) (bool, error) {
 }
 
 func (b balanceLimit) Update(
+	ctx context.Context,
 	id uint,
 	maxPosBal float64,
 	maxNegBal float64,
-) error {
-	err := pg.BalanceLimit.Update(id, maxPosBal, maxNegBal)
+) (err error) {
+	ctx, span := tracing.Start(ctx, "BalanceLimitService Update")
+	defer func() { tracing.End(span, err) }()
+
+	err = b.limits.Update(ctx, id, maxPosBal, maxNegBal)
 	if err != nil {
 		return err
 	}
@@ -1,23 +1,23 @@
 package balancecheck
 
 import (
+	"context"
 	"time"
 
 	"github.com/ic3network/mccs-alpha/internal/app/repositories/pg"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
 	"github.com/ic3network/mccs-alpha/internal/pkg/email"
-	"github.com/ic3network/mccs-alpha/internal/pkg/l"
-	"go.uber.org/zap"
 )
 
 // Run will check whether the last past 5 hours the sum of the balance in the posting table is zero.
-func Run() {
+// It returns the number of checked postings.
+func Run(ctx context.Context) (int, error) {
 	to := time.Now()
 	from := to.Add(-5 * time.Hour)
 
-	postings, err := pg.Posting.FindInRange(from, to)
+	postings, err := pg.Posting.FindInRange(ctx, from, to)
 	if err != nil {
-		l.Logger.Error("checking balance failed", zap.Error(err))
-		return
+		return 0, e.Wrap(err, "checking balance failed")
 	}
 
 	var sum float64
@@ -28,10 +28,8 @@ func Run() {
 	if sum != 0.0 {
 		err := email.Balance.NonZeroBalance(from, to)
 		if err != nil {
-			l.Logger.Error(
-				"sending NonZeroBalance email failed",
-				zap.Error(err),
-			)
+			return len(postings), e.Wrap(err, "sending NonZeroBalance email failed")
 		}
 	}
+	return len(postings), nil
 }
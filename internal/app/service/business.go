@@ -1,35 +1,76 @@
 package service
 
 import (
+	"context"
 	"time"
 
-	"github.com/ic3network/mccs-alpha/internal/app/repositories/es"
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
 	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/search"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-type business struct{}
+type business struct {
+	businesses BusinessRepository
+	searcher   search.BusinessSearcher
+	outbox     OutboxRepository
+}
+
+var Business = NewBusiness(mongo.Business, search.Business, mongo.Outbox)
 
-var Business = &business{}
+// NewBusiness returns the business service using the given repositories.
+func NewBusiness(
+	businesses BusinessRepository,
+	searcher search.BusinessSearcher,
+	outbox OutboxRepository,
+) *business {
+	return &business{businesses: businesses, searcher: searcher, outbox: outbox}
+}
 
-func (b *business) FindByID(id primitive.ObjectID) (*types.Business, error) {
-	bs, err := mongo.Business.FindByID(id)
+func (b *business) FindByID(
+	ctx context.Context,
+	id primitive.ObjectID,
+) (_ *types.Business, err error) {
+	ctx, span := tracing.Start(ctx, "BusinessService FindByID")
+	defer func() { tracing.End(span, err) }()
+
+	bs, err := b.businesses.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	return bs, nil
 }
 
-func (b *business) Create(
-	business *types.BusinessData,
-) (primitive.ObjectID, error) {
-	id, err := mongo.Business.Create(business)
+func (b *business) FindByIDs(ctx context.Context, ids []string) (_ []*types.Business, err error) {
+	ctx, span := tracing.Start(ctx, "BusinessService FindByIDs")
+	defer func() { tracing.End(span, err) }()
+
+	bs, err := b.businesses.FindByIDs(ctx, ids)
 	if err != nil {
-		return primitive.ObjectID{}, e.Wrap(err, "create business failed")
+		return nil, e.Wrap(err, "BusinessService FindByIDs failed")
 	}
-	err = es.Business.Create(id, business)
+	return bs, nil
+}
+
+func (b *business) Create(
+	ctx context.Context,
+	business *types.BusinessData,
+) (_ primitive.ObjectID, err error) {
+	ctx, span := tracing.Start(ctx, "BusinessService Create")
+	defer func() { tracing.End(span, err) }()
+
+	var id primitive.ObjectID
+	err = b.outbox.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		id, err = b.businesses.Create(ctx, business)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, b.outbox, "businesses", id)
+	})
 	if err != nil {
 		return primitive.ObjectID{}, e.Wrap(err, "create business failed")
 	}
@@ -37,23 +78,32 @@ func (b *business) Create(
 }
 
 func (b *business) UpdateBusiness(
+	ctx context.Context,
 	id primitive.ObjectID,
 	business *types.BusinessData,
 	isAdmin bool,
-) error {
-	err := es.Business.UpdateBusiness(id, business)
-	if err != nil {
-		return e.Wrap(err, "update business failed")
-	}
-	err = mongo.Business.UpdateBusiness(id, business, isAdmin)
+) (err error) {
+	ctx, span := tracing.Start(ctx, "BusinessService UpdateBusiness")
+	defer func() { tracing.End(span, err) }()
+
+	err = b.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := b.businesses.UpdateBusiness(ctx, id, business, isAdmin)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, b.outbox, "businesses", id)
+	})
 	if err != nil {
 		return e.Wrap(err, "update business failed")
 	}
 	return nil
 }
 
-func (b *business) SetMemberStartedAt(id primitive.ObjectID) error {
-	err := mongo.Business.SetMemberStartedAt(id)
+func (b *business) SetMemberStartedAt(ctx context.Context, id primitive.ObjectID) (err error) {
+	ctx, span := tracing.Start(ctx, "BusinessService SetMemberStartedAt")
+	defer func() { tracing.End(span, err) }()
+
+	err = b.businesses.SetMemberStartedAt(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -61,14 +111,20 @@ func (b *business) SetMemberStartedAt(id primitive.ObjectID) error {
 }
 
 func (b *business) UpdateAllTagsCreatedAt(
+	ctx context.Context,
 	id primitive.ObjectID,
 	t time.Time,
-) error {
-	err := es.Business.UpdateAllTagsCreatedAt(id, t)
-	if err != nil {
-		return e.Wrap(err, "BusinessService UpdateAllTagsCreatedAt failed")
-	}
-	err = mongo.Business.UpdateAllTagsCreatedAt(id, t)
+) (err error) {
+	ctx, span := tracing.Start(ctx, "BusinessService UpdateAllTagsCreatedAt")
+	defer func() { tracing.End(span, err) }()
+
+	err = b.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := b.businesses.UpdateAllTagsCreatedAt(ctx, id, t)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, b.outbox, "businesses", id)
+	})
 	if err != nil {
 		return e.Wrap(err, "BusinessService UpdateAllTagsCreatedAt failed")
 	}
@@ -76,78 +132,131 @@ func (b *business) UpdateAllTagsCreatedAt(
 }
 
 func (b *business) FindBusiness(
+	ctx context.Context,
 	c *types.SearchCriteria,
 	page int64,
-) (*types.FindBusinessResult, error) {
-	ids, numberOfResults, totalPages, err := es.Business.Find(c, page)
+) (_ *types.FindBusinessResult, err error) {
+	ctx, span := tracing.Start(ctx, "BusinessService FindBusiness")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := b.searcher.Find(ctx, c, page)
 	if err != nil {
 		return nil, e.Wrap(err, "BusinessService FindBusiness failed")
 	}
-	businesses, err := mongo.Business.FindByIDs(ids)
+	businesses, err := b.businesses.FindByIDs(ctx, result.IDs)
 	if err != nil {
 		return nil, e.Wrap(err, "BusinessService FindBusiness failed")
 	}
 	return &types.FindBusinessResult{
 		Businesses:      businesses,
-		NumberOfResults: numberOfResults,
-		TotalPages:      totalPages,
+		NumberOfResults: result.NumberOfResults,
+		TotalPages:      result.TotalPages,
+		Facets:          result.Facets,
 	}, nil
 }
 
-func (b *business) DeleteByID(id primitive.ObjectID) error {
-	err := es.Business.Delete(id.Hex())
+// FindSimilar returns the trading members sharing the most tags with the
+// criteria, the closest first.
+func (b *business) FindSimilar(
+	ctx context.Context,
+	c *types.SimilarCriteria,
+) (_ []*types.Business, err error) {
+	ctx, span := tracing.Start(ctx, "BusinessService FindSimilar")
+	defer func() { tracing.End(span, err) }()
+
+	ids, err := b.searcher.FindSimilar(ctx, c, global.Config().SimilarBusinessesSize)
 	if err != nil {
-		return e.Wrap(err, "delete business by id failed")
+		return nil, e.Wrap(err, "BusinessService FindSimilar failed")
+	}
+	if len(ids) == 0 {
+		return []*types.Business{}, nil
 	}
-	err = mongo.Business.DeleteByID(id)
+	businesses, err := b.businesses.FindByIDs(ctx, ids)
 	if err != nil {
-		return e.Wrap(err, "delete business by id failed")
+		return nil, e.Wrap(err, "BusinessService FindSimilar failed")
 	}
-	return nil
+	return businesses, nil
 }
 
-func (b *business) RenameTag(old string, new string) error {
-	err := es.Business.RenameTag(old, new)
+func (b *business) DeleteByID(ctx context.Context, id primitive.ObjectID) (err error) {
+	ctx, span := tracing.Start(ctx, "BusinessService DeleteByID")
+	defer func() { tracing.End(span, err) }()
+
+	err = b.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := b.businesses.DeleteByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, b.outbox, "businesses", id)
+	})
 	if err != nil {
-		return e.Wrap(err, "BusinessMongo RenameTag failed")
+		return e.Wrap(err, "delete business by id failed")
 	}
-	err = mongo.Business.RenameTag(old, new)
+	return nil
+}
+
+func (b *business) RenameTag(ctx context.Context, old string, new string) (err error) {
+	ctx, span := tracing.Start(ctx, "BusinessService RenameTag")
+	defer func() { tracing.End(span, err) }()
+
+	err = b.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := b.businesses.RenameTag(ctx, old, new)
+		if err != nil {
+			return err
+		}
+		return recordChange(ctx, b.outbox, "businesses", constant.Outbox.RenameTag, old, new)
+	})
 	if err != nil {
 		return e.Wrap(err, "BusinessMongo RenameTag failed")
 	}
 	return nil
 }
 
-func (b *business) RenameAdminTag(old string, new string) error {
-	err := es.Business.RenameAdminTag(old, new)
-	if err != nil {
-		return e.Wrap(err, "BusinessMongo RenameAdminTag failed")
-	}
-	err = mongo.Business.RenameAdminTag(old, new)
+func (b *business) RenameAdminTag(ctx context.Context, old string, new string) (err error) {
+	ctx, span := tracing.Start(ctx, "BusinessService RenameAdminTag")
+	defer func() { tracing.End(span, err) }()
+
+	err = b.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := b.businesses.RenameAdminTag(ctx, old, new)
+		if err != nil {
+			return err
+		}
+		return recordChange(ctx, b.outbox, "businesses", constant.Outbox.RenameAdminTag, old, new)
+	})
 	if err != nil {
 		return e.Wrap(err, "BusinessMongo RenameAdminTag failed")
 	}
 	return nil
 }
 
-func (b *business) DeleteTag(name string) error {
-	err := es.Business.DeleteTag(name)
-	if err != nil {
-		return e.Wrap(err, "BusinessMongo DeleteTag failed")
-	}
-	err = mongo.Business.DeleteTag(name)
+func (b *business) DeleteTag(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "BusinessService DeleteTag")
+	defer func() { tracing.End(span, err) }()
+
+	err = b.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := b.businesses.DeleteTag(ctx, name)
+		if err != nil {
+			return err
+		}
+		return recordChange(ctx, b.outbox, "businesses", constant.Outbox.DeleteTag, name)
+	})
 	if err != nil {
 		return e.Wrap(err, "BusinessMongo DeleteTag failed")
 	}
 	return nil
 }
 
-func (b *business) DeleteAdminTags(name string) error {
-	err := es.Business.DeleteAdminTags(name)
-	if err != nil {
-		return e.Wrap(err, "BusinessMongo DeleteAdminTags failed")
-	}
-	err = mongo.Business.DeleteAdminTags(name)
+func (b *business) DeleteAdminTags(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "BusinessService DeleteAdminTags")
+	defer func() { tracing.End(span, err) }()
+
+	err = b.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := b.businesses.DeleteAdminTags(ctx, name)
+		if err != nil {
+			return err
+		}
+		return recordChange(ctx, b.outbox, "businesses", constant.Outbox.DeleteAdminTag, name)
+	})
 	if err != nil {
 		return e.Wrap(err, "BusinessMongo DeleteAdminTags failed")
 	}
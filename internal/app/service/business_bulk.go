@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
+	"github.com/ic3network/mccs-alpha/internal/pkg/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ApplyBulkAction applies the admin change to the business like the admin
+// business update does: the balance has to stay within the limits it sets,
+// the tags of newly accepted businesses are saved and the first trading
+// acceptance sets the member start date.
+func (b *business) ApplyBulkAction(
+	ctx context.Context,
+	id primitive.ObjectID,
+	a *types.BulkAction,
+) (_ *types.BusinessChange, err error) {
+	ctx, span := tracing.Start(ctx, "BusinessService ApplyBulkAction")
+	defer func() { tracing.End(span, err) }()
+
+	old, err := b.businesses.FindByID(ctx, id)
+	if err != nil {
+		return nil, e.Wrap(err, "BusinessService ApplyBulkAction failed")
+	}
+	account, err := Account.FindByBusinessID(ctx, id.Hex())
+	if err != nil {
+		return nil, e.Wrap(err, "BusinessService ApplyBulkAction failed")
+	}
+	oldBalance, err := BalanceLimit.FindByAccountID(ctx, account.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "BusinessService ApplyBulkAction failed")
+	}
+
+	change := &types.BusinessChange{
+		OldBusiness: old,
+		NewBusiness: bulkBusinessData(old, a),
+		OldBalance:  oldBalance,
+		NewBalance: &types.BalanceLimit{
+			MaxPosBal: oldBalance.MaxPosBal,
+			MaxNegBal: oldBalance.MaxNegBal,
+		},
+	}
+	if a.MaxPosBal != nil {
+		change.NewBalance.MaxPosBal = math.Abs(*a.MaxPosBal)
+	}
+	if a.MaxNegBal != nil {
+		change.NewBalance.MaxNegBal = math.Abs(*a.MaxNegBal)
+	}
+	err = checkBulkLimits(account.Balance, a, change.NewBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.UpdateBusiness(ctx, id, change.NewBusiness, true)
+	if err != nil {
+		return nil, e.Wrap(err, "BusinessService ApplyBulkAction failed")
+	}
+	if a.MaxPosBal != nil || a.MaxNegBal != nil {
+		err := BalanceLimit.Update(
+			ctx,
+			account.ID,
+			change.NewBalance.MaxPosBal,
+			change.NewBalance.MaxNegBal,
+		)
+		if err != nil {
+			return nil, e.Wrap(err, "BusinessService ApplyBulkAction failed")
+		}
+	}
+
+	for _, tag := range a.AddAdminTags {
+		err := AdminTag.Create(ctx, tag)
+		if err != nil {
+			return nil, e.Wrap(err, "BusinessService ApplyBulkAction failed")
+		}
+	}
+	if !util.IsAcceptedStatus(old.Status) &&
+		util.IsAcceptedStatus(change.NewBusiness.Status) {
+		err := b.UpdateAllTagsCreatedAt(ctx, id, time.Now())
+		if err != nil {
+			return nil, e.Wrap(err, "BusinessService ApplyBulkAction failed")
+		}
+		for _, tag := range old.Offers {
+			err := Tag.UpdateOffer(ctx, tag.Name)
+			if err != nil {
+				return nil, e.Wrap(err, "BusinessService ApplyBulkAction failed")
+			}
+		}
+		for _, tag := range old.Wants {
+			err := Tag.UpdateWant(ctx, tag.Name)
+			if err != nil {
+				return nil, e.Wrap(err, "BusinessService ApplyBulkAction failed")
+			}
+		}
+	}
+	if old.MemberStartedAt.IsZero() &&
+		old.Status != constant.Trading.Accepted &&
+		change.NewBusiness.Status == constant.Trading.Accepted {
+		err := b.SetMemberStartedAt(ctx, id)
+		if err != nil {
+			return nil, e.Wrap(err, "BusinessService ApplyBulkAction failed")
+		}
+	}
+
+	return change, nil
+}
+
+// checkBulkLimits makes sure the balance stays within the limits the bulk
+// action sets. The limits the action doesn't change are not checked, so the
+// accounts already outside of them can still get a new status.
+func checkBulkLimits(
+	balance float64,
+	a *types.BulkAction,
+	limit *types.BalanceLimit,
+) error {
+	if a.MaxPosBal != nil && balance > limit.MaxPosBal {
+		return e.New(e.ExceedMaxPosBalance, fmt.Sprintf(
+			"The current account balance (%.2f) has exceed the max pos balance input",
+			balance,
+		))
+	}
+	if a.MaxNegBal != nil && balance < -limit.MaxNegBal {
+		return e.New(e.ExceedMaxNegBalance, fmt.Sprintf(
+			"The current account balance (%.2f) has exceed the max neg balance input",
+			balance,
+		))
+	}
+	return nil
+}
+
+// bulkBusinessData is the business with the status and admin tags of the
+// bulk action.
+func bulkBusinessData(old *types.Business, a *types.BulkAction) *types.BusinessData {
+	data := &types.BusinessData{
+		ID:                 old.ID,
+		BusinessName:       old.BusinessName,
+		IncType:            old.IncType,
+		CompanyNumber:      old.CompanyNumber,
+		BusinessPhone:      old.BusinessPhone,
+		Website:            old.Website,
+		Turnover:           old.Turnover,
+		Offers:             old.Offers,
+		Wants:              old.Wants,
+		Description:        old.Description,
+		LocationAddress:    old.LocationAddress,
+		LocationCity:       old.LocationCity,
+		LocationRegion:     old.LocationRegion,
+		LocationPostalCode: old.LocationPostalCode,
+		LocationCountry:    old.LocationCountry,
+		Status:             old.Status,
+	}
+	if a.Status != "" {
+		data.Status = a.Status
+	}
+
+	removed := map[string]bool{}
+	for _, tag := range a.RemoveAdminTags {
+		removed[helper.FormatAdminTag(tag)] = true
+	}
+	encountered := map[string]bool{}
+	data.AdminTags = []string{}
+	for _, tag := range append(append([]string{}, old.AdminTags...), a.AddAdminTags...) {
+		tag = helper.FormatAdminTag(tag)
+		if tag == "" || removed[tag] || encountered[tag] {
+			continue
+		}
+		encountered[tag] = true
+		data.AdminTags = append(data.AdminTags, tag)
+	}
+	return data
+}
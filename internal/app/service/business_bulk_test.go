@@ -0,0 +1,114 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkBusinessData(t *testing.T) {
+	old := &types.Business{
+		BusinessName: "Bakery",
+		Status:       constant.Trading.Pending,
+		AdminTags:    []string{"partner", "food"},
+	}
+
+	tests := []struct {
+		name              string
+		action            *types.BulkAction
+		expectedStatus    string
+		expectedAdminTags []string
+	}{
+		{
+			"keeps the business unchanged",
+			&types.BulkAction{},
+			constant.Trading.Pending,
+			[]string{"partner", "food"},
+		},
+		{
+			"changes the status",
+			&types.BulkAction{Status: constant.Trading.Accepted},
+			constant.Trading.Accepted,
+			[]string{"partner", "food"},
+		},
+		{
+			"adds and removes admin tags",
+			&types.BulkAction{
+				AddAdminTags:    []string{"local", "partner", "n3w"},
+				RemoveAdminTags: []string{"food"},
+			},
+			constant.Trading.Pending,
+			[]string{"partner", "local", "nw"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := bulkBusinessData(old, tt.action)
+			assert.Equal(t, "Bakery", data.BusinessName)
+			assert.Equal(t, tt.expectedStatus, data.Status)
+			assert.Equal(t, tt.expectedAdminTags, data.AdminTags)
+		})
+	}
+}
+
+func TestCheckBulkLimits(t *testing.T) {
+	limit := &types.BalanceLimit{MaxPosBal: 100, MaxNegBal: 50}
+	newLimit := 10.0
+
+	tests := []struct {
+		name     string
+		balance  float64
+		action   *types.BulkAction
+		expected int
+	}{
+		{
+			"status only, balance outside of the limits",
+			-80,
+			&types.BulkAction{Status: constant.Trading.Accepted},
+			-1,
+		},
+		{
+			"balance within the new limits",
+			5,
+			&types.BulkAction{MaxPosBal: &newLimit, MaxNegBal: &newLimit},
+			-1,
+		},
+		{
+			"balance above the new max pos balance",
+			20,
+			&types.BulkAction{MaxPosBal: &newLimit},
+			e.ExceedMaxPosBalance,
+		},
+		{
+			"balance below the new max neg balance",
+			-80,
+			&types.BulkAction{MaxNegBal: &newLimit},
+			e.ExceedMaxNegBalance,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action := *tt.action
+			limit := *limit
+			if action.MaxPosBal != nil {
+				limit.MaxPosBal = *action.MaxPosBal
+			}
+			if action.MaxNegBal != nil {
+				limit.MaxNegBal = *action.MaxNegBal
+			}
+			err := checkBulkLimits(tt.balance, &action, &limit)
+			if tt.expected < 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.IsType(t, e.Error{}, err)
+			assert.Equal(t, tt.expected, err.(e.Error).Code)
+		})
+	}
+}
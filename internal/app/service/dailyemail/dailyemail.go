@@ -1,40 +1,61 @@
 package dailyemail
 
 import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/service"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
 	"github.com/ic3network/mccs-alpha/internal/pkg/email"
 	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
 	"github.com/ic3network/mccs-alpha/internal/pkg/l"
-	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
 
-// Run performs the daily email notification.
-func Run() {
-	users, err := service.User.FindByDailyNotification()
+// Run performs the daily email notification. It fails when the users
+// cannot be found or when any of the emails could not be sent, and returns
+// the number of sent emails.
+// Once the context is done no more emails are queued, the ones being sent
+// are finished and the remaining users are notified on the next run.
+func Run(ctx context.Context) (int, error) {
+	users, err := service.User.FindByDailyNotification(ctx)
 	if err != nil {
-		l.Logger.Error("dailyemail failed", zap.Error(err))
-		return
+		return 0, e.Wrap(err, "dailyemail failed")
 	}
 
-	viper.SetDefault("concurrency_num", 1)
-	pool := NewPool(viper.GetInt("concurrency_num"))
+	pool := NewPool(global.Config().ConcurrencyNum)
 
+	var sent, failed int32
+	queued := 0
 	for _, user := range users {
-		worker := createEmailWorker(user)
+		if ctx.Err() != nil {
+			break
+		}
+		worker := createEmailWorker(ctx, user, &sent, &failed)
 		pool.Run(worker)
+		queued++
 	}
 
 	pool.Shutdown()
+
+	if queued < len(users) {
+		return int(sent), fmt.Errorf("dailyemail stopped after %d of %d users: %w", queued, len(users), ctx.Err())
+	}
+	if failed > 0 {
+		return int(sent), fmt.Errorf("dailyemail failed for %d of %d users", failed, len(users))
+	}
+	return int(sent), nil
 }
 
-func createEmailWorker(u *types.User) func() {
+func createEmailWorker(ctx context.Context, u *types.User, sent *int32, failed *int32) func() {
 	return func() {
-		matchedTags, err := getMatchTags(u)
+		matchedTags, err := getMatchTags(ctx, u)
 		if err != nil {
 			l.Logger.Error("dailyemail failed", zap.Error(err))
+			atomic.AddInt32(failed, 1)
 			return
 		}
 		if len(matchedTags.MatchedOffers) == 0 &&
@@ -44,21 +65,25 @@ func createEmailWorker(u *types.User) func() {
 		err = email.SendDailyEmailList(u, matchedTags)
 		if err != nil {
 			l.Logger.Error("dailyemail failed", zap.Error(err))
+			atomic.AddInt32(failed, 1)
+		} else {
+			atomic.AddInt32(sent, 1)
 		}
-		err = service.User.UpdateLastNotificationSentDate(u.ID)
+		err = service.User.UpdateLastNotificationSentDate(ctx, u.ID)
 		if err != nil {
 			l.Logger.Error("dailyemail failed", zap.Error(err))
 		}
 	}
 }
 
-func getMatchTags(user *types.User) (*types.MatchedTags, error) {
-	business, err := service.Business.FindByID(user.CompanyID)
+func getMatchTags(ctx context.Context, user *types.User) (*types.MatchedTags, error) {
+	business, err := service.Business.FindByID(ctx, user.CompanyID)
 	if err != nil {
 		return nil, e.Wrap(err, "getMatchTags failed")
 	}
 
 	matchedOffers, err := service.Tag.MatchOffers(
+		ctx,
 		helper.GetTagNames(business.Offers),
 		user.LastNotificationSentDate,
 	)
@@ -66,6 +91,7 @@ func getMatchTags(user *types.User) (*types.MatchedTags, error) {
 		return nil, e.Wrap(err, "getMatchTags failed")
 	}
 	matchedWants, err := service.Tag.MatchWants(
+		ctx,
 		helper.GetTagNames(business.Wants),
 		user.LastNotificationSentDate,
 	)
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/pg"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
+	"github.com/ic3network/mccs-alpha/internal/pkg/util"
+	"github.com/ic3network/mccs-alpha/internal/pkg/validator"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type directory struct{}
+
+var Directory = &directory{}
+
+// directoryStatuses are the statuses an imported business can have.
+var directoryStatuses = map[string]bool{
+	constant.Business.Pending:  true,
+	constant.Business.Accepted: true,
+	constant.Business.Rejected: true,
+	constant.Trading.Pending:   true,
+	constant.Trading.Accepted:  true,
+	constant.Trading.Rejected:  true,
+}
+
+// Import creates the business, user, account and balance limits of every
+// valid row. The invalid rows are reported with their errors.
+func (d *directory) Import(
+	ctx context.Context,
+	rows []*types.DirectoryRow,
+	opts *types.ImportOptions,
+) *types.ImportReport {
+	ctx, span := tracing.Start(ctx, "DirectoryService Import")
+	defer span.End()
+
+	report := &types.ImportReport{DryRun: opts.DryRun}
+	seen := map[string]bool{}
+	for i, row := range rows {
+		result := &types.ImportRowResult{
+			Row:          i + 1,
+			BusinessName: row.BusinessName,
+			Email:        strings.ToLower(row.Email),
+		}
+		if row.Line > 0 {
+			result.Row = row.Line
+		}
+		report.Rows = append(report.Rows, result)
+
+		data, errs := validateDirectoryRow(ctx, row, seen)
+		if len(errs) > 0 {
+			result.Errors = errs
+			report.Failed++
+			continue
+		}
+		report.Valid++
+		if opts.DryRun {
+			continue
+		}
+
+		bID, err := d.importRow(ctx, data, row)
+		if err != nil {
+			result.Errors = []string{err.Error()}
+			report.Failed++
+			continue
+		}
+		result.BusinessID = bID.Hex()
+		result.User = data.User
+		report.Created++
+
+		err = d.saveTags(ctx, bID, data.Business)
+		if err != nil {
+			result.Errors = []string{err.Error()}
+		}
+	}
+	return report
+}
+
+func validateDirectoryRow(
+	ctx context.Context,
+	row *types.DirectoryRow,
+	seen map[string]bool,
+) (*types.RegisterData, []string) {
+	password := row.Password
+	if password == "" {
+		// The user sets a password with the lost password page.
+		uid, _ := uuid.NewV4()
+		password = uid.String() + "!"
+	}
+	data := &types.RegisterData{
+		Business: &types.BusinessData{
+			BusinessName:       row.BusinessName,
+			IncType:            row.IncType,
+			CompanyNumber:      row.CompanyNumber,
+			BusinessPhone:      row.BusinessPhone,
+			Website:            row.Website,
+			Turnover:           row.Turnover,
+			Offers:             helper.GetTags(strings.Join(row.Offers, ",")),
+			Wants:              helper.GetTags(strings.Join(row.Wants, ",")),
+			Description:        row.Description,
+			LocationAddress:    row.LocationAddress,
+			LocationCity:       row.LocationCity,
+			LocationRegion:     row.LocationRegion,
+			LocationPostalCode: row.LocationPostalCode,
+			LocationCountry:    row.LocationCountry,
+			Status:             row.Status,
+		},
+		User: &types.User{
+			FirstName:         row.FirstName,
+			LastName:          row.LastName,
+			Email:             row.Email,
+			Telephone:         row.Telephone,
+			Password:          password,
+			DailyNotification: row.DailyNotification,
+		},
+		ConfirmPassword: password,
+		ConfirmEmail:    row.Email,
+		Terms:           "on",
+	}
+	for _, tag := range row.AdminTags {
+		tag = helper.FormatAdminTag(tag)
+		if tag != "" {
+			data.Business.AdminTags = append(data.Business.AdminTags, tag)
+		}
+	}
+	if data.Business.Status == "" {
+		data.Business.Status = constant.Business.Pending
+	}
+
+	errs := append([]string{}, row.ParseErrors...)
+	errs = append(errs, validator.Register(data)...)
+	if !directoryStatuses[data.Business.Status] {
+		errs = append(errs, "Status "+data.Business.Status+" is invalid.")
+	}
+	if row.MaxPosBal != nil && *row.MaxPosBal < 0 {
+		errs = append(errs, "Max pos balance cannot be negative.")
+	}
+	if row.MaxNegBal != nil && *row.MaxNegBal < 0 {
+		errs = append(errs, "Max neg balance cannot be negative.")
+	}
+	if data.User.Email != "" {
+		if seen[data.User.Email] {
+			errs = append(errs, "Email address is used by another row.")
+		} else if User.UserEmailExists(ctx, data.User.Email) {
+			errs = append(errs, "Email address is already registered.")
+		}
+		seen[data.User.Email] = true
+	}
+	return data, errs
+}
+
+// importRow creates the records of a row. The business, the user and the
+// account are deleted again when a later step fails.
+func (d *directory) importRow(
+	ctx context.Context,
+	data *types.RegisterData,
+	row *types.DirectoryRow,
+) (primitive.ObjectID, error) {
+	bID, err := Business.Create(ctx, data.Business)
+	if err != nil {
+		return primitive.ObjectID{}, e.Wrap(err, "DirectoryService Import failed")
+	}
+	if data.Business.Status != constant.Business.Pending ||
+		len(data.Business.AdminTags) > 0 {
+		err := Business.UpdateBusiness(ctx, bID, data.Business, true)
+		if err != nil {
+			d.rollback(ctx, bID, nil)
+			return primitive.ObjectID{}, e.Wrap(err, "DirectoryService Import failed")
+		}
+	}
+
+	data.User.CompanyID = bID
+	err = User.Create(ctx, data.User)
+	if err != nil {
+		d.rollback(ctx, bID, nil)
+		return primitive.ObjectID{}, e.Wrap(err, "DirectoryService Import failed")
+	}
+	if data.User.DailyNotification {
+		err := User.UpdateUserInfo(ctx, data.User)
+		if err != nil {
+			d.rollback(ctx, bID, data.User)
+			return primitive.ObjectID{}, e.Wrap(err, "DirectoryService Import failed")
+		}
+	}
+
+	err = Account.Create(ctx, bID.Hex())
+	if err != nil {
+		d.rollback(ctx, bID, data.User)
+		return primitive.ObjectID{}, e.Wrap(err, "DirectoryService Import failed")
+	}
+	if row.MaxPosBal != nil || row.MaxNegBal != nil {
+		err := d.updateBalanceLimit(ctx, bID, row)
+		if err != nil {
+			d.rollback(ctx, bID, data.User)
+			return primitive.ObjectID{}, e.Wrap(err, "DirectoryService Import failed")
+		}
+	}
+
+	return bID, nil
+}
+
+// saveTags follows the admin business update: the tags of accepted
+// businesses are added to the tag collections and the trading members get
+// their start date.
+func (d *directory) saveTags(
+	ctx context.Context,
+	bID primitive.ObjectID,
+	b *types.BusinessData,
+) error {
+	for _, tag := range b.AdminTags {
+		err := AdminTag.Create(ctx, tag)
+		if err != nil {
+			return e.Wrap(err, "DirectoryService saveTags failed")
+		}
+	}
+	if util.IsAcceptedStatus(b.Status) {
+		for _, tag := range b.Offers {
+			err := Tag.UpdateOffer(ctx, tag.Name)
+			if err != nil {
+				return e.Wrap(err, "DirectoryService saveTags failed")
+			}
+		}
+		for _, tag := range b.Wants {
+			err := Tag.UpdateWant(ctx, tag.Name)
+			if err != nil {
+				return e.Wrap(err, "DirectoryService saveTags failed")
+			}
+		}
+	}
+	if b.Status == constant.Trading.Accepted {
+		err := Business.SetMemberStartedAt(ctx, bID)
+		if err != nil {
+			return e.Wrap(err, "DirectoryService saveTags failed")
+		}
+	}
+	return nil
+}
+
+func (d *directory) updateBalanceLimit(
+	ctx context.Context,
+	bID primitive.ObjectID,
+	row *types.DirectoryRow,
+) error {
+	limit, err := BalanceLimit.FindByBusinessID(ctx, bID.Hex())
+	if err != nil {
+		return err
+	}
+	maxPosBal, maxNegBal := limit.MaxPosBal, limit.MaxNegBal
+	if row.MaxPosBal != nil {
+		maxPosBal = *row.MaxPosBal
+	}
+	if row.MaxNegBal != nil {
+		maxNegBal = *row.MaxNegBal
+	}
+	return BalanceLimit.Update(ctx, limit.AccountID, maxPosBal, maxNegBal)
+}
+
+func (d *directory) rollback(ctx context.Context, bID primitive.ObjectID, user *types.User) {
+	Account.DeleteByBusinessID(ctx, bID.Hex())
+	Business.DeleteByID(ctx, bID)
+	if user != nil && !user.ID.IsZero() {
+		User.DeleteByID(ctx, user.ID)
+	}
+}
+
+// Export returns every business of the directory with its user and
+// balance limits. The passwords are not exported.
+func (d *directory) Export(ctx context.Context) (_ []*types.DirectoryRow, err error) {
+	ctx, span := tracing.Start(ctx, "DirectoryService Export")
+	defer func() { tracing.End(span, err) }()
+
+	businesses, err := mongo.Business.FindAll(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "DirectoryService Export failed")
+	}
+	users, err := mongo.User.FindAll(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "DirectoryService Export failed")
+	}
+	balances, err := pg.Analytics.Balances(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "DirectoryService Export failed")
+	}
+
+	userByBusiness := make(map[primitive.ObjectID]*types.User, len(users))
+	for _, u := range users {
+		userByBusiness[u.CompanyID] = u
+	}
+	balanceByBusiness := make(map[string]*types.AccountBalance, len(balances))
+	for _, b := range balances {
+		balanceByBusiness[b.BusinessID] = b
+	}
+
+	rows := make([]*types.DirectoryRow, 0, len(businesses))
+	for _, b := range businesses {
+		row := &types.DirectoryRow{
+			BusinessName:       b.BusinessName,
+			IncType:            b.IncType,
+			CompanyNumber:      b.CompanyNumber,
+			BusinessPhone:      b.BusinessPhone,
+			Website:            b.Website,
+			Turnover:           b.Turnover,
+			Offers:             helper.GetTagNames(b.Offers),
+			Wants:              helper.GetTagNames(b.Wants),
+			Description:        b.Description,
+			LocationAddress:    b.LocationAddress,
+			LocationCity:       b.LocationCity,
+			LocationRegion:     b.LocationRegion,
+			LocationPostalCode: b.LocationPostalCode,
+			LocationCountry:    b.LocationCountry,
+			Status:             b.Status,
+			AdminTags:          b.AdminTags,
+		}
+		if u, ok := userByBusiness[b.ID]; ok {
+			row.FirstName = u.FirstName
+			row.LastName = u.LastName
+			row.Email = u.Email
+			row.Telephone = u.Telephone
+			row.DailyNotification = u.DailyNotification
+		}
+		if balance, ok := balanceByBusiness[b.ID.Hex()]; ok {
+			maxPosBal, maxNegBal := balance.MaxPosBal, balance.MaxNegBal
+			row.MaxPosBal = &maxPosBal
+			row.MaxNegBal = &maxNegBal
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,177 @@
+package esindexer
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/es"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/search"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"go.uber.org/zap"
+)
+
+const (
+	batchSize  = 100
+	maxBackoff = 5 * time.Minute
+	// maxAttempts gives up on an event after about an hour of retries.
+	maxAttempts = 20
+)
+
+// Start applies the outbox events to Elasticsearch until the context is
+// done. The batch being applied is finished first.
+func Start(ctx context.Context) {
+	ticker := time.NewTicker(global.Config().ESIndexerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			Run(ctx)
+		}
+	}
+}
+
+// Run applies the due outbox events, oldest first.
+// Once an event of a document fails or is waiting for its retry, the later
+// events of the same document are held back so they are applied in order.
+// The events given up on don't hold back the later ones.
+func Run(ctx context.Context) {
+	events, err := mongo.Outbox.FindPending(ctx, batchSize)
+	if err != nil {
+		l.Logger.Error("esindexer failed", zap.Error(err))
+		return
+	}
+	waiting, err := mongo.Outbox.FindWaiting(ctx)
+	if err != nil {
+		l.Logger.Error("esindexer failed", zap.Error(err))
+		return
+	}
+	events = append(events, waiting...)
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID.Hex() < events[j].ID.Hex()
+	})
+
+	now := time.Now()
+	blocked := map[string]bool{}
+	for _, event := range events {
+		if blocked[event.Key()] || blocked[event.Collection+"/"] {
+			continue
+		}
+		if event.NextAttemptAt.After(now) {
+			blocked[event.Key()] = true
+			continue
+		}
+
+		err := apply(ctx, event)
+		if err != nil {
+			blocked[event.Key()] = true
+			retry(ctx, event, err)
+			continue
+		}
+		err = mongo.Outbox.DeleteByID(ctx, event.ID)
+		if err != nil {
+			l.Logger.Error("esindexer failed", zap.Error(err))
+		}
+	}
+}
+
+// retry schedules the event again with an exponential backoff, or gives up
+// on it after maxAttempts.
+func retry(ctx context.Context, event *types.OutboxEvent, reason error) {
+	attempts := event.Attempts + 1
+	if attempts >= maxAttempts {
+		l.Logger.Error("esindexer gave up on event",
+			zap.String("key", event.Key()),
+			zap.String("action", event.Action),
+			zap.Int("attempts", attempts),
+			zap.Error(reason),
+		)
+		err := mongo.Outbox.DeadLetter(ctx, event.ID, attempts, reason.Error())
+		if err != nil {
+			l.Logger.Error("esindexer failed", zap.Error(err))
+		}
+		return
+	}
+
+	backoff := time.Duration(math.Pow(2, float64(attempts))) * time.Second
+	if backoff > maxBackoff || backoff <= 0 {
+		backoff = maxBackoff
+	}
+
+	l.Logger.Warn("esindexer applying event failed",
+		zap.String("key", event.Key()),
+		zap.String("action", event.Action),
+		zap.Int("attempts", attempts),
+		zap.Error(reason),
+	)
+
+	err := mongo.Outbox.Retry(ctx, event.ID, attempts, time.Now().Add(backoff), reason.Error())
+	if err != nil {
+		l.Logger.Error("esindexer failed", zap.Error(err))
+	}
+}
+
+func apply(ctx context.Context, event *types.OutboxEvent) error {
+	switch event.Action {
+	case constant.Outbox.Sync:
+		return sync(ctx, event.Collection, event.DocumentID)
+	case constant.Outbox.RenameTag:
+		return search.Business.RenameTag(ctx, event.Args[0], event.Args[1])
+	case constant.Outbox.RenameAdminTag:
+		return search.Business.RenameAdminTag(ctx, event.Args[0], event.Args[1])
+	case constant.Outbox.DeleteTag:
+		return search.Business.DeleteTag(ctx, event.Args[0])
+	case constant.Outbox.DeleteAdminTag:
+		return search.Business.DeleteAdminTags(ctx, event.Args[0])
+	}
+	return e.New(e.InternalServerError, "unknown outbox action "+event.Action)
+}
+
+// sync writes the latest version of the document to Elasticsearch, or
+// deletes its record when the document has been deleted.
+func sync(ctx context.Context, collection string, id string) error {
+	raw, err := mongo.Outbox.FindDocument(ctx, collection, id)
+	if err != nil {
+		return err
+	}
+	if raw == nil {
+		return remove(ctx, collection, id)
+	}
+	if _, err := raw.LookupErr("deletedAt"); err == nil {
+		return remove(ctx, collection, id)
+	}
+
+	_, record, err := es.NewRecord(collection, raw)
+	if err != nil {
+		return err
+	}
+	switch r := record.(type) {
+	case *types.BusinessESRecord:
+		return search.Business.Index(ctx, r)
+	case *types.UserESRecord:
+		return search.User.Index(ctx, r)
+	case *types.TagESRecord:
+		return search.Tag.Index(ctx, r)
+	}
+	return e.New(e.InternalServerError, "unknown outbox collection "+collection)
+}
+
+func remove(ctx context.Context, collection string, id string) error {
+	switch collection {
+	case "businesses":
+		return search.Business.Delete(ctx, id)
+	case "users":
+		return search.User.Delete(ctx, id)
+	case "tags":
+		return search.Tag.DeleteByID(ctx, id)
+	}
+	return e.New(e.InternalServerError, "unknown outbox collection "+collection)
+}
@@ -0,0 +1,375 @@
+package esverify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/es"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/olivere/elastic/v7"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// Drift describes an ES record that does not match its MongoDB document.
+type Drift struct {
+	Index string
+	ID    string
+	// Fields lists the differences as "field: mongo -> es".
+	Fields []string
+	// Missing means the record is not in ES.
+	Missing bool
+	// Orphaned means the record is in ES but the document is deleted or
+	// doesn't exist in MongoDB.
+	Orphaned bool
+}
+
+func (d *Drift) String() string {
+	switch {
+	case d.Missing:
+		return d.Index + "/" + d.ID + ": missing in es"
+	case d.Orphaned:
+		return d.Index + "/" + d.ID + ": not in mongo"
+	default:
+		return d.Index + "/" + d.ID + ": " + strings.Join(d.Fields, ", ")
+	}
+}
+
+// collection knows how to compare the expected ES record of a MongoDB
+// document with the stored one.
+type collection struct {
+	// name is both the MongoDB collection and the ES alias.
+	name string
+	diff func(expected interface{}, source json.RawMessage) ([]string, error)
+}
+
+var collections = []*collection{
+	{
+		name: "businesses",
+		diff: diffBusiness,
+	},
+	{
+		name: "users",
+		diff: diffUser,
+	},
+	{
+		name: "tags",
+		diff: diffTag,
+	},
+}
+
+// Run verifies all the ES indexes and repairs the drift when
+// "es_verify_repair" is enabled. It returns the number of drifted records.
+func Run(ctx context.Context) (int, error) {
+	drifts, err := Verify(ctx, global.Config().ESVerifyRepair)
+	if err != nil {
+		return 0, e.Wrap(err, "esverify failed")
+	}
+	for _, d := range drifts {
+		l.Logger.Warn("esverify found drift", zap.String("drift", d.String()))
+	}
+	l.Logger.Info("esverify finished", zap.Int("drifts", len(drifts)))
+	return len(drifts), nil
+}
+
+// Verify compares every MongoDB document with its ES record.
+// When repair is true, the drifted records are fixed with bulk requests.
+func Verify(ctx context.Context, repair bool) ([]*Drift, error) {
+	if global.Config().IsMemorySearch() {
+		return nil, e.New(e.InternalServerError, "esverify needs the elasticsearch search backend")
+	}
+	drifts := []*Drift{}
+	for _, c := range collections {
+		found, err := verify(ctx, c, repair)
+		if err != nil {
+			return nil, e.Wrap(err, "esverify Verify failed")
+		}
+		drifts = append(drifts, found...)
+	}
+	return drifts, nil
+}
+
+func verify(ctx context.Context, c *collection, repair bool) ([]*Drift, error) {
+	batchSize := global.Config().ES.BulkSize
+
+	// Don't incluse deleted item.
+	filter := bson.M{
+		"deletedAt": bson.M{"$exists": false},
+	}
+	cur, err := mongo.DB().Collection(c.name).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	drifts := []*Drift{}
+	seen := map[string]bool{}
+	expected := map[string]interface{}{}
+	ids := make([]string, 0, batchSize)
+
+	check := func() error {
+		if len(ids) == 0 {
+			return nil
+		}
+		found, err := compare(ctx, c, ids, expected)
+		if err != nil {
+			return err
+		}
+		drifts = append(drifts, found...)
+		ids = ids[:0]
+		return nil
+	}
+
+	for cur.Next(ctx) {
+		id, record, err := es.NewRecord(c.name, cur.Current)
+		if err != nil {
+			return nil, err
+		}
+		seen[id] = true
+		expected[id] = record
+		ids = append(ids, id)
+		if len(ids) >= batchSize {
+			if err := check(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	if err := check(); err != nil {
+		return nil, err
+	}
+
+	orphans, err := findOrphans(ctx, c, seen, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	drifts = append(drifts, orphans...)
+
+	if repair {
+		if err := fix(ctx, c, drifts, batchSize); err != nil {
+			return nil, err
+		}
+	}
+	return drifts, nil
+}
+
+// compare fetches the ES records of the ids and compares them with the
+// expected ones.
+func compare(
+	ctx context.Context,
+	c *collection,
+	ids []string,
+	expected map[string]interface{},
+) ([]*Drift, error) {
+	mget := es.Client().Mget()
+	for _, id := range ids {
+		mget.Add(elastic.NewMultiGetItem().Index(c.name).Id(id))
+	}
+	res, err := mget.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := []*Drift{}
+	for _, doc := range res.Docs {
+		if !doc.Found {
+			drifts = append(drifts, &Drift{Index: c.name, ID: doc.Id, Missing: true})
+			continue
+		}
+		fields, err := c.diff(expected[doc.Id], doc.Source)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) != 0 {
+			drifts = append(drifts, &Drift{Index: c.name, ID: doc.Id, Fields: fields})
+		}
+	}
+	return drifts, nil
+}
+
+// findOrphans scrolls through the ES index to find the records that
+// don't have a MongoDB document.
+func findOrphans(
+	ctx context.Context,
+	c *collection,
+	seen map[string]bool,
+	batchSize int,
+) ([]*Drift, error) {
+	drifts := []*Drift{}
+
+	scroll := es.Client().Scroll(c.name).Size(batchSize).FetchSource(false)
+	defer scroll.Clear(ctx)
+	for {
+		res, err := scroll.Do(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, hit := range res.Hits.Hits {
+			if !seen[hit.Id] {
+				drifts = append(drifts, &Drift{Index: c.name, ID: hit.Id, Orphaned: true})
+			}
+		}
+	}
+	return drifts, nil
+}
+
+// fix re-indexes the drifted records and deletes the orphaned ones.
+// MongoDB is read again right before the repair because the documents can
+// change during the verification: a document created after the MongoDB scan
+// looks orphaned and a drifted one may have been updated or deleted since.
+func fix(ctx context.Context, c *collection, drifts []*Drift, batchSize int) error {
+	bulk := es.Client().Bulk().Index(c.name)
+	flush := func() error {
+		if bulk.NumberOfActions() == 0 {
+			return nil
+		}
+		res, err := bulk.Do(ctx)
+		if err != nil {
+			return err
+		}
+		for _, failed := range res.Failed() {
+			if failed.Status == http.StatusNotFound {
+				continue
+			}
+			return e.New(e.InternalServerError, "esverify repair failed for "+failed.Id)
+		}
+		return nil
+	}
+
+	for start := 0; start < len(drifts); start += batchSize {
+		end := start + batchSize
+		if end > len(drifts) {
+			end = len(drifts)
+		}
+		current, err := findCurrent(ctx, c, drifts[start:end])
+		if err != nil {
+			return err
+		}
+		for _, d := range drifts[start:end] {
+			if record, ok := current[d.ID]; ok {
+				bulk.Add(elastic.NewBulkIndexRequest().Id(d.ID).Doc(record))
+			} else {
+				bulk.Add(elastic.NewBulkDeleteRequest().Id(d.ID))
+			}
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findCurrent reads the MongoDB documents of the drifts again and returns
+// their ES records by id. The ids without a document are left out.
+func findCurrent(ctx context.Context, c *collection, drifts []*Drift) (map[string]interface{}, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(drifts))
+	for _, d := range drifts {
+		objectID, err := primitive.ObjectIDFromHex(d.ID)
+		if err != nil {
+			// Not a MongoDB id, the record can only be an orphan.
+			continue
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	filter := bson.M{
+		"_id":       bson.M{"$in": objectIDs},
+		"deletedAt": bson.M{"$exists": false},
+	}
+	cur, err := mongo.DB().Collection(c.name).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	current := map[string]interface{}{}
+	for cur.Next(ctx) {
+		id, record, err := es.NewRecord(c.name, cur.Current)
+		if err != nil {
+			return nil, err
+		}
+		current[id] = record
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+func diffBusiness(expected interface{}, source json.RawMessage) ([]string, error) {
+	want := expected.(*types.BusinessESRecord)
+	got := types.BusinessESRecord{}
+	if err := json.Unmarshal(source, &got); err != nil {
+		return nil, err
+	}
+	fields := []string{}
+	fields = appendDiff(fields, "businessName", want.BusinessName, got.BusinessName)
+	fields = appendDiff(fields, "status", want.Status, got.Status)
+	fields = appendDiff(fields, "locationCity", want.LocationCity, got.LocationCity)
+	fields = appendDiff(fields, "locationCountry", want.LocationCountry, got.LocationCountry)
+	fields = appendDiff(fields, "offers", joinSorted(helper.GetTagNames(want.Offers)), joinSorted(helper.GetTagNames(got.Offers)))
+	fields = appendDiff(fields, "wants", joinSorted(helper.GetTagNames(want.Wants)), joinSorted(helper.GetTagNames(got.Wants)))
+	fields = appendDiff(fields, "adminTags", joinSorted(want.AdminTags), joinSorted(got.AdminTags))
+	return fields, nil
+}
+
+func diffUser(expected interface{}, source json.RawMessage) ([]string, error) {
+	want := expected.(*types.UserESRecord)
+	got := types.UserESRecord{}
+	if err := json.Unmarshal(source, &got); err != nil {
+		return nil, err
+	}
+	fields := []string{}
+	fields = appendDiff(fields, "firstName", want.FirstName, got.FirstName)
+	fields = appendDiff(fields, "lastName", want.LastName, got.LastName)
+	fields = appendDiff(fields, "email", want.Email, got.Email)
+	return fields, nil
+}
+
+func diffTag(expected interface{}, source json.RawMessage) ([]string, error) {
+	want := expected.(*types.TagESRecord)
+	got := types.TagESRecord{}
+	if err := json.Unmarshal(source, &got); err != nil {
+		return nil, err
+	}
+	fields := []string{}
+	fields = appendDiff(fields, "name", want.Name, got.Name)
+	fields = appendDiff(fields, "offerAddedAt", formatTime(want.OfferAddedAt), formatTime(got.OfferAddedAt))
+	fields = appendDiff(fields, "wantAddedAt", formatTime(want.WantAddedAt), formatTime(got.WantAddedAt))
+	return fields, nil
+}
+
+func appendDiff(fields []string, field, mongoValue, esValue string) []string {
+	if mongoValue == esValue {
+		return fields
+	}
+	return append(fields, field+": "+mongoValue+" -> "+esValue)
+}
+
+// formatTime formats the time at the millisecond precision MongoDB stores.
+func formatTime(t time.Time) string {
+	return t.UTC().Truncate(time.Millisecond).Format(time.RFC3339Nano)
+}
+
+func joinSorted(values []string) string {
+	sorted := make([]string, len(values))
+	copy(sorted, values)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
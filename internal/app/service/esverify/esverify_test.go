@@ -0,0 +1,66 @@
+package esverify
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffTag(t *testing.T) {
+	addedAt := time.Date(2020, 3, 1, 10, 0, 0, 123456789, time.UTC)
+	expected := &types.TagESRecord{
+		TagID:        "1",
+		Name:         "bread",
+		OfferAddedAt: addedAt,
+		WantAddedAt:  addedAt,
+	}
+
+	tests := []struct {
+		name     string
+		stored   types.TagESRecord
+		expected []string
+	}{
+		{
+			"should not report the record which matches",
+			types.TagESRecord{TagID: "1", Name: "bread", OfferAddedAt: addedAt, WantAddedAt: addedAt},
+			[]string{},
+		},
+		{
+			"should ignore the precision mongo doesn't store",
+			types.TagESRecord{
+				TagID:        "1",
+				Name:         "bread",
+				OfferAddedAt: addedAt.Truncate(time.Millisecond),
+				WantAddedAt:  addedAt.Truncate(time.Millisecond),
+			},
+			[]string{},
+		},
+		{
+			"should report the drifted dates",
+			types.TagESRecord{TagID: "1", Name: "bread", OfferAddedAt: addedAt.AddDate(0, 0, -1)},
+			[]string{
+				"offerAddedAt: 2020-03-01T10:00:00.123Z -> 2020-02-29T10:00:00.123Z",
+				"wantAddedAt: 2020-03-01T10:00:00.123Z -> 0001-01-01T00:00:00Z",
+			},
+		},
+		{
+			"should report the drifted name",
+			types.TagESRecord{TagID: "1", Name: "breads", OfferAddedAt: addedAt, WantAddedAt: addedAt},
+			[]string{"name: bread -> breads"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := json.Marshal(tt.stored)
+			require.NoError(t, err)
+			fields, err := diffTag(expected, source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, fields)
+		})
+	}
+}
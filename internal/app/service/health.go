@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/es"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/pg"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
+)
+
+type health struct{}
+
+var Health = &health{}
+
+// Readiness pings every store with "readiness_timeout". Elasticsearch is
+// not checked when the in-process search backend is used.
+func (h *health) Readiness(ctx context.Context) *types.Readiness {
+	ctx, span := tracing.Start(ctx, "HealthService Readiness")
+	defer span.End()
+
+	pings := map[string]func(context.Context) error{
+		"postgres": pg.Ping,
+		"mongo":    mongo.Ping,
+	}
+	if !global.Config().IsMemorySearch() {
+		pings["elasticsearch"] = es.Ping
+	}
+	return readiness(pings, global.Config().ReadinessTimeout)
+}
+
+// readiness runs the pings concurrently, each one with its own timeout.
+func readiness(
+	pings map[string]func(context.Context) error,
+	timeout time.Duration,
+) *types.Readiness {
+	result := &types.Readiness{
+		Status:       "ok",
+		Dependencies: make(map[string]*types.DependencyStatus, len(pings)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, ping := range pings {
+		wg.Add(1)
+		go func(name string, ping func(context.Context) error) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			startTime := time.Now()
+			err := ping(ctx)
+			status := &types.DependencyStatus{
+				Status:  "ok",
+				Latency: time.Since(startTime).Milliseconds(),
+			}
+			if err != nil {
+				status.Status = "unavailable"
+				status.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Dependencies[name] = status
+			if err != nil {
+				result.Status = "unavailable"
+			}
+		}(name, ping)
+	}
+	wg.Wait()
+
+	return result
+}
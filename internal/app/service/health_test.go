@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadiness(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	result := readiness(map[string]func(context.Context) error{
+		"postgres": ok,
+		"mongo":    ok,
+	}, time.Second)
+	assert.True(t, result.Ready())
+	assert.Equal(t, "ok", result.Dependencies["postgres"].Status)
+	assert.Equal(t, "ok", result.Dependencies["mongo"].Status)
+
+	result = readiness(map[string]func(context.Context) error{
+		"postgres":      ok,
+		"mongo":         down,
+		"elasticsearch": slow,
+	}, 10*time.Millisecond)
+	assert.False(t, result.Ready())
+	assert.Equal(t, "ok", result.Dependencies["postgres"].Status)
+	assert.Equal(t, "unavailable", result.Dependencies["mongo"].Status)
+	assert.Equal(t, "connection refused", result.Dependencies["mongo"].Error)
+	assert.Equal(t, "unavailable", result.Dependencies["elasticsearch"].Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), result.Dependencies["elasticsearch"].Error)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
+)
+
+type jobRun struct{}
+
+var JobRun = &jobRun{}
+
+// Latest returns the latest run of every job.
+func (j *jobRun) Latest(ctx context.Context) (_ map[string]*types.JobRun, err error) {
+	ctx, span := tracing.Start(ctx, "JobRunService Latest")
+	defer func() { tracing.End(span, err) }()
+
+	latest, err := mongo.JobRun.Latest(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "JobRunService Latest failed")
+	}
+	return latest, nil
+}
+
+// FindByJob returns the run history of the job, the latest first.
+func (j *jobRun) FindByJob(
+	ctx context.Context,
+	job string,
+	page int64,
+) (_ *types.FindJobRunResult, err error) {
+	ctx, span := tracing.Start(ctx, "JobRunService FindByJob")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := mongo.JobRun.FindByJob(ctx, job, page)
+	if err != nil {
+		return nil, e.Wrap(err, "JobRunService FindByJob failed")
+	}
+	return result, nil
+}
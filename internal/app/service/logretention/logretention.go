@@ -0,0 +1,148 @@
+package logretention
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"go.uber.org/zap"
+)
+
+// Run archives the user actions older than "user_action_retention.days"
+// to a gzipped JSON Lines file and deletes them once the file is stored.
+// The file is written to "user_action_retention.archive_dir", or uploaded
+// to GridFS when "user_action_retention.archive_to" is "gridfs". It returns
+// the number of archived user actions. Nothing is deleted when the
+// retention is 0.
+func Run(ctx context.Context) (int, error) {
+	cfg := global.Config().UserActionRetention
+	if cfg.Days <= 0 {
+		return 0, nil
+	}
+	now := time.Now()
+	cutoff := now.AddDate(0, 0, -cfg.Days)
+
+	dir := cfg.ArchiveDir
+	if cfg.ArchiveTo == "gridfs" {
+		tmp, err := os.MkdirTemp("", "user-actions")
+		if err != nil {
+			return 0, e.Wrap(err, "logretention failed")
+		}
+		defer os.RemoveAll(tmp)
+		dir = tmp
+	}
+
+	path, archived, err := archive(
+		dir,
+		now,
+		func(fn func(*types.UserAction) error) error {
+			return mongo.UserAction.ForEachBefore(ctx, cutoff, fn)
+		},
+	)
+	if err != nil {
+		return 0, e.Wrap(err, "logretention failed")
+	}
+	if archived == 0 {
+		return 0, nil
+	}
+	if cfg.ArchiveTo == "gridfs" {
+		path, err = mongo.UserActionArchive.Upload(ctx, path)
+		if err != nil {
+			return 0, e.Wrap(err, "logretention failed")
+		}
+	}
+
+	deleted, err := mongo.UserAction.DeleteBefore(ctx, cutoff)
+	if err != nil {
+		return archived, e.Wrap(err, "logretention failed")
+	}
+	if deleted != int64(archived) {
+		l.Logger.Warn(
+			"logretention deleted a different number of user actions than it archived",
+			zap.Int("archived", archived),
+			zap.Int64("deleted", deleted),
+		)
+	}
+	l.Logger.Info(
+		"logretention archived user actions",
+		zap.String("file", path),
+		zap.Int("archived", archived),
+		zap.Time("before", cutoff),
+	)
+	return archived, nil
+}
+
+// archive writes the user actions to a new file in dir. The file only
+// gets its final name once it is completely written, an empty archive is
+// removed.
+func archive(
+	dir string,
+	now time.Time,
+	forEach func(func(*types.UserAction) error) error,
+) (string, int, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return "", 0, err
+	}
+	path := filepath.Join(
+		dir,
+		fmt.Sprintf("user-actions-%s.jsonl.gz", now.UTC().Format("20060102T150405")),
+	)
+	tmp, err := os.CreateTemp(dir, ".user-actions-*")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	count, err := writeArchive(tmp, forEach)
+	if err != nil {
+		tmp.Close()
+		return "", 0, err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", 0, err
+	}
+	if count == 0 {
+		return "", 0, nil
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, err
+	}
+	return path, count, nil
+}
+
+// writeArchive writes the user actions to w as gzipped JSON Lines and
+// returns how many were written.
+func writeArchive(
+	w io.Writer,
+	forEach func(func(*types.UserAction) error) error,
+) (int, error) {
+	zw := gzip.NewWriter(w)
+	encoder := json.NewEncoder(zw)
+	count := 0
+	err := forEach(func(a *types.UserAction) error {
+		count++
+		return encoder.Encode(a)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := zw.Close(); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
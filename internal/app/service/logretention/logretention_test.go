@@ -0,0 +1,67 @@
+package logretention
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func actions(list ...*types.UserAction) func(func(*types.UserAction) error) error {
+	return func(fn func(*types.UserAction) error) error {
+		for _, a := range list {
+			if err := fn(a); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestWriteArchive(t *testing.T) {
+	var buf bytes.Buffer
+	count, err := writeArchive(&buf, actions(
+		&types.UserAction{Email: "a@example.com", Action: "user login successful"},
+		&types.UserAction{Email: "b@example.com", Action: "user transfer"},
+	))
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	zr, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	scanner := bufio.NewScanner(zr)
+	emails := []string{}
+	for scanner.Scan() {
+		var a types.UserAction
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &a))
+		emails = append(emails, a.Email)
+	}
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, emails)
+}
+
+func TestArchive(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, time.October, 19, 3, 30, 0, 0, time.UTC)
+
+	path, count, err := archive(dir, now, actions(&types.UserAction{Email: "a@example.com"}))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, filepath.Join(dir, "user-actions-20261019T033000.jsonl.gz"), path)
+
+	path, count, err = archive(dir, now.Add(time.Hour), actions())
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.Empty(t, path)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
@@ -1,57 +1,86 @@
 package service
 
 import (
+	"context"
 	"time"
 
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
-	"github.com/spf13/viper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 )
 
-type lostpassword struct{}
+type lostpassword struct {
+	lostPasswords LostPasswordRepository
+}
+
+var Lostpassword = NewLostpassword(mongo.LostPassword)
+
+// NewLostpassword returns the lost password service using the given
+// repository.
+func NewLostpassword(lostPasswords LostPasswordRepository) *lostpassword {
+	return &lostpassword{lostPasswords: lostPasswords}
+}
 
-var Lostpassword = &lostpassword{}
+func (s *lostpassword) Create(ctx context.Context, l *types.LostPassword) (err error) {
+	ctx, span := tracing.Start(ctx, "LostpasswordService Create")
+	defer func() { tracing.End(span, err) }()
 
-func (s *lostpassword) Create(l *types.LostPassword) error {
-	err := mongo.LostPassword.Create(l)
+	err = s.lostPasswords.Create(ctx, l)
 	if err != nil {
 		return e.Wrap(err, "Create failed")
 	}
 	return nil
 }
 
-func (s *lostpassword) FindByToken(token string) (*types.LostPassword, error) {
-	lostPassword, err := mongo.LostPassword.FindByToken(token)
+func (s *lostpassword) FindByToken(
+	ctx context.Context,
+	token string,
+) (_ *types.LostPassword, err error) {
+	ctx, span := tracing.Start(ctx, "LostpasswordService FindByToken")
+	defer func() { tracing.End(span, err) }()
+
+	lostPassword, err := s.lostPasswords.FindByToken(ctx, token)
 	if err != nil {
 		return nil, e.Wrap(err, "FindByToken failed")
 	}
 	return lostPassword, nil
 }
 
-func (s *lostpassword) FindByEmail(email string) (*types.LostPassword, error) {
-	lostPassword, err := mongo.LostPassword.FindByEmail(email)
+func (s *lostpassword) FindByEmail(
+	ctx context.Context,
+	email string,
+) (_ *types.LostPassword, err error) {
+	ctx, span := tracing.Start(ctx, "LostpasswordService FindByEmail")
+	defer func() { tracing.End(span, err) }()
+
+	lostPassword, err := s.lostPasswords.FindByEmail(ctx, email)
 	if err != nil {
 		return nil, e.Wrap(err, "FindByEmail failed")
 	}
 	return lostPassword, nil
 }
 
-func (s *lostpassword) SetTokenUsed(token string) error {
-	err := mongo.LostPassword.SetTokenUsed(token)
+func (s *lostpassword) SetTokenUsed(ctx context.Context, token string) (err error) {
+	ctx, span := tracing.Start(ctx, "LostpasswordService SetTokenUsed")
+	defer func() { tracing.End(span, err) }()
+
+	err = s.lostPasswords.SetTokenUsed(ctx, token)
 	if err != nil {
 		return e.Wrap(err, "SetTokenUsed failed")
 	}
 	return nil
 }
 
-func (s *lostpassword) TokenInvalid(l *types.LostPassword) bool {
+func (s *lostpassword) TokenInvalid(ctx context.Context, l *types.LostPassword) bool {
+	ctx, span := tracing.Start(ctx, "LostpasswordService TokenInvalid")
+	defer span.End()
+
 	if time.Now().
 		Sub(l.CreatedAt).
 		Seconds() >=
-		viper.GetFloat64(
-			"reset_password_timeout",
-		) ||
+		float64(global.Config().ResetPasswordTimeout) ||
 		l.TokenUsed == true {
 		return true
 	}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type outbox struct{}
+
+var Outbox = &outbox{}
+
+func (o *outbox) Stats(ctx context.Context) (_ *types.OutboxStats, err error) {
+	ctx, span := tracing.Start(ctx, "OutboxService Stats")
+	defer func() { tracing.End(span, err) }()
+
+	stats, err := mongo.Outbox.Stats(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "OutboxService Stats failed")
+	}
+	return stats, nil
+}
+
+// recordSync records that the document has been written to MongoDB.
+// The indexer re-reads the document before writing it to Elasticsearch,
+// so applying the same event twice is harmless.
+func recordSync(
+	ctx context.Context,
+	o OutboxRepository,
+	collection string,
+	id primitive.ObjectID,
+) error {
+	return o.Add(ctx, &types.OutboxEvent{
+		Collection: collection,
+		DocumentID: id.Hex(),
+		Action:     constant.Outbox.Sync,
+	})
+}
+
+// recordChange records a change across the whole collection,
+// e.g. renaming a tag in every business.
+func recordChange(
+	ctx context.Context,
+	o OutboxRepository,
+	collection string,
+	action string,
+	args ...string,
+) error {
+	return o.Add(ctx, &types.OutboxEvent{
+		Collection: collection,
+		Action:     action,
+		Args:       args,
+	})
+}
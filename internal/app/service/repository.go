@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// The repositories used by the services built with a constructor. pg, mongo
+// and search implement them for the app, repositories/memory for the tests.
+
+// AccountRepository stores the ledger accounts.
+type AccountRepository interface {
+	Create(ctx context.Context, bID string) error
+	FindByID(ctx context.Context, accountID uint) (*types.Account, error)
+	FindByBusinessID(ctx context.Context, businessID string) (*types.Account, error)
+	DeleteByBusinessID(ctx context.Context, bID string) error
+}
+
+// BalanceLimitRepository stores the balance limits of the accounts.
+type BalanceLimitRepository interface {
+	FindByAccountID(ctx context.Context, accountID uint) (*types.BalanceLimit, error)
+	Update(ctx context.Context, id uint, maxPosBal float64, maxNegBal float64) error
+}
+
+// TransactionRepository stores the journals and postings of the transfers.
+type TransactionRepository interface {
+	Propose(
+		ctx context.Context,
+		initiatedBy uint,
+		fromID uint,
+		fromEmail string,
+		fromBusinessName string,
+		toID uint,
+		toEmail string,
+		toBusinessName string,
+		amount float64,
+		desc string,
+	) (*types.Transaction, error)
+	Find(ctx context.Context, transactionID uint) (*types.Transaction, error)
+	Cancel(ctx context.Context, transactionID uint, reason string) error
+	Accept(
+		ctx context.Context,
+		transactionID uint,
+		fromID uint,
+		toID uint,
+		amount float64,
+	) error
+	FindPendings(ctx context.Context, id uint) ([]*types.Transaction, error)
+	FindRecent(ctx context.Context, id uint) ([]*types.Transaction, error)
+	FindInRange(
+		ctx context.Context,
+		id uint,
+		dateFrom time.Time,
+		dateTo time.Time,
+		page int,
+	) ([]*types.Transaction, int, error)
+}
+
+// UserRepository stores the users.
+type UserRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*types.User, error)
+	FindByEmail(ctx context.Context, email string) (*types.User, error)
+	FindByBusinessID(ctx context.Context, id primitive.ObjectID) (*types.User, error)
+	FindByIDs(ctx context.Context, ids []string) ([]*types.User, error)
+	FindByDailyNotification(ctx context.Context) ([]*types.User, error)
+	Create(ctx context.Context, user *types.User) error
+	UpdatePassword(ctx context.Context, user *types.User) error
+	UpdateUserInfo(ctx context.Context, user *types.User) error
+	AdminUpdateUser(ctx context.Context, user *types.User) error
+	UpdateLoginAttempts(ctx context.Context, email string, attempts int, lockUser bool) error
+	GetLoginInfo(ctx context.Context, id primitive.ObjectID) (*types.LoginInfo, error)
+	UpdateLoginInfo(ctx context.Context, id primitive.ObjectID, i *types.LoginInfo) error
+	UpdateLastNotificationSentDate(ctx context.Context, id primitive.ObjectID) error
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	ToggleShowRecentMatchedTags(ctx context.Context, id primitive.ObjectID) error
+	AddToFavoriteBusinesses(ctx context.Context, uID, bID primitive.ObjectID) error
+	RemoveFromFavoriteBusinesses(ctx context.Context, uID, bID primitive.ObjectID) error
+	UpdateTradingInfo(ctx context.Context, id primitive.ObjectID, data *types.TradingRegisterData) error
+}
+
+// BusinessRepository stores the businesses.
+type BusinessRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*types.Business, error)
+	FindByIDs(ctx context.Context, ids []string) ([]*types.Business, error)
+	Create(ctx context.Context, data *types.BusinessData) (primitive.ObjectID, error)
+	UpdateBusiness(
+		ctx context.Context,
+		id primitive.ObjectID,
+		data *types.BusinessData,
+		isAdmin bool,
+	) error
+	UpdateTradingInfo(ctx context.Context, id primitive.ObjectID, data *types.TradingRegisterData) error
+	SetMemberStartedAt(ctx context.Context, id primitive.ObjectID) error
+	UpdateAllTagsCreatedAt(ctx context.Context, id primitive.ObjectID, t time.Time) error
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	RenameTag(ctx context.Context, old string, new string) error
+	RenameAdminTag(ctx context.Context, old string, new string) error
+	DeleteTag(ctx context.Context, name string) error
+	DeleteAdminTags(ctx context.Context, name string) error
+}
+
+// TradingApplicationRepository stores the trading membership applications.
+type TradingApplicationRepository interface {
+	Submit(
+		ctx context.Context,
+		a *types.TradingApplication,
+		event *types.TradingApplicationEvent,
+	) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*types.TradingApplication, error)
+	FindOpenByBusinessID(ctx context.Context, id primitive.ObjectID) (*types.TradingApplication, error)
+	FindByBusinessID(ctx context.Context, id primitive.ObjectID) ([]*types.TradingApplication, error)
+	Find(
+		ctx context.Context,
+		statuses []string,
+		page int64,
+	) (*types.FindTradingApplicationResult, error)
+	AddNote(ctx context.Context, id primitive.ObjectID, note *types.TradingApplicationNote) error
+	Decide(
+		ctx context.Context,
+		id primitive.ObjectID,
+		status string,
+		event *types.TradingApplicationEvent,
+	) error
+	Reopen(ctx context.Context, id primitive.ObjectID, status string) error
+}
+
+// AgreementRepository stores the versions of the membership agreement.
+type AgreementRepository interface {
+	Publish(ctx context.Context, agreement *types.Agreement) (*types.Agreement, error)
+	FindLatest(ctx context.Context) (*types.Agreement, error)
+	FindByVersion(ctx context.Context, version int) (*types.Agreement, error)
+	FindAll(ctx context.Context) ([]*types.Agreement, error)
+}
+
+// AgreementAcceptanceRepository stores who accepted which version.
+type AgreementAcceptanceRepository interface {
+	Create(ctx context.Context, acceptance *types.AgreementAcceptance) error
+	HasAccepted(ctx context.Context, userID primitive.ObjectID, version int) (bool, error)
+	CountByVersion(ctx context.Context) (map[int]int, error)
+	FindByVersion(
+		ctx context.Context,
+		version int,
+		page int64,
+	) (*types.FindAgreementAcceptanceResult, error)
+}
+
+// AdminUserRepository stores the admin users.
+type AdminUserRepository interface {
+	FindByEmail(ctx context.Context, email string) (*types.AdminUser, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*types.AdminUser, error)
+	GetLoginInfo(ctx context.Context, id primitive.ObjectID) (*types.LoginInfo, error)
+	UpdateLoginInfo(ctx context.Context, id primitive.ObjectID, i *types.LoginInfo) error
+}
+
+// LostPasswordRepository stores the password reset tokens.
+type LostPasswordRepository interface {
+	Create(ctx context.Context, lostPassword *types.LostPassword) error
+	FindByToken(ctx context.Context, token string) (*types.LostPassword, error)
+	FindByEmail(ctx context.Context, email string) (*types.LostPassword, error)
+	SetTokenUsed(ctx context.Context, token string) error
+}
+
+// UserActionRepository stores the user action log.
+type UserActionRepository interface {
+	Log(ctx context.Context, a *types.UserAction) error
+	Find(
+		ctx context.Context,
+		c *types.UserActionSearchCriteria,
+		page int64,
+	) ([]*types.UserAction, int, error)
+	Actions(ctx context.Context) ([]string, error)
+}
+
+// AuditEventRepository stores the audit trail of the admin changes.
+type AuditEventRepository interface {
+	Create(ctx context.Context, event *types.AuditEvent) error
+	Find(
+		ctx context.Context,
+		c *types.AuditSearchCriteria,
+		page int64,
+	) ([]*types.AuditEvent, int, error)
+	ForEach(ctx context.Context, c *types.AuditSearchCriteria, fn func(*types.AuditEvent) error) error
+}
+
+// AdminTagRepository stores the admin tags.
+type AdminTagRepository interface {
+	Create(ctx context.Context, name string) error
+	FindByName(ctx context.Context, name string) (*types.AdminTag, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*types.AdminTag, error)
+	FindTags(ctx context.Context, name string, page int64) (*types.FindAdminTagResult, error)
+	TagStartWith(ctx context.Context, prefix string) ([]string, error)
+	GetAll(ctx context.Context) ([]*types.AdminTag, error)
+	Update(ctx context.Context, t *types.AdminTag) error
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+}
+
+// TagRepository stores the offer and want tags.
+type TagRepository interface {
+	Create(ctx context.Context, name string) (primitive.ObjectID, error)
+	UpdateOffer(ctx context.Context, name string) (primitive.ObjectID, error)
+	UpdateWant(ctx context.Context, name string) (primitive.ObjectID, error)
+	FindByName(ctx context.Context, name string) (*types.Tag, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*types.Tag, error)
+	FindTags(ctx context.Context, name string, page int64) (*types.FindTagResult, error)
+	Rename(ctx context.Context, tag *types.Tag) error
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+}
+
+// OutboxRepository records the changes the indexer copies to Elasticsearch.
+type OutboxRepository interface {
+	Add(ctx context.Context, event *types.OutboxEvent) error
+	// WithTransaction runs fn in a transaction, the writes fn makes with
+	// its ctx are committed together with their outbox events.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,276 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/ic3network/mccs-alpha/internal/pkg/metrics"
+	"github.com/robfig/cron"
+	"go.uber.org/zap"
+)
+
+// Job is a background job. Run returns the number of processed items.
+type Job struct {
+	Name        string
+	Description string
+	// Schedule is the cron spec, the job only runs manually when it is empty.
+	Schedule string
+	Run      func(ctx context.Context) (int, error)
+}
+
+var (
+	mu   sync.Mutex
+	jobs []*Job
+	c    *cron.Cron
+	// stopped is set once the shutdown starts, the jobs are not scheduled
+	// again after it.
+	stopped bool
+	// ctx is done once the shutdown starts.
+	ctx     = context.Background()
+	running = &tracker{}
+	// owner identifies this instance in the job locks.
+	owner = hostname()
+)
+
+func hostname() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s/%d", host, os.Getpid())
+}
+
+// Register adds a job. It must be called before Start.
+func Register(job *Job) {
+	mu.Lock()
+	defer mu.Unlock()
+	jobs = append(jobs, job)
+}
+
+// Jobs returns the registered jobs.
+func Jobs() []*Job {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]*Job{}, jobs...)
+}
+
+// Find returns the registered job with the name.
+func Find(name string) (*Job, error) {
+	for _, job := range Jobs() {
+		if job.Name == name {
+			return job, nil
+		}
+	}
+	return nil, e.New(e.JobNotFound, "job not found")
+}
+
+// Start schedules the jobs. Every instance schedules them, the job lock
+// makes sure only one of them runs each scheduled time.
+// The context passed to the jobs is done once the shutdown starts.
+func Start(parent context.Context) {
+	mu.Lock()
+	defer mu.Unlock()
+	ctx = parent
+	schedule()
+}
+
+// Reschedule changes the schedules of the jobs by name, e.g. after a config
+// reload. The running jobs are not interrupted.
+func Reschedule(schedules map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	changed := false
+	for i, job := range jobs {
+		spec, ok := schedules[job.Name]
+		if !ok || spec == job.Schedule {
+			continue
+		}
+		// The job is copied, so the callers of Jobs keep a consistent one.
+		updated := *job
+		updated.Schedule = spec
+		jobs[i] = &updated
+		changed = true
+		l.Logger.Info("job rescheduled",
+			zap.String("job", job.Name),
+			zap.String("schedule", spec))
+	}
+	if !changed || c == nil || stopped {
+		return
+	}
+	c.Stop()
+	schedule()
+}
+
+// schedule starts a cron with the scheduled jobs. mu must be held.
+func schedule() {
+	c = cron.New()
+	for _, job := range jobs {
+		if job.Schedule == "" {
+			continue
+		}
+		job := job
+		spec, err := cron.Parse(job.Schedule)
+		if err != nil {
+			l.Logger.Error("scheduling job failed",
+				zap.String("job", job.Name),
+				zap.Error(err))
+			continue
+		}
+		slots := newSlots(spec, time.Now())
+		c.Schedule(spec, cron.FuncJob(func() {
+			runScheduled(job, slots.fired(time.Now()))
+		}))
+	}
+	c.Start()
+}
+
+// Stop stops scheduling the jobs and waits for the running ones until the
+// context is done.
+func Stop(ctx context.Context) error {
+	mu.Lock()
+	if c != nil {
+		c.Stop()
+	}
+	stopped = true
+	mu.Unlock()
+	return running.wait(ctx)
+}
+
+// Trigger starts a manual run of the job in the background. It fails when
+// the job is already running.
+func Trigger(name string, triggeredBy string) error {
+	job, err := Find(name)
+	if err != nil {
+		return err
+	}
+	if !running.add() {
+		return e.New(e.JobRunning, "the app is shutting down")
+	}
+	locked, err := mongo.JobLock.Acquire(ctx, job.Name, owner, "", lockTTL())
+	if err != nil {
+		running.done()
+		return e.Wrap(err, "scheduler Trigger failed")
+	}
+	if !locked {
+		running.done()
+		return e.New(e.JobRunning, "job is running")
+	}
+
+	go func() {
+		defer running.done()
+		err := execute(job, constant.JobTrigger.Manual, triggeredBy)
+		if err != nil {
+			l.Logger.Error(job.Name+" failed", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// runScheduled runs the job unless another instance has already run it for
+// the same scheduled time.
+func runScheduled(job *Job, scheduled time.Time) {
+	if !running.add() {
+		return
+	}
+	defer running.done()
+
+	slot := scheduled.UTC().Format(time.RFC3339)
+	locked, err := mongo.JobLock.Acquire(ctx, job.Name, owner, slot, lockTTL())
+	if err != nil {
+		l.Logger.Error(job.Name+" failed", zap.Error(err))
+		return
+	}
+	if !locked {
+		l.Logger.Info("job skipped, it is running or has run on another instance",
+			zap.String("job", job.Name))
+		return
+	}
+
+	err = execute(job, constant.JobTrigger.Scheduled, "")
+	if err != nil {
+		l.Logger.Error(job.Name+" failed", zap.Error(err))
+	}
+}
+
+// execute runs the job while holding its lock and records the run.
+func execute(job *Job, trigger string, triggeredBy string) error {
+	defer func() {
+		err := mongo.JobLock.Release(ctx, job.Name, owner)
+		if err != nil {
+			l.Logger.Error("releasing job lock failed", zap.Error(err))
+		}
+	}()
+	stopHeartbeat := heartbeat(job.Name)
+	defer stopHeartbeat()
+
+	l.Logger.Info("running job",
+		zap.String("job", job.Name),
+		zap.String("trigger", trigger))
+
+	run := &types.JobRun{
+		Job:         job.Name,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		Host:        owner,
+	}
+	err := mongo.JobRun.Create(ctx, run)
+	if err != nil {
+		l.Logger.Error("recording job run failed", zap.Error(err))
+	}
+
+	err = metrics.Job(job.Name, func() error {
+		var err error
+		run.Items, err = job.Run(ctx)
+		return err
+	})
+	run.Status = constant.JobStatus.Succeeded
+	if err != nil {
+		run.Status = constant.JobStatus.Failed
+		run.Error = err.Error()
+	}
+
+	if !run.ID.IsZero() {
+		ferr := mongo.JobRun.Finish(ctx, run)
+		if ferr != nil {
+			l.Logger.Error("recording job run failed", zap.Error(ferr))
+		}
+	}
+	return err
+}
+
+// heartbeat extends the lock while the job is running longer than the ttl.
+func heartbeat(job string) func() {
+	ttl := lockTTL()
+	ticker := time.NewTicker(ttl / 3)
+	done := make(chan struct{})
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				held, err := mongo.JobLock.Extend(ctx, job, owner, ttl)
+				if err != nil {
+					l.Logger.Error("extending job lock failed", zap.Error(err))
+				} else if !held {
+					l.Logger.Warn("job lock lost", zap.String("job", job))
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func lockTTL() time.Duration {
+	return global.Config().JobLockTTL
+}
@@ -0,0 +1,29 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReschedule(t *testing.T) {
+	defer func() { jobs = nil }()
+	Register(&Job{Name: "dailyemail", Schedule: "0 0 7 * * *"})
+	Register(&Job{Name: "esverify"})
+	before, err := Find("dailyemail")
+	require.NoError(t, err)
+
+	Reschedule(map[string]string{
+		"dailyemail": "0 0 9 * * *",
+		"unknown":    "0 0 1 * * *",
+	})
+
+	after, err := Find("dailyemail")
+	require.NoError(t, err)
+	assert.Equal(t, "0 0 9 * * *", after.Schedule)
+	assert.Equal(t, "0 0 7 * * *", before.Schedule, "the job is copied")
+	manual, err := Find("esverify")
+	require.NoError(t, err)
+	assert.Equal(t, "", manual.Schedule)
+}
@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// slots computes the scheduled times the job fires at. The replicas derive
+// the same times from the cron spec, so their lock slots match even when
+// their clocks drift apart.
+type slots struct {
+	mu       sync.Mutex
+	schedule cron.Schedule
+	next     time.Time
+}
+
+func newSlots(schedule cron.Schedule, now time.Time) *slots {
+	return &slots{schedule: schedule, next: schedule.Next(now)}
+}
+
+// fired returns the scheduled time of the tick that fired at now. The
+// ticks missed while the instance was busy are skipped, like cron does.
+func (s *slots) fired(now time.Time) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot := s.next
+	for {
+		next := s.schedule.Next(slot)
+		if next.After(now) {
+			break
+		}
+		slot = next
+	}
+	s.next = s.schedule.Next(slot)
+	return slot
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlotsFired(t *testing.T) {
+	schedule, err := cron.Parse("0 0 * * * *")
+	require.NoError(t, err)
+	start := time.Date(2020, 1, 1, 9, 30, 0, 0, time.UTC)
+	hour := func(h int) time.Time {
+		return time.Date(2020, 1, 1, h, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		fired    []time.Time
+		expected []time.Time
+	}{
+		{
+			"should use the scheduled time",
+			[]time.Time{hour(10).Add(30 * time.Millisecond), hour(11)},
+			[]time.Time{hour(10), hour(11)},
+		},
+		{
+			"should keep the scheduled time of a late tick",
+			[]time.Time{hour(10).Add(90 * time.Second)},
+			[]time.Time{hour(10)},
+		},
+		{
+			"should skip the missed ticks",
+			[]time.Time{hour(12).Add(time.Second), hour(13)},
+			[]time.Time{hour(12), hour(13)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSlots(schedule, start)
+			for i, now := range tt.fired {
+				assert.Equal(t, tt.expected[i], s.fired(now))
+			}
+		})
+	}
+}
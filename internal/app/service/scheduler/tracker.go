@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+)
+
+// tracker keeps track of the running jobs so the shutdown can wait for
+// them.
+type tracker struct {
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	stopped bool
+}
+
+// add registers a new run, it returns false once the shutdown has started.
+// Every successful add must be followed by done.
+func (t *tracker) add() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return false
+	}
+	t.wg.Add(1)
+	return true
+}
+
+func (t *tracker) done() {
+	t.wg.Done()
+}
+
+// wait refuses the new runs and waits for the running jobs until the
+// context is done.
+func (t *tracker) wait(ctx context.Context) error {
+	t.mu.Lock()
+	t.stopped = true
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,22 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackerWait(t *testing.T) {
+	tr := &tracker{}
+	assert.True(t, tr.add())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, tr.wait(ctx))
+	assert.False(t, tr.add(), "no new runs once the shutdown has started")
+
+	tr.done()
+	assert.NoError(t, tr.wait(context.Background()))
+}
@@ -1,25 +1,54 @@
 package service
 
 import (
+	"context"
+	"strings"
 	"time"
 
-	"github.com/ic3network/mccs-alpha/internal/app/repositories/es"
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/search"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-type tag struct{}
-
-var Tag = &tag{}
+type tag struct {
+	tags       TagRepository
+	businesses search.BusinessSearcher
+	matcher    search.TagSearcher
+	outbox     OutboxRepository
+}
 
-func (t *tag) Create(name string) error {
-	id, err := mongo.Tag.Create(name)
-	if err != nil {
-		return e.Wrap(err, "TagService Create failed")
+var Tag = NewTag(mongo.Tag, search.Business, search.Tag, mongo.Outbox)
+
+// NewTag returns the tag service using the given repositories.
+func NewTag(
+	tags TagRepository,
+	businesses search.BusinessSearcher,
+	matcher search.TagSearcher,
+	outbox OutboxRepository,
+) *tag {
+	return &tag{
+		tags:       tags,
+		businesses: businesses,
+		matcher:    matcher,
+		outbox:     outbox,
 	}
-	err = es.Tag.Create(id, name)
+}
+
+func (t *tag) Create(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "TagService Create")
+	defer func() { tracing.End(span, err) }()
+
+	err = t.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		id, err := t.tags.Create(ctx, name)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, t.outbox, "tags", id)
+	})
 	if err != nil {
 		return e.Wrap(err, "TagService Create failed")
 	}
@@ -27,12 +56,17 @@ func (t *tag) Create(name string) error {
 }
 
 // UpdateOffer will add/modify the offer tag.
-func (t *tag) UpdateOffer(name string) error {
-	id, err := mongo.Tag.UpdateOffer(name)
-	if err != nil {
-		return e.Wrap(err, "TagService UpdateOffer failed")
-	}
-	err = es.Tag.UpdateOffer(id.Hex(), name)
+func (t *tag) UpdateOffer(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "TagService UpdateOffer")
+	defer func() { tracing.End(span, err) }()
+
+	err = t.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		id, err := t.tags.UpdateOffer(ctx, name)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, t.outbox, "tags", id)
+	})
 	if err != nil {
 		return e.Wrap(err, "TagService UpdateOffer failed")
 	}
@@ -40,60 +74,114 @@ func (t *tag) UpdateOffer(name string) error {
 }
 
 // UpdateWant will add/modify the want tag.
-func (t *tag) UpdateWant(name string) error {
-	id, err := mongo.Tag.UpdateWant(name)
-	if err != nil {
-		return e.Wrap(err, "TagService UpdateWant failed")
-	}
-	err = es.Tag.UpdateWant(id.Hex(), name)
+func (t *tag) UpdateWant(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "TagService UpdateWant")
+	defer func() { tracing.End(span, err) }()
+
+	err = t.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		id, err := t.tags.UpdateWant(ctx, name)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, t.outbox, "tags", id)
+	})
 	if err != nil {
 		return e.Wrap(err, "TagService UpdateWant failed")
 	}
 	return nil
 }
 
-func (t *tag) FindByName(name string) (*types.Tag, error) {
-	tag, err := mongo.Tag.FindByName(name)
+func (t *tag) FindByName(ctx context.Context, name string) (_ *types.Tag, err error) {
+	ctx, span := tracing.Start(ctx, "TagService FindByName")
+	defer func() { tracing.End(span, err) }()
+
+	tag, err := t.tags.FindByName(ctx, name)
 	if err != nil {
 		return nil, e.Wrap(err, "TagService FindTag failed")
 	}
 	return tag, nil
 }
 
-func (t *tag) FindByID(id primitive.ObjectID) (*types.Tag, error) {
-	tag, err := mongo.Tag.FindByID(id)
+func (t *tag) FindByID(ctx context.Context, id primitive.ObjectID) (_ *types.Tag, err error) {
+	ctx, span := tracing.Start(ctx, "TagService FindByID")
+	defer func() { tracing.End(span, err) }()
+
+	tag, err := t.tags.FindByID(ctx, id)
 	if err != nil {
 		return nil, e.Wrap(err, "TagService FindByID failed")
 	}
 	return tag, nil
 }
 
-func (t *tag) FindTags(name string, page int64) (*types.FindTagResult, error) {
-	result, err := mongo.Tag.FindTags(name, page)
+func (t *tag) FindTags(
+	ctx context.Context,
+	name string,
+	page int64,
+) (_ *types.FindTagResult, err error) {
+	ctx, span := tracing.Start(ctx, "TagService FindTags")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := t.tags.FindTags(ctx, name, page)
 	if err != nil {
 		return nil, e.Wrap(err, "TagService FindTags failed")
 	}
 	return result, nil
 }
 
-func (t *tag) Rename(tag *types.Tag) error {
-	err := es.Tag.Rename(tag)
+// Suggest returns the offers or wants starting with the prefix, the ones
+// used by the most trading members first.
+func (t *tag) Suggest(
+	ctx context.Context,
+	tagType string,
+	prefix string,
+) (_ []*types.TagSuggestion, err error) {
+	ctx, span := tracing.Start(ctx, "TagService Suggest")
+	defer func() { tracing.End(span, err) }()
+
+	prefix = strings.Join(strings.Fields(strings.ToLower(prefix)), "-")
+	if prefix == "" {
+		return []*types.TagSuggestion{}, nil
+	}
+	suggestions, err := t.businesses.SuggestTags(
+		ctx,
+		tagType,
+		prefix,
+		global.Config().TagSuggestionSize,
+	)
 	if err != nil {
-		return e.Wrap(err, "TagService Rename failed")
+		return nil, e.Wrap(err, "TagService Suggest failed")
 	}
-	err = mongo.Tag.Rename(tag)
+	return suggestions, nil
+}
+
+func (t *tag) Rename(ctx context.Context, tag *types.Tag) (err error) {
+	ctx, span := tracing.Start(ctx, "TagService Rename")
+	defer func() { tracing.End(span, err) }()
+
+	err = t.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := t.tags.Rename(ctx, tag)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, t.outbox, "tags", tag.ID)
+	})
 	if err != nil {
 		return e.Wrap(err, "TagService Rename failed")
 	}
 	return nil
 }
 
-func (t *tag) DeleteByID(id primitive.ObjectID) error {
-	err := es.Tag.DeleteByID(id.Hex())
-	if err != nil {
-		return e.Wrap(err, "TagService DeleteByID failed")
-	}
-	err = mongo.Tag.DeleteByID(id)
+func (t *tag) DeleteByID(ctx context.Context, id primitive.ObjectID) (err error) {
+	ctx, span := tracing.Start(ctx, "TagService DeleteByID")
+	defer func() { tracing.End(span, err) }()
+
+	err = t.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := t.tags.DeleteByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, t.outbox, "tags", id)
+	})
 	if err != nil {
 		return e.Wrap(err, "TagService DeleteByID failed")
 	}
@@ -103,13 +191,17 @@ func (t *tag) DeleteByID(id primitive.ObjectID) error {
 // MatchOffers loops through user's offers and finds out the matched wants.
 // Only add to the result when matches more than one tag.
 func (t *tag) MatchOffers(
+	ctx context.Context,
 	offers []string,
 	lastLoginDate time.Time,
-) (map[string][]string, error) {
+) (_ map[string][]string, err error) {
+	ctx, span := tracing.Start(ctx, "TagService MatchOffers")
+	defer func() { tracing.End(span, err) }()
+
 	resultMap := make(map[string][]string, len(offers))
 
 	for _, offer := range offers {
-		matches, err := es.Tag.MatchOffer(offer, lastLoginDate)
+		matches, err := t.matcher.MatchOffer(ctx, offer, lastLoginDate)
 		if err != nil {
 			return nil, e.Wrap(err, "TagService MatchOffers failed")
 		}
@@ -124,13 +216,17 @@ func (t *tag) MatchOffers(
 // MatchWants loops through user's wants and finds out the matched offers.
 // Only add to the result when matches more than one tag.
 func (t *tag) MatchWants(
+	ctx context.Context,
 	wants []string,
 	lastLoginDate time.Time,
-) (map[string][]string, error) {
+) (_ map[string][]string, err error) {
+	ctx, span := tracing.Start(ctx, "TagService MatchWants")
+	defer func() { tracing.End(span, err) }()
+
 	resultMap := make(map[string][]string, len(wants))
 
 	for _, want := range wants {
-		matches, err := es.Tag.MatchWant(want, lastLoginDate)
+		matches, err := t.matcher.MatchWant(ctx, want, lastLoginDate)
 		if err != nil {
 			return nil, e.Wrap(err, "TagService MatchWants failed")
 		}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/memory"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/search"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagMatch(t *testing.T) {
+	search.UseMemory()
+	service := NewTag(memory.NewTags(), search.Business, search.Tag, memory.NewOutbox())
+
+	now := time.Now()
+	lastLogin := now.AddDate(0, 0, -1)
+	records := []*types.TagESRecord{
+		{TagID: "1", Name: "bread", OfferAddedAt: now},
+		{TagID: "2", Name: "sourdough-bread", WantAddedAt: now},
+		{TagID: "3", Name: "plumbing", WantAddedAt: now.AddDate(0, 0, -2)},
+		{TagID: "4", Name: "cakes", OfferAddedAt: now, WantAddedAt: now},
+	}
+	for _, r := range records {
+		require.NoError(t, search.Tag.Index(context.Background(), r))
+	}
+
+	tests := []struct {
+		name     string
+		match    func(context.Context, []string, time.Time) (map[string][]string, error)
+		tags     []string
+		expected map[string][]string
+	}{
+		{
+			"should match the wants added since the last login",
+			service.MatchOffers,
+			[]string{"bread", "plumbing", "gardening"},
+			map[string][]string{"bread": {"sourdough-bread"}},
+		},
+		{
+			"should match the offers added since the last login",
+			service.MatchWants,
+			[]string{"bread", "cake"},
+			map[string][]string{"bread": {"bread"}, "cake": {"cakes"}},
+		},
+		{
+			"should skip the tags without matches",
+			service.MatchOffers,
+			[]string{"gardening"},
+			map[string][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := tt.match(context.Background(), tt.tags, lastLogin)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, matches)
+		})
+	}
+}
+
+func TestTagUpdateOffer(t *testing.T) {
+	tags := memory.NewTags()
+	outbox := memory.NewOutbox()
+	service := NewTag(tags, search.Business, search.Tag, outbox)
+
+	require.NoError(t, service.UpdateOffer(context.Background(), "bread"))
+	require.NoError(t, service.UpdateWant(context.Background(), "bread"))
+
+	tag, err := service.FindByName(context.Background(), "bread")
+	require.NoError(t, err)
+	assert.False(t, tag.OfferAddedAt.IsZero())
+	assert.False(t, tag.WantAddedAt.IsZero())
+
+	events := outbox.Events()
+	require.Len(t, events, 2)
+	for _, event := range events {
+		assert.Equal(t, "tags", event.Collection)
+		assert.Equal(t, tag.ID.Hex(), event.DocumentID)
+	}
+
+	suggestions, err := service.Suggest(context.Background(), constant.OFFERS, "  ")
+	assert.NoError(t, err)
+	assert.Empty(t, suggestions)
+}
@@ -1,25 +1,46 @@
 package service
 
 import (
-	"github.com/ic3network/mccs-alpha/internal/app/repositories/es"
+	"context"
+
 	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-type trading struct{}
+type trading struct {
+	businesses BusinessRepository
+	users      UserRepository
+	outbox     OutboxRepository
+}
+
+var Trading = NewTrading(mongo.Business, mongo.User, mongo.Outbox)
 
-var Trading = &trading{}
+// NewTrading returns the trading service using the given repositories.
+func NewTrading(
+	businesses BusinessRepository,
+	users UserRepository,
+	outbox OutboxRepository,
+) *trading {
+	return &trading{businesses: businesses, users: users, outbox: outbox}
+}
 
 func (t *trading) UpdateBusiness(
+	ctx context.Context,
 	id primitive.ObjectID,
 	data *types.TradingRegisterData,
-) error {
-	err := es.Business.UpdateTradingInfo(id, data)
-	if err != nil {
-		return err
-	}
-	err = mongo.Business.UpdateTradingInfo(id, data)
+) (err error) {
+	ctx, span := tracing.Start(ctx, "TradingService UpdateBusiness")
+	defer func() { tracing.End(span, err) }()
+
+	err = t.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := t.businesses.UpdateTradingInfo(ctx, id, data)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, t.outbox, "businesses", id)
+	})
 	if err != nil {
 		return err
 	}
@@ -27,14 +48,20 @@ func (t *trading) UpdateBusiness(
 }
 
 func (t *trading) UpdateUser(
+	ctx context.Context,
 	id primitive.ObjectID,
 	data *types.TradingRegisterData,
-) error {
-	err := es.User.UpdateTradingInfo(id, data)
-	if err != nil {
-		return err
-	}
-	err = mongo.User.UpdateTradingInfo(id, data)
+) (err error) {
+	ctx, span := tracing.Start(ctx, "TradingService UpdateUser")
+	defer func() { tracing.End(span, err) }()
+
+	err = t.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := t.users.UpdateTradingInfo(ctx, id, data)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, t.outbox, "users", id)
+	})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
+	"github.com/ic3network/mccs-alpha/internal/pkg/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type tradingApplication struct {
+	applications TradingApplicationRepository
+}
+
+var TradingApplication = NewTradingApplication(mongo.TradingApplication)
+
+// NewTradingApplication returns the trading application service using the
+// given repository.
+func NewTradingApplication(
+	applications TradingApplicationRepository,
+) *tradingApplication {
+	return &tradingApplication{applications: applications}
+}
+
+// Submit records the trading membership application of the business with
+// the fields it changes. It has to be called before the business and the
+// user are updated with the data.
+func (t *tradingApplication) Submit(
+	ctx context.Context,
+	business *types.Business,
+	user *types.User,
+	data *types.TradingRegisterData,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "TradingApplicationService Submit")
+	defer func() { tracing.End(span, err) }()
+
+	action := constant.ApplicationEvent.Submitted
+	open, err := t.applications.FindOpenByBusinessID(ctx, business.ID)
+	if err == nil && open.Status == constant.TradingApplication.InfoRequested {
+		action = constant.ApplicationEvent.Resubmitted
+	}
+
+	submitted := applicationData(data)
+	err = t.applications.Submit(
+		ctx,
+		&types.TradingApplication{
+			BusinessID: business.ID,
+			UserID:     user.ID,
+			Data:       submitted,
+			Changes:    applicationChanges(currentApplicationData(business, user), submitted),
+		},
+		&types.TradingApplicationEvent{
+			CreatedAt: time.Now(),
+			Email:     user.Email,
+			Action:    action,
+		},
+	)
+	if err != nil {
+		return e.Wrap(err, "TradingApplicationService Submit failed")
+	}
+	return nil
+}
+
+func applicationData(data *types.TradingRegisterData) *types.TradingApplicationData {
+	return &types.TradingApplicationData{
+		BusinessName:       data.BusinessName,
+		IncType:            data.IncType,
+		CompanyNumber:      data.CompanyNumber,
+		BusinessPhone:      data.BusinessPhone,
+		Website:            data.Website,
+		Turnover:           data.Turnover,
+		Description:        data.Description,
+		LocationAddress:    data.LocationAddress,
+		LocationCity:       data.LocationCity,
+		LocationRegion:     data.LocationRegion,
+		LocationPostalCode: data.LocationPostalCode,
+		LocationCountry:    data.LocationCountry,
+		FirstName:          data.FirstName,
+		LastName:           data.LastName,
+		Telephone:          data.Telephone,
+	}
+}
+
+func currentApplicationData(b *types.Business, u *types.User) *types.TradingApplicationData {
+	return &types.TradingApplicationData{
+		BusinessName:       b.BusinessName,
+		IncType:            b.IncType,
+		CompanyNumber:      b.CompanyNumber,
+		BusinessPhone:      b.BusinessPhone,
+		Website:            b.Website,
+		Turnover:           b.Turnover,
+		Description:        b.Description,
+		LocationAddress:    b.LocationAddress,
+		LocationCity:       b.LocationCity,
+		LocationRegion:     b.LocationRegion,
+		LocationPostalCode: b.LocationPostalCode,
+		LocationCountry:    b.LocationCountry,
+		FirstName:          u.FirstName,
+		LastName:           u.LastName,
+		Telephone:          u.Telephone,
+	}
+}
+
+// applicationChanges lists the changed fields like "field: old -> new".
+func applicationChanges(old, new *types.TradingApplicationData) []string {
+	changes := util.CheckDiff(old, new, nil)
+	sort.Strings(changes)
+	return changes
+}
+
+// Find returns the applications with the status, the open applications
+// when the status is empty.
+func (t *tradingApplication) Find(
+	ctx context.Context,
+	status string,
+	page int64,
+) (_ *types.FindTradingApplicationResult, err error) {
+	ctx, span := tracing.Start(ctx, "TradingApplicationService Find")
+	defer func() { tracing.End(span, err) }()
+
+	statuses := []string{status}
+	if status == "" {
+		statuses = []string{
+			constant.TradingApplication.Pending,
+			constant.TradingApplication.InfoRequested,
+		}
+	}
+	result, err := t.applications.Find(ctx, statuses, page)
+	if err != nil {
+		return nil, e.Wrap(err, "TradingApplicationService Find failed")
+	}
+	return result, nil
+}
+
+func (t *tradingApplication) FindByID(
+	ctx context.Context,
+	id primitive.ObjectID,
+) (_ *types.TradingApplication, err error) {
+	ctx, span := tracing.Start(ctx, "TradingApplicationService FindByID")
+	defer func() { tracing.End(span, err) }()
+
+	a, err := t.applications.FindByID(ctx, id)
+	if err != nil {
+		return nil, e.Wrap(err, "TradingApplicationService FindByID failed")
+	}
+	return a, nil
+}
+
+func (t *tradingApplication) FindOpenByBusinessID(
+	ctx context.Context,
+	id primitive.ObjectID,
+) (_ *types.TradingApplication, err error) {
+	ctx, span := tracing.Start(ctx, "TradingApplicationService FindOpenByBusinessID")
+	defer func() { tracing.End(span, err) }()
+
+	a, err := t.applications.FindOpenByBusinessID(ctx, id)
+	if err != nil {
+		return nil, e.Wrap(err, "TradingApplicationService FindOpenByBusinessID failed")
+	}
+	return a, nil
+}
+
+// History returns the events of all the applications of the business,
+// the oldest first.
+func (t *tradingApplication) History(
+	ctx context.Context,
+	businessID primitive.ObjectID,
+) (_ []*types.TradingApplicationEvent, err error) {
+	ctx, span := tracing.Start(ctx, "TradingApplicationService History")
+	defer func() { tracing.End(span, err) }()
+
+	applications, err := t.applications.FindByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, e.Wrap(err, "TradingApplicationService History failed")
+	}
+	events := []*types.TradingApplicationEvent{}
+	for _, a := range applications {
+		events = append(events, a.Events...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+	return events, nil
+}
+
+func (t *tradingApplication) AddNote(
+	ctx context.Context,
+	id primitive.ObjectID,
+	email string,
+	text string,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "TradingApplicationService AddNote")
+	defer func() { tracing.End(span, err) }()
+
+	err = t.applications.AddNote(ctx, id, &types.TradingApplicationNote{
+		CreatedAt: time.Now(),
+		Email:     email,
+		Text:      text,
+	})
+	if err != nil {
+		return e.Wrap(err, "TradingApplicationService AddNote failed")
+	}
+	return nil
+}
+
+// decisions are the application and business statuses of the decisions.
+var decisions = map[string]struct {
+	applicationStatus string
+	businessStatus    string
+}{
+	constant.ApplicationEvent.Approved: {
+		constant.TradingApplication.Approved,
+		constant.Trading.Accepted,
+	},
+	constant.ApplicationEvent.Rejected: {
+		constant.TradingApplication.Rejected,
+		constant.Trading.Rejected,
+	},
+	constant.ApplicationEvent.InfoRequested: {
+		constant.TradingApplication.InfoRequested,
+		"",
+	},
+}
+
+// Decide approves, rejects or asks for more information about the open
+// application. The business change is nil when the status of the business
+// stays the same.
+func (t *tradingApplication) Decide(
+	ctx context.Context,
+	id primitive.ObjectID,
+	action string,
+	adminEmail string,
+	message string,
+) (_ *types.TradingApplication, _ *types.BusinessChange, err error) {
+	ctx, span := tracing.Start(ctx, "TradingApplicationService Decide")
+	defer func() { tracing.End(span, err) }()
+
+	d, ok := decisions[action]
+	if !ok {
+		return nil, nil, e.New(e.InternalServerError, "unknown decision "+action)
+	}
+	a, err := t.applications.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, e.Wrap(err, "TradingApplicationService Decide failed")
+	}
+	if a.Status != constant.TradingApplication.Pending &&
+		a.Status != constant.TradingApplication.InfoRequested {
+		return nil, nil, e.New(e.ApplicationDecided, "TradingApplicationService Decide failed")
+	}
+
+	// The decision is recorded first, only when the application is still
+	// open, so the business is changed once when two admins decide at the
+	// same time.
+	err = t.applications.Decide(ctx, id, d.applicationStatus, &types.TradingApplicationEvent{
+		CreatedAt: time.Now(),
+		Email:     adminEmail,
+		Action:    action,
+		Message:   message,
+	})
+	if err != nil {
+		return nil, nil, e.Wrap(err, "TradingApplicationService Decide failed")
+	}
+
+	var change *types.BusinessChange
+	if d.businessStatus != "" {
+		change, err = Business.ApplyBulkAction(
+			ctx,
+			a.BusinessID,
+			&types.BulkAction{Status: d.businessStatus},
+		)
+		if err != nil {
+			reopenErr := t.applications.Reopen(ctx, id, a.Status)
+			if reopenErr != nil {
+				return nil, nil, e.Wrap(reopenErr, "TradingApplicationService Decide failed")
+			}
+			return nil, nil, e.Wrap(err, "TradingApplicationService Decide failed")
+		}
+	}
+	a.Status = d.applicationStatus
+	return a, change, nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplicationChanges(t *testing.T) {
+	business := &types.Business{
+		BusinessName: "Bakery",
+		Website:      "bakery.example",
+		Turnover:     1000,
+		LocationCity: "Leeds",
+	}
+	user := &types.User{
+		FirstName: "Ann",
+		LastName:  "Smith",
+	}
+	data := &types.TradingRegisterData{
+		BusinessName: "Bakery",
+		Website:      "bakery.example",
+		Turnover:     2000,
+		LocationCity: "York",
+		FirstName:    "Ann",
+		LastName:     "Smith",
+		Telephone:    "0123",
+	}
+
+	changes := applicationChanges(currentApplicationData(business, user), applicationData(data))
+	assert.Equal(t, []string{
+		"LocationCity: Leeds -> York",
+		"Telephone:  -> 0123",
+		"Turnover: 1000 -> 2000",
+	}, changes)
+
+	unchanged := applicationChanges(applicationData(data), applicationData(data))
+	assert.Empty(t, unchanged)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"time"
@@ -8,19 +9,39 @@ import (
 	"github.com/ic3network/mccs-alpha/internal/app/repositories/pg"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 )
 
-type transaction struct{}
+type transaction struct {
+	accounts     AccountRepository
+	limits       balanceLimit
+	transactions TransactionRepository
+}
 
 // Transaction services.
-var Transaction = &transaction{}
+var Transaction = NewTransaction(pg.Account, pg.BalanceLimit, pg.Transaction)
+
+// NewTransaction returns the transaction service using the given
+// repositories.
+func NewTransaction(
+	accounts AccountRepository,
+	limits BalanceLimitRepository,
+	transactions TransactionRepository,
+) *transaction {
+	return &transaction{
+		accounts:     accounts,
+		limits:       NewBalanceLimit(accounts, limits),
+		transactions: transactions,
+	}
+}
 
 func (t *transaction) maxBalanceCanBeTransferred(
+	ctx context.Context,
 	a *types.Account,
 	kind string,
 ) (float64, error) {
 	if kind == "positive" {
-		maxPosBal, err := BalanceLimit.GetMaxPosBalance(a.ID)
+		maxPosBal, err := t.limits.GetMaxPosBalance(ctx, a.ID)
 		if err != nil {
 			return 0, err
 		}
@@ -29,7 +50,7 @@ func (t *transaction) maxBalanceCanBeTransferred(
 		}
 		return math.Abs(a.Balance) + maxPosBal, nil
 	}
-	maxNegBal, err := BalanceLimit.GetMaxNegBalance(a.ID)
+	maxNegBal, err := t.limits.GetMaxNegBalance(ctx, a.ID)
 	if err != nil {
 		return 0, err
 	}
@@ -40,6 +61,7 @@ func (t *transaction) maxBalanceCanBeTransferred(
 }
 
 func (t *transaction) Propose(
+	ctx context.Context,
 	proposerID,
 
 	fromID,
@@ -52,28 +74,31 @@ func (t *transaction) Propose(
 
 	amount float64,
 	description string,
-) (*types.Transaction, error) {
+) (_ *types.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "TransactionService Propose")
+	defer func() { tracing.End(span, err) }()
+
 	// Get the Account IDs using MongoIDs.
-	proposer, err := pg.Account.FindByBusinessID(proposerID)
+	proposer, err := t.accounts.FindByBusinessID(ctx, proposerID)
 	if err != nil {
 		return nil, e.Wrap(err, "service.Transaction.Propose")
 	}
-	from, err := pg.Account.FindByBusinessID(fromID)
+	from, err := t.accounts.FindByBusinessID(ctx, fromID)
 	if err != nil {
 		return nil, e.Wrap(err, "service.Transaction.Propose")
 	}
-	to, err := pg.Account.FindByBusinessID(toID)
+	to, err := t.accounts.FindByBusinessID(ctx, toID)
 	if err != nil {
 		return nil, e.Wrap(err, "service.Transaction.Propose")
 	}
 
 	// Check the account balance.
-	exceed, err := BalanceLimit.IsExceedLimit(from.ID, from.Balance-amount)
+	exceed, err := t.limits.IsExceedLimit(ctx, from.ID, from.Balance-amount)
 	if err != nil {
 		return nil, e.Wrap(err, "service.Transaction.Propose")
 	}
 	if exceed {
-		amount, err := t.maxBalanceCanBeTransferred(from, "negative")
+		amount, err := t.maxBalanceCanBeTransferred(ctx, from, "negative")
 		if err != nil {
 			return nil, e.Wrap(err, "service.Transaction.Propose")
 		}
@@ -84,12 +109,12 @@ func (t *transaction) Propose(
 			),
 		)
 	}
-	exceed, err = BalanceLimit.IsExceedLimit(to.ID, to.Balance+amount)
+	exceed, err = t.limits.IsExceedLimit(ctx, to.ID, to.Balance+amount)
 	if err != nil {
 		return nil, e.Wrap(err, "service.Transaction.Propose")
 	}
 	if exceed {
-		amount, err := t.maxBalanceCanBeTransferred(to, "positive")
+		amount, err := t.maxBalanceCanBeTransferred(ctx, to, "positive")
 		if err != nil {
 			return nil, e.Wrap(err, "service.Transaction.Propose")
 		}
@@ -101,7 +126,8 @@ func (t *transaction) Propose(
 		)
 	}
 
-	transaction, err := pg.Transaction.Propose(
+	transaction, err := t.transactions.Propose(
+		ctx,
 		proposer.ID,
 		from.ID,
 		fromEmail,
@@ -118,8 +144,14 @@ func (t *transaction) Propose(
 	return transaction, nil
 }
 
-func (t *transaction) Find(transactionID uint) (*types.Transaction, error) {
-	transaction, err := pg.Transaction.Find(transactionID)
+func (t *transaction) Find(
+	ctx context.Context,
+	transactionID uint,
+) (_ *types.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "TransactionService Find")
+	defer func() { tracing.End(span, err) }()
+
+	transaction, err := t.transactions.Find(ctx, transactionID)
 	if err != nil {
 		return nil, err
 	}
@@ -127,17 +159,24 @@ func (t *transaction) Find(transactionID uint) (*types.Transaction, error) {
 }
 
 func (t *transaction) FindPendings(
+	ctx context.Context,
 	accountID uint,
-) ([]*types.Transaction, error) {
-	transactions, err := pg.Transaction.FindPendings(accountID)
+) (_ []*types.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "TransactionService FindPendings")
+	defer func() { tracing.End(span, err) }()
+
+	transactions, err := t.transactions.FindPendings(ctx, accountID)
 	if err != nil {
 		return nil, err
 	}
 	return transactions, nil
 }
 
-func (t *transaction) Cancel(transactionID uint, reason string) error {
-	err := pg.Transaction.Cancel(transactionID, reason)
+func (t *transaction) Cancel(ctx context.Context, transactionID uint, reason string) (err error) {
+	ctx, span := tracing.Start(ctx, "TransactionService Cancel")
+	defer func() { tracing.End(span, err) }()
+
+	err = t.transactions.Cancel(ctx, transactionID, reason)
 	if err != nil {
 		return err
 	}
@@ -145,12 +184,17 @@ func (t *transaction) Cancel(transactionID uint, reason string) error {
 }
 
 func (t *transaction) Accept(
+	ctx context.Context,
 	transactionID uint,
 	fromID uint,
 	toID uint,
 	amount float64,
-) error {
-	err := pg.Transaction.Accept(
+) (err error) {
+	ctx, span := tracing.Start(ctx, "TransactionService Accept")
+	defer func() { tracing.End(span, err) }()
+
+	err = t.transactions.Accept(
+		ctx,
 		transactionID,
 		fromID,
 		toID,
@@ -162,8 +206,14 @@ func (t *transaction) Accept(
 	return nil
 }
 
-func (t *transaction) FindRecent(accountID uint) ([]*types.Transaction, error) {
-	transactions, err := pg.Transaction.FindRecent(accountID)
+func (t *transaction) FindRecent(
+	ctx context.Context,
+	accountID uint,
+) (_ []*types.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "TransactionService FindRecent")
+	defer func() { tracing.End(span, err) }()
+
+	transactions, err := t.transactions.FindRecent(ctx, accountID)
 	if err != nil {
 		return nil, err
 	}
@@ -171,12 +221,17 @@ func (t *transaction) FindRecent(accountID uint) ([]*types.Transaction, error) {
 }
 
 func (t *transaction) FindInRange(
+	ctx context.Context,
 	accountID uint,
 	dateFrom time.Time,
 	dateTo time.Time,
 	page int,
-) ([]*types.Transaction, int, error) {
-	transactions, totalPages, err := pg.Transaction.FindInRange(
+) (_ []*types.Transaction, _ int, err error) {
+	ctx, span := tracing.Start(ctx, "TransactionService FindInRange")
+	defer func() { tracing.End(span, err) }()
+
+	transactions, totalPages, err := t.transactions.FindInRange(
+		ctx,
 		accountID,
 		dateFrom,
 		dateTo,
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/memory"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsExceedLimit(t *testing.T) {
+	ledger := memory.NewLedger()
+	require.NoError(t, ledger.Accounts.Create(context.Background(), "a"))
+	require.NoError(t, ledger.BalanceLimits.Update(context.Background(), 1, 500, -100))
+	limits := NewBalanceLimit(ledger.Accounts, ledger.BalanceLimits)
+
+	tests := []struct {
+		name     string
+		balance  float64
+		expected bool
+	}{
+		{"should allow the max negative balance", -100, false},
+		{"should exceed the max negative balance", -100.01, true},
+		{"should allow the max positive balance", 500, false},
+		{"should exceed the max positive balance", 500.01, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exceed, err := limits.IsExceedLimit(context.Background(), 1, tt.balance)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, exceed)
+		})
+	}
+}
+
+func TestTransactionPropose(t *testing.T) {
+	ledger := memory.NewLedger()
+	require.NoError(t, ledger.Accounts.Create(context.Background(), "sender"))
+	require.NoError(t, ledger.Accounts.Create(context.Background(), "receiver"))
+	require.NoError(t, ledger.BalanceLimits.Update(context.Background(), 1, 500, 100))
+	require.NoError(t, ledger.BalanceLimits.Update(context.Background(), 2, 50, 0))
+	service := NewTransaction(ledger.Accounts, ledger.BalanceLimits, ledger.Transactions)
+
+	propose := func(amount float64) error {
+		_, err := service.Propose(
+			context.Background(),
+			"sender",
+			"sender", "sender@example.com", "Sender",
+			"receiver", "receiver@example.com", "Receiver",
+			amount, "",
+		)
+		return err
+	}
+
+	err := propose(150)
+	require.Error(t, err)
+	assert.Equal(t,
+		"Sender will exceed its credit limit. The maximum amount that can be sent is: 100.00",
+		err.(e.Error).Message(),
+	)
+
+	err = propose(80)
+	require.Error(t, err)
+	assert.Equal(t,
+		"Receiver will exceed its maximum balance limit. The maximum amount that can be received is: 50.00",
+		err.(e.Error).Message(),
+	)
+
+	require.NoError(t, propose(40))
+	pendings, err := service.FindPendings(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, pendings, 1)
+	assert.True(t, pendings[0].IsInitiator)
+
+	require.NoError(t, service.Accept(context.Background(), pendings[0].ID, 1, 2, 40))
+	sender, _ := ledger.Accounts.FindByID(context.Background(), 1)
+	receiver, _ := ledger.Accounts.FindByID(context.Background(), 2)
+	assert.Equal(t, -40.0, sender.Balance)
+	assert.Equal(t, 40.0, receiver.Balance)
+
+	pendings, err = service.FindPendings(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Empty(t, pendings)
+
+	// The receiver can only take 10 more.
+	err = propose(20)
+	require.Error(t, err)
+	assert.Contains(t, err.(e.Error).Message(), "received is: 10.00")
+}
@@ -1,47 +1,88 @@
 package service
 
 import (
+	"context"
 	"time"
 
-	"github.com/ic3network/mccs-alpha/internal/app/repositories/es"
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/search"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/bcrypt"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
-	"github.com/spf13/viper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-type user struct{}
+type user struct {
+	users    UserRepository
+	searcher search.UserSearcher
+	outbox   OutboxRepository
+}
+
+var User = NewUser(mongo.User, search.User, mongo.Outbox)
 
-var User = &user{}
+// NewUser returns the user service using the given repositories.
+func NewUser(
+	users UserRepository,
+	searcher search.UserSearcher,
+	outbox OutboxRepository,
+) *user {
+	return &user{users: users, searcher: searcher, outbox: outbox}
+}
 
-func (u *user) FindByID(id primitive.ObjectID) (*types.User, error) {
-	user, err := mongo.User.FindByID(id)
+func (u *user) FindByID(ctx context.Context, id primitive.ObjectID) (_ *types.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserService FindByID")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := u.users.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (u *user) FindByEmail(email string) (*types.User, error) {
-	user, err := mongo.User.FindByEmail(email)
+func (u *user) FindByEmail(ctx context.Context, email string) (_ *types.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserService FindByEmail")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := u.users.FindByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (u *user) FindByBusinessID(id primitive.ObjectID) (*types.User, error) {
-	user, err := mongo.User.FindByBusinessID(id)
+func (u *user) FindByBusinessID(
+	ctx context.Context,
+	id primitive.ObjectID,
+) (_ *types.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserService FindByBusinessID")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := u.users.FindByBusinessID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (u *user) Create(user *types.User) error {
-	_, err := mongo.User.FindByEmail(user.Email)
+func (u *user) FindByIDs(ctx context.Context, ids []string) (_ []*types.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserService FindByIDs")
+	defer func() { tracing.End(span, err) }()
+
+	users, err := u.users.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, e.Wrap(err, "UserService FindByIDs failed")
+	}
+	return users, nil
+}
+
+func (u *user) Create(ctx context.Context, user *types.User) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService Create")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = u.users.FindByEmail(ctx, user.Email)
 	if err == nil {
 		return e.New(e.EmailExisted, "email existed")
 	}
@@ -52,19 +93,28 @@ func (u *user) Create(user *types.User) error {
 	}
 
 	user.Password = hashedPassword
-	err = mongo.User.Create(user)
-	if err != nil {
-		return e.Wrap(err, "create user failed")
-	}
-	err = es.User.Create(user)
+	err = u.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := u.users.Create(ctx, user)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, u.outbox, "users", user.ID)
+	})
 	if err != nil {
 		return e.Wrap(err, "create user failed")
 	}
 	return nil
 }
 
-func (u *user) Login(email string, password string) (*types.User, error) {
-	user, err := mongo.User.FindByEmail(email)
+func (u *user) Login(
+	ctx context.Context,
+	email string,
+	password string,
+) (_ *types.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserService Login")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := u.users.FindByEmail(ctx, email)
 	if err != nil {
 		return &types.User{}, e.Wrap(err, "login user failed")
 	}
@@ -72,9 +122,7 @@ func (u *user) Login(email string, password string) (*types.User, error) {
 	if time.Now().
 		Sub(user.LastLoginFailDate).
 		Seconds() <=
-		viper.GetFloat64(
-			"login_attempts_timeout",
-		) {
+		float64(global.Config().LoginAttemptsTimeout) {
 		return &types.User{}, e.New(e.AccountLocked, "")
 	}
 
@@ -87,8 +135,11 @@ func (u *user) Login(email string, password string) (*types.User, error) {
 }
 
 // UserEmailExists checks if the email exists in the database.
-func (u *user) UserEmailExists(email string) bool {
-	_, err := mongo.User.FindByEmail(email)
+func (u *user) UserEmailExists(ctx context.Context, email string) bool {
+	ctx, span := tracing.Start(ctx, "UserService UserEmailExists")
+	defer span.End()
+
+	_, err := u.users.FindByEmail(ctx, email)
 	if err != nil {
 		return false
 	}
@@ -96,14 +147,18 @@ func (u *user) UserEmailExists(email string) bool {
 }
 
 func (u *user) FindUsers(
+	ctx context.Context,
 	user *types.User,
 	page int64,
-) (*types.FindUserResult, error) {
-	ids, numberOfResults, totalPages, err := es.User.Find(user, page)
+) (_ *types.FindUserResult, err error) {
+	ctx, span := tracing.Start(ctx, "UserService FindUsers")
+	defer func() { tracing.End(span, err) }()
+
+	ids, numberOfResults, totalPages, err := u.searcher.Find(ctx, user, page)
 	if err != nil {
 		return nil, e.Wrap(err, "UserService FindUsers failed")
 	}
-	users, err := mongo.User.FindByIDs(ids)
+	users, err := u.users.FindByIDs(ctx, ids)
 	if err != nil {
 		return nil, e.Wrap(err, "UserService FindUsers failed")
 	}
@@ -114,8 +169,11 @@ func (u *user) FindUsers(
 	}, nil
 }
 
-func (u *user) FindByDailyNotification() ([]*types.User, error) {
-	users, err := mongo.User.FindByDailyNotification()
+func (u *user) FindByDailyNotification(ctx context.Context) (_ []*types.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserService FindByDailyNotification")
+	defer func() { tracing.End(span, err) }()
+
+	users, err := u.users.FindByDailyNotification(ctx)
 	if err != nil {
 		return nil, e.Wrap(err, "UserService FindByDailyNotification failed")
 	}
@@ -123,12 +181,18 @@ func (u *user) FindByDailyNotification() ([]*types.User, error) {
 }
 
 // Logout logs out the user.
-func (u *user) Logout() error {
+func (u *user) Logout(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService Logout")
+	defer func() { tracing.End(span, err) }()
+
 	return nil
 }
 
-func (u *user) ResetPassword(email string, newPassword string) error {
-	user, err := mongo.User.FindByEmail(email)
+func (u *user) ResetPassword(ctx context.Context, email string, newPassword string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService ResetPassword")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := u.users.FindByEmail(ctx, email)
 	if err != nil {
 		return err
 	}
@@ -139,7 +203,7 @@ func (u *user) ResetPassword(email string, newPassword string) error {
 	}
 
 	user.Password = hashedPassword
-	err = mongo.User.UpdatePassword(user)
+	err = u.users.UpdatePassword(ctx, user)
 	if err != nil {
 		return e.Wrap(err, "reset password failed")
 	}
@@ -147,40 +211,59 @@ func (u *user) ResetPassword(email string, newPassword string) error {
 	return nil
 }
 
-func (u *user) UpdateUserInfo(user *types.User) error {
-	err := es.User.Update(user)
-	if err != nil {
-		return e.Wrap(err, "update user info failed")
-	}
-	err = mongo.User.UpdateUserInfo(user)
+func (u *user) UpdateUserInfo(ctx context.Context, user *types.User) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService UpdateUserInfo")
+	defer func() { tracing.End(span, err) }()
+
+	err = u.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := u.users.UpdateUserInfo(ctx, user)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, u.outbox, "users", user.ID)
+	})
 	if err != nil {
 		return e.Wrap(err, "update user info failed")
 	}
 	return nil
 }
 
-func (u *user) UpdateLastNotificationSentDate(id primitive.ObjectID) error {
-	err := mongo.User.UpdateLastNotificationSentDate(id)
+func (u *user) UpdateLastNotificationSentDate(
+	ctx context.Context,
+	id primitive.ObjectID,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService UpdateLastNotificationSentDate")
+	defer func() { tracing.End(span, err) }()
+
+	err = u.users.UpdateLastNotificationSentDate(ctx, id)
 	if err != nil {
 		return e.Wrap(err, "UserService UpdateLastNotificationSentDate failed")
 	}
 	return nil
 }
 
-func (u *user) AdminUpdateUser(user *types.User) error {
-	err := es.User.Update(user)
-	if err != nil {
-		return e.Wrap(err, "AdminUpdateUser failed")
-	}
-	err = mongo.User.AdminUpdateUser(user)
+func (u *user) AdminUpdateUser(ctx context.Context, user *types.User) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService AdminUpdateUser")
+	defer func() { tracing.End(span, err) }()
+
+	err = u.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := u.users.AdminUpdateUser(ctx, user)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, u.outbox, "users", user.ID)
+	})
 	if err != nil {
 		return e.Wrap(err, "AdminUpdateUser failed")
 	}
 	return nil
 }
 
-func (u *user) UpdateLoginAttempts(email string) error {
-	user, err := mongo.User.FindByEmail(email)
+func (u *user) UpdateLoginAttempts(ctx context.Context, email string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService UpdateLoginAttempts")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := u.users.FindByEmail(ctx, email)
 	if err != nil {
 		return err
 	}
@@ -188,14 +271,14 @@ func (u *user) UpdateLoginAttempts(email string) error {
 	attempts := user.LoginAttempts
 	lockUser := false
 
-	if attempts+1 >= viper.GetInt("login_attempts_limit") {
+	if attempts+1 >= global.Config().LoginAttemptsLimit {
 		attempts = 0
 		lockUser = true
 	} else {
 		attempts++
 	}
 
-	err = mongo.User.UpdateLoginAttempts(email, attempts, lockUser)
+	err = u.users.UpdateLoginAttempts(ctx, email, attempts, lockUser)
 	if err != nil {
 		return err
 	}
@@ -203,8 +286,11 @@ func (u *user) UpdateLoginAttempts(email string) error {
 	return nil
 }
 
-func (u *user) UpdateLoginInfo(id primitive.ObjectID, ip string) error {
-	loginInfo, err := mongo.User.GetLoginInfo(id)
+func (u *user) UpdateLoginInfo(ctx context.Context, id primitive.ObjectID, ip string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService UpdateLoginInfo")
+	defer func() { tracing.End(span, err) }()
+
+	loginInfo, err := u.users.GetLoginInfo(ctx, id)
 	if err != nil {
 		return e.Wrap(err, "UserService UpdateLoginInfo failed")
 	}
@@ -215,19 +301,24 @@ func (u *user) UpdateLoginInfo(id primitive.ObjectID, ip string) error {
 		LastLoginDate:  loginInfo.CurrentLoginDate,
 	}
 
-	err = mongo.User.UpdateLoginInfo(id, newLoginInfo)
+	err = u.users.UpdateLoginInfo(ctx, id, newLoginInfo)
 	if err != nil {
 		return e.Wrap(err, "UserService UpdateLoginInfo failed")
 	}
 	return nil
 }
 
-func (u *user) DeleteByID(id primitive.ObjectID) error {
-	err := es.User.Delete(id.Hex())
-	if err != nil {
-		return e.Wrap(err, "delete user by id failed")
-	}
-	err = mongo.User.DeleteByID(id)
+func (u *user) DeleteByID(ctx context.Context, id primitive.ObjectID) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService DeleteByID")
+	defer func() { tracing.End(span, err) }()
+
+	err = u.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := u.users.DeleteByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return recordSync(ctx, u.outbox, "users", id)
+	})
 	if err != nil {
 		return e.Wrap(err, "delete user by id failed")
 	}
@@ -236,8 +327,11 @@ func (u *user) DeleteByID(id primitive.ObjectID) error {
 
 // APIs
 
-func (u *user) ToggleShowRecentMatchedTags(id primitive.ObjectID) error {
-	err := mongo.User.ToggleShowRecentMatchedTags(id)
+func (u *user) ToggleShowRecentMatchedTags(ctx context.Context, id primitive.ObjectID) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService ToggleShowRecentMatchedTags")
+	defer func() { tracing.End(span, err) }()
+
+	err = u.users.ToggleShowRecentMatchedTags(ctx, id)
 	if err != nil {
 		return e.Wrap(err, "UserService ToggleShowRecentMatchedTags failed")
 	}
@@ -245,9 +339,13 @@ func (u *user) ToggleShowRecentMatchedTags(id primitive.ObjectID) error {
 }
 
 func (u *user) AddToFavoriteBusinesses(
+	ctx context.Context,
 	uID, businessID primitive.ObjectID,
-) error {
-	err := mongo.User.AddToFavoriteBusinesses(uID, businessID)
+) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService AddToFavoriteBusinesses")
+	defer func() { tracing.End(span, err) }()
+
+	err = u.users.AddToFavoriteBusinesses(ctx, uID, businessID)
 	if err != nil {
 		return e.Wrap(err, "UserService AddToFavoriteBusinesses failed")
 	}
@@ -255,9 +353,13 @@ func (u *user) AddToFavoriteBusinesses(
 }
 
 func (u *user) RemoveFromFavoriteBusinesses(
+	ctx context.Context,
 	uID, businessID primitive.ObjectID,
-) error {
-	err := mongo.User.RemoveFromFavoriteBusinesses(uID, businessID)
+) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService RemoveFromFavoriteBusinesses")
+	defer func() { tracing.End(span, err) }()
+
+	err = u.users.RemoveFromFavoriteBusinesses(ctx, uID, businessID)
 	if err != nil {
 		return e.Wrap(err, "UserService RemoveFromFavoriteBusinesses failed")
 	}
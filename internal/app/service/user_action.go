@@ -1,20 +1,33 @@
 package service
 
 import (
+	"context"
+
 	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/tracing"
 )
 
-type userAction struct{}
+type userAction struct {
+	userActions UserActionRepository
+}
+
+var UserAction = NewUserAction(mongo.UserAction)
 
-var UserAction = &userAction{}
+// NewUserAction returns the user action service using the given repository.
+func NewUserAction(userActions UserActionRepository) *userAction {
+	return &userAction{userActions: userActions}
+}
+
+func (u *userAction) Log(ctx context.Context, log *types.UserAction) (err error) {
+	ctx, span := tracing.Start(ctx, "UserActionService Log")
+	defer func() { tracing.End(span, err) }()
 
-func (u *userAction) Log(log *types.UserAction) error {
 	if log == nil {
 		return nil
 	}
-	err := mongo.UserAction.Log(log)
+	err = u.userActions.Log(ctx, log)
 	if err != nil {
 		return e.Wrap(err, "UserActionService Log failed")
 	}
@@ -22,12 +35,28 @@ func (u *userAction) Log(log *types.UserAction) error {
 }
 
 func (u *userAction) Find(
+	ctx context.Context,
 	c *types.UserActionSearchCriteria,
 	page int64,
-) ([]*types.UserAction, int, error) {
-	userActions, totalPages, err := mongo.UserAction.Find(c, page)
+) (_ []*types.UserAction, _ int, err error) {
+	ctx, span := tracing.Start(ctx, "UserActionService Find")
+	defer func() { tracing.End(span, err) }()
+
+	userActions, totalPages, err := u.userActions.Find(ctx, c, page)
 	if err != nil {
 		return nil, 0, e.Wrap(err, "UserActionService Find failed")
 	}
 	return userActions, totalPages, nil
 }
+
+// Actions returns the distinct actions that have been logged.
+func (u *userAction) Actions(ctx context.Context) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "UserActionService Actions")
+	defer func() { tracing.End(span, err) }()
+
+	actions, err := u.userActions.Actions(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "UserActionService Actions failed")
+	}
+	return actions, nil
+}
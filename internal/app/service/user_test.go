@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/memory"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/search"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func errorCode(t *testing.T, err error) int {
+	t.Helper()
+	require.Error(t, err)
+	require.IsType(t, e.Error{}, err)
+	return err.(e.Error).Code
+}
+
+func TestUserCreate(t *testing.T) {
+	outbox := memory.NewOutbox()
+	service := NewUser(memory.NewUsers(), search.User, outbox)
+
+	user := &types.User{Email: "Ann@Example.com", Password: "password"}
+	require.NoError(t, service.Create(context.Background(), user))
+	assert.NotEqual(t, "password", user.Password)
+
+	events := outbox.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "users", events[0].Collection)
+	assert.Equal(t, user.ID.Hex(), events[0].DocumentID)
+
+	err := service.Create(context.Background(), &types.User{Email: "ann@example.com", Password: "password"})
+	assert.Equal(t, e.EmailExisted, errorCode(t, err))
+}
+
+func TestUserLoginLockout(t *testing.T) {
+	defer global.SetConfig(global.Config())
+	c := *global.Config()
+	c.LoginAttemptsLimit = 3
+	c.LoginAttemptsTimeout = 900
+	global.SetConfig(&c)
+
+	users := memory.NewUsers()
+	service := NewUser(users, search.User, memory.NewOutbox())
+	require.NoError(t, service.Create(context.Background(), &types.User{
+		Email:    "ann@example.com",
+		Password: "password",
+	}))
+
+	_, err := service.Login(context.Background(), "ann@example.com", "wrong")
+	assert.Equal(t, e.PasswordIncorrect, errorCode(t, err))
+
+	// The first failed attempts are only counted.
+	for i := 1; i < c.LoginAttemptsLimit; i++ {
+		require.NoError(t, service.UpdateLoginAttempts(context.Background(), "ann@example.com"))
+		user, _ := users.FindByEmail(context.Background(), "ann@example.com")
+		assert.Equal(t, i, user.LoginAttempts)
+		assert.True(t, user.LastLoginFailDate.IsZero())
+	}
+	_, err = service.Login(context.Background(), "ann@example.com", "password")
+	assert.NoError(t, err)
+
+	// Reaching the limit locks the account and resets the attempts.
+	require.NoError(t, service.UpdateLoginAttempts(context.Background(), "ann@example.com"))
+	user, _ := users.FindByEmail(context.Background(), "ann@example.com")
+	assert.Equal(t, 0, user.LoginAttempts)
+	assert.False(t, user.LastLoginFailDate.IsZero())
+
+	_, err = service.Login(context.Background(), "ann@example.com", "password")
+	assert.Equal(t, e.AccountLocked, errorCode(t, err))
+
+	// The account is unlocked once the timeout has passed.
+	c.LoginAttemptsTimeout = 0
+	global.SetConfig(&c)
+	loggedIn, err := service.Login(context.Background(), "ann@example.com", "password")
+	assert.NoError(t, err)
+	assert.Equal(t, "ann@example.com", loggedIn.Email)
+}
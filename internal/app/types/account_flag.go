@@ -0,0 +1,60 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AccountFlag is an account the account check found dormant or at risk.
+// An account has at most one open flag.
+type AccountFlag struct {
+	ID        primitive.ObjectID `json:"_id,omitempty"       bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+
+	BusinessID    primitive.ObjectID `json:"businessID,omitempty"    bson:"businessID,omitempty"`
+	AccountID     uint               `json:"accountID,omitempty"     bson:"accountID,omitempty"`
+	Reasons       []string           `json:"reasons,omitempty"       bson:"reasons,omitempty"`
+	Balance       float64            `json:"balance"                 bson:"balance"`
+	LastPostingAt time.Time          `json:"lastPostingAt,omitempty" bson:"lastPostingAt,omitempty"`
+	Status        string             `json:"status,omitempty"        bson:"status,omitempty"`
+	ResolvedAt    time.Time          `json:"resolvedAt,omitempty"    bson:"resolvedAt,omitempty"`
+	// AutoResolved means the account check closed the flag because the
+	// account doesn't match any rule anymore.
+	AutoResolved bool               `json:"autoResolved,omitempty"  bson:"autoResolved,omitempty"`
+	Notes        []*AccountFlagNote `json:"notes,omitempty"         bson:"notes,omitempty"`
+}
+
+type AccountFlagNote struct {
+	CreatedAt time.Time `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	Email     string    `json:"email,omitempty"     bson:"email,omitempty"`
+	Text      string    `json:"text,omitempty"      bson:"text,omitempty"`
+}
+
+// Helper types
+
+// AccountActivity is the balance of an account with its last posting.
+type AccountActivity struct {
+	AccountID     uint
+	BusinessID    string
+	CreatedAt     time.Time
+	Balance       float64
+	MaxNegBal     float64
+	MaxPosBal     float64
+	LastPostingAt *time.Time
+}
+
+// WeeklyNet is the sum of the postings of an account in a week. Week 0 is
+// the last seven days.
+type WeeklyNet struct {
+	AccountID uint
+	Week      int
+	Net       float64
+}
+
+type FindAccountFlagResult struct {
+	AccountFlags    []*AccountFlag
+	NumberOfResults int
+	TotalPages      int
+}
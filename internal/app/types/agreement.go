@@ -0,0 +1,40 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Agreement is a published version of the membership agreement. Versions
+// are never edited, a change is published as a new version.
+type Agreement struct {
+	ID        primitive.ObjectID `json:"_id,omitempty"       bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+
+	Version int    `json:"version,omitempty" bson:"version,omitempty"`
+	Text    string `json:"text,omitempty"    bson:"text,omitempty"`
+	// PublishedBy is the email of the admin.
+	PublishedBy string `json:"publishedBy,omitempty" bson:"publishedBy,omitempty"`
+	// RequiredForTrading blocks the transfers of the trading members until
+	// they accept the version.
+	RequiredForTrading bool `json:"requiredForTrading" bson:"requiredForTrading"`
+}
+
+// AgreementAcceptance records a user accepting a version of the agreement
+// on behalf of the business.
+type AgreementAcceptance struct {
+	ID        primitive.ObjectID `json:"_id,omitempty"       bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+
+	UserID     primitive.ObjectID `json:"userID,omitempty"     bson:"userID,omitempty"`
+	BusinessID primitive.ObjectID `json:"businessID,omitempty" bson:"businessID,omitempty"`
+	Version    int                `json:"version,omitempty"    bson:"version,omitempty"`
+	IPAddress  string             `json:"ipAddress,omitempty"  bson:"ipAddress,omitempty"`
+}
+
+type FindAgreementAcceptanceResult struct {
+	AgreementAcceptances []*AgreementAcceptance
+	NumberOfResults      int
+	TotalPages           int
+}
@@ -0,0 +1,76 @@
+package types
+
+import "time"
+
+type AnalyticsCriteria struct {
+	// Interval is "day", "week" or "month".
+	Interval string
+	DateFrom time.Time
+	DateTo   time.Time
+}
+
+// TradeAnalytics is the network-wide view of the trades in a date range.
+type TradeAnalytics struct {
+	Interval string
+	DateFrom time.Time
+	DateTo   time.Time
+
+	Periods []*AnalyticsPeriod
+
+	TradeCount  int
+	TradeVolume float64
+
+	// Accounts with at least one posting in the date range are active.
+	TotalAccounts   int
+	ActiveAccounts  int
+	DormantAccounts int
+
+	// MoneySupply is the sum of the positive balances.
+	MoneySupply float64
+	// Velocity is the trade volume divided by the money supply.
+	Velocity float64
+
+	BalanceDistribution []*BalanceBucket
+	TopTraders          []*TopTrader
+}
+
+// AnalyticsPeriod is a day ("2006-01-02"), a week ("2006-W01") or a month
+// ("2006-01").
+type AnalyticsPeriod struct {
+	Period      string
+	TradeCount  int
+	TradeVolume float64
+	NewMembers  int
+}
+
+// PeriodCount counts the records created in a period.
+type PeriodCount struct {
+	Period string `bson:"_id"`
+	Count  int    `bson:"count"`
+	Volume float64
+}
+
+// AccountBalance is the balance of an account with its limits.
+type AccountBalance struct {
+	AccountID  uint
+	BusinessID string
+	Balance    float64
+	MaxNegBal  float64
+	MaxPosBal  float64
+}
+
+// BalanceBucket counts the accounts whose balance uses the given share of
+// their limit. Negative balances are measured against the max negative
+// balance and positive ones against the max positive balance.
+type BalanceBucket struct {
+	Label string
+	Count int
+}
+
+type TopTrader struct {
+	AccountID    uint
+	BusinessID   string
+	BusinessName string
+	TradeCount   int
+	TradeVolume  float64
+}
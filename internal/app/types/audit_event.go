@@ -0,0 +1,45 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditEvent is a change an admin made to a record. Events are only ever
+// added to the trail, never modified or deleted.
+type AuditEvent struct {
+	ID        primitive.ObjectID `json:"_id,omitempty"       bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+
+	ActorID    primitive.ObjectID `json:"actorID,omitempty"    bson:"actorID,omitempty"`
+	ActorEmail string             `json:"actorEmail,omitempty" bson:"actorEmail,omitempty"`
+	Action     string             `json:"action,omitempty"     bson:"action,omitempty"`
+	TargetType string             `json:"targetType,omitempty" bson:"targetType,omitempty"`
+	TargetID   string             `json:"targetID,omitempty"   bson:"targetID,omitempty"`
+	Changes    []*AuditChange     `json:"changes,omitempty"    bson:"changes,omitempty"`
+
+	IPAddress     string `json:"ipAddress,omitempty"     bson:"ipAddress,omitempty"`
+	CorrelationID string `json:"correlationID,omitempty" bson:"correlationID,omitempty"`
+}
+
+// AuditChange is the value of a field before and after the change.
+type AuditChange struct {
+	Field  string `json:"field"  bson:"field"`
+	Before string `json:"before" bson:"before"`
+	After  string `json:"after"  bson:"after"`
+}
+
+// AuditRequest is the request the change was made in.
+type AuditRequest struct {
+	IPAddress     string
+	CorrelationID string
+}
+
+type AuditSearchCriteria struct {
+	ActorEmail string
+	TargetType string
+	TargetID   string
+	DateFrom   time.Time
+	DateTo     time.Time
+}
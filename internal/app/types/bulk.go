@@ -0,0 +1,27 @@
+package types
+
+// BulkAction is an admin change applied to each of the selected
+// businesses. The empty fields are left unchanged.
+type BulkAction struct {
+	BusinessIDs     []string `json:"businessIDs"`
+	Status          string   `json:"status"`
+	AddAdminTags    []string `json:"addAdminTags"`
+	RemoveAdminTags []string `json:"removeAdminTags"`
+	MaxPosBal       *float64 `json:"maxPosBal"`
+	MaxNegBal       *float64 `json:"maxNegBal"`
+}
+
+// BusinessChange is a business and its balance limit before and after an
+// admin update.
+type BusinessChange struct {
+	OldBusiness *Business
+	NewBusiness *BusinessData
+	OldBalance  *BalanceLimit
+	NewBalance  *BalanceLimit
+}
+
+type BulkActionResult struct {
+	BusinessID   string `json:"businessID"`
+	BusinessName string `json:"businessName,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
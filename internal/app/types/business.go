@@ -88,10 +88,53 @@ type SearchCriteria struct {
 	ShowUserFavoritesOnly bool
 	FavoriteBusinesses    []primitive.ObjectID
 	AdminTag              string
+
+	// Facet filters. A business matches when it has one of the selected
+	// values of every facet.
+	AdminTags         []string
+	LocationCountries []string
+	LocationCities    []string
+	FacetStatuses     []string
+}
+
+// SimilarCriteria finds the trading members sharing the most offers and
+// wants with the given tags.
+type SimilarCriteria struct {
+	// Offers are matched against the offers of the businesses.
+	Offers []string
+	// Wants are matched against the wants of the businesses.
+	Wants      []string
+	ExcludeIDs []string
+}
+
+// SearchBusinessResult is a page of business ids found by the search
+// backend.
+type SearchBusinessResult struct {
+	IDs             []string
+	NumberOfResults int
+	TotalPages      int
+	Facets          *BusinessFacets
+}
+
+// BusinessFacets count the businesses for each value of a facet. The
+// count of a value ignores the selection of its own facet so that more
+// values can be selected.
+type BusinessFacets struct {
+	AdminTags         []*FacetCount
+	LocationCountries []*FacetCount
+	LocationCities    []*FacetCount
+	Statuses          []*FacetCount
+}
+
+type FacetCount struct {
+	Value    string
+	Count    int
+	Selected bool
 }
 
 type FindBusinessResult struct {
 	Businesses      []*Business
 	NumberOfResults int
 	TotalPages      int
+	Facets          *BusinessFacets
 }
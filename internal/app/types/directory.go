@@ -0,0 +1,65 @@
+package types
+
+// DirectoryRow is a business with its user and balance limits. It is the
+// format of the directory import and export, the names follow the signup
+// form fields.
+type DirectoryRow struct {
+	// Business
+	BusinessName       string   `json:"business_name"`
+	IncType            string   `json:"inc_type,omitempty"`
+	CompanyNumber      string   `json:"company_number,omitempty"`
+	BusinessPhone      string   `json:"business_phone,omitempty"`
+	Website            string   `json:"website,omitempty"`
+	Turnover           int      `json:"turnover,omitempty"`
+	Offers             []string `json:"offers,omitempty"`
+	Wants              []string `json:"wants,omitempty"`
+	Description        string   `json:"description,omitempty"`
+	LocationAddress    string   `json:"location_address,omitempty"`
+	LocationCity       string   `json:"location_city,omitempty"`
+	LocationRegion     string   `json:"location_region,omitempty"`
+	LocationPostalCode string   `json:"location_postal_code,omitempty"`
+	LocationCountry    string   `json:"location_country,omitempty"`
+	Status             string   `json:"status,omitempty"`
+	AdminTags          []string `json:"admin_tags,omitempty"`
+	// User
+	FirstName         string `json:"first_name,omitempty"`
+	LastName          string `json:"last_name,omitempty"`
+	Email             string `json:"email"`
+	Telephone         string `json:"telephone,omitempty"`
+	Password          string `json:"password,omitempty"`
+	DailyNotification bool   `json:"daily_notification,omitempty"`
+	// Balance limits, the defaults are used when they are empty.
+	MaxPosBal *float64 `json:"max_pos_bal,omitempty"`
+	MaxNegBal *float64 `json:"max_neg_bal,omitempty"`
+
+	// ParseErrors are the values of the row that could not be read.
+	ParseErrors []string `json:"-"`
+	// Line is where the row starts in a CSV file.
+	Line int `json:"-"`
+}
+
+type ImportOptions struct {
+	// DryRun only validates the rows.
+	DryRun           bool
+	SendWelcomeEmail bool
+}
+
+type ImportRowResult struct {
+	// Row is the line of the row in the file, or its position in the list.
+	Row          int      `json:"row"`
+	BusinessName string   `json:"businessName"`
+	Email        string   `json:"email"`
+	BusinessID   string   `json:"businessID,omitempty"`
+	Errors       []string `json:"errors,omitempty"`
+
+	// User is the created user.
+	User *User `json:"-"`
+}
+
+type ImportReport struct {
+	DryRun  bool               `json:"dryRun"`
+	Valid   int                `json:"valid"`
+	Created int                `json:"created"`
+	Failed  int                `json:"failed"`
+	Rows    []*ImportRowResult `json:"rows"`
+}
@@ -0,0 +1,20 @@
+package types
+
+// DependencyStatus is the result of pinging a store.
+type DependencyStatus struct {
+	Status string `json:"status"`
+	// Latency is the duration of the ping in milliseconds.
+	Latency int64  `json:"latencyMs"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Readiness tells whether every store the app depends on is reachable.
+type Readiness struct {
+	Status       string                       `json:"status"`
+	Dependencies map[string]*DependencyStatus `json:"dependencies"`
+}
+
+// Ready is true when every dependency is reachable.
+func (r *Readiness) Ready() bool {
+	return r.Status == "ok"
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JobLock makes sure only one instance runs a background job at a time.
+type JobLock struct {
+	// Job is the name of the job.
+	Job string `json:"job,omitempty" bson:"_id,omitempty"`
+	// Owner identifies the instance holding the lock.
+	Owner       string    `json:"owner,omitempty"       bson:"owner,omitempty"`
+	LockedUntil time.Time `json:"lockedUntil,omitempty" bson:"lockedUntil"`
+	// LastSlot is the last scheduled time the job ran for, so the other
+	// instances skip it.
+	LastSlot string `json:"lastSlot,omitempty" bson:"lastSlot,omitempty"`
+}
+
+// JobRun records a run of a background job.
+type JobRun struct {
+	ID        primitive.ObjectID `json:"_id,omitempty"       bson:"_id,omitempty"`
+	StartedAt time.Time          `json:"startedAt,omitempty" bson:"startedAt,omitempty"`
+	EndedAt   time.Time          `json:"endedAt,omitempty"   bson:"endedAt,omitempty"`
+
+	Job     string `json:"job,omitempty"     bson:"job,omitempty"`
+	Trigger string `json:"trigger,omitempty" bson:"trigger,omitempty"`
+	// TriggeredBy is the email of the admin who started a manual run.
+	TriggeredBy string `json:"triggeredBy,omitempty" bson:"triggeredBy,omitempty"`
+	// Host is the instance the job ran on.
+	Host   string `json:"host,omitempty"   bson:"host,omitempty"`
+	Status string `json:"status,omitempty" bson:"status,omitempty"`
+	// Items is the number of items the job processed, e.g. emails sent.
+	Items int    `json:"items"           bson:"items"`
+	Error string `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+// Duration is how long the run took, zero while it is running.
+func (j *JobRun) Duration() time.Duration {
+	if j.EndedAt.IsZero() {
+		return 0
+	}
+	return j.EndedAt.Sub(j.StartedAt).Round(time.Millisecond)
+}
+
+type FindJobRunResult struct {
+	JobRuns         []*JobRun
+	NumberOfResults int
+	TotalPages      int
+}
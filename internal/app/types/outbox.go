@@ -0,0 +1,48 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OutboxEvent is a change waiting to be applied to Elasticsearch.
+type OutboxEvent struct {
+	ID        primitive.ObjectID `json:"_id,omitempty"       bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+
+	// Collection is both the MongoDB collection and the ES alias.
+	Collection string `json:"collection,omitempty" bson:"collection,omitempty"`
+	// DocumentID is empty for the changes across the whole collection.
+	DocumentID string   `json:"documentID,omitempty" bson:"documentID,omitempty"`
+	Action     string   `json:"action,omitempty"     bson:"action,omitempty"`
+	Args       []string `json:"args,omitempty"       bson:"args,omitempty"`
+
+	Attempts      int       `json:"attempts,omitempty"      bson:"attempts,omitempty"`
+	NextAttemptAt time.Time `json:"nextAttemptAt,omitempty" bson:"nextAttemptAt,omitempty"`
+	LastError     string    `json:"lastError,omitempty"     bson:"lastError,omitempty"`
+	// DeadLetteredAt is set once the indexer gave up on the event.
+	DeadLetteredAt time.Time `json:"deadLetteredAt,omitempty" bson:"deadLetteredAt,omitempty"`
+}
+
+// Key groups the events that must be applied in order.
+func (o *OutboxEvent) Key() string {
+	return o.Collection + "/" + o.DocumentID
+}
+
+// OutboxStats describes how far Elasticsearch is behind MongoDB.
+type OutboxStats struct {
+	Pending         int64
+	OldestCreatedAt time.Time
+	// DeadLetters are the events the indexer gave up on, they are not
+	// counted in Pending.
+	DeadLetters int64
+}
+
+// Lag returns the age of the oldest pending event.
+func (s *OutboxStats) Lag() time.Duration {
+	if s.Pending == 0 {
+		return 0
+	}
+	return time.Since(s.OldestCreatedAt)
+}
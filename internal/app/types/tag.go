@@ -37,3 +37,12 @@ type MatchedTags struct {
 	MatchedOffers map[string][]string
 	MatchedWants  map[string][]string
 }
+
+// TagSuggestion is a tag used by trading members ranked for autocomplete.
+type TagSuggestion struct {
+	Name string
+	// Count is the number of trading members using the tag.
+	Count int
+	// LastAddedAt is the last time a trading member added the tag.
+	LastAddedAt time.Time
+}
@@ -21,7 +21,11 @@ type TradingRegisterData struct {
 	LastName  string
 	Telephone string
 	// Terms
-	Authorised string
+	Authorised       string
+	AgreementVersion int
+	// Agreement is the latest version of the membership agreement, nil
+	// when no version has been published.
+	Agreement *Agreement
 	// Recaptcha
 	RecaptchaSitekey string
 }
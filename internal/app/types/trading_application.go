@@ -0,0 +1,69 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TradingApplication is a request of a business to become a trading
+// member. A business has at most one open application, it is submitted
+// again when the admins ask for more information.
+type TradingApplication struct {
+	ID        primitive.ObjectID `json:"_id,omitempty"       bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+
+	BusinessID  primitive.ObjectID `json:"businessID,omitempty"  bson:"businessID,omitempty"`
+	UserID      primitive.ObjectID `json:"userID,omitempty"      bson:"userID,omitempty"`
+	Status      string             `json:"status,omitempty"      bson:"status,omitempty"`
+	SubmittedAt time.Time          `json:"submittedAt,omitempty" bson:"submittedAt,omitempty"`
+	DecidedAt   time.Time          `json:"decidedAt,omitempty"   bson:"decidedAt,omitempty"`
+	// Data is the latest submitted data.
+	Data *TradingApplicationData `json:"data,omitempty" bson:"data,omitempty"`
+	// Changes are the fields the latest submission changed.
+	Changes []string                   `json:"changes,omitempty" bson:"changes,omitempty"`
+	Notes   []*TradingApplicationNote  `json:"notes,omitempty"   bson:"notes,omitempty"`
+	Events  []*TradingApplicationEvent `json:"events,omitempty"  bson:"events,omitempty"`
+}
+
+// TradingApplicationData is the submitted business and user data.
+type TradingApplicationData struct {
+	BusinessName       string `json:"businessName,omitempty"       bson:"businessName,omitempty"`
+	IncType            string `json:"incType,omitempty"            bson:"incType,omitempty"`
+	CompanyNumber      string `json:"companyNumber,omitempty"      bson:"companyNumber,omitempty"`
+	BusinessPhone      string `json:"businessPhone,omitempty"      bson:"businessPhone,omitempty"`
+	Website            string `json:"website,omitempty"            bson:"website,omitempty"`
+	Turnover           int    `json:"turnover,omitempty"           bson:"turnover,omitempty"`
+	Description        string `json:"description,omitempty"        bson:"description,omitempty"`
+	LocationAddress    string `json:"locationAddress,omitempty"    bson:"locationAddress,omitempty"`
+	LocationCity       string `json:"locationCity,omitempty"       bson:"locationCity,omitempty"`
+	LocationRegion     string `json:"locationRegion,omitempty"     bson:"locationRegion,omitempty"`
+	LocationPostalCode string `json:"locationPostalCode,omitempty" bson:"locationPostalCode,omitempty"`
+	LocationCountry    string `json:"locationCountry,omitempty"    bson:"locationCountry,omitempty"`
+	FirstName          string `json:"firstName,omitempty"          bson:"firstName,omitempty"`
+	LastName           string `json:"lastName,omitempty"           bson:"lastName,omitempty"`
+	Telephone          string `json:"telephone,omitempty"          bson:"telephone,omitempty"`
+}
+
+// TradingApplicationNote is an internal note of a reviewer.
+type TradingApplicationNote struct {
+	CreatedAt time.Time `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	Email     string    `json:"email,omitempty"     bson:"email,omitempty"`
+	Text      string    `json:"text,omitempty"      bson:"text,omitempty"`
+}
+
+// TradingApplicationEvent is a submission or a decision. The message is
+// the one sent to the applicant.
+type TradingApplicationEvent struct {
+	CreatedAt time.Time `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	Email     string    `json:"email,omitempty"     bson:"email,omitempty"`
+	Action    string    `json:"action,omitempty"    bson:"action,omitempty"`
+	Message   string    `json:"message,omitempty"   bson:"message,omitempty"`
+}
+
+type FindTradingApplicationResult struct {
+	TradingApplications []*TradingApplication
+	NumberOfResults     int
+	TotalPages          int
+}
@@ -18,11 +18,16 @@ type UserAction struct {
 	Action        string             `json:"action,omitempty"        bson:"action,omitempty"`
 	ActionDetails string             `json:"actionDetails,omitempty" bson:"actionDetails,omitempty"`
 	Category      string             `json:"category,omitempty"      bson:"category,omitempty"`
+	IPAddress     string             `json:"ipAddress,omitempty"     bson:"ipAddress,omitempty"`
 }
 
 type UserActionSearchCriteria struct {
 	Email    string
 	Category string
-	DateFrom time.Time
-	DateTo   time.Time
+	// Details is matched against the words of the action details.
+	Details   string
+	Action    string
+	IPAddress string
+	DateFrom  time.Time
+	DateTo    time.Time
 }
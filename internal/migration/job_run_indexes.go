@@ -0,0 +1,23 @@
+package migration
+
+import (
+	"log"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+)
+
+// CreateJobRunIndexes creates the index of the background job history.
+// It runs on every start, existing indexes are left as they are.
+func CreateJobRunIndexes() {
+	log.Println("start creating job run indexes")
+	startTime := time.Now()
+
+	err := mongo.JobRun.EnsureIndexes()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	log.Printf("took  %v\n\n", time.Now().Sub(startTime))
+}
@@ -0,0 +1,23 @@
+package migration
+
+import (
+	"log"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+)
+
+// CreateOutboxIndexes creates the index of the outbox dead letters.
+// It runs on every start, existing indexes are left as they are.
+func CreateOutboxIndexes() {
+	log.Println("start creating outbox indexes")
+	startTime := time.Now()
+
+	err := mongo.Outbox.EnsureIndexes()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	log.Printf("took  %v\n\n", time.Now().Sub(startTime))
+}
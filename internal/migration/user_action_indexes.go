@@ -0,0 +1,23 @@
+package migration
+
+import (
+	"log"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+)
+
+// CreateUserActionIndexes creates the indexes the activity log search
+// uses. It runs on every start, existing indexes are left as they are.
+func CreateUserActionIndexes() {
+	log.Println("start creating user action indexes")
+	startTime := time.Now()
+
+	err := mongo.UserAction.EnsureIndexes()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	log.Printf("took  %v\n\n", time.Now().Sub(startTime))
+}
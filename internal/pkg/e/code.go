@@ -11,6 +11,17 @@ const (
 	InvalidPageNumber
 	ExceedMaxPosBalance
 	ExceedMaxNegBalance
+	InvalidInterval
+	ApplicationDecided
+	ApplicationMessageRequired
+	AgreementNotFound
+	AgreementNotAccepted
+	JobNotFound
+	JobRunning
+	InvalidInput
+	TagNotFound
+	TagExisted
+	AccountFlagNotFound
 )
 
 var Msg = map[int]string{
@@ -24,4 +35,16 @@ var Msg = map[int]string{
 	InvalidPageNumber:   "Invalid page number: should start with 1.",
 	ExceedMaxPosBalance: "Transfer rejected: receiver will exceed maximum balance limit.",
 	ExceedMaxNegBalance: "Transfer rejected: you will exceed your maximum negative balance limit.",
+	InvalidInterval:     "Invalid interval: should be day, week or month.",
+	ApplicationDecided:  "The application has already been decided.",
+
+	ApplicationMessageRequired: "Please tell the applicant what information is needed.",
+	AgreementNotFound:          "Membership agreement not found.",
+	AgreementNotAccepted:       "Please accept the latest Membership Agreement before trading.",
+	JobNotFound:                "Job not found.",
+	JobRunning:                 "The job is already running.",
+	InvalidInput:               "Invalid input.",
+	TagNotFound:                "Tag not found.",
+	TagExisted:                 "Tag already exists.",
+	AccountFlagNotFound:        "Account flag not found.",
 }
@@ -0,0 +1,49 @@
+package email
+
+import (
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+)
+
+type accountFlag struct{}
+
+var AccountFlag = &accountFlag{}
+
+// Summary sends the open account flags to the admins. The names are the
+// business names keyed by business id.
+func (a *accountFlag) Summary(flags []*types.AccountFlag, names map[string]string) error {
+	link := global.Config().URL + "/admin/flagged-accounts?status=open&page=1"
+
+	text := strconv.Itoa(len(flags)) + " accounts are flagged as dormant or at risk: " + link + "\n\n"
+	rows := ""
+	for _, f := range flags {
+		name := names[f.BusinessID.Hex()]
+		balance := strconv.FormatFloat(f.Balance, 'f', 2, 64)
+		reasons := strings.Join(f.Reasons, ", ")
+		text += name + " (" + balance + "): " + reasons + "\n"
+		rows += "<tr><td>" + html.EscapeString(name) + "</td><td>" + balance +
+			"</td><td>" + html.EscapeString(reasons) + "</td><td>" +
+			f.CreatedAt.Format("2006-01-02") + "</td></tr>"
+	}
+	body := "<p>" + strconv.Itoa(len(flags)) + " accounts are flagged as dormant or at risk. " +
+		"<a href=\"" + link + "\">Review the flagged accounts</a>.</p>" +
+		"<table><tr><th>Business</th><th>Balance</th><th>Reasons</th><th>Flagged</th></tr>" +
+		rows + "</table>"
+
+	d := emailData{
+		receiver:      global.Config().EmailFrom,
+		receiverEmail: global.Config().Sendgrid.SenderEmail,
+		subject:       "[Account Check] " + strconv.Itoa(len(flags)) + " flagged accounts",
+		text:          text,
+		html:          body,
+	}
+	err := e.send(d)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -3,7 +3,7 @@ package email
 import (
 	"time"
 
-	"github.com/spf13/viper"
+	"github.com/ic3network/mccs-alpha/global"
 )
 
 type balance struct{}
@@ -18,8 +18,8 @@ func (b *balance) NonZeroBalance(from time.Time, to time.Time) error {
 	) + " in the posting table."
 
 	d := emailData{
-		receiver:      viper.GetString("email_from"),
-		receiverEmail: viper.GetString("sendgrid.sender_email"),
+		receiver:      global.Config().EmailFrom,
+		receiverEmail: global.Config().Sendgrid.SenderEmail,
 		subject:       "[System Check] Non-zero balance encountered",
 		text:          body,
 		html:          body,
@@ -0,0 +1,80 @@
+package email
+
+import (
+	"html"
+	"strconv"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+)
+
+type businessStatus struct{}
+
+var BusinessStatus = &businessStatus{}
+
+// statusMessages are the subjects and messages sent when an admin changes
+// the status of a business.
+var statusMessages = map[string]struct {
+	subject string
+	text    string
+}{
+	constant.Business.Accepted: {
+		"Your business is listed in the directory",
+		"has been accepted and is now listed in the directory.",
+	},
+	constant.Business.Rejected: {
+		"Your directory listing",
+		"has not been accepted into the directory. Please reply to this email if you have any questions.",
+	},
+	constant.Trading.Accepted: {
+		"You can now trade on the Open Credit Network",
+		"has been accepted as a trading member. You can now send and receive transfers.",
+	},
+	constant.Trading.Rejected: {
+		"Your trading membership application",
+		"has not been accepted as a trading member. Please reply to this email if you have any questions.",
+	},
+}
+
+// Changed tells the user the new status of the business. Nothing is sent
+// for the statuses without a message.
+func (b *businessStatus) Changed(user *types.User, businessName string, status string) error {
+	msg, ok := statusMessages[status]
+	if !ok {
+		return nil
+	}
+	body := businessName + " " + msg.text + " " + global.Config().URL
+	d := emailData{
+		receiver:      user.FirstName + " " + user.LastName,
+		receiverEmail: user.Email,
+		replyToName:   global.Config().EmailFrom,
+		replyToEmail:  global.Config().Sendgrid.SenderEmail,
+		subject:       msg.subject,
+		text:          body,
+		html:          html.EscapeString(body),
+	}
+	return e.send(d)
+}
+
+// BalanceLimitChanged tells the user the new balance limits of the
+// business.
+func (b *businessStatus) BalanceLimitChanged(
+	user *types.User,
+	businessName string,
+	limit *types.BalanceLimit,
+) error {
+	body := "The balance limits of " + businessName + " have been changed. You can now go down to -" +
+		strconv.FormatFloat(limit.MaxNegBal, 'f', 2, 64) + " and up to " +
+		strconv.FormatFloat(limit.MaxPosBal, 'f', 2, 64) + "."
+	d := emailData{
+		receiver:      user.FirstName + " " + user.LastName,
+		receiverEmail: user.Email,
+		replyToName:   global.Config().EmailFrom,
+		replyToEmail:  global.Config().Sendgrid.SenderEmail,
+		subject:       "Your balance limits have changed",
+		text:          body,
+		html:          html.EscapeString(body),
+	}
+	return e.send(d)
+}
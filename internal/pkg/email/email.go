@@ -7,10 +7,11 @@ import (
 	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
 	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/ic3network/mccs-alpha/internal/pkg/metrics"
 	"github.com/ic3network/mccs-alpha/internal/pkg/template"
+	"github.com/sendgrid/rest"
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
-	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
 
@@ -21,23 +22,33 @@ func init() {
 	e = New()
 }
 
+// Sender delivers the messages, the SendGrid client in the app.
+type Sender interface {
+	Send(email *mail.SGMailV3) (*rest.Response, error)
+}
+
+// UseSender delivers the emails with s, e.g. to capture them in the tests.
+func UseSender(s Sender) {
+	e.client = s
+}
+
 // Email is a prioritized configuration registry.
 type Email struct {
 	serverAddr string
 	from       *mail.Email
-	client     *sendgrid.Client
+	client     Sender
 }
 
 // New returns an initialized Email instance.
 func New() *Email {
 	e := new(Email)
-	e.serverAddr = viper.GetString("url")
+	e.serverAddr = global.Config().URL
 	// Always send from MCCS
 	e.from = mail.NewEmail(
-		viper.GetString("email_from"),
-		viper.GetString("sendgrid.sender_email"),
+		global.Config().EmailFrom,
+		global.Config().Sendgrid.SenderEmail,
 	)
-	e.client = sendgrid.NewSendClient(viper.GetString("sendgrid.key"))
+	e.client = sendgrid.NewSendClient(global.Config().Sendgrid.Key)
 	return e
 }
 
@@ -65,6 +76,7 @@ func (e *Email) send(d emailData) error {
 	}
 
 	info, err := e.client.Send(message)
+	metrics.EmailSent(err)
 	if err != nil {
 		l.Logger.Error("error sending email", zap.String("info", info.Body))
 		return err
@@ -152,8 +164,8 @@ func SendNewMemberSignupEmail(businessName, email string) error {
 }
 func (e *Email) sendNewMemberSignupEmail(businessName, email string) error {
 	d := emailData{
-		receiver:      viper.GetString("email_from"),
-		receiverEmail: viper.GetString("sendgrid.sender_email"),
+		receiver:      global.Config().EmailFrom,
+		receiverEmail: global.Config().Sendgrid.SenderEmail,
 		subject:       "New Trading Member Application",
 		text:          "New Trading Member Application",
 		html:          "Business Name: " + businessName + ", Email Address: " + email,
@@ -215,7 +227,7 @@ func (e *Email) sendDailyEmailList(
 		User:          user,
 		MatchedOffers: matchedTags.MatchedOffers,
 		MatchedWants:  matchedTags.MatchedWants,
-		URL:           viper.GetString("url"),
+		URL:           global.Config().URL,
 	}
 
 	var tpl bytes.Buffer
@@ -270,12 +282,12 @@ func (e *Email) sendContactBusiness(
 
 	// Send a copy of the email to the sengrid: sender_email address.
 	go func() {
-		if !viper.GetBool("receive_trade_contact_emails") {
+		if !global.Config().ReceiveTradeContactEmails {
 			return
 		}
 		d := emailData{
-			receiver:      viper.GetString("email_from"),
-			receiverEmail: viper.GetString("sendgrid.sender_email"),
+			receiver:      global.Config().EmailFrom,
+			receiverEmail: global.Config().Sendgrid.SenderEmail,
 			subject:       "Contact from OCN directory member " + replyToName + " to " + receiver,
 			text:          body,
 			html:          body,
@@ -300,8 +312,8 @@ func (e *Email) sendSignupNotification(
 ) error {
 	body := "Business Name: " + businessName + ", Contact Email: " + contactEmail
 	d := emailData{
-		receiver:      viper.GetString("email_from"),
-		receiverEmail: viper.GetString("sendgrid.sender_email"),
+		receiver:      global.Config().EmailFrom,
+		receiverEmail: global.Config().Sendgrid.SenderEmail,
 		subject:       "A new business has been signed up!",
 		text:          body,
 		html:          body,
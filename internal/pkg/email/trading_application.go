@@ -0,0 +1,77 @@
+package email
+
+import (
+	"bytes"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/template"
+)
+
+type tradingApplication struct{}
+
+var TradingApplication = &tradingApplication{}
+
+// applicationEmails are the templates in tradingApplication.html and the
+// subjects of the decisions.
+var applicationEmails = map[string]struct {
+	template string
+	subject  string
+}{
+	constant.ApplicationEvent.Approved: {
+		"tradingApplicationApproved",
+		"Your Trading Membership Application Has Been Approved",
+	},
+	constant.ApplicationEvent.Rejected: {
+		"tradingApplicationRejected",
+		"Your Trading Membership Application",
+	},
+	constant.ApplicationEvent.InfoRequested: {
+		"tradingApplicationInfoRequested",
+		"More Information Needed for Your Trading Membership Application",
+	},
+}
+
+// Decided tells the applicant about the decision. The message of the
+// reviewer is added to the email.
+func (t *tradingApplication) Decided(
+	user *types.User,
+	businessName string,
+	action string,
+	message string,
+) error {
+	m, ok := applicationEmails[action]
+	if !ok {
+		return nil
+	}
+	tpl, err := template.NewEmailView("tradingApplication")
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		FirstName    string
+		BusinessName string
+		Message      string
+		URL          string
+	}{
+		FirstName:    user.FirstName,
+		BusinessName: businessName,
+		Message:      message,
+		URL:          global.Config().URL,
+	}
+	var buf bytes.Buffer
+	if err := tpl.ExecuteTemplate(&buf, m.template, data); err != nil {
+		return err
+	}
+
+	d := emailData{
+		receiver:      user.FirstName + " " + user.LastName,
+		receiverEmail: user.Email,
+		subject:       m.subject,
+		text:          m.subject,
+		html:          buf.String(),
+	}
+	return e.send(d)
+}
@@ -3,8 +3,8 @@ package email
 import (
 	"fmt"
 
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
-	"github.com/spf13/viper"
 )
 
 type transaction struct{}
@@ -44,7 +44,7 @@ func (tr *transaction) Initiate(
 	t *types.Transaction,
 ) error {
 	info := tr.getEmailInfo(t)
-	url := viper.GetString("url") + "/pending_transactions"
+	url := global.Config().URL + "/pending_transactions"
 
 	var body string
 	if transactionType == "send" {
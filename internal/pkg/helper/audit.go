@@ -0,0 +1,28 @@
+package helper
+
+import (
+	"net/http"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/ip"
+)
+
+// CorrelationIDHeader carries the ID that ties the audit events to the
+// request they were made in.
+const CorrelationIDHeader = "X-Request-ID"
+
+// AuditRequest returns the request details of the audit events. A new
+// correlation ID is generated when the request does not carry one.
+func AuditRequest(r *http.Request) *types.AuditRequest {
+	id := r.Header.Get(CorrelationIDHeader)
+	if id == "" {
+		if u, err := uuid.NewV4(); err == nil {
+			id = u.String()
+		}
+	}
+	return &types.AuditRequest{
+		IPAddress:     ip.FromRequest(r),
+		CorrelationID: id,
+	}
+}
@@ -0,0 +1,195 @@
+package helper
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+)
+
+// DirectoryColumns are the columns of the directory CSV files.
+var DirectoryColumns = []string{
+	"business_name",
+	"inc_type",
+	"company_number",
+	"business_phone",
+	"website",
+	"turnover",
+	"offers",
+	"wants",
+	"description",
+	"location_address",
+	"location_city",
+	"location_region",
+	"location_postal_code",
+	"location_country",
+	"status",
+	"admin_tags",
+	"first_name",
+	"last_name",
+	"email",
+	"telephone",
+	"password",
+	"daily_notification",
+	"max_pos_bal",
+	"max_neg_bal",
+}
+
+// ParseDirectoryCSV reads the rows of a CSV file with a header line.
+// The columns can be in any order, the unknown columns are ignored and
+// the tags are separated by commas.
+func ParseDirectoryCSV(r io.Reader) ([]*types.DirectoryRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	rows := []*types.DirectoryRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		get := func(column string) string {
+			i, ok := index[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		row := directoryRowFromCSV(get)
+		row.Line, _ = reader.FieldPos(0)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func directoryRowFromCSV(get func(column string) string) *types.DirectoryRow {
+	row := &types.DirectoryRow{
+		BusinessName:       get("business_name"),
+		IncType:            get("inc_type"),
+		CompanyNumber:      get("company_number"),
+		BusinessPhone:      get("business_phone"),
+		Website:            get("website"),
+		Offers:             splitList(get("offers")),
+		Wants:              splitList(get("wants")),
+		Description:        get("description"),
+		LocationAddress:    get("location_address"),
+		LocationCity:       get("location_city"),
+		LocationRegion:     get("location_region"),
+		LocationPostalCode: get("location_postal_code"),
+		LocationCountry:    get("location_country"),
+		Status:             get("status"),
+		AdminTags:          splitList(get("admin_tags")),
+		FirstName:          get("first_name"),
+		LastName:           get("last_name"),
+		Email:              get("email"),
+		Telephone:          get("telephone"),
+		Password:           get("password"),
+	}
+	if v := get("turnover"); v != "" {
+		turnover, err := strconv.Atoi(v)
+		if err != nil {
+			row.ParseErrors = append(row.ParseErrors, "Turnover should be a whole number.")
+		}
+		row.Turnover = turnover
+	}
+	if v := get("daily_notification"); v != "" {
+		daily, err := strconv.ParseBool(v)
+		if err != nil {
+			row.ParseErrors = append(row.ParseErrors, "Daily notification should be true or false.")
+		}
+		row.DailyNotification = daily
+	}
+	if v := get("max_pos_bal"); v != "" {
+		maxPosBal, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			row.ParseErrors = append(row.ParseErrors, "Max pos balance should be a number.")
+		}
+		row.MaxPosBal = &maxPosBal
+	}
+	if v := get("max_neg_bal"); v != "" {
+		maxNegBal, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			row.ParseErrors = append(row.ParseErrors, "Max neg balance should be a number.")
+		}
+		row.MaxNegBal = &maxNegBal
+	}
+	return row
+}
+
+func splitList(s string) []string {
+	list := []string{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// ParseDirectoryJSON reads the rows of a JSON array.
+func ParseDirectoryJSON(r io.Reader) ([]*types.DirectoryRow, error) {
+	rows := []*types.DirectoryRow{}
+	err := json.NewDecoder(r).Decode(&rows)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// DirectoryCSVRecord returns the values of the row in the order of
+// DirectoryColumns.
+func DirectoryCSVRecord(row *types.DirectoryRow) []string {
+	formatBal := func(bal *float64) string {
+		if bal == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*bal, 'f', -1, 64)
+	}
+	turnover := ""
+	if row.Turnover != 0 {
+		turnover = strconv.Itoa(row.Turnover)
+	}
+	return []string{
+		row.BusinessName,
+		row.IncType,
+		row.CompanyNumber,
+		row.BusinessPhone,
+		row.Website,
+		turnover,
+		strings.Join(row.Offers, ","),
+		strings.Join(row.Wants, ","),
+		row.Description,
+		row.LocationAddress,
+		row.LocationCity,
+		row.LocationRegion,
+		row.LocationPostalCode,
+		row.LocationCountry,
+		row.Status,
+		strings.Join(row.AdminTags, ","),
+		row.FirstName,
+		row.LastName,
+		row.Email,
+		row.Telephone,
+		row.Password,
+		strconv.FormatBool(row.DailyNotification),
+		formatBal(row.MaxPosBal),
+		formatBal(row.MaxNegBal),
+	}
+}
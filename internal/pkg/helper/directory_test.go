@@ -0,0 +1,77 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDirectoryCSV(t *testing.T) {
+	input := "Email,business_name,offers,turnover,max_pos_bal,unknown\n" +
+		"a@example.com,Bakery,\"bread,\ncakes\",12,500,x\n" +
+		"b@example.com,Shop,,many,,\n"
+
+	rows, err := ParseDirectoryCSV(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, 4, rows[1].Line)
+
+	assert.Equal(t, "Bakery", rows[0].BusinessName)
+	assert.Equal(t, "a@example.com", rows[0].Email)
+	assert.Equal(t, []string{"bread", "cakes"}, rows[0].Offers)
+	assert.Equal(t, 12, rows[0].Turnover)
+	require.NotNil(t, rows[0].MaxPosBal)
+	assert.Equal(t, 500.0, *rows[0].MaxPosBal)
+	assert.Nil(t, rows[0].MaxNegBal)
+	assert.Empty(t, rows[0].ParseErrors)
+
+	assert.Equal(t, []string{}, rows[1].Offers)
+	assert.Equal(t, []string{"Turnover should be a whole number."}, rows[1].ParseErrors)
+}
+
+func TestParseDirectoryJSON(t *testing.T) {
+	input := `[{"business_name": "Bakery", "email": "a@example.com", "offers": ["bread"], "max_neg_bal": 100}]`
+
+	rows, err := ParseDirectoryJSON(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "Bakery", rows[0].BusinessName)
+	assert.Equal(t, []string{"bread"}, rows[0].Offers)
+	require.NotNil(t, rows[0].MaxNegBal)
+	assert.Equal(t, 100.0, *rows[0].MaxNegBal)
+}
+
+func TestDirectoryCSVRecord(t *testing.T) {
+	maxPosBal, maxNegBal := 500.0, 0.0
+	row := &types.DirectoryRow{
+		BusinessName: "Bakery",
+		Offers:       []string{"bread", "cakes"},
+		AdminTags:    []string{"partner"},
+		Email:        "a@example.com",
+		MaxPosBal:    &maxPosBal,
+		MaxNegBal:    &maxNegBal,
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write(DirectoryColumns)
+	writer.Write(DirectoryCSVRecord(row))
+	writer.Flush()
+
+	rows, err := ParseDirectoryCSV(&buf)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, row.BusinessName, rows[0].BusinessName)
+	assert.Equal(t, row.Offers, rows[0].Offers)
+	assert.Equal(t, row.AdminTags, rows[0].AdminTags)
+	assert.Equal(t, row.Email, rows[0].Email)
+	assert.Equal(t, maxPosBal, *rows[0].MaxPosBal)
+	assert.Equal(t, maxNegBal, *rows[0].MaxNegBal)
+	assert.Empty(t, rows[0].ParseErrors)
+}
@@ -13,6 +13,7 @@ var Trading = &trading{}
 
 func (t *trading) GetRegisterData(r *http.Request) *types.TradingRegisterData {
 	turnover, _ := strconv.Atoi(r.FormValue("turnover"))
+	agreementVersion, _ := strconv.Atoi(r.FormValue("agreement_version"))
 	return &types.TradingRegisterData{
 		BusinessName:       r.FormValue("business_name"),        // 100 chars
 		IncType:            r.FormValue("inc_type"),             // 25 chars
@@ -30,6 +31,7 @@ func (t *trading) GetRegisterData(r *http.Request) *types.TradingRegisterData {
 		LastName:           r.FormValue("last_name"),            // 100 chars
 		Telephone:          r.FormValue("telephone"),            // 25 chars
 		Authorised:         r.FormValue("authorised"),
+		AgreementVersion:   agreementVersion,
 	}
 }
 
@@ -1,16 +1,51 @@
 package jsonerror
 
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/requestid"
+	"github.com/unrolled/render"
+)
+
+// JE is the error returned by the JSON endpoints. The code is the e package
+// code, so the clients can tell the errors apart without parsing the message.
 type JE struct {
-	Code    string
-	message string
+	Code      string
+	message   string
+	requestID string
 }
 
-// New creates a new JE struct.
-func New(code, message string) JE {
-	j := JE{Code: code, message: message}
+// New creates a new JE struct with a message of its own.
+func New(code int, message string) JE {
+	j := JE{Code: strconv.Itoa(code), message: message}
 	return j
 }
 
+// FromError creates the JE of an e.Error. Any other error is reported as an
+// internal server error, so the system errors are not shown to the users.
+func FromError(err error) JE {
+	if v, ok := err.(e.Error); ok {
+		if v.CustomMessage != "" {
+			return New(e.InvalidInput, v.CustomMessage)
+		}
+		return New(v.Code, v.Message())
+	}
+	return New(e.InternalServerError, e.Msg[e.InternalServerError])
+}
+
 func (j JE) Render() map[string]string {
-	return map[string]string{"code": j.Code, "message": j.message}
+	m := map[string]string{"code": j.Code, "message": j.message}
+	if j.requestID != "" {
+		m["requestID"] = j.requestID
+	}
+	return m
+}
+
+// Write renders the error envelope with the ID of the request, e.g.
+// {"code": "2", "message": "...", "requestID": "..."}.
+func Write(w http.ResponseWriter, r *http.Request, status int, j JE) {
+	j.requestID = requestid.FromContext(r.Context())
+	render.New().JSON(w, status, j.Render())
 }
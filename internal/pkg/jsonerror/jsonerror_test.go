@@ -0,0 +1,54 @@
+package jsonerror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ic3network/mccs-alpha/internal/pkg/e"
+	"github.com/ic3network/mccs-alpha/internal/pkg/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    int
+		message string
+	}{
+		{"e error", e.New(e.BusinessNotFound, "no documents"), e.BusinessNotFound, e.Msg[e.BusinessNotFound]},
+		{"wrapped e error", e.Wrap(e.New(e.TagNotFound, "no documents"), "find"), e.TagNotFound, e.Msg[e.TagNotFound]},
+		{"custom message", e.CustomMessage("Please enter a name."), e.InvalidInput, "Please enter a name."},
+		{"system error", errors.New("connection refused"), e.InternalServerError, e.Msg[e.InternalServerError]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered := FromError(tt.err).Render()
+			assert.Equal(t, strconv.Itoa(tt.code), rendered["code"])
+			assert.Equal(t, tt.message, rendered["message"])
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	r := httptest.NewRequest("POST", "/api/acceptTransaction", nil)
+	r = r.WithContext(requestid.NewContext(r.Context(), "req-123"))
+	w := httptest.NewRecorder()
+
+	Write(w, r, http.StatusForbidden, New(e.AgreementNotAccepted, e.Msg[e.AgreementNotAccepted]))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, map[string]string{
+		"code":      strconv.Itoa(e.AgreementNotAccepted),
+		"message":   e.Msg[e.AgreementNotAccepted],
+		"requestID": "req-123",
+	}, body)
+}
@@ -8,7 +8,7 @@ import (
 	"time"
 
 	jwtlib "github.com/golang-jwt/jwt/v5"
-	"github.com/spf13/viper"
+	"github.com/ic3network/mccs-alpha/global"
 )
 
 // JWTManager manages JWT operations.
@@ -19,8 +19,16 @@ type JWTManager struct {
 
 // NewJWTManager initializes and returns a new JWTManager instance.
 func NewJWTManager() *JWTManager {
-	privateKeyPEM := getEnvOrFallback("jwt.private_key", "JWT_PRIVATE_KEY")
-	publicKeyPEM := getEnvOrFallback("jwt.public_key", "JWT_PUBLIC_KEY")
+	privateKeyPEM := getEnvOrFallback(
+		global.Config().JWT.PrivateKey,
+		"jwt.private_key",
+		"JWT_PRIVATE_KEY",
+	)
+	publicKeyPEM := getEnvOrFallback(
+		global.Config().JWT.PublicKey,
+		"jwt.public_key",
+		"JWT_PUBLIC_KEY",
+	)
 
 	signKey, err := jwtlib.ParseRSAPrivateKeyFromPEM([]byte(privateKeyPEM))
 	if err != nil {
@@ -83,8 +91,7 @@ func (jm *JWTManager) Validate(tokenString string) (*userClaims, error) {
 	return claims, nil
 }
 
-func getEnvOrFallback(viperKey, envKey string) string {
-	value := viper.GetString(viperKey)
+func getEnvOrFallback(value, viperKey, envKey string) string {
 	if value == "" {
 		value = os.Getenv(viperKey)
 	}
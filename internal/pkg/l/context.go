@@ -0,0 +1,29 @@
+package l
+
+import (
+	"context"
+
+	"github.com/ic3network/mccs-alpha/internal/pkg/requestid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// WithContext returns the logger with the request ID and the trace and span
+// IDs of the context, so the log lines of a request can be found together
+// and from a trace.
+func WithContext(ctx context.Context) *zap.Logger {
+	var fields []zap.Field
+	if id := requestid.FromContext(ctx); id != "" {
+		fields = append(fields, zap.String("requestID", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields,
+			zap.String("traceID", sc.TraceID().String()),
+			zap.String("spanID", sc.SpanID().String()),
+		)
+	}
+	if len(fields) == 0 {
+		return Logger
+	}
+	return Logger.With(fields...)
+}
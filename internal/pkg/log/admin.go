@@ -17,14 +17,15 @@ func (a admin) LoginSuccess(
 	admin *types.AdminUser,
 	ip string,
 ) *types.UserAction {
-	admin.Email = strings.ToLower(admin.Email)
+	email := strings.ToLower(admin.Email)
 	return &types.UserAction{
 		UserID: admin.ID,
-		Email:  admin.Email,
+		Email:  email,
 		Action: "admin user login successful",
 		// [email] - [IP address]
-		ActionDetails: admin.Email + " - " + ip,
+		ActionDetails: email + " - " + ip,
 		Category:      "admin",
+		IPAddress:     ip,
 	}
 }
 
@@ -32,14 +33,15 @@ func (a admin) LoginFailure(
 	admin *types.AdminUser,
 	ip string,
 ) *types.UserAction {
-	admin.Email = strings.ToLower(admin.Email)
+	email := strings.ToLower(admin.Email)
 	return &types.UserAction{
 		UserID: admin.ID,
-		Email:  admin.Email,
+		Email:  email,
 		Action: "admin user login failed",
 		// [email] - [IP address]
-		ActionDetails: admin.Email + " - " + ip,
+		ActionDetails: email + " - " + ip,
 		Category:      "admin",
+		IPAddress:     ip,
 	}
 }
 
@@ -141,12 +143,12 @@ func (a admin) CreateTag(
 	admin *types.AdminUser,
 	tagName string,
 ) *types.UserAction {
-	admin.Email = strings.ToLower(admin.Email)
+	email := strings.ToLower(admin.Email)
 	return &types.UserAction{
 		UserID:        admin.ID,
-		Email:         admin.Email,
+		Email:         email,
 		Action:        "admin created new tag",
-		ActionDetails: admin.Email + " - " + tagName,
+		ActionDetails: email + " - " + tagName,
 		Category:      "admin",
 	}
 }
@@ -156,12 +158,12 @@ func (a admin) ModifyTag(
 	old string,
 	new string,
 ) *types.UserAction {
-	admin.Email = strings.ToLower(admin.Email)
+	email := strings.ToLower(admin.Email)
 	return &types.UserAction{
 		UserID:        admin.ID,
-		Email:         admin.Email,
+		Email:         email,
 		Action:        "admin modified a tag",
-		ActionDetails: admin.Email + " - " + old + " -> " + new,
+		ActionDetails: email + " - " + old + " -> " + new,
 		Category:      "admin",
 	}
 }
@@ -170,12 +172,12 @@ func (a admin) DeleteTag(
 	admin *types.AdminUser,
 	tagName string,
 ) *types.UserAction {
-	admin.Email = strings.ToLower(admin.Email)
+	email := strings.ToLower(admin.Email)
 	return &types.UserAction{
 		UserID:        admin.ID,
-		Email:         admin.Email,
+		Email:         email,
 		Action:        "admin deleted a tag",
-		ActionDetails: admin.Email + " - " + tagName,
+		ActionDetails: email + " - " + tagName,
 		Category:      "admin",
 	}
 }
@@ -184,12 +186,12 @@ func (a admin) CreateAdminTag(
 	admin *types.AdminUser,
 	tagName string,
 ) *types.UserAction {
-	admin.Email = strings.ToLower(admin.Email)
+	email := strings.ToLower(admin.Email)
 	return &types.UserAction{
 		UserID:        admin.ID,
-		Email:         admin.Email,
+		Email:         email,
 		Action:        "admin created new admin tag",
-		ActionDetails: admin.Email + " - " + tagName,
+		ActionDetails: email + " - " + tagName,
 		Category:      "admin",
 	}
 }
@@ -199,12 +201,12 @@ func (a admin) ModifyAdminTag(
 	old string,
 	new string,
 ) *types.UserAction {
-	admin.Email = strings.ToLower(admin.Email)
+	email := strings.ToLower(admin.Email)
 	return &types.UserAction{
 		UserID:        admin.ID,
-		Email:         admin.Email,
+		Email:         email,
 		Action:        "admin modified an admin tag",
-		ActionDetails: admin.Email + " - " + old + " -> " + new,
+		ActionDetails: email + " - " + old + " -> " + new,
 		Category:      "admin",
 	}
 }
@@ -213,12 +215,12 @@ func (a admin) DeleteAdminTag(
 	admin *types.AdminUser,
 	tagName string,
 ) *types.UserAction {
-	admin.Email = strings.ToLower(admin.Email)
+	email := strings.ToLower(admin.Email)
 	return &types.UserAction{
 		UserID:        admin.ID,
-		Email:         admin.Email,
+		Email:         email,
 		Action:        "admin deleted an admin tag",
-		ActionDetails: admin.Email + " - " + tagName,
+		ActionDetails: email + " - " + tagName,
 		Category:      "admin",
 	}
 }
@@ -230,16 +232,85 @@ func (a admin) Transfer(
 	amount float64,
 	desc string,
 ) *types.UserAction {
-	admin.Email = strings.ToLower(admin.Email)
+	email := strings.ToLower(admin.Email)
 	return &types.UserAction{
 		UserID: admin.ID,
-		Email:  admin.Email,
+		Email:  email,
 		Action: "admin transfer for user",
 		// admin - [from] -> [to] - [amount]
-		ActionDetails: admin.Email + " - " + fromEmail + " -> " + toEmail + " - " + fmt.Sprintf(
+		ActionDetails: email + " - " + fromEmail + " -> " + toEmail + " - " + fmt.Sprintf(
 			"%.2f",
 			amount,
 		) + " - " + desc,
 		Category: "admin",
 	}
 }
+
+func (a admin) ImportDirectory(
+	admin *types.AdminUser,
+	fileName string,
+	report *types.ImportReport,
+) *types.UserAction {
+	email := strings.ToLower(admin.Email)
+	return &types.UserAction{
+		UserID: admin.ID,
+		Email:  email,
+		Action: "admin imported businesses",
+		// admin - [file] - [created] created - [failed] failed
+		ActionDetails: email + " - " + fileName + " - " + fmt.Sprintf(
+			"%d created - %d failed",
+			report.Created,
+			report.Failed,
+		),
+		Category: "admin",
+	}
+}
+
+func (a admin) PublishAgreement(
+	admin *types.AdminUser,
+	agreement *types.Agreement,
+) *types.UserAction {
+	email := strings.ToLower(admin.Email)
+	return &types.UserAction{
+		UserID: admin.ID,
+		Email:  email,
+		Action: "admin published membership agreement",
+		// admin - version [version] - required for trading: [bool]
+		ActionDetails: email + " - " + fmt.Sprintf(
+			"version %d - required for trading: %t",
+			agreement.Version,
+			agreement.RequiredForTrading,
+		),
+		Category: "admin",
+	}
+}
+
+func (a admin) TriggerJob(admin *types.AdminUser, job string) *types.UserAction {
+	email := strings.ToLower(admin.Email)
+	return &types.UserAction{
+		UserID: admin.ID,
+		Email:  email,
+		Action: "admin triggered job",
+		// admin - [job]
+		ActionDetails: email + " - " + job,
+		Category:      "admin",
+	}
+}
+
+func (a admin) DeleteBusiness(
+	admin *types.AdminUser,
+	business *types.Business,
+	user *types.User,
+) *types.UserAction {
+	email := strings.ToLower(admin.Email)
+	return &types.UserAction{
+		UserID: admin.ID,
+		Email:  email,
+		Action: "admin deleted business",
+		// admin - [business] - [user email]
+		ActionDetails: email + " - " + business.BusinessName + " - " + strings.ToLower(
+			user.Email,
+		),
+		Category: "admin",
+	}
+}
@@ -0,0 +1,332 @@
+package log
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/helper"
+	"github.com/ic3network/mccs-alpha/internal/pkg/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type audit struct{}
+
+// Audit builds the structured audit events of the admin changes.
+var Audit = audit{}
+
+func auditChanges(diffs []util.FieldDiff) []*types.AuditChange {
+	changes := make([]*types.AuditChange, 0, len(diffs))
+	for _, d := range diffs {
+		changes = append(changes, &types.AuditChange{
+			Field:  d.Field,
+			Before: d.Old,
+			After:  d.New,
+		})
+	}
+	return changes
+}
+
+func newAuditEvent(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	action string,
+	targetType string,
+	targetID string,
+	changes []*types.AuditChange,
+) *types.AuditEvent {
+	return &types.AuditEvent{
+		ActorID:       admin.ID,
+		ActorEmail:    strings.ToLower(admin.Email),
+		Action:        action,
+		TargetType:    targetType,
+		TargetID:      targetID,
+		Changes:       changes,
+		IPAddress:     req.IPAddress,
+		CorrelationID: req.CorrelationID,
+	}
+}
+
+func (a audit) ModifyBusiness(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	oldBusiness *types.Business,
+	newBusiness *types.BusinessData,
+	oldBalance *types.BalanceLimit,
+	newBalance *types.BalanceLimit,
+) *types.AuditEvent {
+	changes := auditChanges(util.Diff(oldBusiness, newBusiness, nil))
+	if !helper.SameTags(newBusiness.Offers, oldBusiness.Offers) {
+		changes = append(changes, &types.AuditChange{
+			Field:  "Offers",
+			Before: strings.Join(helper.GetTagNames(oldBusiness.Offers), " "),
+			After:  strings.Join(helper.GetTagNames(newBusiness.Offers), " "),
+		})
+	}
+	if !helper.SameTags(newBusiness.Wants, oldBusiness.Wants) {
+		changes = append(changes, &types.AuditChange{
+			Field:  "Wants",
+			Before: strings.Join(helper.GetTagNames(oldBusiness.Wants), " "),
+			After:  strings.Join(helper.GetTagNames(newBusiness.Wants), " "),
+		})
+	}
+	oldAdminTags := strings.Join(oldBusiness.AdminTags, " ")
+	newAdminTags := strings.Join(newBusiness.AdminTags, " ")
+	if oldAdminTags != newAdminTags {
+		changes = append(changes, &types.AuditChange{
+			Field:  "AdminTags",
+			Before: oldAdminTags,
+			After:  newAdminTags,
+		})
+	}
+	changes = append(changes, auditChanges(util.Diff(oldBalance, newBalance, nil))...)
+	if len(changes) == 0 {
+		return nil
+	}
+	return newAuditEvent(
+		admin,
+		req,
+		"modify business",
+		constant.AuditTarget.Business,
+		oldBusiness.ID.Hex(),
+		changes,
+	)
+}
+
+func (a audit) ModifyUser(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	oldUser *types.User,
+	newUser *types.User,
+) *types.AuditEvent {
+	changes := auditChanges(util.Diff(oldUser, newUser, map[string]bool{
+		"CurrentLoginIP": true,
+		"Password":       true,
+		"LastLoginIP":    true,
+	}))
+	// Only record that the password was reset, never its value.
+	if newUser.Password != "" {
+		changes = append(changes, &types.AuditChange{
+			Field: "Password",
+			After: "reset",
+		})
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return newAuditEvent(
+		admin,
+		req,
+		"modify user",
+		constant.AuditTarget.User,
+		oldUser.ID.Hex(),
+		changes,
+	)
+}
+
+func (a audit) CreateTag(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	tag *types.Tag,
+) *types.AuditEvent {
+	return newAuditEvent(
+		admin,
+		req,
+		"create tag",
+		constant.AuditTarget.Tag,
+		tag.ID.Hex(),
+		[]*types.AuditChange{{Field: "Name", After: tag.Name}},
+	)
+}
+
+func (a audit) ModifyTag(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	tagID primitive.ObjectID,
+	old string,
+	new string,
+) *types.AuditEvent {
+	return newAuditEvent(
+		admin,
+		req,
+		"modify tag",
+		constant.AuditTarget.Tag,
+		tagID.Hex(),
+		[]*types.AuditChange{{Field: "Name", Before: old, After: new}},
+	)
+}
+
+func (a audit) DeleteTag(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	tag *types.Tag,
+) *types.AuditEvent {
+	return newAuditEvent(
+		admin,
+		req,
+		"delete tag",
+		constant.AuditTarget.Tag,
+		tag.ID.Hex(),
+		[]*types.AuditChange{{Field: "Name", Before: tag.Name}},
+	)
+}
+
+func (a audit) CreateAdminTag(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	adminTag *types.AdminTag,
+) *types.AuditEvent {
+	return newAuditEvent(
+		admin,
+		req,
+		"create admin tag",
+		constant.AuditTarget.AdminTag,
+		adminTag.ID.Hex(),
+		[]*types.AuditChange{{Field: "Name", After: adminTag.Name}},
+	)
+}
+
+func (a audit) ModifyAdminTag(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	adminTagID primitive.ObjectID,
+	old string,
+	new string,
+) *types.AuditEvent {
+	return newAuditEvent(
+		admin,
+		req,
+		"modify admin tag",
+		constant.AuditTarget.AdminTag,
+		adminTagID.Hex(),
+		[]*types.AuditChange{{Field: "Name", Before: old, After: new}},
+	)
+}
+
+func (a audit) DeleteAdminTag(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	adminTag *types.AdminTag,
+) *types.AuditEvent {
+	return newAuditEvent(
+		admin,
+		req,
+		"delete admin tag",
+		constant.AuditTarget.AdminTag,
+		adminTag.ID.Hex(),
+		[]*types.AuditChange{{Field: "Name", Before: adminTag.Name}},
+	)
+}
+
+func (a audit) Transfer(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	transactionID string,
+	fromEmail string,
+	toEmail string,
+	amount float64,
+	desc string,
+) *types.AuditEvent {
+	return newAuditEvent(
+		admin,
+		req,
+		"transfer",
+		constant.AuditTarget.Transfer,
+		transactionID,
+		[]*types.AuditChange{
+			{Field: "From", After: fromEmail},
+			{Field: "To", After: toEmail},
+			{Field: "Amount", After: fmt.Sprintf("%.2f", amount)},
+			{Field: "Description", After: desc},
+		},
+	)
+}
+
+// ImportDirectory targets the imported file, the created businesses are
+// not listed one by one.
+func (a audit) ImportDirectory(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	fileName string,
+	report *types.ImportReport,
+) *types.AuditEvent {
+	return newAuditEvent(
+		admin,
+		req,
+		"import directory",
+		constant.AuditTarget.Directory,
+		fileName,
+		[]*types.AuditChange{
+			{Field: "Created", After: strconv.Itoa(report.Created)},
+			{Field: "Failed", After: strconv.Itoa(report.Failed)},
+		},
+	)
+}
+
+func (a audit) PublishAgreement(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	agreement *types.Agreement,
+) *types.AuditEvent {
+	return newAuditEvent(
+		admin,
+		req,
+		"publish agreement",
+		constant.AuditTarget.Agreement,
+		agreement.ID.Hex(),
+		[]*types.AuditChange{
+			{Field: "Version", After: strconv.Itoa(agreement.Version)},
+			{
+				Field: "RequiredForTrading",
+				After: strconv.FormatBool(agreement.RequiredForTrading),
+			},
+		},
+	)
+}
+
+func (a audit) TriggerJob(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	job string,
+) *types.AuditEvent {
+	return newAuditEvent(
+		admin,
+		req,
+		"trigger job",
+		constant.AuditTarget.Job,
+		job,
+		nil,
+	)
+}
+
+func (a audit) DeleteBusiness(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	business *types.Business,
+) *types.AuditEvent {
+	return newAuditEvent(
+		admin,
+		req,
+		"delete business",
+		constant.AuditTarget.Business,
+		business.ID.Hex(),
+		[]*types.AuditChange{{Field: "BusinessName", Before: business.BusinessName}},
+	)
+}
+
+func (a audit) DeleteUser(
+	admin *types.AdminUser,
+	req *types.AuditRequest,
+	user *types.User,
+) *types.AuditEvent {
+	return newAuditEvent(
+		admin,
+		req,
+		"delete user",
+		constant.AuditTarget.User,
+		user.ID.Hex(),
+		[]*types.AuditChange{{Field: "Email", Before: user.Email}},
+	)
+}
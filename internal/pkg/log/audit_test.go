@@ -0,0 +1,290 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAuditModifyBusiness(t *testing.T) {
+	admin := &types.AdminUser{ID: primitive.NewObjectID(), Email: "Admin@OCN.test"}
+	req := &types.AuditRequest{IPAddress: "10.0.0.1", CorrelationID: "req-1"}
+	old := &types.Business{
+		ID:           primitive.NewObjectID(),
+		BusinessName: "Corner Bakery",
+		Status:       constant.Business.Pending,
+		Offers:       []*types.TagField{{Name: "bread"}},
+		AdminTags:    []string{"north"},
+	}
+	limit := &types.BalanceLimit{MaxPosBal: 500, MaxNegBal: 100}
+
+	tests := []struct {
+		name     string
+		business *types.BusinessData
+		balance  *types.BalanceLimit
+		expected []*types.AuditChange
+	}{
+		{
+			"should not build an event without changes",
+			&types.BusinessData{
+				BusinessName: "Corner Bakery",
+				Status:       constant.Business.Pending,
+				Offers:       []*types.TagField{{Name: "bread"}},
+				AdminTags:    []string{"north"},
+			},
+			limit,
+			nil,
+		},
+		{
+			"should record the changed fields",
+			&types.BusinessData{
+				BusinessName: "Corner Bakery",
+				Status:       constant.Business.Accepted,
+				Offers:       []*types.TagField{{Name: "bread"}},
+				AdminTags:    []string{"north"},
+			},
+			limit,
+			[]*types.AuditChange{
+				{Field: "Status", Before: constant.Business.Pending, After: constant.Business.Accepted},
+			},
+		},
+		{
+			"should record the tags and the balance limits",
+			&types.BusinessData{
+				BusinessName: "Corner Bakery",
+				Status:       constant.Business.Pending,
+				Offers:       []*types.TagField{{Name: "bread"}, {Name: "cake"}},
+				Wants:        []*types.TagField{{Name: "flour"}},
+				AdminTags:    []string{"north", "vip"},
+			},
+			&types.BalanceLimit{MaxPosBal: 800, MaxNegBal: 100},
+			[]*types.AuditChange{
+				{Field: "Offers", Before: "bread", After: "bread cake"},
+				{Field: "Wants", Before: "", After: "flour"},
+				{Field: "AdminTags", Before: "north", After: "north vip"},
+				{Field: "MaxPosBal", Before: "500.00", After: "800.00"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := Audit.ModifyBusiness(admin, req, old, tt.business, limit, tt.balance)
+			if tt.expected == nil {
+				assert.Nil(t, event)
+				return
+			}
+			assert.Equal(t, &types.AuditEvent{
+				ActorID:       admin.ID,
+				ActorEmail:    "admin@ocn.test",
+				Action:        "modify business",
+				TargetType:    constant.AuditTarget.Business,
+				TargetID:      old.ID.Hex(),
+				Changes:       tt.expected,
+				IPAddress:     "10.0.0.1",
+				CorrelationID: "req-1",
+			}, event)
+		})
+	}
+}
+
+func TestAuditModifyUser(t *testing.T) {
+	admin := &types.AdminUser{ID: primitive.NewObjectID(), Email: "admin@ocn.test"}
+	req := &types.AuditRequest{IPAddress: "10.0.0.1"}
+	old := &types.User{
+		ID:             primitive.NewObjectID(),
+		FirstName:      "Alice",
+		Email:          "alice@cafe.test",
+		LastLoginIP:    "10.0.0.2",
+		CurrentLoginIP: "10.0.0.3",
+	}
+
+	tests := []struct {
+		name     string
+		user     *types.User
+		expected []*types.AuditChange
+	}{
+		{
+			"should not build an event without changes",
+			&types.User{FirstName: "Alice", Email: "alice@cafe.test"},
+			nil,
+		},
+		{
+			"should record the changed fields",
+			&types.User{FirstName: "Alicia", Email: "alice@cafe.test"},
+			[]*types.AuditChange{
+				{Field: "FirstName", Before: "Alice", After: "Alicia"},
+			},
+		},
+		{
+			"should record the password reset without its value",
+			&types.User{FirstName: "Alice", Email: "alice@cafe.test", Password: "secret"},
+			[]*types.AuditChange{
+				{Field: "Password", After: "reset"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := Audit.ModifyUser(admin, req, old, tt.user)
+			if tt.expected == nil {
+				assert.Nil(t, event)
+				return
+			}
+			assert.Equal(t, "modify user", event.Action)
+			assert.Equal(t, constant.AuditTarget.User, event.TargetType)
+			assert.Equal(t, old.ID.Hex(), event.TargetID)
+			assert.Equal(t, tt.expected, event.Changes)
+		})
+	}
+}
+
+func TestAuditAdminMutations(t *testing.T) {
+	admin := &types.AdminUser{ID: primitive.NewObjectID(), Email: "admin@ocn.test"}
+	req := &types.AuditRequest{IPAddress: "10.0.0.1", CorrelationID: "req-1"}
+	tag := &types.Tag{ID: primitive.NewObjectID(), Name: "bread"}
+	adminTag := &types.AdminTag{ID: primitive.NewObjectID(), Name: "north"}
+	agreement := &types.Agreement{
+		ID:                 primitive.NewObjectID(),
+		Version:            3,
+		RequiredForTrading: true,
+	}
+	business := &types.Business{ID: primitive.NewObjectID(), BusinessName: "Corner Bakery"}
+	user := &types.User{ID: primitive.NewObjectID(), Email: "alice@cafe.test"}
+
+	tests := []struct {
+		name       string
+		event      *types.AuditEvent
+		action     string
+		targetType string
+		targetID   string
+		changes    []*types.AuditChange
+	}{
+		{
+			"create tag",
+			Audit.CreateTag(admin, req, tag),
+			"create tag",
+			constant.AuditTarget.Tag,
+			tag.ID.Hex(),
+			[]*types.AuditChange{{Field: "Name", After: "bread"}},
+		},
+		{
+			"modify tag",
+			Audit.ModifyTag(admin, req, tag.ID, "bread", "bagels"),
+			"modify tag",
+			constant.AuditTarget.Tag,
+			tag.ID.Hex(),
+			[]*types.AuditChange{{Field: "Name", Before: "bread", After: "bagels"}},
+		},
+		{
+			"delete tag",
+			Audit.DeleteTag(admin, req, tag),
+			"delete tag",
+			constant.AuditTarget.Tag,
+			tag.ID.Hex(),
+			[]*types.AuditChange{{Field: "Name", Before: "bread"}},
+		},
+		{
+			"create admin tag",
+			Audit.CreateAdminTag(admin, req, adminTag),
+			"create admin tag",
+			constant.AuditTarget.AdminTag,
+			adminTag.ID.Hex(),
+			[]*types.AuditChange{{Field: "Name", After: "north"}},
+		},
+		{
+			"modify admin tag",
+			Audit.ModifyAdminTag(admin, req, adminTag.ID, "north", "south"),
+			"modify admin tag",
+			constant.AuditTarget.AdminTag,
+			adminTag.ID.Hex(),
+			[]*types.AuditChange{{Field: "Name", Before: "north", After: "south"}},
+		},
+		{
+			"delete admin tag",
+			Audit.DeleteAdminTag(admin, req, adminTag),
+			"delete admin tag",
+			constant.AuditTarget.AdminTag,
+			adminTag.ID.Hex(),
+			[]*types.AuditChange{{Field: "Name", Before: "north"}},
+		},
+		{
+			"transfer",
+			Audit.Transfer(admin, req, "tx-1", "a@ocn.test", "b@ocn.test", 12.5, "rent"),
+			"transfer",
+			constant.AuditTarget.Transfer,
+			"tx-1",
+			[]*types.AuditChange{
+				{Field: "From", After: "a@ocn.test"},
+				{Field: "To", After: "b@ocn.test"},
+				{Field: "Amount", After: "12.50"},
+				{Field: "Description", After: "rent"},
+			},
+		},
+		{
+			"import directory",
+			Audit.ImportDirectory(admin, req, "members.csv", &types.ImportReport{Created: 4, Failed: 1}),
+			"import directory",
+			constant.AuditTarget.Directory,
+			"members.csv",
+			[]*types.AuditChange{
+				{Field: "Created", After: "4"},
+				{Field: "Failed", After: "1"},
+			},
+		},
+		{
+			"publish agreement",
+			Audit.PublishAgreement(admin, req, agreement),
+			"publish agreement",
+			constant.AuditTarget.Agreement,
+			agreement.ID.Hex(),
+			[]*types.AuditChange{
+				{Field: "Version", After: "3"},
+				{Field: "RequiredForTrading", After: "true"},
+			},
+		},
+		{
+			"trigger job",
+			Audit.TriggerJob(admin, req, "dailyemail"),
+			"trigger job",
+			constant.AuditTarget.Job,
+			"dailyemail",
+			nil,
+		},
+		{
+			"delete business",
+			Audit.DeleteBusiness(admin, req, business),
+			"delete business",
+			constant.AuditTarget.Business,
+			business.ID.Hex(),
+			[]*types.AuditChange{{Field: "BusinessName", Before: "Corner Bakery"}},
+		},
+		{
+			"delete user",
+			Audit.DeleteUser(admin, req, user),
+			"delete user",
+			constant.AuditTarget.User,
+			user.ID.Hex(),
+			[]*types.AuditChange{{Field: "Email", Before: "alice@cafe.test"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, &types.AuditEvent{
+				ActorID:       admin.ID,
+				ActorEmail:    "admin@ocn.test",
+				Action:        tt.action,
+				TargetType:    tt.targetType,
+				TargetID:      tt.targetID,
+				Changes:       tt.changes,
+				IPAddress:     "10.0.0.1",
+				CorrelationID: "req-1",
+			}, tt.event)
+		})
+	}
+}
@@ -14,59 +14,61 @@ type user struct{}
 var User = user{}
 
 func (us user) Signup(u *types.User, b *types.BusinessData) *types.UserAction {
-	u.Email = strings.ToLower(u.Email)
+	email := strings.ToLower(u.Email)
 	return &types.UserAction{
 		UserID: u.ID,
-		Email:  u.Email,
+		Email:  email,
 		Action: "account created",
 		// [businessName] - [firstName] [lastName] - [email]
-		ActionDetails: b.BusinessName + " - " + u.FirstName + " " + u.LastName + " - " + u.Email,
+		ActionDetails: b.BusinessName + " - " + u.FirstName + " " + u.LastName + " - " + email,
 		Category:      "user",
 	}
 }
 
 func (us user) LoginSuccess(u *types.User, ip string) *types.UserAction {
-	u.Email = strings.ToLower(u.Email)
+	email := strings.ToLower(u.Email)
 	return &types.UserAction{
 		UserID: u.ID,
-		Email:  u.Email,
+		Email:  email,
 		Action: "user login successful",
 		// [email] - [IP address]
-		ActionDetails: u.Email + " - " + ip,
+		ActionDetails: email + " - " + ip,
 		Category:      "user",
+		IPAddress:     ip,
 	}
 }
 
 func (us user) LoginFailure(u *types.User, ip string) *types.UserAction {
-	u.Email = strings.ToLower(u.Email)
+	email := strings.ToLower(u.Email)
 	return &types.UserAction{
 		UserID: u.ID,
-		Email:  u.Email,
+		Email:  email,
 		Action: "user login failed",
 		// [email] - [IP address]
-		ActionDetails: u.Email + " - " + ip,
+		ActionDetails: email + " - " + ip,
 		Category:      "user",
+		IPAddress:     ip,
 	}
 }
 
 func (us user) LostPassword(u *types.User) *types.UserAction {
-	u.Email = strings.ToLower(u.Email)
+	email := strings.ToLower(u.Email)
 	return &types.UserAction{
 		UserID:        u.ID,
-		Email:         u.Email,
+		Email:         email,
 		Action:        "sent password reset",
-		ActionDetails: u.Email,
+		ActionDetails: email,
 		Category:      "user",
 	}
 }
 
 func (us user) ChangePassword(u *types.User) *types.UserAction {
-	u.Email = strings.ToLower(u.Email)
+	email := strings.ToLower(u.Email)
 	return &types.UserAction{
 		UserID:        u.ID,
-		Email:         u.Email,
+		Email:         email,
 		Action:        "changed password",
-		ActionDetails: u.Email,
+		ActionDetails: email,
 		Category:      "user",
 	}
 }
@@ -137,13 +139,13 @@ func (us user) ProposeTransfer(
 	amount float64,
 	desc string,
 ) *types.UserAction {
-	proposer.Email = strings.ToLower(proposer.Email)
+	email := strings.ToLower(proposer.Email)
 	return &types.UserAction{
 		UserID: proposer.ID,
-		Email:  proposer.Email,
+		Email:  email,
 		Action: "user proposed a transfer",
 		// [proposer] - [from] - [to] - [amount] - [desc]
-		ActionDetails: proposer.Email + " - " + fromEmail + " - " + toEmail + " - " + fmt.Sprintf(
+		ActionDetails: email + " - " + fromEmail + " - " + toEmail + " - " + fmt.Sprintf(
 			"%.2f",
 			amount,
 		) + " - " + desc,
@@ -157,16 +159,33 @@ func (us user) Transfer(
 	amount float64,
 	desc string,
 ) *types.UserAction {
-	u.Email = strings.ToLower(u.Email)
+	email := strings.ToLower(u.Email)
 	return &types.UserAction{
 		UserID: u.ID,
-		Email:  u.Email,
+		Email:  email,
 		Action: "user transfer",
 		// [from] - [to] - [amount] - [desc]
-		ActionDetails: u.Email + " - " + toEmail + " - " + fmt.Sprintf(
+		ActionDetails: email + " - " + toEmail + " - " + fmt.Sprintf(
 			"%.2f",
 			amount,
 		) + " - " + desc,
 		Category: "user",
 	}
 }
+
+func (us user) AcceptAgreement(
+	u *types.User,
+	version int,
+	ip string,
+) *types.UserAction {
+	email := strings.ToLower(u.Email)
+	return &types.UserAction{
+		UserID: u.ID,
+		Email:  email,
+		Action: "user accepted membership agreement",
+		// [email] - version [version] - [IP address]
+		ActionDetails: email + " - " + fmt.Sprintf("version %d", version) + " - " + ip,
+		Category:      "user",
+		IPAddress:     ip,
+	}
+}
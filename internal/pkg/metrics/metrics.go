@@ -0,0 +1,220 @@
+package metrics
+
+import (
+	"context"
+	"crypto/subtle"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/l"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+const namespace = "mccs"
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "code"})
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	transfers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfers_total",
+		Help:      "Number of transfers proposed, accepted, rejected and cancelled.",
+	}, []string{"event"})
+
+	emails = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "emails_sent_total",
+		Help:      "Number of emails sent by result.",
+	}, []string{"result"})
+
+	jobRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "job_runs_total",
+		Help:      "Number of background job runs by outcome.",
+	}, []string{"job", "outcome"})
+	jobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_duration_seconds",
+		Help:      "Duration of the background jobs.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 4, 8),
+	}, []string{"job"})
+
+	storeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "store_duration_seconds",
+		Help:      "Latency of the MongoDB, PostgreSQL and Elasticsearch calls.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"store", "operation"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		httpRequests,
+		httpDuration,
+		transfers,
+		emails,
+		jobRuns,
+		jobDuration,
+		storeDuration,
+	)
+}
+
+// Transfer events.
+const (
+	TransferProposed  = "proposed"
+	TransferAccepted  = "accepted"
+	TransferRejected  = "rejected"
+	TransferCancelled = "cancelled"
+)
+
+// Stores.
+const (
+	Mongo    = "mongo"
+	Postgres = "postgres"
+	ES       = "elasticsearch"
+)
+
+// ObserveRequest records a handled HTTP request.
+// The route is the path template, so the ids in the paths are not used as labels.
+func ObserveRequest(route string, method string, code int, elapsed time.Duration) {
+	httpRequests.WithLabelValues(route, method, strconv.Itoa(code)).Inc()
+	httpDuration.WithLabelValues(route, method).Observe(elapsed.Seconds())
+}
+
+// Transfer counts a transfer event.
+func Transfer(event string) {
+	transfers.WithLabelValues(event).Inc()
+}
+
+// EmailSent counts an email by whether it has been sent.
+func EmailSent(err error) {
+	if err != nil {
+		emails.WithLabelValues("failure").Inc()
+		return
+	}
+	emails.WithLabelValues("success").Inc()
+}
+
+// Job runs the job and records its duration and outcome.
+func Job(name string, run func() error) error {
+	startTime := time.Now()
+	err := run()
+	jobDuration.WithLabelValues(name).Observe(time.Since(startTime).Seconds())
+	if err != nil {
+		jobRuns.WithLabelValues(name, "failure").Inc()
+		return err
+	}
+	jobRuns.WithLabelValues(name, "success").Inc()
+	return nil
+}
+
+// ObserveStore records the latency of a database call.
+func ObserveStore(store string, operation string, elapsed time.Duration) {
+	storeDuration.WithLabelValues(store, operation).Observe(elapsed.Seconds())
+}
+
+// RegisterOutstandingCredit exposes the total outstanding credit.
+// The value is read on every scrape.
+func RegisterOutstandingCredit(read func(ctx context.Context) (float64, error)) {
+	prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "outstanding_credit",
+		Help:      "Sum of the negative balances of the accounts.",
+	}, func() float64 {
+		credit, err := read(context.Background())
+		if err != nil {
+			l.Logger.Error("metrics outstanding credit failed", zap.Error(err))
+			return math.NaN()
+		}
+		return credit
+	}))
+}
+
+// RegisterOutbox exposes the events waiting to be applied to Elasticsearch.
+// The stats are read once on every scrape.
+func RegisterOutbox(read func(ctx context.Context) (*types.OutboxStats, error)) {
+	prometheus.MustRegister(newOutboxCollector(read))
+}
+
+type outboxCollector struct {
+	read        func(ctx context.Context) (*types.OutboxStats, error)
+	pending     *prometheus.Desc
+	lag         *prometheus.Desc
+	deadLetters *prometheus.Desc
+}
+
+func newOutboxCollector(read func(ctx context.Context) (*types.OutboxStats, error)) *outboxCollector {
+	return &outboxCollector{
+		read: read,
+		pending: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "outbox", "pending_events"),
+			"Number of events waiting to be applied to Elasticsearch.",
+			nil, nil,
+		),
+		lag: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "outbox", "lag_seconds"),
+			"Age of the oldest event waiting to be applied to Elasticsearch.",
+			nil, nil,
+		),
+		deadLetters: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "outbox", "dead_letter_events"),
+			"Number of events the indexer gave up on.",
+			nil, nil,
+		),
+	}
+}
+
+func (c *outboxCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.pending
+	ch <- c.lag
+	ch <- c.deadLetters
+}
+
+func (c *outboxCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := c.read(context.Background())
+	if err != nil {
+		l.Logger.Error("metrics outbox failed", zap.Error(err))
+		ch <- prometheus.NewInvalidMetric(c.pending, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(stats.Pending))
+	ch <- prometheus.MustNewConstMetric(c.lag, prometheus.GaugeValue, stats.Lag().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.deadLetters, prometheus.GaugeValue, float64(stats.DeadLetters))
+}
+
+// Handler serves the metrics to the requests bearing metrics.token.
+// The endpoint is disabled when no token is configured.
+func Handler() http.Handler {
+	h := promhttp.Handler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := global.Config().Metrics.Token
+		if token == "" {
+			http.NotFound(w, r)
+			return
+		}
+		bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
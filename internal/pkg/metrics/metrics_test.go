@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestESOperation(t *testing.T) {
+	tests := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{"POST", "/businesses/_search", "_search"},
+		{"PUT", "/businesses/_doc/5d1b2f", "_doc"},
+		{"POST", "/_bulk", "_bulk"},
+		{"HEAD", "/businesses", "head"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			assert.Equal(t, tt.expected, ESOperation(req))
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	defer global.SetConfig(global.Config())
+
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		expected      int
+	}{
+		{"disabled", "", "Bearer ", http.StatusNotFound},
+		{"missing token", "secret", "", http.StatusUnauthorized},
+		{"wrong token", "secret", "Bearer wrong", http.StatusUnauthorized},
+		{"valid token", "secret", "Bearer secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := *global.Config()
+			c.Metrics.Token = tt.token
+			global.SetConfig(&c)
+			req := httptest.NewRequest("GET", "/metrics", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			Handler().ServeHTTP(rec, req)
+			assert.Equal(t, tt.expected, rec.Code)
+		})
+	}
+}
+
+func TestOutboxCollector(t *testing.T) {
+	c := newOutboxCollector(func(ctx context.Context) (*types.OutboxStats, error) {
+		return &types.OutboxStats{Pending: 3, DeadLetters: 1}, nil
+	})
+	expected := `
+# HELP mccs_outbox_dead_letter_events Number of events the indexer gave up on.
+# TYPE mccs_outbox_dead_letter_events gauge
+mccs_outbox_dead_letter_events 1
+# HELP mccs_outbox_pending_events Number of events waiting to be applied to Elasticsearch.
+# TYPE mccs_outbox_pending_events gauge
+mccs_outbox_pending_events 3
+`
+	err := testutil.CollectAndCompare(
+		c,
+		strings.NewReader(expected),
+		"mccs_outbox_pending_events",
+		"mccs_outbox_dead_letter_events",
+	)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// MongoMonitor records the latency of every MongoDB command.
+func MongoMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			ObserveStore(Mongo, evt.CommandName, evt.Duration)
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			ObserveStore(Mongo, evt.CommandName, evt.Duration)
+		},
+	}
+}
+
+const gormStartKey = "metrics:start_time"
+
+// RegisterGormCallbacks records the latency of the gorm create, query,
+// update and delete calls.
+func RegisterGormCallbacks(db *gorm.DB) {
+	before := func(scope *gorm.Scope) {
+		scope.Set(gormStartKey, time.Now())
+	}
+	after := func(operation string) func(scope *gorm.Scope) {
+		return func(scope *gorm.Scope) {
+			v, ok := scope.Get(gormStartKey)
+			if !ok {
+				return
+			}
+			startTime, ok := v.(time.Time)
+			if !ok {
+				return
+			}
+			ObserveStore(Postgres, operation, time.Since(startTime))
+		}
+	}
+
+	c := db.Callback()
+	c.Create().Before("gorm:begin_transaction").Register("metrics:before_create", before)
+	c.Create().After("gorm:commit_or_rollback_transaction").Register("metrics:after_create", after("create"))
+	c.Query().Before("gorm:query").Register("metrics:before_query", before)
+	c.Query().After("gorm:after_query").Register("metrics:after_query", after("query"))
+	c.RowQuery().Before("gorm:row_query").Register("metrics:before_row_query", before)
+	c.RowQuery().After("gorm:row_query").Register("metrics:after_row_query", after("row_query"))
+	c.Update().Before("gorm:begin_transaction").Register("metrics:before_update", before)
+	c.Update().After("gorm:commit_or_rollback_transaction").Register("metrics:after_update", after("update"))
+	c.Delete().Before("gorm:begin_transaction").Register("metrics:before_delete", before)
+	c.Delete().After("gorm:commit_or_rollback_transaction").Register("metrics:after_delete", after("delete"))
+}
+
+// ESTransport records the latency of the Elasticsearch requests.
+type ESTransport struct {
+	Next http.RoundTripper
+}
+
+func (t *ESTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	startTime := time.Now()
+	res, err := next.RoundTrip(req)
+	ObserveStore(ES, ESOperation(req), time.Since(startTime))
+	return res, err
+}
+
+// ESOperation returns the endpoint of the request, e.g. "_search" or "_doc",
+// so the index names and document ids are not used as labels.
+func ESOperation(req *http.Request) string {
+	for _, segment := range strings.Split(req.URL.Path, "/") {
+		if strings.HasPrefix(segment, "_") {
+			return segment
+		}
+	}
+	return strings.ToLower(req.Method)
+}
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/ic3network/mccs-alpha/global"
-	"github.com/spf13/viper"
 )
 
 var r *Recaptcha
@@ -27,7 +26,7 @@ type Recaptcha struct {
 // New returns an initialized recaptcha instance.
 func New() *Recaptcha {
 	j := new(Recaptcha)
-	j.Secret = viper.GetString("recaptcha.secret_key")
+	j.Secret = global.Config().Recaptcha.SecretKey
 	return j
 }
 
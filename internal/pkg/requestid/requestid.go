@@ -0,0 +1,51 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+type contextKey struct{}
+
+// maxLength caps the IDs sent by the clients and the proxies.
+const maxLength = 64
+
+// New returns the ID of the request. The ID sent by the client or the proxy
+// is kept when it is safe to log, a new one is generated otherwise.
+func New(sent string) string {
+	if valid(sent) {
+		return sent
+	}
+	u, err := uuid.NewV4()
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
+
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns the context carrying the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID of the context, if any.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
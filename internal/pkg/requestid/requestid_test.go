@@ -0,0 +1,26 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	assert.Equal(t, "abc-123", New("abc-123"))
+	assert.Equal(t, "lb:1f2e.3_a", New("lb:1f2e.3_a"))
+
+	for _, sent := range []string{"", "a b", "id\nfake log line", strings.Repeat("a", 65)} {
+		id := New(sent)
+		assert.NotEqual(t, sent, id)
+		assert.Len(t, id, 36)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, "", FromContext(context.Background()))
+	ctx := NewContext(context.Background(), "abc-123")
+	assert.Equal(t, "abc-123", FromContext(ctx))
+}
@@ -6,7 +6,10 @@ type Data struct {
 		Admin bool
 	}
 	ErrorMessages []string
-	Messages      struct {
+	// RequestID is shown with the error messages, so the users can quote it
+	// when they report a problem.
+	RequestID string
+	Messages  struct {
 		Success string
 		Info    string
 	}
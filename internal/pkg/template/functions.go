@@ -2,6 +2,8 @@ package template
 
 import (
 	"fmt"
+	"html/template"
+	"net/url"
 	"sort"
 	"strings"
 	"time"
@@ -114,3 +116,11 @@ func containPrefix(arr []string, prefix string) bool {
 	}
 	return false
 }
+
+// queryString appends the values to a URL query.
+func queryString(values url.Values) template.URL {
+	if len(values) == 0 {
+		return ""
+	}
+	return template.URL("&" + values.Encode())
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/ic3network/mccs-alpha/global/constant"
 	"github.com/ic3network/mccs-alpha/internal/pkg/e"
 	"github.com/ic3network/mccs-alpha/internal/pkg/flash"
+	"github.com/ic3network/mccs-alpha/internal/pkg/requestid"
 )
 
 var (
@@ -43,6 +44,7 @@ func NewView(templateName string) *View {
 			"DaysBefore":           daysBefore,
 			"SortAdminTags":        sortAdminTags,
 			"ContainPrefix":        containPrefix,
+			"QueryString":          queryString,
 		}).
 		ParseFiles(templates...)
 	if err != nil {
@@ -90,6 +92,7 @@ func (v *View) Render(
 
 	var vd Data
 	vd.User.ID = r.Header.Get("userID")
+	vd.RequestID = requestid.FromContext(r.Context())
 	admin, err := strconv.ParseBool(r.Header.Get("admin"))
 	if err != nil {
 		vd.User.Admin = false
@@ -115,6 +118,7 @@ func (v *View) Success(
 
 	var vd Data
 	vd.User.ID = r.Header.Get("userID")
+	vd.RequestID = requestid.FromContext(r.Context())
 	admin, err := strconv.ParseBool(r.Header.Get("admin"))
 	if err != nil {
 		vd.User.Admin = false
@@ -144,6 +148,7 @@ func (v *View) Error(
 		vd.ErrorMessages = []string{"Sorry, something went wrong. Please try again later."}
 	}
 	vd.User.ID = r.Header.Get("userID")
+	vd.RequestID = requestid.FromContext(r.Context())
 	admin, err := strconv.ParseBool(r.Header.Get("admin"))
 	if err != nil {
 		vd.User.Admin = false
@@ -0,0 +1,156 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/ic3network/mccs-alpha/internal/pkg/metrics"
+	"github.com/jinzhu/gorm"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MongoMonitor starts a span for every MongoDB command of a traced context
+// and then calls next.
+func MongoMonitor(next *event.CommandMonitor) *event.CommandMonitor {
+	var spans sync.Map
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			_, span, ok := StartChild(ctx, "mongo "+evt.CommandName,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("db.system", "mongodb"),
+					attribute.String("db.name", evt.DatabaseName),
+					attribute.String("db.operation", evt.CommandName),
+				),
+			)
+			if ok {
+				spans.Store(evt.RequestID, span)
+			}
+			if next != nil && next.Started != nil {
+				next.Started(ctx, evt)
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			if span, ok := spans.LoadAndDelete(evt.RequestID); ok {
+				End(span.(trace.Span), nil)
+			}
+			if next != nil && next.Succeeded != nil {
+				next.Succeeded(ctx, evt)
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			if span, ok := spans.LoadAndDelete(evt.RequestID); ok {
+				span.(trace.Span).SetAttributes(attribute.String("error", evt.Failure))
+				End(span.(trace.Span), errString(evt.Failure))
+			}
+			if next != nil && next.Failed != nil {
+				next.Failed(ctx, evt)
+			}
+		},
+	}
+}
+
+type errString string
+
+func (e errString) Error() string { return string(e) }
+
+const (
+	// GormContextKey carries the request context of a gorm call, set it
+	// with db.Set(GormContextKey, ctx).
+	GormContextKey = "tracing:context"
+	gormSpanKey    = "tracing:span"
+)
+
+// RegisterGormCallbacks starts a span for the gorm calls made with a
+// traced context.
+func RegisterGormCallbacks(db *gorm.DB) {
+	before := func(operation string) func(scope *gorm.Scope) {
+		return func(scope *gorm.Scope) {
+			v, ok := scope.Get(GormContextKey)
+			if !ok {
+				return
+			}
+			ctx, ok := v.(context.Context)
+			if !ok {
+				return
+			}
+			_, span, ok := StartChild(ctx, "postgres "+operation,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("db.system", "postgresql"),
+					attribute.String("db.operation", operation),
+					attribute.String("db.sql.table", scope.TableName()),
+				),
+			)
+			if ok {
+				scope.Set(gormSpanKey, span)
+			}
+		}
+	}
+	after := func(scope *gorm.Scope) {
+		v, ok := scope.Get(gormSpanKey)
+		if !ok {
+			return
+		}
+		span, ok := v.(trace.Span)
+		if !ok {
+			return
+		}
+		span.SetAttributes(attribute.String("db.statement", scope.SQL))
+		var err error
+		if scope.HasError() && !gorm.IsRecordNotFoundError(scope.DB().Error) {
+			err = scope.DB().Error
+		}
+		End(span, err)
+	}
+
+	c := db.Callback()
+	c.Create().Before("gorm:begin_transaction").Register("tracing:before_create", before("create"))
+	c.Create().After("gorm:commit_or_rollback_transaction").Register("tracing:after_create", after)
+	c.Query().Before("gorm:query").Register("tracing:before_query", before("query"))
+	c.Query().After("gorm:after_query").Register("tracing:after_query", after)
+	c.RowQuery().Before("gorm:row_query").Register("tracing:before_row_query", before("row_query"))
+	c.RowQuery().After("gorm:row_query").Register("tracing:after_row_query", after)
+	c.Update().Before("gorm:begin_transaction").Register("tracing:before_update", before("update"))
+	c.Update().After("gorm:commit_or_rollback_transaction").Register("tracing:after_update", after)
+	c.Delete().Before("gorm:begin_transaction").Register("tracing:before_delete", before("delete"))
+	c.Delete().After("gorm:commit_or_rollback_transaction").Register("tracing:after_delete", after)
+}
+
+// ESTransport starts a span for the Elasticsearch requests made with a
+// traced context.
+type ESTransport struct {
+	Next http.RoundTripper
+}
+
+func (t *ESTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	operation := metrics.ESOperation(req)
+	_, span, ok := StartChild(req.Context(), "elasticsearch "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "elasticsearch"),
+			attribute.String("db.operation", operation),
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", req.URL.Path),
+		),
+	)
+	res, err := next.RoundTrip(req)
+	if ok {
+		spanErr := err
+		if err == nil {
+			span.SetAttributes(attribute.Int("http.status_code", res.StatusCode))
+			if res.StatusCode >= 500 {
+				spanErr = errString(res.Status)
+			}
+		}
+		End(span, spanErr)
+	}
+	return res, err
+}
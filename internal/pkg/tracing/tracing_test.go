@@ -0,0 +1,29 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestStartChild(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, _, ok := StartChild(context.Background(), "mongo find")
+	assert.False(t, ok, "no span without a parent")
+
+	ctx, parent := tracer.Start(context.Background(), "GET /businesses")
+	ctx, span, ok := StartChild(ctx, "mongo find")
+	assert.True(t, ok)
+	assert.Equal(t,
+		parent.SpanContext().TraceID(),
+		trace.SpanContextFromContext(ctx).TraceID(),
+	)
+	span.End()
+	parent.End()
+}
@@ -3,19 +3,27 @@ package util
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 
 	"gopkg.in/oleiade/reflections.v1"
 )
 
-// CheckDiff checks what fields have been changed.
+// FieldDiff is a changed field with its old and new values.
+type FieldDiff struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Diff returns the changed fields sorted by name.
 // Only checks "String", "Int" and "Float64" types.
-func CheckDiff(
+func Diff(
 	old interface{},
 	new interface{},
 	fieldsToSkip map[string]bool,
-) []string {
-	modifiedFields := make([]string, 0)
+) []FieldDiff {
+	diffs := make([]FieldDiff, 0)
 	structItems, _ := reflections.Items(old)
 
 	for field, oldValue := range structItems {
@@ -28,23 +36,39 @@ func CheckDiff(
 			continue
 		}
 		newValue, _ := reflections.GetField(new, field)
-		if newValue != oldValue {
-			if fieldKind == reflect.Int {
-				modifiedFields = append(
-					modifiedFields,
-					field+": "+strconv.Itoa(
-						oldValue.(int),
-					)+" -> "+strconv.Itoa(
-						newValue.(int),
-					),
-				)
-			} else if fieldKind == reflect.Float64 {
-				modifiedFields = append(modifiedFields, field+": "+fmt.Sprintf("%.2f", oldValue.(float64))+" -> "+fmt.Sprintf("%.2f", newValue.(float64)))
-			} else {
-				modifiedFields = append(modifiedFields, field+": "+oldValue.(string)+" -> "+newValue.(string))
-			}
+		if newValue == oldValue {
+			continue
+		}
+		d := FieldDiff{Field: field}
+		if fieldKind == reflect.Int {
+			d.Old = strconv.Itoa(oldValue.(int))
+			d.New = strconv.Itoa(newValue.(int))
+		} else if fieldKind == reflect.Float64 {
+			d.Old = fmt.Sprintf("%.2f", oldValue.(float64))
+			d.New = fmt.Sprintf("%.2f", newValue.(float64))
+		} else {
+			d.Old = oldValue.(string)
+			d.New = newValue.(string)
 		}
+		diffs = append(diffs, d)
 	}
 
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Field < diffs[j].Field
+	})
+	return diffs
+}
+
+// CheckDiff checks what fields have been changed.
+// Only checks "String", "Int" and "Float64" types.
+func CheckDiff(
+	old interface{},
+	new interface{},
+	fieldsToSkip map[string]bool,
+) []string {
+	modifiedFields := make([]string, 0)
+	for _, d := range Diff(old, new, fieldsToSkip) {
+		modifiedFields = append(modifiedFields, d.Field+": "+d.Old+" -> "+d.New)
+	}
 	return modifiedFields
 }
@@ -0,0 +1,31 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	type record struct {
+		Name    string
+		Count   int
+		Balance float64
+		Tags    []string
+	}
+	old := &record{Name: "Bakery", Count: 1, Balance: 10, Tags: []string{"a"}}
+	new := &record{Name: "Bakery & Co", Count: 1, Balance: 12.5, Tags: []string{"b"}}
+
+	assert.Equal(t, []FieldDiff{
+		{Field: "Balance", Old: "10.00", New: "12.50"},
+		{Field: "Name", Old: "Bakery", New: "Bakery & Co"},
+	}, Diff(old, new, nil))
+	assert.Equal(t, []FieldDiff{
+		{Field: "Name", Old: "Bakery", New: "Bakery & Co"},
+	}, Diff(old, new, map[string]bool{"Balance": true}))
+	assert.Equal(t, []string{
+		"Balance: 10.00 -> 12.50",
+		"Name: Bakery -> Bakery & Co",
+	}, CheckDiff(old, new, nil))
+	assert.Empty(t, Diff(old, old, nil))
+}
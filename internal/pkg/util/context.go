@@ -0,0 +1,33 @@
+package util
+
+import (
+	"context"
+	"time"
+)
+
+// Detach returns a context with the values of ctx, e.g. the request ID and
+// the trace, which is not cancelled with it. It is for the work a handler
+// leaves running after the response is written.
+func Detach(ctx context.Context) context.Context {
+	return detached{ctx}
+}
+
+type detached struct {
+	parent context.Context
+}
+
+func (d detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (d detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (d detached) Err() error {
+	return nil
+}
+
+func (d detached) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
@@ -1,8 +1,10 @@
 package validator
 
 import (
+	"strconv"
+
+	"github.com/ic3network/mccs-alpha/global"
 	"github.com/ic3network/mccs-alpha/internal/app/types"
-	"github.com/spf13/viper"
 )
 
 func validateTagsLimit(b *types.BusinessData) []string {
@@ -13,8 +15,8 @@ func validateTagsLimit(b *types.BusinessData) []string {
 			errorMessages,
 			"Missing at least one valid tag for Products/Services Offered.",
 		)
-	} else if len(b.Offers) > viper.GetInt("tags_limit") {
-		errorMessages = append(errorMessages, "No more than "+viper.GetString("tags_limit")+" tags can be specified for Products/Services Offered.")
+	} else if len(b.Offers) > global.Config().TagsLimit {
+		errorMessages = append(errorMessages, "No more than "+strconv.Itoa(global.Config().TagsLimit)+" tags can be specified for Products/Services Offered.")
 	}
 
 	if len(b.Wants) == 0 {
@@ -22,8 +24,8 @@ func validateTagsLimit(b *types.BusinessData) []string {
 			errorMessages,
 			"Missing at least one valid tag for Products/Services Wanted.",
 		)
-	} else if len(b.Wants) > viper.GetInt("tags_limit") {
-		errorMessages = append(errorMessages, "No more than "+viper.GetString("tags_limit")+" tags can be specified for Products/Services Wanted.")
+	} else if len(b.Wants) > global.Config().TagsLimit {
+		errorMessages = append(errorMessages, "No more than "+strconv.Itoa(global.Config().TagsLimit)+" tags can be specified for Products/Services Wanted.")
 	}
 
 	for _, offer := range b.Offers {
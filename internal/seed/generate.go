@@ -0,0 +1,444 @@
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+)
+
+// Options configure the generated network.
+type Options struct {
+	Businesses int
+	// Transfers is the number of transfers tried, the ones which would
+	// break the balance limits are dropped.
+	Transfers int
+	Seed      int64
+	// Days is the length of the history, which ends at End.
+	Days int
+	End  time.Time
+	// The balance limits of the accounts.
+	MaxNegBal float64
+	MaxPosBal float64
+}
+
+// Member is a generated business with its user.
+type Member struct {
+	Business types.Business
+	User     types.User
+}
+
+// Transfer is a generated transfer between two members, From and To are
+// indexes of Dataset.Members.
+type Transfer struct {
+	From        int
+	To          int
+	InitiatedBy int
+	Amount      float64
+	Description string
+	Status      string
+	CreatedAt   time.Time
+	// UpdatedAt is when the transfer was accepted.
+	UpdatedAt time.Time
+}
+
+// Dataset is a generated network.
+type Dataset struct {
+	Members   []*Member
+	Transfers []*Transfer
+	AdminTags []string
+}
+
+type sector struct {
+	adminTag string
+	nouns    []string
+	offers   []string
+}
+
+var sectors = []sector{
+	{
+		"Cafe/Bar",
+		[]string{"Cafe", "Coffee House", "Bar", "Tea Room"},
+		[]string{"coffee", "tea", "smoothies", "cakes", "soft-drinks", "beer", "wine", "bartenders"},
+	},
+	{
+		"Restaurant",
+		[]string{"Kitchen", "Bistro", "Diner", "Pizzeria"},
+		[]string{"catering", "pizza", "hamburgers", "seafood", "vegetarian", "buffet", "parties", "waiters"},
+	},
+	{
+		"Agriculture",
+		[]string{"Farm", "Growers", "Orchard", "Market Garden"},
+		[]string{"vegetables", "fruits", "eggs", "honey", "flour", "wood", "compost"},
+	},
+	{
+		"Manufacturing",
+		[]string{"Works", "Fabrication", "Joinery", "Foundry"},
+		[]string{"furniture", "steel", "parts", "packaging", "printing", "signage"},
+	},
+	{
+		"Transport",
+		[]string{"Couriers", "Logistics", "Cycles", "Motors"},
+		[]string{"delivery", "removals", "cars", "scooters", "motorcycles", "mechanics", "fuel", "repair"},
+	},
+	{
+		"Professional Services",
+		[]string{"Associates", "Consulting", "Accountants", "Studio"},
+		[]string{"accounting", "legal-advice", "marketing", "web-design", "photography", "bookkeeping"},
+	},
+	{
+		"Retail",
+		[]string{"Store", "Emporium", "Shop", "Traders"},
+		[]string{"clothing", "books", "gifts", "hardware", "stationery", "restaurant-supplies"},
+	},
+	{
+		"Construction",
+		[]string{"Builders", "Construction", "Roofing", "Plumbing"},
+		[]string{"plumbing", "electrics", "roofing", "decorating", "laborers", "cleaning-services"},
+	},
+}
+
+// Partners are also tagged by the admins, beside their sector.
+const partnerTag = "Partner"
+
+type location struct {
+	city     string
+	region   string
+	country  string
+	postcode string
+}
+
+var locations = []location{
+	{"London", "Greater London", "England", "E"},
+	{"Bristol", "Avon", "England", "BS"},
+	{"Manchester", "Greater Manchester", "England", "M"},
+	{"Leeds", "West Yorkshire", "England", "LS"},
+	{"Norwich", "Norfolk", "England", "NR"},
+	{"Brighton", "East Sussex", "England", "BN"},
+	{"Totnes", "Devon", "England", "TQ"},
+	{"Glasgow", "Lanarkshire", "Scotland", "G"},
+	{"Edinburgh", "Midlothian", "Scotland", "EH"},
+	{"Cardiff", "South Glamorgan", "Wales", "CF"},
+	{"Swansea", "West Glamorgan", "Wales", "SA"},
+	{"Belfast", "Antrim", "Northern Ireland", "BT"},
+	{"Derry", "Derry/Londonderry", "Northern Ireland", "BT"},
+}
+
+var (
+	namePrefixes = []string{
+		"Harbour", "Green", "Hilltop", "Riverside", "Old Town", "Northern",
+		"Oak", "Copper", "Meadow", "Station", "Corner", "Common", "Union",
+		"Phoenix", "Lantern", "Willow", "Granite", "Bridge", "Market", "Good",
+	}
+	streets       = []string{"High", "Church", "Mill", "Station", "Victoria", "Park", "Market", "Bridge", "Queen", "Castle"}
+	streetSuffix  = []string{"Street", "Road", "Lane", "Avenue", "Way"}
+	incTypes      = []string{"ips", "ips", "ips", "cic", "cic", "llp", "ltd", "unltd", "guar", "plc"}
+	firstNames    = []string{"Amelia", "Oliver", "Isla", "Jack", "Ava", "Harry", "Mia", "George", "Freya", "Noah", "Aisha", "Mohammed", "Sofia", "Dylan", "Niamh", "Rhys", "Priya", "Callum", "Zara", "Owen"}
+	lastNames     = []string{"Smith", "Jones", "Taylor", "Brown", "Williams", "Wilson", "Evans", "Thomas", "Roberts", "Walker", "Khan", "Patel", "Murphy", "Campbell", "Kelly", "Hughes", "Davies", "Begum", "Morgan", "Reid"}
+	postcodeChars = "ABDEFGHJLNPQRSTUWXYZ"
+)
+
+// Share of the statuses, the trading members make the transfers.
+var statuses = []struct {
+	status string
+	weight float64
+}{
+	{constant.Trading.Accepted, 0.55},
+	{constant.Business.Accepted, 0.25},
+	{constant.Business.Pending, 0.1},
+	{constant.Trading.Pending, 0.05},
+	{constant.Business.Rejected, 0.05},
+}
+
+const (
+	// activityShape gives the 80/20 split of the transfers between the
+	// members.
+	activityShape = 1.16
+	// The amounts start at minAmount with a long tail.
+	minAmount   = 5.0
+	amountShape = 1.5
+	// The transfers of the last pendingDays can still be pending.
+	pendingDays  = 14
+	pendingShare = 0.4
+)
+
+// generator draws the data from one source so a seed always gives the same
+// network.
+type generator struct {
+	o     Options
+	r     *rand.Rand
+	start time.Time
+	names map[string]bool
+}
+
+// Generate creates the network described by the options. The same options
+// always give the same dataset.
+func Generate(o Options) *Dataset {
+	g := &generator{
+		o:     o,
+		r:     rand.New(rand.NewSource(o.Seed)),
+		start: o.End.AddDate(0, 0, -o.Days),
+		names: map[string]bool{},
+	}
+
+	d := &Dataset{}
+	for _, s := range sectors {
+		d.AdminTags = append(d.AdminTags, s.adminTag)
+	}
+	d.AdminTags = append(d.AdminTags, partnerTag)
+
+	for i := 0; i < o.Businesses; i++ {
+		d.Members = append(d.Members, g.member(i))
+	}
+	d.Transfers = g.transfers(d.Members)
+	return d
+}
+
+func (g *generator) pick(values []string) string {
+	return values[g.r.Intn(len(values))]
+}
+
+// between returns a time in [from, to).
+func (g *generator) between(from, to time.Time) time.Time {
+	if !to.After(from) {
+		return from
+	}
+	return from.Add(time.Duration(g.r.Int63n(int64(to.Sub(from)))))
+}
+
+// pareto draws from a Pareto distribution starting at scale.
+func (g *generator) pareto(scale float64, shape float64) float64 {
+	return scale / math.Pow(1-g.r.Float64(), 1/shape)
+}
+
+// tags picks n different tags of the values.
+func (g *generator) tags(values []string, n int, from, to time.Time) []*types.TagField {
+	var tags []*types.TagField
+	for _, i := range g.r.Perm(len(values))[:n] {
+		tags = append(tags, &types.TagField{
+			Name:      values[i],
+			CreatedAt: g.between(from, to),
+		})
+	}
+	return tags
+}
+
+func (g *generator) businessName(s sector, l location) string {
+	name := g.pick(namePrefixes) + " " + g.pick(s.nouns)
+	if g.names[name] {
+		name += " " + l.city
+	}
+	for i := 2; g.names[name]; i++ {
+		name = fmt.Sprintf("%s %s %d", g.pick(namePrefixes), g.pick(s.nouns), i)
+	}
+	g.names[name] = true
+	return name
+}
+
+func (g *generator) postcode(l location) string {
+	return fmt.Sprintf(
+		"%s%d %d%c%c",
+		l.postcode,
+		1+g.r.Intn(20),
+		g.r.Intn(10),
+		postcodeChars[g.r.Intn(len(postcodeChars))],
+		postcodeChars[g.r.Intn(len(postcodeChars))],
+	)
+}
+
+func (g *generator) status() string {
+	x := g.r.Float64()
+	for _, s := range statuses {
+		if x < s.weight {
+			return s.status
+		}
+		x -= s.weight
+	}
+	return constant.Business.Accepted
+}
+
+func slug(name string) string {
+	s := strings.ToLower(name)
+	s = strings.NewReplacer(" ", "-", "/", "-", "&", "and").Replace(s)
+	return s
+}
+
+func (g *generator) member(i int) *Member {
+	s := sectors[g.r.Intn(len(sectors))]
+	l := locations[g.r.Intn(len(locations))]
+	name := g.businessName(s, l)
+
+	// The members join during the first half of the history, so most of
+	// them trade for a while.
+	window := g.o.End.Sub(g.start)
+	createdAt := g.between(g.start, g.start.Add(window/2))
+
+	// The wants are offered by the other sectors.
+	other := sectors[g.r.Intn(len(sectors))]
+	for other.adminTag == s.adminTag {
+		other = sectors[g.r.Intn(len(sectors))]
+	}
+	offers := g.tags(s.offers, 2+g.r.Intn(3), createdAt, g.o.End)
+	wants := g.tags(other.offers, 1+g.r.Intn(3), createdAt, g.o.End)
+
+	adminTags := []string{s.adminTag}
+	if g.r.Float64() < 0.1 {
+		adminTags = append(adminTags, partnerTag)
+	}
+
+	var names []string
+	for _, t := range offers {
+		names = append(names, strings.ReplaceAll(t.Name, "-", " "))
+	}
+
+	b := types.Business{
+		CreatedAt:          createdAt,
+		UpdatedAt:          createdAt,
+		BusinessName:       name,
+		BusinessPhone:      fmt.Sprintf("07700 900%03d", g.r.Intn(1000)),
+		IncType:            g.pick(incTypes),
+		CompanyNumber:      fmt.Sprintf("%08d", g.r.Intn(100000000)),
+		Website:            "https://" + slug(name) + ".example.org",
+		Turnover:           10000 + g.r.Intn(490000),
+		Offers:             offers,
+		Wants:              wants,
+		Description:        fmt.Sprintf("%s in %s offering %s.", name, l.city, strings.Join(names, ", ")),
+		LocationAddress:    fmt.Sprintf("%d %s %s", 1+g.r.Intn(200), g.pick(streets), g.pick(streetSuffix)),
+		LocationCity:       l.city,
+		LocationRegion:     l.region,
+		LocationPostalCode: g.postcode(l),
+		LocationCountry:    l.country,
+		Status:             g.status(),
+		AdminTags:          adminTags,
+	}
+	if b.Status == constant.Trading.Accepted {
+		b.MemberStartedAt = g.between(createdAt, createdAt.Add(window/4))
+	}
+
+	first, last := g.pick(firstNames), g.pick(lastNames)
+	u := types.User{
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		FirstName: first,
+		LastName:  last,
+		Email:     fmt.Sprintf("%s.%s%d@dev.null", strings.ToLower(first), strings.ToLower(last), i+1),
+		Telephone: fmt.Sprintf("07700 900%03d", g.r.Intn(1000)),
+	}
+	return &Member{Business: b, User: u}
+}
+
+// transfers draws the senders and the receivers by their activity, so a
+// few members make most of the transfers. The balances are checked in the
+// order the transfers were accepted, the ones which would break the limits
+// are dropped.
+func (g *generator) transfers(members []*Member) []*Transfer {
+	var traders []int
+	var cumulative []float64
+	total := 0.0
+	for i, m := range members {
+		if m.Business.Status != constant.Trading.Accepted {
+			continue
+		}
+		traders = append(traders, i)
+		total += g.pareto(1, activityShape)
+		cumulative = append(cumulative, total)
+	}
+	if len(traders) < 2 {
+		return nil
+	}
+	draw := func() int {
+		return traders[sort.SearchFloat64s(cumulative, g.r.Float64()*total)]
+	}
+
+	// An amount can always be sent from a zero balance.
+	maxAmount := math.Min(g.o.MaxNegBal, g.o.MaxPosBal) / 2
+
+	times := make([]time.Time, g.o.Transfers)
+	for i := range times {
+		times[i] = g.between(g.start, g.o.End)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	balances := make([]float64, len(members))
+	pendingFrom := g.o.End.AddDate(0, 0, -pendingDays)
+	var transfers []*Transfer
+	for _, at := range times {
+		from, to := draw(), draw()
+		if from == to ||
+			members[from].Business.MemberStartedAt.After(at) ||
+			members[to].Business.MemberStartedAt.After(at) {
+			continue
+		}
+		amount := roundAmount(math.Min(g.pareto(minAmount, amountShape), maxAmount))
+
+		t := &Transfer{
+			From:        from,
+			To:          to,
+			InitiatedBy: from,
+			Amount:      amount,
+			Description: "Payment for " + strings.ReplaceAll(
+				g.pick(tagNames(members[to].Business.Offers)), "-", " ",
+			),
+			Status:    constant.Transaction.Completed,
+			CreatedAt: at.Add(-time.Duration(g.r.Int63n(int64(48 * time.Hour)))),
+			UpdatedAt: at,
+		}
+		// Both members have to be trading when the transfer is proposed.
+		started := members[from].Business.MemberStartedAt
+		if members[to].Business.MemberStartedAt.After(started) {
+			started = members[to].Business.MemberStartedAt
+		}
+		if t.CreatedAt.Before(started) {
+			t.CreatedAt = started
+		}
+		if g.r.Intn(2) == 0 {
+			t.InitiatedBy = to
+		}
+		if at.After(pendingFrom) && g.r.Float64() < pendingShare {
+			t.Status = constant.Transaction.Initiated
+			t.CreatedAt = at
+			transfers = append(transfers, t)
+			continue
+		}
+		if balances[from]-amount < -g.o.MaxNegBal ||
+			balances[to]+amount > g.o.MaxPosBal {
+			continue
+		}
+		balances[from] = roundAmount(balances[from] - amount)
+		balances[to] = roundAmount(balances[to] + amount)
+		transfers = append(transfers, t)
+	}
+	return transfers
+}
+
+// roundAmount rounds to the cent, like the amounts entered in the app.
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func tagNames(tags []*types.TagField) []string {
+	var names []string
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// Balances returns the balances of the members after the completed
+// transfers.
+func (d *Dataset) Balances() []float64 {
+	balances := make([]float64, len(d.Members))
+	for _, t := range d.Transfers {
+		if t.Status != constant.Transaction.Completed {
+			continue
+		}
+		balances[t.From] = roundAmount(balances[t.From] - t.Amount)
+		balances[t.To] = roundAmount(balances[t.To] + t.Amount)
+	}
+	return balances
+}
@@ -0,0 +1,97 @@
+package seed
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testOptions = Options{
+	Businesses: 200,
+	Transfers:  4000,
+	Seed:       7,
+	Days:       365,
+	End:        time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	MaxNegBal:  500,
+	MaxPosBal:  2000,
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	d := Generate(testOptions)
+	assert.Equal(t, d, Generate(testOptions))
+
+	o := testOptions
+	o.Seed = 8
+	assert.NotEqual(t, d.Members[0], Generate(o).Members[0])
+}
+
+func TestGenerateMembers(t *testing.T) {
+	d := Generate(testOptions)
+	require.Len(t, d.Members, testOptions.Businesses)
+
+	emails := map[string]bool{}
+	for _, m := range d.Members {
+		b := m.Business
+		assert.NotEmpty(t, b.BusinessName)
+		assert.NotEmpty(t, b.Offers)
+		assert.NotEmpty(t, b.Wants)
+		assert.NotEmpty(t, b.LocationCity)
+		assert.NotEmpty(t, b.AdminTags)
+		assert.Subset(t, d.AdminTags, b.AdminTags)
+		if b.Status == constant.Trading.Accepted {
+			assert.False(t, b.MemberStartedAt.Before(b.CreatedAt))
+		} else {
+			assert.True(t, b.MemberStartedAt.IsZero())
+		}
+		assert.False(t, emails[m.User.Email], m.User.Email)
+		emails[m.User.Email] = true
+	}
+}
+
+func TestGenerateTransfers(t *testing.T) {
+	d := Generate(testOptions)
+	require.NotEmpty(t, d.Transfers)
+
+	pendingFrom := testOptions.End.AddDate(0, 0, -pendingDays)
+	counts := map[int]int{}
+	for _, tr := range d.Transfers {
+		from, to := d.Members[tr.From].Business, d.Members[tr.To].Business
+		assert.NotEqual(t, tr.From, tr.To)
+		assert.Equal(t, constant.Trading.Accepted, from.Status)
+		assert.Equal(t, constant.Trading.Accepted, to.Status)
+		assert.False(t, tr.UpdatedAt.Before(from.MemberStartedAt))
+		assert.False(t, tr.UpdatedAt.Before(to.MemberStartedAt))
+		assert.False(t, tr.CreatedAt.Before(from.MemberStartedAt))
+		assert.False(t, tr.CreatedAt.Before(to.MemberStartedAt))
+		assert.False(t, tr.CreatedAt.After(tr.UpdatedAt))
+		assert.Greater(t, tr.Amount, 0.0)
+		if tr.Status == constant.Transaction.Initiated {
+			assert.True(t, tr.CreatedAt.After(pendingFrom))
+		}
+		counts[tr.From]++
+		counts[tr.To]++
+	}
+
+	for _, balance := range d.Balances() {
+		assert.GreaterOrEqual(t, balance, -testOptions.MaxNegBal)
+		assert.LessOrEqual(t, balance, testOptions.MaxPosBal)
+	}
+
+	// The most active fifth of the traders take part in most transfers.
+	var active []int
+	total := 0
+	for _, n := range counts {
+		active = append(active, n)
+		total += n
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(active)))
+	top := 0
+	for _, n := range active[:len(active)/5] {
+		top += n
+	}
+	assert.Greater(t, float64(top)/float64(total), 0.5)
+}
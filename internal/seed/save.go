@@ -0,0 +1,328 @@
+package seed
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ic3network/mccs-alpha/global"
+	"github.com/ic3network/mccs-alpha/global/constant"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/es"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/mongo"
+	"github.com/ic3network/mccs-alpha/internal/app/repositories/pg"
+	"github.com/ic3network/mccs-alpha/internal/app/service"
+	"github.com/ic3network/mccs-alpha/internal/app/types"
+	"github.com/ic3network/mccs-alpha/internal/pkg/bcrypt"
+	"github.com/jinzhu/gorm"
+	"github.com/olivere/elastic/v7"
+	"github.com/segmentio/ksuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Password is the password of the generated users.
+const Password = "password"
+
+// Save writes the dataset to MongoDB, PostgreSQL and Elasticsearch, which is
+// skipped when the search runs in memory. The balances of the accounts are
+// the sums of their postings. The records get the same IDs on every run of
+// the same dataset, so Save can be run again after a failure: the records
+// saved already are updated or skipped.
+func Save(ctx context.Context, d *Dataset, o Options) {
+	log.Println("start saving the generated data")
+	startTime := time.Now()
+
+	if len(d.Members) == 0 {
+		return
+	}
+
+	// One hash for all the users, hashing is slow on purpose.
+	hashedPassword, err := bcrypt.Hash(Password)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var businessIDs, userIDs []primitive.ObjectID
+	var businesses, users []interface{}
+	for _, m := range d.Members {
+		m.Business.ID = objectID(m.Business.CreatedAt, "business/"+m.User.Email)
+		m.User.ID = objectID(m.User.CreatedAt, "user/"+m.User.Email)
+		m.User.CompanyID = m.Business.ID
+		m.User.Password = hashedPassword
+		businessIDs = append(businessIDs, m.Business.ID)
+		businesses = append(businesses, m.Business)
+		userIDs = append(userIDs, m.User.ID)
+		users = append(users, m.User)
+	}
+	upsertMany(ctx, "businesses", businessIDs, businesses)
+	upsertMany(ctx, "users", userIDs, users)
+	saveTags(ctx, d)
+	saveAdminTags(ctx, d)
+
+	if !global.Config().IsMemorySearch() {
+		var businessIDs, userIDs []string
+		var businessRecords, userRecords []interface{}
+		for _, m := range d.Members {
+			businessIDs = append(businessIDs, m.Business.ID.Hex())
+			businessRecords = append(businessRecords, es.NewBusinessRecord(&m.Business))
+			userIDs = append(userIDs, m.User.ID.Hex())
+			userRecords = append(userRecords, es.NewUserRecord(&m.User))
+		}
+		bulkIndex(ctx, "businesses", businessIDs, businessRecords)
+		bulkIndex(ctx, "users", userIDs, userRecords)
+	}
+
+	accountIDs := saveAccounts(ctx, d, o)
+	saveTransfers(ctx, d, accountIDs)
+
+	log.Printf("businesses %v\n", len(d.Members))
+	log.Printf("transfers %v\n", len(d.Transfers))
+	log.Printf("took  %v\n", time.Now().Sub(startTime))
+}
+
+// objectID returns the ID of a generated record: the time it was created
+// and a hash of its key.
+func objectID(createdAt time.Time, key string) primitive.ObjectID {
+	var id primitive.ObjectID
+	binary.BigEndian.PutUint32(id[0:4], uint32(createdAt.Unix()))
+	sum := sha1.Sum([]byte(key))
+	copy(id[4:], sum[:])
+	return id
+}
+
+// upsertMany replaces the documents with the same IDs, or inserts them, in
+// batches.
+func upsertMany(ctx context.Context, collection string, ids []primitive.ObjectID, documents []interface{}) {
+	const batchSize = 1000
+	for start := 0; start < len(documents); start += batchSize {
+		end := start + batchSize
+		if end > len(documents) {
+			end = len(documents)
+		}
+		models := make([]mongodriver.WriteModel, 0, end-start)
+		for i := start; i < end; i++ {
+			models = append(models, mongodriver.NewReplaceOneModel().
+				SetFilter(bson.M{"_id": ids[i]}).
+				SetReplacement(documents[i]).
+				SetUpsert(true))
+		}
+		_, err := mongo.DB().
+			Collection(collection).
+			BulkWrite(ctx, models)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("%v: %v/%v\n", collection, end, len(documents))
+	}
+}
+
+// saveTags adds the offers and the wants to the tags, the tags which exist
+// keep their earliest dates.
+func saveTags(ctx context.Context, d *Dataset) {
+	offers := map[string]time.Time{}
+	wants := map[string]time.Time{}
+	var names []string
+	earliest := func(dates map[string]time.Time, tags []*types.TagField) {
+		for _, t := range tags {
+			_, offered := offers[t.Name]
+			_, wanted := wants[t.Name]
+			if !offered && !wanted {
+				names = append(names, t.Name)
+			}
+			if at, ok := dates[t.Name]; !ok || t.CreatedAt.Before(at) {
+				dates[t.Name] = t.CreatedAt
+			}
+		}
+	}
+	for _, m := range d.Members {
+		if m.Business.Status != constant.Trading.Accepted {
+			continue
+		}
+		earliest(offers, m.Business.Offers)
+		earliest(wants, m.Business.Wants)
+	}
+
+	var ids []string
+	var records []interface{}
+	for _, name := range names {
+		added := bson.M{}
+		if at, ok := offers[name]; ok {
+			added["offerAddedAt"] = at
+		}
+		if at, ok := wants[name]; ok {
+			added["wantAddedAt"] = at
+		}
+		update := bson.M{
+			"$min": added,
+			"$set": bson.M{"updatedAt": time.Now()},
+			"$setOnInsert": bson.M{
+				"name":      name,
+				"createdAt": time.Now(),
+			},
+		}
+		opts := options.FindOneAndUpdate().
+			SetUpsert(true).
+			SetReturnDocument(options.After)
+
+		var tag types.Tag
+		err := mongo.DB().
+			Collection("tags").
+			FindOneAndUpdate(ctx, bson.M{"name": name}, update, opts).
+			Decode(&tag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ids = append(ids, tag.ID.Hex())
+		records = append(records, es.NewTagRecord(&tag))
+	}
+	log.Printf("tags: %v\n", len(ids))
+
+	if !global.Config().IsMemorySearch() {
+		bulkIndex(ctx, "tags", ids, records)
+	}
+}
+
+// saveAdminTags adds the admin tags which do not exist yet.
+func saveAdminTags(ctx context.Context, d *Dataset) {
+	for _, name := range d.AdminTags {
+		_, err := mongo.DB().Collection("adminTags").UpdateOne(
+			ctx,
+			bson.M{"name": name},
+			bson.M{"$setOnInsert": bson.M{
+				"name":      name,
+				"createdAt": time.Now(),
+				"updatedAt": time.Now(),
+			}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// bulkIndex indexes the records under their ids with bulk requests.
+func bulkIndex(ctx context.Context, index string, ids []string, records []interface{}) {
+	bulkSize := global.Config().ES.BulkSize
+	if bulkSize <= 0 {
+		bulkSize = 500
+	}
+
+	bulk := es.Client().Bulk().Index(index)
+	flush := func(counter int) {
+		if bulk.NumberOfActions() == 0 {
+			return
+		}
+		res, err := bulk.Do(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, failed := range res.Failed() {
+			if failed.Status != http.StatusNotFound {
+				log.Fatalf("bulk request failed for %v: %+v", failed.Id, failed.Error)
+			}
+		}
+		log.Printf("%v: %v/%v\n", index, counter, len(records))
+	}
+
+	for i, record := range records {
+		bulk.Add(elastic.NewBulkIndexRequest().Id(ids[i]).Doc(record))
+		if bulk.NumberOfActions() >= bulkSize {
+			flush(i + 1)
+		}
+	}
+	flush(len(records))
+}
+
+// saveAccounts creates the missing accounts and gives the trading members the
+// balance limits of the options. It returns the account IDs of the members.
+func saveAccounts(ctx context.Context, d *Dataset, o Options) []uint {
+	accountIDs := make([]uint, len(d.Members))
+	for i, m := range d.Members {
+		account, err := service.Account.FindByBusinessID(ctx, m.Business.ID.Hex())
+		if err != nil {
+			err = service.Account.Create(ctx, m.Business.ID.Hex())
+			if err != nil {
+				log.Fatal(err)
+			}
+			account, err = service.Account.FindByBusinessID(ctx, m.Business.ID.Hex())
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		accountIDs[i] = account.ID
+
+		if m.Business.Status == constant.Trading.Accepted {
+			err = service.BalanceLimit.Update(ctx, account.ID, o.MaxPosBal, o.MaxNegBal)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+	log.Printf("accounts: %v\n", len(accountIDs))
+	return accountIDs
+}
+
+// saveTransfers stores the journals, the completed ones with their postings.
+// The journals stored by an earlier run are skipped.
+func saveTransfers(ctx context.Context, d *Dataset, accountIDs []uint) {
+	imported, err := pg.Transaction.ImportedIDs(ctx, accountIDs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	skipped := 0
+	for i, t := range d.Transfers {
+		from, to := d.Members[t.From], d.Members[t.To]
+		transactionID := journalID(i, t, from, to)
+		if imported[transactionID] {
+			skipped++
+			continue
+		}
+		j := &types.Journal{
+			Model: gorm.Model{
+				CreatedAt: t.CreatedAt,
+				UpdatedAt: t.UpdatedAt,
+			},
+			TransactionID:    transactionID,
+			InitiatedBy:      accountIDs[t.InitiatedBy],
+			FromID:           accountIDs[t.From],
+			FromEmail:        from.User.Email,
+			FromBusinessName: from.Business.BusinessName,
+			ToID:             accountIDs[t.To],
+			ToEmail:          to.User.Email,
+			ToBusinessName:   to.Business.BusinessName,
+			Amount:           t.Amount,
+			Description:      t.Description,
+			Type:             constant.Journal.Transfer,
+			Status:           t.Status,
+		}
+		err := pg.Transaction.Import(ctx, j)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if (i+1)%1000 == 0 {
+			log.Printf("transfers: %v/%v\n", i+1, len(d.Transfers))
+		}
+	}
+	if skipped > 0 {
+		log.Printf("transfers saved already: %v\n", skipped)
+	}
+}
+
+// journalID returns the transaction ID of the i-th transfer, the same on
+// every run of the dataset.
+func journalID(i int, t *Transfer, from *Member, to *Member) string {
+	key := fmt.Sprintf("%d/%s/%s/%d", i, from.User.Email, to.User.Email, t.UpdatedAt.UnixNano())
+	sum := sha1.Sum([]byte(key))
+	id, err := ksuid.FromParts(t.CreatedAt, sum[:16])
+	if err != nil {
+		log.Fatal(err)
+	}
+	return id.String()
+}
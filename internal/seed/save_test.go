@@ -0,0 +1,25 @@
+package seed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavedIDsAreStable(t *testing.T) {
+	d := Generate(testOptions)
+	again := Generate(testOptions)
+	m := d.Members[0]
+
+	id := objectID(m.Business.CreatedAt, "business/"+m.User.Email)
+	assert.Equal(t, id, objectID(again.Members[0].Business.CreatedAt, "business/"+m.User.Email))
+	assert.Equal(t, m.Business.CreatedAt.Unix(), id.Timestamp().Unix())
+	assert.NotEqual(t, id, objectID(m.Business.CreatedAt, "user/"+m.User.Email))
+
+	tr := d.Transfers[0]
+	from, to := d.Members[tr.From], d.Members[tr.To]
+	journal := journalID(0, tr, from, to)
+	assert.Len(t, journal, 27)
+	assert.Equal(t, journal, journalID(0, again.Transfers[0], from, to))
+	assert.NotEqual(t, journal, journalID(1, tr, from, to))
+}
@@ -70,7 +70,7 @@ func LoadData() {
 	adminTagData = adminTags
 }
 
-func Run() {
+func Run(ctx context.Context) {
 	log.Println("start seeding")
 	startTime := time.Now()
 
@@ -78,7 +78,7 @@ func Run() {
 	for i, b := range businessData {
 		res, err := mongo.DB().
 			Collection("businesses").
-			InsertOne(context.Background(), b)
+			InsertOne(ctx, b)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -98,13 +98,13 @@ func Run() {
 			Index("businesses").
 			Id(b.ID.Hex()).
 			BodyJson(bRecord).
-			Do(context.Background())
+			Do(ctx)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 		// PostgresSQL - Create account from business.
-		err = service.Account.Create(b.ID.Hex())
+		err = service.Account.Create(ctx, b.ID.Hex())
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -115,7 +115,7 @@ func Run() {
 		u.Password = hashedPassword
 		res, err = mongo.DB().
 			Collection("users").
-			InsertOne(context.Background(), u)
+			InsertOne(ctx, u)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -133,7 +133,7 @@ func Run() {
 				Index("users").
 				Id(userID).
 				BodyJson(uRecord).
-				Do(context.Background())
+				Do(ctx)
 		}
 
 		if err != nil {
@@ -147,7 +147,7 @@ func Run() {
 		u.Password = hashedPassword
 		_, err := mongo.DB().
 			Collection("adminUsers").
-			InsertOne(context.Background(), u)
+			InsertOne(ctx, u)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -157,7 +157,7 @@ func Run() {
 	for _, t := range tagData {
 		res, err := mongo.DB().
 			Collection("tags").
-			InsertOne(context.Background(), t)
+			InsertOne(ctx, t)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -176,7 +176,7 @@ func Run() {
 				Index("tags").
 				Id(tagID).
 				BodyJson(tagRecord).
-				Do(context.Background())
+				Do(ctx)
 			if err != nil {
 				log.Fatal(err)
 			}
@@ -187,7 +187,7 @@ func Run() {
 	for _, a := range adminTagData {
 		_, err := mongo.DB().
 			Collection("adminTags").
-			InsertOne(context.Background(), a)
+			InsertOne(ctx, a)
 		if err != nil {
 			log.Fatal(err)
 		}
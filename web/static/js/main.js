@@ -31,21 +31,24 @@ $(function () {
         allowAdditions: true
     });
 
-    $(".wants.dropdown, .offers.dropdown").dropdown({
-        allowAdditions: true,
-        ignoreCase: true,
-        forceSelection: false,
-        hideAdditions: false,
-        apiSettings: {
-            throttle: 700,
-            url: '/api/tags/{query}',
-            beforeSend: function (settings) {
-                if (settings.urlData.query === "") {
-                    return false;
+    // Suggest the tags used by the most trading members first.
+    ["offers", "wants"].forEach(function (tagType) {
+        $("." + tagType + ".dropdown").dropdown({
+            allowAdditions: true,
+            ignoreCase: true,
+            forceSelection: false,
+            hideAdditions: false,
+            apiSettings: {
+                throttle: 700,
+                url: '/api/tags/' + tagType + '/{query}',
+                beforeSend: function (settings) {
+                    if (settings.urlData.query === "") {
+                        return false;
+                    }
+                    return settings
                 }
-                return settings
-            }
-        },
+            },
+        });
     });
     // ****************************
 
@@ -81,6 +84,75 @@ $(function () {
         });
     });
 
+    // Bulk Business Actions
+    function bulkSelectedIDs() {
+        return $(".bulk-select:checked").map(function () {
+            return $(this).val()
+        }).get()
+    }
+
+    function bulkList(value) {
+        return value.split(",").map(function (tag) {
+            return tag.trim()
+        }).filter(function (tag) {
+            return tag !== ""
+        })
+    }
+
+    function bulkNumber(value) {
+        return value === "" ? null : parseFloat(value)
+    }
+
+    $(".bulk-select").change(function () {
+        $("#bulk-count").text(bulkSelectedIDs().length)
+    });
+
+    $("#bulk-select-all").change(function () {
+        $(".bulk-select").prop("checked", $(this).prop("checked"))
+        $("#bulk-count").text(bulkSelectedIDs().length)
+    });
+
+    $("#bulk-apply").click(function () {
+        const ids = bulkSelectedIDs()
+        if (ids.length === 0) {
+            showErrorMessage("Please select at least one business.")
+            return
+        }
+        if (!confirm(`Apply the changes to ${ids.length} businesses?`)) {
+            return
+        }
+        $.ajax({
+            url: "/admin/api/businesses/bulk",
+            method: "POST",
+            contentType: "application/json",
+            data: JSON.stringify({
+                businessIDs: ids,
+                status: $("#bulk-status").val(),
+                addAdminTags: bulkList($("#bulk-add-admin-tags").val()),
+                removeAdminTags: bulkList($("#bulk-remove-admin-tags").val()),
+                maxPosBal: bulkNumber($("#bulk-max-pos-bal").val()),
+                maxNegBal: bulkNumber($("#bulk-max-neg-bal").val())
+            }),
+            success: function (res) {
+                const list = $("#bulk-results").empty()
+                res.results.forEach(function (result) {
+                    if (result.error) {
+                        const name = $(`#${result.businessID} td:nth-child(2)`).text().trim() || result.businessID
+                        $("<div class='item'></div>").text(name + ": " + result.error).appendTo(list)
+                    }
+                })
+                if (res.failed > 0) {
+                    showErrorMessage(`${res.updated} businesses updated, ${res.failed} failed.`)
+                } else {
+                    showSuccessMessage(`${res.updated} businesses updated. Search again to see the changes.`)
+                }
+            },
+            error: function (xhr) {
+                showErrorMessage(errorMessage(xhr));
+            }
+        });
+    });
+
     // Create Tag
     $("#submit-tag").click(function () {
         const tagName = $("#new-tag").val()
@@ -95,7 +167,7 @@ $(function () {
                 showSuccessMessage("Tag created.")
             },
             error: function (xhr) {
-                showErrorMessage(xhr.responseText);
+                showErrorMessage(errorMessage(xhr));
             }
         });
     });
@@ -110,7 +182,7 @@ $(function () {
                 showSuccessMessage("Tag has been removed.")
             },
             error: function (xhr) {
-                showErrorMessage("An error occurred: " + xhr.status + " " + xhr.statusText);
+                showErrorMessage(errorMessage(xhr));
             }
         });
     });
@@ -130,7 +202,7 @@ $(function () {
                 showSuccessMessage("Admin tag created.")
             },
             error: function (xhr) {
-                showErrorMessage(xhr.responseText);
+                showErrorMessage(errorMessage(xhr));
             }
         });
     });
@@ -158,7 +230,7 @@ $(function () {
                 $(`tr[id=${id}] td:first`).html(name);
             },
             error: function (xhr) {
-                showErrorMessage(xhr.responseText);
+                showErrorMessage(errorMessage(xhr));
             }
         });
     });
@@ -189,7 +261,7 @@ $(function () {
                 $(`input[admin-tag-id=${id}]`).val("");
             },
             error: function (xhr) {
-                showErrorMessage(xhr.responseText);
+                showErrorMessage(errorMessage(xhr));
             }
         });
     });
@@ -220,7 +292,7 @@ $(function () {
                 showSuccessMessage("Tag has been removed.")
             },
             error: function (xhr) {
-                showErrorMessage("An error occurred: " + xhr.status + " " + xhr.statusText);
+                showErrorMessage(errorMessage(xhr));
             }
         });
     });
@@ -245,7 +317,7 @@ function handleClickFavorite(bID) {
                 elem.addClass("red")
             },
             error: function(xhr) {
-                showErrorMessage("An error occurred: " + xhr.status + " " + xhr.statusText);
+                showErrorMessage(errorMessage(xhr));
             }
         });
     } else {
@@ -261,7 +333,7 @@ function handleClickFavorite(bID) {
                 elem.removeClass("red")
             },
             error: function(xhr) {
-                showErrorMessage("An error occurred: " + xhr.status + " " + xhr.statusText);
+                showErrorMessage(errorMessage(xhr));
             }
         });
     }
@@ -281,6 +353,15 @@ const showErrorMessage = message => {
     $(".ajax-error .header").text(message)
 }
 
+// The JSON endpoints return {code, message, requestID} on errors.
+const errorMessage = xhr => {
+    const res = xhr.responseJSON
+    if (!res || !res.message) {
+        return "An error occurred: " + xhr.status + " " + xhr.statusText
+    }
+    return res.requestID ? `${res.message} (Request ID: ${res.requestID})` : res.message
+}
+
 // Advanced Search Toggle
 const handleClickAdvancedSearch = () => {
     if ($("#advanced-search").css("display") === "none") {